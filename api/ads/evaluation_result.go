@@ -20,6 +20,24 @@ func (p *EvaluationResult) AddPolicy(policy *pms.Policy, policyStatus string, re
 	p.Policies = append(p.Policies, &apiEvaluatedPolicy)
 }
 
+// AddInactivePolicy adds a disabled, not yet active or expired policy, which is not evaluated
+func (p *EvaluationResult) AddInactivePolicy(policy *pms.Policy) {
+	var apiEvaluatedPolicy EvaluatedPolicy
+	convertMetaPolicy2ApiEvaluatedPolicy(policy, &apiEvaluatedPolicy, Evaluation_Inactive, "")
+	p.Policies = append(p.Policies, &apiEvaluatedPolicy)
+}
+
+// AddInactiveRolePolicy adds a disabled, not yet active or expired role policy, which is not evaluated
+func (p *EvaluationResult) AddInactiveRolePolicy(rolePolicy *pms.RolePolicy) {
+	var apiEvaluatedRolePolicy EvaluatedRolePolicy
+	convertMetaRolePolicy2ApiEvaluatedRolePolicy(rolePolicy, &apiEvaluatedRolePolicy, false)
+	apiEvaluatedRolePolicy.Status = Evaluation_Inactive
+	if apiEvaluatedRolePolicy.Condition != nil {
+		apiEvaluatedRolePolicy.Condition.EvaluationResult = ""
+	}
+	p.RolePolicies = append(p.RolePolicies, &apiEvaluatedRolePolicy)
+}
+
 func (p *EvaluationResult) AddPolicies(grantedPolicies []*pms.Policy, deniedPolicies []*pms.Policy) {
 	needIgnore := false
 	for _, metaPolicy := range deniedPolicies {
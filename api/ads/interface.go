@@ -11,6 +11,10 @@ type PolicyEvaluator interface {
 	// IsAllowed returns if the subject has been granted to a resource specified by a request context
 	IsAllowed(c RequestContext) (allowed bool, reason Reason, err error)
 
+	// IsAllowedByPolicies returns if the subject has been granted by the policies of the service, the
+	// mode and the default decision of the service don't apply. It is used for internal authorization.
+	IsAllowedByPolicies(c RequestContext) (allowed bool, reason Reason, err error)
+
 	// GetAllGrantedRoles returns the granted app roles in an application.
 	GetAllGrantedRoles(c RequestContext) ([]string, error)
 
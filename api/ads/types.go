@@ -3,7 +3,11 @@
 
 package ads
 
-import "github.com/teramoby/speedle-plus/api/pms"
+import (
+	"context"
+
+	"github.com/teramoby/speedle-plus/api/pms"
+)
 
 type Principal struct {
 	Type string `json:"type,omitempty"`
@@ -20,6 +24,8 @@ type Subject struct {
 	TokenType  string       `json:"tokenType,omitempty"`
 	Token      string       `json:"token,omitempty"`
 	Asserted   bool         `json:"asserted,omitempty"`
+	// attributes returned by the token asserter, never accepted from callers
+	AssertedAttributes map[string]interface{} `json:"-"`
 }
 
 type RequestContext struct {
@@ -28,6 +34,11 @@ type RequestContext struct {
 	Resource    string                 `json:"resource,omitempty"`
 	Action      string                 `json:"action,omitempty"`
 	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	// ShadowReason is the reason why a request allowed in shadow mode would have been denied, it is
+	// only set in the discover store
+	ShadowReason string `json:"shadowReason,omitempty" bson:"shadowreason,omitempty"`
+	// TraceContext carries the span of the request into the evaluation, it is not serialized
+	TraceContext context.Context `json:"-" bson:"-"`
 }
 
 type EvaluationResult struct {
@@ -38,8 +49,72 @@ type EvaluationResult struct {
 	GrantedRoles []string               `json:"grantedRoles,omitempty"`
 	RolePolicies []*EvaluatedRolePolicy `json:"rolePolicies,omitempty"`
 	Policies     []*EvaluatedPolicy     `json:"policies,omitempty"`
+	Functions    []*FunctionStatus      `json:"functions,omitempty"`
+	// where each attribute comes from
+	AttributeSources map[string]*AttributeSource `json:"attributeSources,omitempty"`
+}
+
+// AttributeSource tells where an attribute comes from
+type AttributeSource struct {
+	Source string `json:"source"`           // request, builtin, token, or name of the attribute provider
+	Cached bool   `json:"cached,omitempty"` // whether the attribute is read from the cache of the attribute provider
+	Error  string `json:"error,omitempty"`  // error of the attribute provider if the attribute is not found
+}
+
+const (
+	AttributeSource_Request = "request"
+	AttributeSource_BuiltIn = "builtin"
+	AttributeSource_Token   = "token"
+)
+
+// FunctionStatus is the circuit breaker state and call counters of a customer function
+type FunctionStatus struct {
+	Name                string `json:"name"`
+	State               string `json:"state"`
+	ConsecutiveFailures int    `json:"consecutiveFailures"`
+	OpenedAt            int64  `json:"openedAt,omitempty"`
+	Calls               int64  `json:"calls"`
+	Failures            int64  `json:"failures"`
+	Retries             int64  `json:"retries"`
+	Rejected            int64  `json:"rejected"`
+	Fallbacks           int64  `json:"fallbacks"`
 }
 
+// FunctionCacheStats is the result cache statistics of a customer function
+type FunctionCacheStats struct {
+	Name       string `json:"name"`
+	Entries    int    `json:"entries"`
+	MaxEntries int    `json:"maxEntries"`
+	Bytes      int64  `json:"bytes"`
+	Hits       int64  `json:"hits"`
+	Misses     int64  `json:"misses"`
+	Evictions  int64  `json:"evictions"`
+}
+
+// AsserterStats is the call counters and latency of a token asserter
+type AsserterStats struct {
+	Name         string  `json:"name"`
+	Type         string  `json:"type"`
+	Calls        int64   `json:"calls"`
+	Failures     int64   `json:"failures"`
+	AvgLatencyMs float64 `json:"avgLatencyMs"`
+	MaxLatencyMs float64 `json:"maxLatencyMs"`
+}
+
+// AsserterStatus is the statistics of token asserters and of the asserted subject cache
+type AsserterStatus struct {
+	Asserters    []*AsserterStats `json:"asserters"`
+	CacheEntries int              `json:"cacheEntries"`
+	CacheHits    int64            `json:"cacheHits"`
+	CacheMisses  int64            `json:"cacheMisses"`
+}
+
+const (
+	Breaker_Closed   string = "closed"
+	Breaker_Open     string = "open"
+	Breaker_HalfOpen string = "halfOpen"
+)
+
 type EvaluatedPolicy struct {
 	Status      string              `json:"status,omitempty"`
 	ID          string              `json:"id,omitempty"`
@@ -71,6 +146,7 @@ const (
 	Evaluation_TakeEffect      string = "takeEffect"
 	Evaluation_ConditionFailed string = "conditionFailed"
 	Evaluation_Ignored         string = "ignored"
+	Evaluation_Inactive        string = "inactive"
 )
 
 //reason for evaluation result
@@ -84,6 +160,10 @@ const (
 	ERROR_IN_EVALUATION
 	DISCOVER_MODE
 	REASON_NOT_AVAILABLE
+	DEFAULT_GRANT  // no policy applies, and the default effect of the service is grant
+	FAIL_OPEN      // the evaluation fails, and the service fails open
+	SHADOW_MODE    // the service is in shadow mode, and the request would have been denied
+	TOKEN_REJECTED // the identity token is rejected by the asserter, the request is always denied
 )
 
 const (
@@ -113,8 +193,20 @@ var reason = []string{
 	"ERROR_IN_EVALUATION",
 	"DISCOVER_MODE",
 	"REASON_NOT_AVAILABLE",
+	"DEFAULT_GRANT",
+	"FAIL_OPEN",
+	"SHADOW_MODE",
+	"TOKEN_REJECTED",
 }
 
+// Kinds of decisions, which tell the decisions made by policies from the default ones
+const (
+	Decision_Policy   = "policy"
+	Decision_Default  = "default"
+	Decision_Failure  = "failure"
+	Decision_Discover = "discover"
+)
+
 const (
 	PRINCIPAL_TYPE_USER   = "user"
 	PRINCIPAL_TYPE_GROUP  = "group"
@@ -124,3 +216,18 @@ const (
 
 // String returns the English name of the Reason
 func (m Reason) String() string { return reason[m] }
+
+// Decision returns whether the decision of the Reason is made by policies, by default or on failure
+func (m Reason) Decision() string {
+	switch m {
+	case GRANT_POLICY_FOUND, DENY_POLICY_FOUND:
+		return Decision_Policy
+	case SERVICE_NOT_FOUND, NO_APPLICABLE_POLICIES, DEFAULT_GRANT:
+		return Decision_Default
+	case ERROR_IN_EVALUATION, FAIL_OPEN, TOKEN_REJECTED:
+		return Decision_Failure
+	case DISCOVER_MODE, SHADOW_MODE:
+		return Decision_Discover
+	}
+	return ""
+}
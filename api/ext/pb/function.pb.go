@@ -0,0 +1,427 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: function.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type NullValue struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NullValue) Reset()         { *m = NullValue{} }
+func (m *NullValue) String() string { return proto.CompactTextString(m) }
+func (*NullValue) ProtoMessage()    {}
+func (*NullValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8ac74addf543d91a, []int{0}
+}
+
+func (m *NullValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NullValue.Unmarshal(m, b)
+}
+func (m *NullValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NullValue.Marshal(b, m, deterministic)
+}
+func (m *NullValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NullValue.Merge(m, src)
+}
+func (m *NullValue) XXX_Size() int {
+	return xxx_messageInfo_NullValue.Size(m)
+}
+func (m *NullValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_NullValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NullValue proto.InternalMessageInfo
+
+type ListValue struct {
+	Values               []*Value `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListValue) Reset()         { *m = ListValue{} }
+func (m *ListValue) String() string { return proto.CompactTextString(m) }
+func (*ListValue) ProtoMessage()    {}
+func (*ListValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8ac74addf543d91a, []int{1}
+}
+
+func (m *ListValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListValue.Unmarshal(m, b)
+}
+func (m *ListValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListValue.Marshal(b, m, deterministic)
+}
+func (m *ListValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListValue.Merge(m, src)
+}
+func (m *ListValue) XXX_Size() int {
+	return xxx_messageInfo_ListValue.Size(m)
+}
+func (m *ListValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListValue proto.InternalMessageInfo
+
+func (m *ListValue) GetValues() []*Value {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type Value struct {
+	// Types that are valid to be assigned to Kind:
+	//	*Value_NullValue
+	//	*Value_BoolValue
+	//	*Value_NumberValue
+	//	*Value_StringValue
+	//	*Value_ListValue
+	Kind                 isValue_Kind `protobuf_oneof:"kind"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Value) Reset()         { *m = Value{} }
+func (m *Value) String() string { return proto.CompactTextString(m) }
+func (*Value) ProtoMessage()    {}
+func (*Value) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8ac74addf543d91a, []int{2}
+}
+
+func (m *Value) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Value.Unmarshal(m, b)
+}
+func (m *Value) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Value.Marshal(b, m, deterministic)
+}
+func (m *Value) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Value.Merge(m, src)
+}
+func (m *Value) XXX_Size() int {
+	return xxx_messageInfo_Value.Size(m)
+}
+func (m *Value) XXX_DiscardUnknown() {
+	xxx_messageInfo_Value.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Value proto.InternalMessageInfo
+
+type isValue_Kind interface {
+	isValue_Kind()
+}
+
+type Value_NullValue struct {
+	NullValue *NullValue `protobuf:"bytes,1,opt,name=nullValue,proto3,oneof"`
+}
+
+type Value_BoolValue struct {
+	BoolValue bool `protobuf:"varint,2,opt,name=boolValue,proto3,oneof"`
+}
+
+type Value_NumberValue struct {
+	NumberValue float64 `protobuf:"fixed64,3,opt,name=numberValue,proto3,oneof"`
+}
+
+type Value_StringValue struct {
+	StringValue string `protobuf:"bytes,4,opt,name=stringValue,proto3,oneof"`
+}
+
+type Value_ListValue struct {
+	ListValue *ListValue `protobuf:"bytes,5,opt,name=listValue,proto3,oneof"`
+}
+
+func (*Value_NullValue) isValue_Kind() {}
+
+func (*Value_BoolValue) isValue_Kind() {}
+
+func (*Value_NumberValue) isValue_Kind() {}
+
+func (*Value_StringValue) isValue_Kind() {}
+
+func (*Value_ListValue) isValue_Kind() {}
+
+func (m *Value) GetKind() isValue_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return nil
+}
+
+func (m *Value) GetNullValue() *NullValue {
+	if x, ok := m.GetKind().(*Value_NullValue); ok {
+		return x.NullValue
+	}
+	return nil
+}
+
+func (m *Value) GetBoolValue() bool {
+	if x, ok := m.GetKind().(*Value_BoolValue); ok {
+		return x.BoolValue
+	}
+	return false
+}
+
+func (m *Value) GetNumberValue() float64 {
+	if x, ok := m.GetKind().(*Value_NumberValue); ok {
+		return x.NumberValue
+	}
+	return 0
+}
+
+func (m *Value) GetStringValue() string {
+	if x, ok := m.GetKind().(*Value_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (m *Value) GetListValue() *ListValue {
+	if x, ok := m.GetKind().(*Value_ListValue); ok {
+		return x.ListValue
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Value) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Value_NullValue)(nil),
+		(*Value_BoolValue)(nil),
+		(*Value_NumberValue)(nil),
+		(*Value_StringValue)(nil),
+		(*Value_ListValue)(nil),
+	}
+}
+
+type FunctionRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Params               []*Value `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FunctionRequest) Reset()         { *m = FunctionRequest{} }
+func (m *FunctionRequest) String() string { return proto.CompactTextString(m) }
+func (*FunctionRequest) ProtoMessage()    {}
+func (*FunctionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8ac74addf543d91a, []int{3}
+}
+
+func (m *FunctionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FunctionRequest.Unmarshal(m, b)
+}
+func (m *FunctionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FunctionRequest.Marshal(b, m, deterministic)
+}
+func (m *FunctionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FunctionRequest.Merge(m, src)
+}
+func (m *FunctionRequest) XXX_Size() int {
+	return xxx_messageInfo_FunctionRequest.Size(m)
+}
+func (m *FunctionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FunctionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FunctionRequest proto.InternalMessageInfo
+
+func (m *FunctionRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FunctionRequest) GetParams() []*Value {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+type FunctionResponse struct {
+	Result               *Value   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FunctionResponse) Reset()         { *m = FunctionResponse{} }
+func (m *FunctionResponse) String() string { return proto.CompactTextString(m) }
+func (*FunctionResponse) ProtoMessage()    {}
+func (*FunctionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8ac74addf543d91a, []int{4}
+}
+
+func (m *FunctionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FunctionResponse.Unmarshal(m, b)
+}
+func (m *FunctionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FunctionResponse.Marshal(b, m, deterministic)
+}
+func (m *FunctionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FunctionResponse.Merge(m, src)
+}
+func (m *FunctionResponse) XXX_Size() int {
+	return xxx_messageInfo_FunctionResponse.Size(m)
+}
+func (m *FunctionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FunctionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FunctionResponse proto.InternalMessageInfo
+
+func (m *FunctionResponse) GetResult() *Value {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *FunctionResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*NullValue)(nil), "pb.NullValue")
+	proto.RegisterType((*ListValue)(nil), "pb.ListValue")
+	proto.RegisterType((*Value)(nil), "pb.Value")
+	proto.RegisterType((*FunctionRequest)(nil), "pb.FunctionRequest")
+	proto.RegisterType((*FunctionResponse)(nil), "pb.FunctionResponse")
+}
+
+func init() { proto.RegisterFile("function.proto", fileDescriptor_8ac74addf543d91a) }
+
+var fileDescriptor_8ac74addf543d91a = []byte{
+	// 304 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xbf, 0x4e, 0xc3, 0x30,
+	0x10, 0xc6, 0xeb, 0x36, 0xad, 0xf0, 0x45, 0x40, 0x65, 0x3a, 0x44, 0x0c, 0x28, 0x78, 0xca, 0x42,
+	0x86, 0xf6, 0x0d, 0xa8, 0x04, 0x95, 0x40, 0x0c, 0x1e, 0xd8, 0x13, 0x30, 0x28, 0xc2, 0xb1, 0x83,
+	0xff, 0xf0, 0xa6, 0xbc, 0x0f, 0xb2, 0x9d, 0x26, 0x01, 0x36, 0xfb, 0xbe, 0x9f, 0xbf, 0xbb, 0xfb,
+	0x0c, 0x67, 0x6f, 0x4e, 0xbe, 0xd8, 0x46, 0xc9, 0xb2, 0xd3, 0xca, 0x2a, 0x32, 0xef, 0x6a, 0x9a,
+	0x02, 0x7e, 0x72, 0x42, 0x3c, 0x57, 0xc2, 0x71, 0x5a, 0x02, 0x7e, 0x6c, 0x8c, 0x0d, 0x17, 0x72,
+	0x0d, 0xab, 0x2f, 0x7f, 0x30, 0x19, 0xca, 0x17, 0x45, 0xba, 0xc5, 0x65, 0x57, 0x97, 0x41, 0x62,
+	0xbd, 0x40, 0xbf, 0x11, 0x2c, 0x23, 0x7c, 0x03, 0x58, 0x1e, 0x6d, 0x32, 0x94, 0xa3, 0x22, 0xdd,
+	0x9e, 0x7a, 0x7e, 0xf0, 0x3e, 0xcc, 0xd8, 0x48, 0x90, 0x2b, 0xc0, 0xb5, 0x52, 0x3d, 0x3e, 0xcf,
+	0x51, 0x71, 0xe2, 0xf5, 0xa1, 0x44, 0x28, 0xa4, 0xd2, 0xb5, 0x35, 0xd7, 0x91, 0x58, 0xe4, 0xa8,
+	0x40, 0x87, 0x19, 0x9b, 0x16, 0x3d, 0x63, 0xac, 0x6e, 0xe4, 0x7b, 0x64, 0x92, 0x1c, 0x15, 0xd8,
+	0x33, 0x93, 0xa2, 0x1f, 0x4b, 0x1c, 0x17, 0xca, 0x96, 0xe3, 0x58, 0xc3, 0x96, 0xbe, 0xed, 0x40,
+	0xdc, 0xae, 0x20, 0xf9, 0x68, 0xe4, 0x2b, 0x3d, 0xc0, 0xf9, 0x5d, 0x1f, 0x15, 0xe3, 0x9f, 0x8e,
+	0x1b, 0x4b, 0x08, 0x24, 0xb2, 0x6a, 0xe3, 0x6e, 0x98, 0x85, 0xb3, 0x4f, 0xa8, 0xab, 0x74, 0xd5,
+	0x9a, 0x6c, 0xfe, 0x2f, 0xa1, 0x28, 0xd0, 0x07, 0x58, 0x8f, 0x4e, 0xa6, 0x53, 0xd2, 0x84, 0x67,
+	0x9a, 0x1b, 0x27, 0x6c, 0x1f, 0xd4, 0xf4, 0x59, 0x14, 0xc8, 0x06, 0x96, 0x5c, 0x6b, 0xa5, 0x43,
+	0x36, 0x98, 0xc5, 0xcb, 0xf6, 0x1e, 0xd6, 0x7b, 0x67, 0xac, 0x6a, 0xb9, 0x3e, 0x9a, 0x92, 0x1d,
+	0x24, 0xfb, 0x4a, 0x08, 0x72, 0xe1, 0x4d, 0xfe, 0x0c, 0x7d, 0xb9, 0xf9, 0x5d, 0x8c, 0xfd, 0xe9,
+	0xac, 0x5e, 0x85, 0xff, 0xdf, 0xfd, 0x0c, 0x00, 0x2c, 0x9f, 0xa4, 0xe2, 0x11, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// CustomerFunctionClient is the client API for CustomerFunction service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CustomerFunctionClient interface {
+	Call(ctx context.Context, in *FunctionRequest, opts ...grpc.CallOption) (*FunctionResponse, error)
+}
+
+type customerFunctionClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCustomerFunctionClient(cc grpc.ClientConnInterface) CustomerFunctionClient {
+	return &customerFunctionClient{cc}
+}
+
+func (c *customerFunctionClient) Call(ctx context.Context, in *FunctionRequest, opts ...grpc.CallOption) (*FunctionResponse, error) {
+	out := new(FunctionResponse)
+	err := c.cc.Invoke(ctx, "/pb.CustomerFunction/Call", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CustomerFunctionServer is the server API for CustomerFunction service.
+type CustomerFunctionServer interface {
+	Call(context.Context, *FunctionRequest) (*FunctionResponse, error)
+}
+
+// UnimplementedCustomerFunctionServer can be embedded to have forward compatible implementations.
+type UnimplementedCustomerFunctionServer struct {
+}
+
+func (*UnimplementedCustomerFunctionServer) Call(ctx context.Context, req *FunctionRequest) (*FunctionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Call not implemented")
+}
+
+func RegisterCustomerFunctionServer(s *grpc.Server, srv CustomerFunctionServer) {
+	s.RegisterService(&_CustomerFunction_serviceDesc, srv)
+}
+
+func _CustomerFunction_Call_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FunctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerFunctionServer).Call(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.CustomerFunction/Call",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerFunctionServer).Call(ctx, req.(*FunctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _CustomerFunction_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.CustomerFunction",
+	HandlerType: (*CustomerFunctionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Call",
+			Handler:    _CustomerFunction_Call_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "function.proto",
+}
@@ -0,0 +1,70 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pb
+
+import (
+	"fmt"
+)
+
+// NewValue converts a condition value (nil, bool, number, string or a list of
+// those) into its typed protobuf representation.
+func NewValue(v interface{}) (*Value, error) {
+	switch val := v.(type) {
+	case nil:
+		return &Value{Kind: &Value_NullValue{NullValue: &NullValue{}}}, nil
+	case bool:
+		return &Value{Kind: &Value_BoolValue{BoolValue: val}}, nil
+	case float64:
+		return &Value{Kind: &Value_NumberValue{NumberValue: val}}, nil
+	case float32:
+		return &Value{Kind: &Value_NumberValue{NumberValue: float64(val)}}, nil
+	case int:
+		return &Value{Kind: &Value_NumberValue{NumberValue: float64(val)}}, nil
+	case int32:
+		return &Value{Kind: &Value_NumberValue{NumberValue: float64(val)}}, nil
+	case int64:
+		return &Value{Kind: &Value_NumberValue{NumberValue: float64(val)}}, nil
+	case string:
+		return &Value{Kind: &Value_StringValue{StringValue: val}}, nil
+	case []string:
+		list := &ListValue{}
+		for _, item := range val {
+			list.Values = append(list.Values, &Value{Kind: &Value_StringValue{StringValue: item}})
+		}
+		return &Value{Kind: &Value_ListValue{ListValue: list}}, nil
+	case []interface{}:
+		list := &ListValue{}
+		for _, item := range val {
+			itemValue, err := NewValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list.Values = append(list.Values, itemValue)
+		}
+		return &Value{Kind: &Value_ListValue{ListValue: list}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+// AsInterface converts a typed protobuf value back into the generic
+// representation used by condition expressions.
+func (m *Value) AsInterface() interface{} {
+	switch kind := m.GetKind().(type) {
+	case *Value_BoolValue:
+		return kind.BoolValue
+	case *Value_NumberValue:
+		return kind.NumberValue
+	case *Value_StringValue:
+		return kind.StringValue
+	case *Value_ListValue:
+		list := []interface{}{}
+		for _, item := range kind.ListValue.GetValues() {
+			list = append(list, item.AsInterface())
+		}
+		return list
+	default:
+		return nil
+	}
+}
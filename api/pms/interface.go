@@ -27,6 +27,7 @@ type ServiceManager interface {
 	GetServiceCount() (int64, error)
 	GetServiceNames() ([]string, error)
 	GetPolicyAndRolePolicyCounts() (map[string]*PolicyAndRolePolicyCount, error)
+	SetServiceOwners(serviceName string, owners []string) error
 }
 
 type PolicyManager interface {
@@ -3,6 +3,8 @@
 
 package pms
 
+import "time"
+
 type Permission struct {
 	Resource           string   `json:"resource,omitempty"`
 	ResourceExpression string   `json:"resourceExpression,omitempty"`
@@ -10,14 +12,40 @@ type Permission struct {
 }
 
 type Function struct {
-	Name           string            `json:"name" bson:"_id"`
-	Description    string            `json:"description,omitempty" bson:"description,omitempty"`
-	FuncURL        string            `json:"funcURL" bson:"funcurl"`                                   //used by speedle/sphinx ADS
-	LocalFuncURL   string            `json:"localFuncURL,omitempty"  bson:"localfuncurl"`              //used by sphinx runtime proxy to get better performance
-	CA             string            `json:"ca,omitempty" bson:"ca,omitempty"`                         //security related configurations
-	ResultCachable bool              `json:"resultCachable,omitempty" bson:"resultcachable,omitempty"` //false by default
-	ResultTTL      int64             `json:"resultTTL,omitempty" bson:"resultttl,omitempty"`           // TTL of function result in second
-	Metadata       map[string]string `json:"metadata,omitempty" bson:"metadata,omitempty"`
+	Name           string              `json:"name" bson:"_id"`
+	Description    string              `json:"description,omitempty" bson:"description,omitempty"`
+	FuncURL        string              `json:"funcURL" bson:"funcurl"`                                   //used by speedle/sphinx ADS
+	LocalFuncURL   string              `json:"localFuncURL,omitempty"  bson:"localfuncurl"`              //used by sphinx runtime proxy to get better performance
+	CA             string              `json:"ca,omitempty" bson:"ca,omitempty"`                         //security related configurations
+	ResultCachable bool                `json:"resultCachable,omitempty" bson:"resultcachable,omitempty"` //false by default
+	ResultTTL      int64               `json:"resultTTL,omitempty" bson:"resultttl,omitempty"`           // TTL of function result in second
+	CacheSize      int                 `json:"cacheSize,omitempty" bson:"cachesize,omitempty"`           // max number of cached results, overrides the global default
+	ErrorTTL       int64               `json:"errorTTL,omitempty" bson:"errorttl,omitempty"`             // TTL of failed call in second, 0 disables caching of errors
+	Resilience     *FunctionResilience `json:"resilience,omitempty" bson:"resilience,omitempty"`         //timeout, retry and circuit breaker settings
+	Metadata       map[string]string   `json:"metadata,omitempty" bson:"metadata,omitempty"`
+}
+
+// FunctionResilience bounds the damage a slow or dead customer function server can do
+type FunctionResilience struct {
+	Timeout          int64             `json:"timeout,omitempty" bson:"timeout,omitempty"`                   // timeout of a single call in millisecond, 5 seconds by default
+	Retries          int               `json:"retries,omitempty" bson:"retries,omitempty"`                   // number of retries after a failed call
+	RetryBackoff     int64             `json:"retryBackoff,omitempty" bson:"retrybackoff,omitempty"`         // wait before the first retry in millisecond, doubled on every retry
+	FailureThreshold int               `json:"failureThreshold,omitempty" bson:"failurethreshold,omitempty"` // consecutive failures opening the circuit breaker, 0 disables the breaker
+	OpenDuration     int64             `json:"openDuration,omitempty" bson:"openduration,omitempty"`         // time in second the breaker stays open before a trial call
+	Fallback         *FunctionFallback `json:"fallback,omitempty" bson:"fallback,omitempty"`                 // result used when the breaker is open
+}
+
+const (
+	FallbackValue = "value"
+	FallbackError = "error"
+	FallbackStale = "stale"
+)
+
+// FunctionFallback defines the result of a customer function while its circuit breaker is open
+type FunctionFallback struct {
+	Type  string      `json:"type" bson:"type"`                       // one of value, error or stale
+	Value interface{} `json:"value,omitempty" bson:"value,omitempty"` // result used by value fallback
+	Error string      `json:"error,omitempty" bson:"error,omitempty"` // error message used by error fallback
 }
 
 type Policy struct {
@@ -27,6 +55,9 @@ type Policy struct {
 	Permissions []*Permission     `json:"permissions,omitempty" bson:"permissions,omitempty"`
 	Principals  [][]string        `json:"principals,omitempty" bson:"principals,omitempty"`
 	Condition   string            `json:"condition,omitempty" bson:"condition,omitempty"`
+	Disabled    bool              `json:"disabled,omitempty" bson:"disabled,omitempty"`   // disabled policies are kept but never take effect
+	NotBefore   *time.Time        `json:"notBefore,omitempty" bson:"notbefore,omitempty"` // the policy takes effect from this instant
+	NotAfter    *time.Time        `json:"notAfter,omitempty" bson:"notafter,omitempty"`   // the policy takes no effect from this instant
 	Metadata    map[string]string `json:"metadata,omitempty" bson:"metadata,omitempty"`
 }
 
@@ -49,24 +80,80 @@ type RolePolicy struct {
 	Resources           []string          `json:"resources,omitempty" bson:"resources,omitempty"`
 	ResourceExpressions []string          `json:"resourceExpressions,omitempty" bson:"resourceexpressions,omitempty"`
 	Condition           string            `json:"condition,omitempty" bson:"condition,omitempty"`
+	Disabled            bool              `json:"disabled,omitempty" bson:"disabled,omitempty"`   // disabled role policies are kept but never take effect
+	NotBefore           *time.Time        `json:"notBefore,omitempty" bson:"notbefore,omitempty"` // the role policy takes effect from this instant
+	NotAfter            *time.Time        `json:"notAfter,omitempty" bson:"notafter,omitempty"`   // the role policy takes no effect from this instant
 	Metadata            map[string]string `json:"metadata,omitempty" bson:"metadata,omitempty"`
 }
 
 type Service struct {
-	Name         string            `json:"name" binding:"required"  bson:"_id"`
-	Type         string            `json:"type,omitempty" bson:"type,omitempty"`
-	Policies     []*Policy         `json:"policies,omitempty" bson:"policies,omitempty"`
-	RolePolicies []*RolePolicy     `json:"rolePolicies,omitempty" bson:"rolepolicies,omitempty"`
-	Metadata     map[string]string `json:"metadata,omitempty" bson:"metadata,omitempty"`
+	Name          string            `json:"name" binding:"required"  bson:"_id"`
+	Type          string            `json:"type,omitempty" bson:"type,omitempty"`
+	Policies      []*Policy         `json:"policies,omitempty" bson:"policies,omitempty"`
+	RolePolicies  []*RolePolicy     `json:"rolePolicies,omitempty" bson:"rolepolicies,omitempty"`
+	Owners        []string          `json:"owners,omitempty" bson:"owners,omitempty"`               //principals managing the service, like "user:alice" or "group:team-a"
+	DefaultEffect string            `json:"defaultEffect,omitempty" bson:"defaulteffect,omitempty"` //effect when no policy applies, grant or deny
+	FailureMode   string            `json:"failureMode,omitempty" bson:"failuremode,omitempty"`     //decision on evaluation errors, open or closed
+	Mode          string            `json:"mode,omitempty" bson:"mode,omitempty"`                   //enforce (default), shadow or discover
+	Metadata      map[string]string `json:"metadata,omitempty" bson:"metadata,omitempty"`
 }
 
 const GlobalService = "global"
 
+// Failure modes of services. Services failing open allow requests when the evaluation fails, services failing
+// closed deny them, even when a condition or a customer function of a policy fails.
+const (
+	FailOpen   = "open"
+	FailClosed = "closed"
+)
+
+// Modes of services. Services in shadow mode evaluate requests and allow them, the requests which would
+// have been denied are recorded in the discover store with their reasons. Services in discover mode allow
+// and record all requests without evaluating them.
+const (
+	ModeEnforce  = "enforce"
+	ModeShadow   = "shadow"
+	ModeDiscover = "discover"
+)
+
 type PolicyStore struct {
 	Functions []*Function `json:"functions,omitempty"`
 	Services  []*Service  `json:"services,omitempty"`
 }
 
+// ChangeRequest is a staged set of policy and role policy changes of a service. The changes are invisible to
+// the authorization decision service until the change request is approved and published.
+type ChangeRequest struct {
+	ID                 string                  `json:"id" bson:"_id"`
+	ServiceName        string                  `json:"serviceName" bson:"servicename"`
+	Title              string                  `json:"title,omitempty" bson:"title,omitempty"`
+	Description        string                  `json:"description,omitempty" bson:"description,omitempty"`
+	Author             string                  `json:"author,omitempty" bson:"author,omitempty"` //principal creating the change request, like "user:alice"
+	Status             string                  `json:"status,omitempty" bson:"status,omitempty"`
+	AddPolicies        []*Policy               `json:"addPolicies,omitempty" bson:"addpolicies,omitempty"`
+	DeletePolicies     []string                `json:"deletePolicies,omitempty" bson:"deletepolicies,omitempty"` //IDs of policies
+	AddRolePolicies    []*RolePolicy           `json:"addRolePolicies,omitempty" bson:"addrolepolicies,omitempty"`
+	DeleteRolePolicies []string                `json:"deleteRolePolicies,omitempty" bson:"deleterolepolicies,omitempty"` //IDs of role policies
+	Approvals          []*ChangeRequestComment `json:"approvals,omitempty" bson:"approvals,omitempty"`
+	Comments           []*ChangeRequestComment `json:"comments,omitempty" bson:"comments,omitempty"`
+	Metadata           map[string]string       `json:"metadata,omitempty" bson:"metadata,omitempty"`
+}
+
+// Status of change requests
+const (
+	ChangeRequestDraft     = "draft"     //changes are edited by the author
+	ChangeRequestReview    = "review"    //changes are frozen and approved by reviewers
+	ChangeRequestPublished = "published" //changes are applied to the service
+	ChangeRequestClosed    = "closed"    //changes are abandoned
+)
+
+// ChangeRequestComment is a comment or an approval of a change request
+type ChangeRequestComment struct {
+	Author string `json:"author" bson:"author"`
+	Text   string `json:"text,omitempty" bson:"text,omitempty"`
+	Time   string `json:"time,omitempty" bson:"time,omitempty"`
+}
+
 type PolicyAndRolePolicyCount struct {
 	PolicyCount     int64 `json:"policycount,omitempty"`
 	RolePolicyCount int64 `json:"rolePolicycount,omitempty"`
@@ -88,10 +175,36 @@ const (
 	FULL_RELOAD
 )
 
+var eventType = []string{
+	"INVALID",
+	"SERVICE_DELETE",
+	"SERVICE_ADD",
+	"POLICY_DELETE",
+	"POLICY_ADD",
+	"ROLEPOLICY_DELETE",
+	"ROLEPOLICY_ADD",
+	"FUNCTION_DELETE",
+	"FUNCTION_ADD",
+	"SYNC_RELOAD",
+	"FULL_RELOAD",
+}
+
+// String returns the name of the EventType
+func (t EventType) String() string {
+	if int(t) < len(eventType) {
+		return eventType[t]
+	}
+	return "INVALID"
+}
+
 type StoreChangeEvent struct {
 	Type EventType
 	// Event ID
 	ID int64
+	// Store revision of the change, for stores which have revisions
+	Revision int64
+	// Token to resume a watch after the change, for stores which have resume tokens
+	ResumeToken []byte
 	// Event content.
 	// In case of a delete event, the content is the identity of the deleted item, such as the application name;
 	// in case of put events, the content is the value of the newly created item, like an application
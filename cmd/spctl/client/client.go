@@ -23,6 +23,9 @@ type Client struct {
 }
 
 func setAuthorizationHeader(req *http.Request, token string) {
+	if len(token) == 0 {
+		return
+	}
 	req.Header.Set("Authorization", "Bearer "+token)
 
 }
@@ -127,7 +130,11 @@ func (c *Client) Get(paths []string, params url.Values, token string) ([]byte, e
 }
 
 func (c *Client) post(u *url.URL, paths []string, payload io.Reader, token string) (string, error) {
-	req, err := http.NewRequest("POST", u.String(), payload)
+	return c.send("POST", u, payload, token)
+}
+
+func (c *Client) send(method string, u *url.URL, payload io.Reader, token string) (string, error) {
+	req, err := http.NewRequest(method, u.String(), payload)
 	if err != nil {
 		return "", err
 	}
@@ -173,6 +180,14 @@ func (c *Client) Post(paths []string, payload io.Reader, token string) (string,
 	return c.post(u, paths, payload, token)
 }
 
+func (c *Client) Put(paths []string, payload io.Reader, token string) (string, error) {
+	u, err := c.pmsURL(paths)
+	if err != nil {
+		return "", err
+	}
+	return c.send("PUT", u, payload, token)
+}
+
 func getURL(baseURL string, paths []string) (*url.URL, error) {
 	u, err := url.Parse(baseURL)
 	if err != nil {
@@ -0,0 +1,132 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/teramoby/speedle-plus/cmd/spctl/client"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	changeMessage string
+	changeStatus  string
+)
+
+var (
+	changesExample = `
+		# List the change requests of service "foo" under review
+		spctl changes list foo --status=review
+
+		# Create a change request of service "foo", the JSON file contains title, description, addPolicies,
+		# deletePolicies, addRolePolicies and deleteRolePolicies
+		spctl changes create foo --json-file ./changes.json
+
+		# Show the policies and role policies a change request adds or removes
+		spctl changes diff foo 0a1b2c3d
+
+		# Submit a change request for review, approve it as another user, then publish it
+		spctl changes submit foo 0a1b2c3d
+		spctl changes approve foo 0a1b2c3d --message "looks good"
+		spctl changes publish foo 0a1b2c3d
+
+		# Send a change request back to its author
+		spctl changes reject foo 0a1b2c3d --message "the role is too broad"`
+)
+
+func NewChangesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "changes (list | get | create | update | diff | comment | submit | approve | reject | close | publish) SERVICE [ID] [--json-file JSON_FILENAME] [--message TEXT] [--status STATUS]",
+		Short:   "Draft, review and publish changes of policies",
+		Example: changesExample,
+		Run:     changesCommandFunc,
+	}
+	cmd.Flags().StringVarP(&jsonFileName, "json-file", "f", "", "file that contains the change request in json format")
+	cmd.Flags().StringVarP(&changeMessage, "message", "m", "", "comment of the action")
+	cmd.Flags().StringVar(&changeStatus, "status", "", "only list change requests with the status, could be 'draft', 'review', 'published' or 'closed'")
+	return cmd
+}
+
+func changesCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) < 2 {
+		cmd.Help()
+		return
+	}
+	action, service := strings.ToLower(args[0]), args[1]
+	var id string
+	if action != "list" && action != "create" {
+		if len(args) != 3 {
+			cmd.Help()
+			return
+		}
+		id = args[2]
+	}
+
+	hc, err := httpClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
+	}
+	cli := &client.Client{
+		PMSEndpoint: globalFlags.PMSEndpoint,
+		HTTPClient:  hc,
+	}
+
+	paths := []string{"service", service, "change-request", id}
+	var res []byte
+	switch action {
+	case "list":
+		var params url.Values
+		if len(changeStatus) > 0 {
+			params = url.Values{"status": []string{changeStatus}}
+		}
+		res, err = cli.Get(paths, params, globalFlags.Token)
+	case "get":
+		res, err = cli.Get(paths, nil, globalFlags.Token)
+	case "diff":
+		res, err = cli.Get(append(paths, "diff"), nil, globalFlags.Token)
+	case "create", "update":
+		if jsonFileName == "" {
+			cmd.Help()
+			return
+		}
+		var buf []byte
+		if buf, err = ioutil.ReadFile(jsonFileName); err == nil {
+			var body string
+			if action == "create" {
+				body, err = cli.Post(paths, bytes.NewBuffer(buf), globalFlags.Token)
+			} else {
+				body, err = cli.Put(paths, bytes.NewBuffer(buf), globalFlags.Token)
+			}
+			res = []byte(body)
+		}
+	case "comment", "submit", "approve", "reject", "close", "publish":
+		payload, _ := json.Marshal(map[string]string{"text": changeMessage})
+		var body string
+		body, err = cli.Post(append(paths, action), bytes.NewBuffer(payload), globalFlags.Token)
+		res = []byte(body)
+	default:
+		cmd.Help()
+		return
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	var output bytes.Buffer
+	if json.Indent(&output, res, "", strings.Repeat(" ", 4)) != nil {
+		fmt.Println(string(res))
+		return
+	}
+	fmt.Println(output.String())
+}
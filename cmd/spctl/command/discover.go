@@ -21,6 +21,9 @@ var (
 	last                                       bool
 	force                                      bool
 	principalType, principalName, principalIDD string
+	generalize                                 bool
+	resourceThreshold                          int
+	top                                        int
 )
 
 var (
@@ -47,12 +50,18 @@ var (
         spctl discover policy  --service-name="foo"
 
         # Generate JSON based policy definition, only for discover requests triggered by principal which has name 'Jon'
-        spctl discover policy --principal-name="Jon" --service-name="foo"`
+        spctl discover policy --principal-name="Jon" --service-name="foo"
+
+        # Generate generalized policies, sibling resources are collapsed into resource expressions and users with the same access share a role
+        spctl discover policy --service-name="foo" --generalize --resource-threshold=5
+
+        # List the statistics of the requests of service "foo", with its top 5 principals, resources and actions
+        spctl discover stats --service-name="foo" --top=5`
 )
 
 func NewDiscoverCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "discover (request/policy/reset  | --service-name=NAME | --last | --force | --principal-name=USERNAME)",
+		Use:     "discover (request/policy/stats/reset  | --service-name=NAME | --last | --force | --principal-name=USERNAME)",
 		Short:   "discover request or policy for services ",
 		Example: discoverExample,
 		Run:     discoverCommandFunc,
@@ -64,6 +73,9 @@ func NewDiscoverCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&principalType, "principal-type", "", "", "principal type, could be 'user', 'group','entity'")
 	cmd.Flags().StringVarP(&principalName, "principal-name", "", "", "principal name")
 	cmd.Flags().StringVarP(&principalIDD, "principal-IDD", "", "", "principal Identity Domain")
+	cmd.Flags().BoolVarP(&generalize, "generalize", "", false, "generalize the discovered policies")
+	cmd.Flags().IntVarP(&resourceThreshold, "resource-threshold", "", 0, "number of sibling resources collapsed into a resource expression when policies are generalized, 3 by default")
+	cmd.Flags().IntVarP(&top, "top", "", 0, "number of top principals, resources and actions of the statistics, 10 by default")
 	return cmd
 }
 
@@ -86,7 +98,7 @@ func discoverCommandFunc(cmd *cobra.Command, args []string) {
 			if force {
 				v := url.Values{}
 				v.Add("last", "true")
-				res, err = cli.Get([]string{"discover-request", serviceName}, v, "")
+				res, err = cli.Get([]string{"discover-request", serviceName}, v, globalFlags.Token)
 				if err == nil {
 					var revision int64
 					var response pmsrest.GetDiscoverRequestsResponse
@@ -100,7 +112,7 @@ func discoverCommandFunc(cmd *cobra.Command, args []string) {
 					for {
 						v = url.Values{}
 						v.Add("revision", strconv.FormatInt(revision, 10))
-						res, err = cli.Get([]string{"discover-request", serviceName}, v, "")
+						res, err = cli.Get([]string{"discover-request", serviceName}, v, globalFlags.Token)
 						if err == nil {
 							if json.Unmarshal(res, &response) == nil {
 								revision = response.Revision
@@ -118,7 +130,7 @@ func discoverCommandFunc(cmd *cobra.Command, args []string) {
 			} else {
 				v := url.Values{}
 				v.Add("last", "true")
-				res, err = cli.Get([]string{"discover-request", serviceName}, v, "")
+				res, err = cli.Get([]string{"discover-request", serviceName}, v, globalFlags.Token)
 				if err == nil {
 					var response pmsrest.GetDiscoverRequestsResponse
 					if json.Unmarshal(res, &response) == nil {
@@ -131,7 +143,7 @@ func discoverCommandFunc(cmd *cobra.Command, args []string) {
 			}
 		} else {
 			// spxctl discover request --all [--service-name="foo"]
-			res, err = cli.Get([]string{"discover-request", serviceName}, nil, "")
+			res, err = cli.Get([]string{"discover-request", serviceName}, nil, globalFlags.Token)
 			if err == nil {
 				var response pmsrest.GetDiscoverRequestsResponse
 				err = json.Unmarshal(res, &response)
@@ -156,7 +168,13 @@ func discoverCommandFunc(cmd *cobra.Command, args []string) {
 			if len(principalIDD) > 0 {
 				v.Add("principalIDD", principalIDD)
 			}
-			res, err = cli.Get([]string{"discover-policy", serviceName}, v, "")
+			if generalize {
+				v.Add("generalize", "true")
+				if resourceThreshold != 0 {
+					v.Add("resourceThreshold", strconv.Itoa(resourceThreshold))
+				}
+			}
+			res, err = cli.Get([]string{"discover-policy", serviceName}, v, globalFlags.Token)
 			if err == nil {
 				var response pmsrest.GetDiscoverPoliciesResponse
 				err = json.Unmarshal(res, &response)
@@ -175,15 +193,33 @@ func discoverCommandFunc(cmd *cobra.Command, args []string) {
 			}
 		}
 
+	case "stats":
+		v := url.Values{}
+		if top != 0 {
+			v.Add("top", strconv.Itoa(top))
+		}
+		path := []string{"discover-statistics"}
+		if serviceName != "" {
+			path = append(path, serviceName)
+		}
+		res, err = cli.Get(path, v, globalFlags.Token)
+		if err == nil {
+			var response pmsrest.GetDiscoverStatisticsResponse
+			if err = json.Unmarshal(res, &response); err == nil {
+				output, _ = json.MarshalIndent(response.Statistics, "", strings.Repeat(" ", 4))
+				fmt.Println(string(output))
+			}
+		}
+
 	case "reset":
 		if serviceName == "" {
 			// spxctl reset service --service-name="foo"
-			err = cli.Delete([]string{"discover-request"}, "")
+			err = cli.Delete([]string{"discover-request"}, globalFlags.Token)
 			if err == nil {
 				fmt.Printf("All requests are deleted.\n")
 			}
 		} else {
-			err = cli.Delete([]string{"discover-request", serviceName}, "")
+			err = cli.Delete([]string{"discover-request", serviceName}, globalFlags.Token)
 			if err == nil {
 				fmt.Printf("Requests for service(%s) are deleted.\n", serviceName)
 			}
@@ -6,6 +6,7 @@ package command
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
 
@@ -19,6 +20,8 @@ import (
 var (
 	all         bool
 	serviceName string
+	owned       bool
+	owner       string
 )
 
 var (
@@ -26,6 +29,12 @@ var (
 		# List all services
 		spctl get service --all 
 
+		# List all services owned by the caller
+		spctl get service --all --owned
+
+		# List all services owned by group "team-a"
+		spctl get service --all --owner=group:team-a
+
 		# List services "foo"
 		spctl get service foo
 		
@@ -52,6 +61,8 @@ func NewGetCommand() *cobra.Command {
 
 	cmd.Flags().BoolVarP(&all, "all", "a", false, "Get all elements")
 	cmd.Flags().StringVar(&serviceName, "service-name", "", "Service name")
+	cmd.Flags().BoolVar(&owned, "owned", false, "Get only services owned by the caller")
+	cmd.Flags().StringVar(&owner, "owner", "", "Get only services owned by the principal, like user:alice")
 	return cmd
 }
 
@@ -76,7 +87,14 @@ func getCommandFunc(cmd *cobra.Command, args []string) {
 	switch strings.ToLower(args[0]) {
 	case "service":
 		if all {
-			res, err = cli.Get([]string{"service"}, nil, "")
+			params := url.Values{}
+			if owned {
+				params.Set("owned", "true")
+			}
+			if len(owner) > 0 {
+				params.Set("owner", owner)
+			}
+			res, err = cli.Get([]string{"service"}, params, globalFlags.Token)
 			if err == nil {
 				services := []pms.Service{}
 				if json.Unmarshal(res, &services) == nil {
@@ -90,7 +108,7 @@ func getCommandFunc(cmd *cobra.Command, args []string) {
 			}
 			for _, name := range args[1:] {
 				service := pms.Service{}
-				res, err = cli.Get([]string{"service", name}, nil, "")
+				res, err = cli.Get([]string{"service", name}, nil, globalFlags.Token)
 				if err != nil {
 					break
 				}
@@ -113,7 +131,7 @@ func getCommandFunc(cmd *cobra.Command, args []string) {
 			kind = "role-policy"
 		}
 		if all {
-			res, err = cli.Get([]string{"service", serviceName, kind}, nil, "")
+			res, err = cli.Get([]string{"service", serviceName, kind}, nil, globalFlags.Token)
 
 			if err == nil {
 				var policies interface{}
@@ -139,7 +157,7 @@ func getCommandFunc(cmd *cobra.Command, args []string) {
 				} else {
 					policy = pms.RolePolicy{}
 				}
-				res, err = cli.Get([]string{"service", serviceName, kind, name}, nil, "")
+				res, err = cli.Get([]string{"service", serviceName, kind, name}, nil, globalFlags.Token)
 
 				if err != nil {
 					break
@@ -153,7 +171,7 @@ func getCommandFunc(cmd *cobra.Command, args []string) {
 		}
 	case "function":
 		if all {
-			res, err = cli.Get([]string{"function"}, nil, "")
+			res, err = cli.Get([]string{"function"}, nil, globalFlags.Token)
 			if err == nil {
 				functions := []pms.Function{}
 				if json.Unmarshal(res, &functions) == nil {
@@ -167,7 +185,7 @@ func getCommandFunc(cmd *cobra.Command, args []string) {
 			}
 			for _, name := range args[1:] {
 				function := pms.Function{}
-				res, err = cli.Get([]string{"function", name}, nil, "")
+				res, err = cli.Get([]string{"function", name}, nil, globalFlags.Token)
 				if err != nil {
 					break
 				}
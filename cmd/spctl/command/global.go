@@ -25,6 +25,7 @@ type GlobalFlags struct {
 	KeyFile            string
 	CAFile             string
 	InsecureSkipVerify bool
+	Token              string
 }
 
 const (
@@ -0,0 +1,110 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/teramoby/speedle-plus/cmd/spctl/client"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	ownersExample = `
+		# List the owners of service "foo"
+		spctl owners get foo
+
+		# Add user "alice" and group "team-a" to the owners of service "foo"
+		spctl owners add foo user:alice group:team-a
+
+		# Remove user "alice" from the owners of service "foo"
+		spctl owners remove foo user:alice`
+)
+
+type ownersBody struct {
+	Owners []string `json:"owners"`
+}
+
+func NewOwnersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "owners (get | add | remove) SERVICE [PRINCIPAL...]",
+		Short:   "Manage the owners of a service",
+		Example: ownersExample,
+		Run:     ownersCommandFunc,
+	}
+	return cmd
+}
+
+func ownersCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) < 2 {
+		cmd.Help()
+		return
+	}
+
+	hc, err := httpClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
+	}
+	cli := &client.Client{
+		PMSEndpoint: globalFlags.PMSEndpoint,
+		HTTPClient:  hc,
+	}
+
+	service, principals := args[1], args[2:]
+	var output []byte
+	switch strings.ToLower(args[0]) {
+	case "get":
+		var res []byte
+		res, err = cli.Get([]string{"service", service, "owners"}, nil, globalFlags.Token)
+		if err == nil {
+			var body ownersBody
+			if json.Unmarshal(res, &body) == nil {
+				output, _ = json.MarshalIndent(&body, "", strings.Repeat(" ", 4))
+			}
+		}
+	case "add":
+		if len(principals) == 0 {
+			cmd.Help()
+			return
+		}
+		payload, _ := json.Marshal(&ownersBody{Owners: principals})
+		var res string
+		res, err = cli.Post([]string{"service", service, "owners"}, bytes.NewBuffer(payload), globalFlags.Token)
+		if err == nil {
+			var body ownersBody
+			if json.Unmarshal([]byte(res), &body) == nil {
+				output, _ = json.MarshalIndent(&body, "", strings.Repeat(" ", 4))
+			}
+		}
+	case "remove":
+		if len(principals) == 0 {
+			cmd.Help()
+			return
+		}
+		for _, principal := range principals {
+			err = cli.Delete([]string{"service", service, "owners", principal}, globalFlags.Token)
+			if err != nil {
+				break
+			}
+		}
+		if err == nil {
+			output = []byte(fmt.Sprintf("%s removed from the owners of service %s.", strings.Join(principals, " "), service))
+		}
+	default:
+		cmd.Help()
+		return
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println(string(output))
+}
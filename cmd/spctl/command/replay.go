@@ -0,0 +1,214 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package command
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/decisionlog"
+	"github.com/teramoby/speedle-plus/pkg/eval"
+	_ "github.com/teramoby/speedle-plus/pkg/store/file"
+	"github.com/teramoby/speedle-plus/pkg/subjectutils"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replayLogFile   string
+	replayStoreFile string
+	replayStrict    bool
+)
+
+var (
+	replayExample = `
+		# Replay the recorded decisions against the policies of a file, and report the decisions which change
+		spctl replay --log decisions.jsonl --store new-policies.json
+
+		# Also report the decisions whose matched policies change
+		spctl replay --log decisions.jsonl --store new-policies.json --strict`
+)
+
+// decider makes the decision record of a request
+type decider interface {
+	Decide(ctx adsapi.RequestContext) *decisionlog.Record
+}
+
+// replayStats counts the replayed decisions
+type replayStats struct {
+	Replayed int
+	Changed  int
+}
+
+func NewReplayCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "replay --log DECISION_LOG_FILE --store POLICY_FILE [--strict]",
+		Short:   "Replay recorded decisions against policies and report the changed decisions",
+		Long:    "Replay the requests of a decision log file against the policies of a policy store file with an embedded evaluator, and report the decisions which differ from the recorded ones. It exits with 1 if any decision differs, so that it can be used in CI before policies are migrated.",
+		Example: replayExample,
+		Run:     replayCommandFunc,
+	}
+	cmd.Flags().StringVar(&replayLogFile, "log", "", "decision log file with a JSON record per line")
+	cmd.Flags().StringVar(&replayStoreFile, "store", "", "policy store file the decisions are replayed against")
+	cmd.Flags().BoolVar(&replayStrict, "strict", false, "also report decisions whose matched policies or role policies change")
+	return cmd
+}
+
+func replayCommandFunc(cmd *cobra.Command, args []string) {
+	if replayLogFile == "" || replayStoreFile == "" {
+		cmd.Help()
+		return
+	}
+	stats, err := replayFiles(replayLogFile, replayStoreFile, os.Stdout, replayStrict)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if stats.Changed > 0 {
+		os.Exit(1)
+	}
+}
+
+func replayFiles(logFile, storeFile string, w io.Writer, strict bool) (*replayStats, error) {
+	f, err := os.Open(logFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// the evaluator logs the loading of policies, which would be mixed with the report
+	level := log.GetLevel()
+	log.SetLevel(log.WarnLevel)
+	defer log.SetLevel(level)
+	evaluator, err := eval.NewFromFile(storeFile, false)
+	if err != nil {
+		return nil, err
+	}
+	d, ok := evaluator.(decider)
+	if !ok {
+		return nil, fmt.Errorf("evaluator can't replay decisions")
+	}
+	return replay(d, f, w, strict)
+}
+
+// replay replays the recorded decisions read from r, and writes the changed ones and a summary to w
+func replay(d decider, r io.Reader, w io.Writer, strict bool) (*replayStats, error) {
+	stats := &replayStats{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var recorded decisionlog.Record
+		if err := json.Unmarshal(scanner.Bytes(), &recorded); err != nil {
+			return nil, fmt.Errorf("invalid decision record at line %d: %v", line, err)
+		}
+		replayed := d.Decide(replayContext(&recorded))
+		stats.Replayed++
+
+		policies := diffIDs(recorded.Policies, replayed.Policies)
+		rolePolicies := diffIDs(recorded.RolePolicies, replayed.RolePolicies)
+		decisionChanged := recorded.Decision != replayed.Decision
+		if !decisionChanged && (!strict || policies == "" && rolePolicies == "") {
+			continue
+		}
+		stats.Changed++
+		if decisionChanged {
+			fmt.Fprintf(w, "Decision %s of service %q changed: %s -> %s\n", recorded.DecisionID, recorded.Service, recorded.Decision, replayed.Decision)
+		} else {
+			fmt.Fprintf(w, "Decision %s of service %q matched other policies: %s\n", recorded.DecisionID, recorded.Service, recorded.Decision)
+		}
+		fmt.Fprintf(w, "    subject: %s, resource: %s, action: %s\n", formatSubject(recorded.Subject), recorded.Resource, recorded.Action)
+		if recorded.Reason != replayed.Reason {
+			fmt.Fprintf(w, "    reason: %s -> %s\n", recorded.Reason, replayed.Reason)
+		}
+		if replayed.Error != "" {
+			fmt.Fprintf(w, "    error: %s\n", replayed.Error)
+		}
+		if policies != "" {
+			fmt.Fprintf(w, "    policies: %s\n", policies)
+		}
+		if rolePolicies != "" {
+			fmt.Fprintf(w, "    role policies: %s\n", rolePolicies)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(w, "Replayed %d decisions, %d changed.\n", stats.Replayed, stats.Changed)
+	return stats, nil
+}
+
+// replayContext recreates the request of a recorded decision. The subject is the asserted one, so the
+// token isn't asserted again, and the built-in time attributes are the ones of the recorded decision.
+// Redacted attributes are replayed with their redacted values.
+func replayContext(record *decisionlog.Record) adsapi.RequestContext {
+	ctx := adsapi.RequestContext{
+		ServiceName: record.Service,
+		Resource:    record.Resource,
+		Action:      record.Action,
+		Attributes:  map[string]interface{}{},
+	}
+	if record.Subject != nil {
+		ctx.Subject = &adsapi.Subject{Principals: record.Subject.Principals}
+	}
+	if !record.Timestamp.IsZero() {
+		t := record.Timestamp
+		year, month, day := t.Date()
+		ctx.Attributes[adsapi.BuiltIn_Attr_RequestTime] = t.Unix()
+		ctx.Attributes[adsapi.BuiltIn_Attr_RequestYear] = year
+		ctx.Attributes[adsapi.BuiltIn_Attr_RequestMonth] = int(month)
+		ctx.Attributes[adsapi.BuiltIn_Attr_RequestDay] = day
+		ctx.Attributes[adsapi.BuiltIn_Attr_RequestWeekday] = t.Weekday().String()
+		ctx.Attributes[adsapi.BuiltIn_Attr_RequestHour] = t.Hour()
+	}
+	for name, value := range record.Attributes {
+		ctx.Attributes[name] = value
+	}
+	return ctx
+}
+
+// diffIDs returns the removed IDs prefixed with - and the added IDs prefixed with +, or an empty string
+// if the IDs are the same
+func diffIDs(recorded, replayed []string) string {
+	var diffs []string
+	for _, id := range recorded {
+		if !containsID(replayed, id) {
+			diffs = append(diffs, "-"+id)
+		}
+	}
+	for _, id := range replayed {
+		if !containsID(recorded, id) {
+			diffs = append(diffs, "+"+id)
+		}
+	}
+	return strings.Join(diffs, " ")
+}
+
+func containsID(ids []string, id string) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
+
+func formatSubject(subject *decisionlog.Subject) string {
+	if subject == nil || len(subject.Principals) == 0 {
+		return "anonymous"
+	}
+	principals := make([]string, 0, len(subject.Principals))
+	for _, principal := range subject.Principals {
+		principals = append(principals, subjectutils.EncodePrincipal(principal))
+	}
+	return strings.Join(principals, " ")
+}
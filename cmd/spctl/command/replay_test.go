@@ -0,0 +1,98 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/decisionlog"
+)
+
+func TestReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// alice keeps her access through a new role policy, bob loses his, and carol's weekend access
+	// depends on the time of the recorded decision
+	ps := pms.PolicyStore{Services: []*pms.Service{{
+		Name: "crm",
+		Policies: []*pms.Policy{
+			{ID: "p2", Effect: pms.Grant, Principals: [][]string{{"role:reader"}},
+				Permissions: []*pms.Permission{{Resource: "/report", Actions: []string{"read"}}}},
+			{ID: "p3", Effect: pms.Grant, Principals: [][]string{{"user:carol"}}, Condition: "request_weekday == 'Saturday'",
+				Permissions: []*pms.Permission{{Resource: "/report", Actions: []string{"read"}}}},
+		},
+		RolePolicies: []*pms.RolePolicy{
+			{ID: "rp1", Effect: pms.Grant, Roles: []string{"reader"}, Principals: []string{"user:alice"}},
+		},
+	}}}
+	storeFile := filepath.Join(dir, "policies.json")
+	raw, _ := json.Marshal(&ps)
+	if err := ioutil.WriteFile(storeFile, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	saturday := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	newRecord := func(id, user, decision string, policies ...string) *decisionlog.Record {
+		return &decisionlog.Record{
+			DecisionID: id,
+			Timestamp:  saturday,
+			Service:    "crm",
+			Subject:    &decisionlog.Subject{Principals: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_USER, Name: user}}},
+			Resource:   "/report",
+			Action:     "read",
+			Decision:   decision,
+			Policies:   policies,
+		}
+	}
+	var logBuf bytes.Buffer
+	encoder := json.NewEncoder(&logBuf)
+	encoder.Encode(newRecord("d1", "alice", decisionlog.DecisionAllow, "p1"))
+	encoder.Encode(newRecord("d2", "bob", decisionlog.DecisionAllow, "p1"))
+	encoder.Encode(newRecord("d3", "carol", decisionlog.DecisionAllow, "p3"))
+	logFile := filepath.Join(dir, "decisions.jsonl")
+	if err := ioutil.WriteFile(logFile, logBuf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	stats, err := replayFiles(logFile, storeFile, &out, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := out.String()
+	if stats.Replayed != 3 || stats.Changed != 1 || !strings.Contains(report, "Decision d2 of service \"crm\" changed: allow -> deny") ||
+		!strings.Contains(report, "policies: -p1\n") || strings.Contains(report, "d1") || strings.Contains(report, "d3") {
+		t.Errorf("only the decision of bob should change, stats %+v, report:\n%s", stats, report)
+	}
+
+	out.Reset()
+	if stats, err = replayFiles(logFile, storeFile, &out, true); err != nil {
+		t.Fatal(err)
+	}
+	report = out.String()
+	if stats.Changed != 2 || !strings.Contains(report, "Decision d1 of service \"crm\" matched other policies: allow") ||
+		!strings.Contains(report, "policies: -p1 +p2\n") || !strings.Contains(report, "role policies: +rp1\n") {
+		t.Errorf("the matched policies of alice should change, stats %+v, report:\n%s", stats, report)
+	}
+
+	if err := ioutil.WriteFile(logFile, []byte("{\"decisionId\": \"d1\"}\nnot json\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := replayFiles(logFile, storeFile, &out, false); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("invalid record should be reported with its line, err: %v", err)
+	}
+}
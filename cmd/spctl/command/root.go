@@ -38,6 +38,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&globalFlags.KeyFile, "key", "", "identify secure client using this TLS key file")
 	rootCmd.PersistentFlags().StringVar(&globalFlags.CAFile, "cacert", "", "verify certificates of TLS-enabled secure servers using this CA bundle")
 	rootCmd.PersistentFlags().BoolVar(&globalFlags.InsecureSkipVerify, "skipverify", false, "control whether a client verifies the server's certificate chain and host name or not")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.Token, "token", "", "bearer token identifying the client when authorization of policy management is enabled")
 
 	args, _ := readConfigFile()
 	for name, val := range args {
@@ -50,6 +51,10 @@ func init() {
 		NewCreateCommand(),
 		NewConfigCommand(),
 		NewDiscoverCommand(),
+		NewOwnersCommand(),
+		NewChangesCommand(),
+		NewReplayCommand(),
+		NewTestCommand(),
 		NewVersionCommand(),
 	)
 }
@@ -0,0 +1,66 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/teramoby/speedle-plus/testutil/policytest"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testExample = `
+		# Run the cases of a test file against the policy file it refers to
+		spctl test expenses_test.json
+
+		# Run several test files
+		spctl test policies/*_test.json`
+)
+
+func NewTestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "test TEST_FILE...",
+		Short:   "Run unit tests of policies",
+		Long:    "Run the cases of policy test files against their .json or .spdl policy files with an in-process evaluator. Each case is a request with its expected decision, granted roles or granted permissions. Failed cases are printed with the diagnosis of their requests, and the policies no request matched are listed. It exits with 1 if any case fails.",
+		Example: testExample,
+		Run:     testCommandFunc,
+	}
+	return cmd
+}
+
+func testCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Help()
+		return
+	}
+	if failed := runPolicyTests(args, os.Stdout); failed {
+		os.Exit(1)
+	}
+}
+
+// runPolicyTests runs the test files and writes their reports, it returns true if any case fails
+func runPolicyTests(testFiles []string, w io.Writer) bool {
+	// the evaluator logs the loading of policies, which would be mixed with the reports
+	level := log.GetLevel()
+	log.SetLevel(log.WarnLevel)
+	defer log.SetLevel(level)
+
+	failed := false
+	for _, testFile := range testFiles {
+		report, err := policytest.RunFile(testFile)
+		if err != nil {
+			fmt.Fprintf(w, "%s: %v\n", testFile, err)
+			failed = true
+			continue
+		}
+		report.Write(w)
+		failed = failed || report.Failed > 0
+	}
+	return failed
+}
@@ -10,22 +10,26 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 
-	adsapi "github.com/teramoby/speedle-plus/api/ads"
-	"github.com/teramoby/speedle-plus/pkg/assertion"
 	"github.com/teramoby/speedle-plus/pkg/cfg"
 	"github.com/teramoby/speedle-plus/pkg/cmd/flags"
+	"github.com/teramoby/speedle-plus/pkg/decisionlog"
 	"github.com/teramoby/speedle-plus/pkg/errors"
 	"github.com/teramoby/speedle-plus/pkg/eval"
+	"github.com/teramoby/speedle-plus/pkg/health"
 	"github.com/teramoby/speedle-plus/pkg/logging"
 	"github.com/teramoby/speedle-plus/pkg/store"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
 	"github.com/teramoby/speedle-plus/pkg/svcs/adsgrpc"
 	"github.com/teramoby/speedle-plus/pkg/svcs/adsgrpc/pb"
 	"github.com/teramoby/speedle-plus/pkg/svcs/adsrest"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
 
 	log "github.com/sirupsen/logrus"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -70,17 +74,43 @@ func main() {
 		log.Error("No any audit log configurations for authorization service.\n")
 	}
 
+	// Initialize the tracing
+	tracer, err := tracing.Init(conf.TracingConfig)
+	if err != nil {
+		log.Fatalf("Authz_check failed to initialize the tracing, err: %v.", err)
+	}
+
+	// Initialize the decision log
+	decisionLogger, err := decisionlog.Init(conf.DecisionLogConfig)
+	if err != nil {
+		log.Fatalf("Authz_check failed to initialize the decision log, err: %v.", err)
+	}
+
+	// Retention of discover requests, which are saved by the ADS
+	if err := store.SetDiscoverConfig(conf.DiscoverConfig); err != nil {
+		log.Fatal(err)
+	}
+
 	evaluator, err := newEvaluator(conf)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	httpServer, err := newHTTPServer(&params, evaluator)
+	var clientAuthz *svcs.ClientAuthz
+	if enableAuthz, _ := strconv.ParseBool(params.EnableAuthz.Value); enableAuthz {
+		log.Info("Authorization of authorization service clients is enabled.")
+		clientAuthz, err = svcs.NewClientAuthz(conf.ADSAuthzConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	httpServer, err := newHTTPServer(&params, conf.ServerConfig, evaluator, clientAuthz)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	grpcServer, err := newGRPCServer(evaluator)
+	grpcServer, err := newGRPCServer(&params, conf.ServerConfig, evaluator, clientAuthz)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -119,6 +149,14 @@ func main() {
 		log.Info("Stopping GRPC Server.")
 		grpcServer.Stop()
 	}
+	if tracer != nil {
+		log.Info("Flushing spans...")
+		tracer.Shutdown()
+	}
+	if decisionLogger != nil {
+		log.Info("Flushing decision log...")
+		decisionLogger.Shutdown()
+	}
 
 	if err != nil {
 		os.Exit(1)
@@ -126,50 +164,33 @@ func main() {
 }
 
 func newEvaluator(conf *cfg.Config) (eval.InternalEvaluator, error) {
-	evaluator, err := eval.NewFromConfig(conf)
-	if err != nil {
-		return nil, err
-	}
-
-	log.Info("Loading asserters.")
-	as, errLoadAsserter := assertion.NewAsserter(conf.AsserterWebhookConfig, nil)
-	if errLoadAsserter != nil {
-		log.Warningf("load asserter error: %v", errLoadAsserter)
-	} else {
-		f := func(ctx *adsapi.RequestContext) error {
-			if ctx.Subject != nil &&
-				len(ctx.Subject.TokenType) != 0 &&
-				len(ctx.Subject.Token) != 0 {
-				tokenType := ctx.Subject.TokenType
-				token := ctx.Subject.Token
-				log.Debugf("Asserting token %s with token type %s.", token, tokenType)
-				s, err := as.AssertToken(token, tokenType, "", nil)
-				if err == nil {
-					for _, p := range s.Principals {
-						ctx.Subject.Principals = append(ctx.Subject.Principals, p)
-					}
-				} else {
-					log.Errorf("Failed to assert due to error %v.", err)
-					return err
-				}
-			}
-			return nil
-		}
-		evaluator.SetAsserterFunc(f)
-	}
-
-	return evaluator, nil
+	// The token asserter is loaded by the evaluator, so that embedded evaluators behave the same
+	return eval.NewFromConfig(conf)
 }
 
-func newGRPCServer(evaluator eval.InternalEvaluator) (*grpc.Server, error) {
+func newGRPCServer(params *flags.Parameters, serverConf *cfg.ServerConfig, evaluator eval.InternalEvaluator, clientAuthz *svcs.ClientAuthz) (*grpc.Server, error) {
 
-	serviceImpl, err := adsgrpc.NewGRPCService(evaluator)
+	serviceImpl, err := adsgrpc.NewGRPCServiceWithClientAuthz(evaluator, serverConf, clientAuthz)
 	if err != nil {
 		return nil, err
 	}
 
-	server := grpc.NewServer()
+	var opts []grpc.ServerOption
+	// Client certificates can only be verified over TLS
+	if (serverConf != nil && serverConf.ClientCertIdentity != nil) || clientAuthz != nil {
+		tlsConfig, err := params.NewGRPCTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		} else {
+			log.Warn("The gRPC server is insecure, client certificates are not available.")
+		}
+	}
+	server := grpc.NewServer(opts...)
 	pb.RegisterEvaluatorServer(server, serviceImpl)
+	health.RegisterGRPC(server, evaluator)
 	// Register reflection service on gRPC server.
 	reflection.Register(server)
 	return server, nil
@@ -186,8 +207,8 @@ func listenGRPCServer(server *grpc.Server) error {
 	return nil
 }
 
-func newHTTPServer(params *flags.Parameters, evaluator eval.InternalEvaluator) (*http.Server, error) {
-	routers, err := adsrest.NewRouter(evaluator)
+func newHTTPServer(params *flags.Parameters, serverConf *cfg.ServerConfig, evaluator eval.InternalEvaluator, clientAuthz *svcs.ClientAuthz) (*http.Server, error) {
+	routers, err := adsrest.NewRouterWithClientAuthz(evaluator, serverConf, clientAuthz)
 	if err != nil {
 		return nil, err
 	}
@@ -10,19 +10,24 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 
 	"github.com/teramoby/speedle-plus/api/pms"
 	"github.com/teramoby/speedle-plus/pkg/cmd/flags"
 	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/health"
 	"github.com/teramoby/speedle-plus/pkg/logging"
 	"github.com/teramoby/speedle-plus/pkg/store"
 	"github.com/teramoby/speedle-plus/pkg/svcs/pmsgrpc"
 	"github.com/teramoby/speedle-plus/pkg/svcs/pmsgrpc/pb"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsimpl"
 	"github.com/teramoby/speedle-plus/pkg/svcs/pmsrest"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
 
 	log "github.com/sirupsen/logrus"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -67,17 +72,40 @@ func main() {
 		log.Error("No any audit log configurations for Policy_mgmt.")
 	}
 
+	// Initialize the tracing
+	tracer, err := tracing.Init(conf.TracingConfig)
+	if err != nil {
+		log.Fatalf("Policy_mgmt failed to initialize the tracing, err: %v.", err)
+	}
+
 	ps, err := store.NewStore(conf.StoreConfig.StoreType, conf.StoreConfig.StoreProps)
 	if err != nil {
 		log.Fatal(err)
 	}
+	// Retention of discover requests, whose expired ones are filtered
+	if err := store.SetDiscoverConfig(conf.DiscoverConfig); err != nil {
+		log.Fatal(err)
+	}
+	// Observe the latency of the policy store in metrics
+	ps = store.NewInstrumentedStore(ps)
 
-	httpServer, err := newHTTPServer(&params, ps)
+	var authorizer *pmsimpl.Authorizer
+	if enableAuthz, _ := strconv.ParseBool(params.EnableAuthz.Value); enableAuthz {
+		log.Info("Authorization of policy management is enabled.")
+		authorizer, err = pmsimpl.NewAuthorizer(conf.PMSAuthzConfig, ps)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	changeRequests := pmsimpl.NewChangeRequests(conf.ChangeRequestConfig, ps)
+
+	httpServer, err := newHTTPServer(&params, ps, authorizer, changeRequests)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	grpcServer, err := newGRPCServer(ps)
+	grpcServer, err := newGRPCServer(&params, ps, authorizer, changeRequests)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -116,15 +144,37 @@ func main() {
 		log.Info("Stopping GRPC Server...")
 		grpcServer.Stop()
 	}
+	if tracer != nil {
+		log.Info("Flushing spans...")
+		tracer.Shutdown()
+	}
 
 	if err != nil {
 		os.Exit(1)
 	}
 }
 
-func newGRPCServer(ps pms.PolicyStoreManager) (*grpc.Server, error) {
-	server := grpc.NewServer()
-	pb.RegisterPolicyManagerServer(server, pmsgrpc.NewServiceImpl(ps))
+func newGRPCServer(params *flags.Parameters, ps pms.PolicyStoreManager, authorizer *pmsimpl.Authorizer, changeRequests *pmsimpl.ChangeRequests) (*grpc.Server, error) {
+	interceptors := []grpc.UnaryServerInterceptor{pmsgrpc.NewTracingInterceptor(), pmsgrpc.NewMetricsInterceptor()}
+	var opts []grpc.ServerOption
+	if authorizer != nil {
+		interceptors = append(interceptors, pmsgrpc.NewAuthzInterceptor(authorizer))
+		// Tokens and client certificates are only protected over TLS
+		tlsConfig, err := params.NewGRPCTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		} else {
+			log.Warn("The gRPC server is insecure, tokens are sent in clear text.")
+		}
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(interceptors...))
+	server := grpc.NewServer(opts...)
+	pb.RegisterPolicyManagerServer(server, pmsgrpc.NewServiceImplWithChangeRequests(ps, changeRequests))
+	pb.RegisterChangeRequestManagerServer(server, pmsgrpc.NewChangeRequestServiceImpl(changeRequests))
+	health.RegisterGRPC(server, &health.StoreChecker{Store: ps})
 	reflection.Register(server)
 	return server, nil
 }
@@ -141,8 +191,8 @@ func listenGRPCServer(server *grpc.Server) error {
 	return nil
 }
 
-func newHTTPServer(params *flags.Parameters, ps pms.PolicyStoreManager) (*http.Server, error) {
-	routers, err := pmsrest.NewRouter(ps)
+func newHTTPServer(params *flags.Parameters, ps pms.PolicyStoreManager, authorizer *pmsimpl.Authorizer, changeRequests *pmsimpl.ChangeRequests) (*http.Server, error) {
+	routers, err := pmsrest.NewRouterWithChangeRequests(ps, authorizer, changeRequests)
 	if err != nil {
 		log.Error("Fail to create handler...")
 		return nil, err
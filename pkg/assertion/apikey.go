@@ -0,0 +1,110 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+)
+
+const (
+	// APIKeyAsserterType asserts API keys with a static table
+	APIKeyAsserterType = "apikey"
+
+	// prefix of keys given as hex encoded SHA-256 hashes
+	apiKeyHashPrefix = "sha256:"
+)
+
+// APIKeySubject is the identity of an API key
+type APIKeySubject struct {
+	Principals []*adsapi.Principal    `json:"principals"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// APIKeyAsserterConfig API key asserter configuration. Keys are either the API keys, or
+// "sha256:" followed by the hex encoded SHA-256 hash of the API keys.
+type APIKeyAsserterConfig struct {
+	Keys map[string]*APIKeySubject `json:"keys,omitempty"`
+	File string                    `json:"file,omitempty"` //JSON file of keys
+}
+
+// APIKeyAsserter asserts API keys with a static table
+type APIKeyAsserter struct {
+	subjects map[string]*APIKeySubject // by hash of keys
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewAPIKeyAsserter creates an API key asserter
+func NewAPIKeyAsserter(conf *APIKeyAsserterConfig) (*APIKeyAsserter, error) {
+	if conf == nil {
+		return nil, fmt.Errorf("API key asserter configuration is nil")
+	}
+	keys := make(map[string]*APIKeySubject)
+	if len(conf.File) > 0 {
+		raw, err := ioutil.ReadFile(conf.File)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &keys); err != nil {
+			return nil, fmt.Errorf("invalid API key file %s: %v", conf.File, err)
+		}
+	}
+	for key, subject := range conf.Keys {
+		keys[key] = subject
+	}
+
+	a := APIKeyAsserter{subjects: make(map[string]*APIKeySubject, len(keys))}
+	for key, subject := range keys {
+		if subject == nil || len(subject.Principals) == 0 {
+			return nil, fmt.Errorf("API key has no principal")
+		}
+		hash := hashAPIKey(key)
+		if strings.HasPrefix(key, apiKeyHashPrefix) {
+			hash = strings.ToLower(key[len(apiKeyHashPrefix):])
+		}
+		a.subjects[hash] = subject
+	}
+	return &a, nil
+}
+
+// AssertToken returns the identity of an API key
+func (a *APIKeyAsserter) AssertToken(token string, idpType string, allowedIDD string, requestHeaders map[string]string) (*AssertResponse, error) {
+	subject, ok := a.subjects[hashAPIKey(token)]
+	if !ok {
+		return nil, fmt.Errorf("unknown API key")
+	}
+	ar := AssertResponse{Principals: subject.Principals, Attributes: subject.Attributes}
+	if len(allowedIDD) > 0 {
+		for _, p := range ar.Principals {
+			if len(p.IDD) > 0 && p.IDD != allowedIDD {
+				return nil, fmt.Errorf("identity domain %q is not allowed", p.IDD)
+			}
+		}
+	}
+	return copyResponse(&ar), nil
+}
+
+type apiKeyAsserterBuilder struct{}
+
+func (apiKeyAsserterBuilder) NewTokenAsserter(props map[string]interface{}) (TokenAsserter, error) {
+	var conf APIKeyAsserterConfig
+	if err := decodeProps(props, &conf); err != nil {
+		return nil, err
+	}
+	return NewAPIKeyAsserter(&conf)
+}
+
+func init() {
+	Register(APIKeyAsserterType, apiKeyAsserterBuilder{})
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -32,6 +33,7 @@ const (
 type AssertResponse struct {
 	Principals []*adsapi.Principal    `json:"principals,omitempty"`
 	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	ExpiresAt  int64                  `json:"expiresAt,omitempty"` //when the asserted subject expires, in seconds since epoch
 	ErrCode    int                    `json:"errCode"`
 	ErrMessage string                 `json:"errMessage,omitempty"`
 }
@@ -156,19 +158,19 @@ func (a *WebHookAsserter) AssertToken(token string, idpType string, allowedIDD s
 	resp, errResp := a.httpClient.Do(req)
 	if errResp != nil {
 		log.Errorf("Do error: %v", errResp)
-		return nil, errResp
+		return nil, errors.Wrap(errResp, errors.AsserterUnavailable, "failed to call the asserter")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		log.Errorf("assertion error, status code: %d", resp.StatusCode)
-		return nil, fmt.Errorf("asserter error, status code: %d", resp.StatusCode)
+		return nil, errors.Errorf(errors.AsserterUnavailable, "asserter error, status code: %d", resp.StatusCode)
 	}
 
 	raw, errRaw := ioutil.ReadAll(resp.Body)
 	if errRaw != nil {
 		log.Errorf("ReadAll error: %v", errRaw)
-		return nil, errRaw
+		return nil, errors.Wrap(errRaw, errors.AsserterUnavailable, "failed to read the asserter response")
 	}
 
 	var ar AssertResponse
@@ -176,7 +178,7 @@ func (a *WebHookAsserter) AssertToken(token string, idpType string, allowedIDD s
 	errJSON := json.Unmarshal(raw, &ar)
 	if errJSON != nil {
 		log.Errorf("Unmarshal error: %v", errJSON)
-		return nil, errJSON
+		return nil, errors.Wrap(errJSON, errors.AsserterUnavailable, "invalid asserter response")
 	}
 
 	// flag error if asserter indicates failure
@@ -189,3 +191,20 @@ func (a *WebHookAsserter) AssertToken(token string, idpType string, allowedIDD s
 
 	return &ar, nil
 }
+
+// WebhookAsserterType asserts tokens with an asserter webhook
+const WebhookAsserterType = "webhook"
+
+type webhookAsserterBuilder struct{}
+
+func (webhookAsserterBuilder) NewTokenAsserter(props map[string]interface{}) (TokenAsserter, error) {
+	var conf AsserterConfig
+	if err := decodeProps(props, &conf); err != nil {
+		return nil, err
+	}
+	return NewAsserter(&conf, nil)
+}
+
+func init() {
+	Register(WebhookAsserterType, webhookAsserterBuilder{})
+}
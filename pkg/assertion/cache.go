@@ -0,0 +1,142 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+)
+
+const (
+	defaultSubjectCacheMaxTTL     = 60
+	defaultSubjectCacheMaxEntries = 10000
+)
+
+// SubjectCacheConfig configures the cache of asserted subjects
+type SubjectCacheConfig struct {
+	Disabled   bool `json:"disabled,omitempty"`
+	MaxTTL     int  `json:"maxTTL,omitempty"`     //in seconds, 60 by default. Subjects are cached until the token expires, but no longer than this
+	MaxEntries int  `json:"maxEntries,omitempty"` //10000 by default
+}
+
+type subjectCacheEntry struct {
+	key       string
+	response  *AssertResponse
+	expiresAt time.Time
+}
+
+// subjectCache is a LRU cache of asserted subjects keyed by the hash of tokens
+type subjectCache struct {
+	maxTTL     time.Duration
+	maxEntries int
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	hits    int64
+	misses  int64
+}
+
+func newSubjectCache(conf *SubjectCacheConfig) *subjectCache {
+	if conf != nil && conf.Disabled {
+		return nil
+	}
+	c := subjectCache{
+		maxTTL:     defaultSubjectCacheMaxTTL * time.Second,
+		maxEntries: defaultSubjectCacheMaxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+	if conf != nil && conf.MaxTTL > 0 {
+		c.maxTTL = time.Duration(conf.MaxTTL) * time.Second
+	}
+	if conf != nil && conf.MaxEntries > 0 {
+		c.maxEntries = conf.MaxEntries
+	}
+	return &c
+}
+
+// subjectCacheKey hashes the token, so that tokens are not kept in memory
+func subjectCacheKey(token string, tokenType string, allowedIDD string) string {
+	h := sha256.New()
+	h.Write([]byte(tokenType))
+	h.Write([]byte{0})
+	h.Write([]byte(allowedIDD))
+	h.Write([]byte{0})
+	h.Write([]byte(token))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// copyResponse returns a copy of an asserted subject, which may be modified by the caller
+func copyResponse(ar *AssertResponse) *AssertResponse {
+	c := *ar
+	c.Principals = make([]*adsapi.Principal, len(ar.Principals))
+	for i, p := range ar.Principals {
+		principal := *p
+		c.Principals[i] = &principal
+	}
+	if ar.Attributes != nil {
+		c.Attributes = make(map[string]interface{}, len(ar.Attributes))
+		for k, v := range ar.Attributes {
+			c.Attributes[k] = v
+		}
+	}
+	return &c
+}
+
+func (c *subjectCache) get(key string) (*AssertResponse, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	entry := elem.Value.(*subjectCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		c.misses++
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	c.hits++
+	return copyResponse(entry.response), true
+}
+
+func (c *subjectCache) add(key string, ar *AssertResponse) {
+	expiresAt := time.Now().Add(c.maxTTL)
+	if ar.ExpiresAt > 0 {
+		if exp := time.Unix(ar.ExpiresAt, 0); exp.Before(expiresAt) {
+			expiresAt = exp
+		}
+	}
+	if !expiresAt.After(time.Now()) {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+	}
+	for c.lru.Len() >= c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*subjectCacheEntry).key)
+	}
+	c.entries[key] = c.lru.PushFront(&subjectCacheEntry{key: key, response: copyResponse(ar), expiresAt: expiresAt})
+}
+
+func (c *subjectCache) stats() (entries int, hits int64, misses int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lru.Len(), c.hits, c.misses
+}
@@ -0,0 +1,177 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"regexp"
+	"strings"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+)
+
+const (
+	// CertIdentitySourceCN maps the common name of the certificate subject
+	CertIdentitySourceCN = "cn"
+	// CertIdentitySourceURI maps the URI subject alternative names, e.g. SPIFFE IDs
+	CertIdentitySourceURI = "uri"
+	// CertIdentitySourceDNS maps the DNS subject alternative names
+	CertIdentitySourceDNS = "dns"
+
+	// CertIdentityModeSubject injects the entity of the client certificate into the subject of requests
+	CertIdentityModeSubject = "subject"
+	// CertIdentityModeAuthorize authorizes the entity of the client certificate to query the requested resource
+	CertIdentityModeAuthorize = "authorize"
+
+	defaultCertIdentityCallerAction = "query"
+)
+
+// CertIdentityRule maps a name of client certificates to an entity
+type CertIdentityRule struct {
+	Source string `json:"source"`           //cn, uri or dns
+	Match  string `json:"match,omitempty"`  //regular expression matching the whole name, any name by default
+	Entity string `json:"entity,omitempty"` //entity name, may refer to submatches like $1 or ${name}, "$0" by default
+}
+
+// CertIdentityConfig controls how the identity of verified client certificates is used by the ADS
+type CertIdentityConfig struct {
+	Rules               []*CertIdentityRule `json:"rules"`                         //the first matching rule wins
+	IDD                 string              `json:"idd,omitempty"`                 //identity domain of mapped entities
+	Mode                string              `json:"mode,omitempty"`                //"subject" by default, or "authorize"
+	CallerAction        string              `json:"callerAction,omitempty"`        //action the caller is authorized for in authorize mode, "query" by default
+	Required            bool                `json:"required,omitempty"`            //whether requests without a mapped client certificate are rejected
+	KeepRequestEntities bool                `json:"keepRequestEntities,omitempty"` //whether entity principals sent by the caller are kept in subject mode
+}
+
+type certIdentityRule struct {
+	source string
+	match  *regexp.Regexp
+	entity string
+}
+
+// CertIdentityMapper maps verified client certificates to entity principals
+type CertIdentityMapper struct {
+	rules               []*certIdentityRule
+	idd                 string
+	mode                string
+	callerAction        string
+	required            bool
+	keepRequestEntities bool
+}
+
+// NewCertIdentityMapper creates a client certificate identity mapper
+func NewCertIdentityMapper(conf *CertIdentityConfig) (*CertIdentityMapper, error) {
+	if conf == nil || len(conf.Rules) == 0 {
+		return nil, fmt.Errorf("client certificate identity configuration is nil or has no rule")
+	}
+	m := CertIdentityMapper{
+		idd:                 conf.IDD,
+		mode:                strings.ToLower(conf.Mode),
+		callerAction:        conf.CallerAction,
+		required:            conf.Required,
+		keepRequestEntities: conf.KeepRequestEntities,
+	}
+	switch m.mode {
+	case "":
+		m.mode = CertIdentityModeSubject
+	case CertIdentityModeSubject, CertIdentityModeAuthorize:
+	default:
+		return nil, fmt.Errorf("unknown client certificate identity mode %q", conf.Mode)
+	}
+	if len(m.callerAction) == 0 {
+		m.callerAction = defaultCertIdentityCallerAction
+	}
+
+	for i, rule := range conf.Rules {
+		if rule == nil {
+			return nil, fmt.Errorf("client certificate identity rule %d is nil", i)
+		}
+		source := strings.ToLower(rule.Source)
+		switch source {
+		case CertIdentitySourceCN, CertIdentitySourceURI, CertIdentitySourceDNS:
+		default:
+			return nil, fmt.Errorf("unknown source %q of client certificate identity rule %d", rule.Source, i)
+		}
+		match := rule.Match
+		if len(match) == 0 {
+			match = ".+"
+		}
+		re, err := regexp.Compile("^(?:" + match + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid match of client certificate identity rule %d: %v", i, err)
+		}
+		entity := rule.Entity
+		if len(entity) == 0 {
+			entity = "$0"
+		}
+		m.rules = append(m.rules, &certIdentityRule{source: source, match: re, entity: entity})
+	}
+	return &m, nil
+}
+
+// Mode returns how the identity of client certificates is used
+func (m *CertIdentityMapper) Mode() string {
+	return m.mode
+}
+
+// CallerAction returns the action the caller is authorized for in authorize mode
+func (m *CertIdentityMapper) CallerAction() string {
+	return m.callerAction
+}
+
+// Required returns whether requests without a mapped client certificate are rejected
+func (m *CertIdentityMapper) Required() bool {
+	return m.required
+}
+
+// KeepRequestEntities returns whether entity principals sent by the caller are kept in subject mode
+func (m *CertIdentityMapper) KeepRequestEntities() bool {
+	return m.keepRequestEntities
+}
+
+func (r *certIdentityRule) names(cert *x509.Certificate) []string {
+	switch r.source {
+	case CertIdentitySourceCN:
+		return []string{cert.Subject.CommonName}
+	case CertIdentitySourceURI:
+		names := make([]string, 0, len(cert.URIs))
+		for _, uri := range cert.URIs {
+			names = append(names, uri.String())
+		}
+		return names
+	default:
+		return cert.DNSNames
+	}
+}
+
+// Map returns the entity principal of a client certificate, or nil if no rule matches
+func (m *CertIdentityMapper) Map(cert *x509.Certificate) *adsapi.Principal {
+	if cert == nil {
+		return nil
+	}
+	for _, rule := range m.rules {
+		for _, name := range rule.names(cert) {
+			submatches := rule.match.FindStringSubmatchIndex(name)
+			if submatches == nil {
+				continue
+			}
+			entity := string(rule.match.ExpandString(nil, rule.entity, name, submatches))
+			if len(entity) > 0 {
+				return &adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: entity, IDD: m.idd}
+			}
+		}
+	}
+	return nil
+}
+
+// PeerPrincipal returns the entity principal of the verified client certificate of a TLS
+// connection, or nil if there is no verified client certificate or no rule matches
+func (m *CertIdentityMapper) PeerPrincipal(state *tls.ConnectionState) *adsapi.Principal {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return m.Map(state.VerifiedChains[0][0])
+}
@@ -0,0 +1,89 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+)
+
+func newTestCert(cn string, uris []string, dnsNames []string) *x509.Certificate {
+	cert := x509.Certificate{Subject: pkix.Name{CommonName: cn}, DNSNames: dnsNames}
+	for _, uri := range uris {
+		u, _ := url.Parse(uri)
+		cert.URIs = append(cert.URIs, u)
+	}
+	return &cert
+}
+
+func TestCertIdentityMapper(t *testing.T) {
+	mapper, err := NewCertIdentityMapper(&CertIdentityConfig{
+		Rules: []*CertIdentityRule{
+			{Source: "uri", Match: `spiffe://acme\.com/ns/(?P<ns>[^/]+)/sa/([^/]+)`, Entity: "${ns}/$2"},
+			{Source: "dns", Match: `([a-z]+)\.svc\.acme\.com`, Entity: "$1"},
+			{Source: "CN"},
+		},
+		IDD: "acme",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapper.Mode() != CertIdentityModeSubject || mapper.CallerAction() != "query" {
+		t.Errorf("unexpected defaults %s, %s", mapper.Mode(), mapper.CallerAction())
+	}
+
+	testCases := []struct {
+		cert   *x509.Certificate
+		entity string
+	}{
+		{newTestCert("billing", []string{"spiffe://acme.com/ns/finance/sa/billing"}, []string{"billing.svc.acme.com"}), "finance/billing"},
+		{newTestCert("billing", []string{"spiffe://other.com/ns/finance/sa/billing"}, []string{"www.acme.com", "billing.svc.acme.com"}), "billing"},
+		{newTestCert("billing-client", nil, []string{"billing.svc.acme.com.evil.com"}), "billing-client"},
+		{newTestCert("", nil, nil), ""},
+		{nil, ""},
+	}
+	for i, tc := range testCases {
+		p := mapper.Map(tc.cert)
+		if len(tc.entity) == 0 {
+			if p != nil {
+				t.Errorf("case %d: no entity expected, got %v", i, p)
+			}
+			continue
+		}
+		expected := adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: tc.entity, IDD: "acme"}
+		if p == nil || *p != expected {
+			t.Errorf("case %d: expected %v, got %v", i, expected, p)
+		}
+	}
+
+	// Only verified certificates are mapped
+	cert := newTestCert("billing", nil, nil)
+	if p := mapper.PeerPrincipal(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}); p != nil {
+		t.Errorf("unverified certificate should not be mapped, got %v", p)
+	}
+	if p := mapper.PeerPrincipal(&tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}); p == nil || p.Name != "billing" {
+		t.Errorf("expected billing, got %v", p)
+	}
+	if p := mapper.PeerPrincipal(nil); p != nil {
+		t.Errorf("no principal expected without TLS, got %v", p)
+	}
+
+	invalid := []*CertIdentityConfig{
+		nil,
+		{},
+		{Rules: []*CertIdentityRule{{Source: "email"}}},
+		{Rules: []*CertIdentityRule{{Source: "cn", Match: "("}}},
+		{Rules: []*CertIdentityRule{{Source: "cn"}}, Mode: "enforce"},
+	}
+	for i, conf := range invalid {
+		if _, err := NewCertIdentityMapper(conf); err == nil {
+			t.Errorf("invalid configuration %d should be rejected", i)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// TokenTypeOpaque is the token type asserted by the introspection asserter by default
+	TokenTypeOpaque = "opaque"
+	// IntrospectionAsserterType asserts opaque OAuth tokens with token introspection (RFC 7662)
+	IntrospectionAsserterType = "introspection"
+
+	defaultIntrospectionCacheMaxTTL = 300
+
+	defaultIntrospectionUserClaim   = "username"
+	defaultIntrospectionEntityClaim = "client_id"
+	// attribute of the scopes of the token
+	scopeAttribute = "scope"
+)
+
+// IntrospectionAsserterConfig token introspection asserter configuration
+type IntrospectionAsserterConfig struct {
+	TokenTypes       []string       `json:"tokenTypes,omitempty"` //token types asserted by the introspection asserter, "opaque" by default
+	Endpoint         string         `json:"endpoint"`
+	ClientID         string         `json:"clientID,omitempty"`
+	ClientSecret     string         `json:"clientSecret,omitempty"`
+	ClientSecretFile string         `json:"clientSecretFile,omitempty"`
+	TokenTypeHint    string         `json:"tokenTypeHint,omitempty"` //"access_token" by default
+	CACert           string         `json:"caCert,omitempty"`
+	HTTPTimeout      int            `json:"httpTimeout,omitempty"`
+	CacheMaxTTL      int            `json:"cacheMaxTTL,omitempty"` //in seconds, 300 by default, negative disables the cache
+	ClaimMappings    *ClaimMappings `json:"claimMappings,omitempty"`
+}
+
+// IntrospectionAsserter asserts opaque OAuth tokens with the introspection endpoint of the
+// authorization server. Active tokens are cached until they expire.
+type IntrospectionAsserter struct {
+	tokenTypes    []string
+	endpoint      string
+	clientID      string
+	clientSecret  string
+	tokenTypeHint string
+	httpClient    *http.Client
+	cache         *subjectCache
+	mappings      ClaimMappings
+}
+
+// NewIntrospectionAsserter creates a token introspection asserter
+func NewIntrospectionAsserter(conf *IntrospectionAsserterConfig) (*IntrospectionAsserter, error) {
+	if conf == nil || len(conf.Endpoint) == 0 {
+		return nil, fmt.Errorf("introspection asserter configuration is nil or endpoint is empty")
+	}
+	a := IntrospectionAsserter{
+		tokenTypes:    conf.TokenTypes,
+		endpoint:      conf.Endpoint,
+		clientID:      conf.ClientID,
+		clientSecret:  conf.ClientSecret,
+		tokenTypeHint: conf.TokenTypeHint,
+	}
+	if len(a.tokenTypes) == 0 {
+		a.tokenTypes = []string{TokenTypeOpaque}
+	}
+	if len(a.tokenTypeHint) == 0 {
+		a.tokenTypeHint = "access_token"
+	}
+	if len(conf.ClientSecretFile) > 0 {
+		raw, err := ioutil.ReadFile(conf.ClientSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		a.clientSecret = strings.TrimSpace(string(raw))
+	}
+
+	tr := http.Transport{
+		MaxIdleConns:    1000,
+		IdleConnTimeout: 60 * time.Second,
+		Proxy:           http.ProxyFromEnvironment,
+	}
+	if len(conf.CACert) > 0 {
+		caCert, err := ioutil.ReadFile(conf.CACert)
+		if err != nil {
+			return nil, err
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(caCert)
+		tr.TLSClientConfig = &tls.Config{RootCAs: caCertPool}
+	}
+	timeout := conf.HTTPTimeout
+	if timeout <= 0 {
+		timeout = 10
+	}
+	a.httpClient = &http.Client{Transport: &tr, Timeout: time.Duration(timeout) * time.Second}
+
+	if conf.CacheMaxTTL >= 0 {
+		maxTTL := conf.CacheMaxTTL
+		if maxTTL == 0 {
+			maxTTL = defaultIntrospectionCacheMaxTTL
+		}
+		a.cache = newSubjectCache(&SubjectCacheConfig{MaxTTL: maxTTL})
+	}
+
+	if conf.ClaimMappings != nil {
+		a.mappings = *conf.ClaimMappings
+	}
+	if len(a.mappings.User) == 0 {
+		a.mappings.User = defaultIntrospectionUserClaim
+	}
+	if len(a.mappings.Groups) == 0 {
+		a.mappings.Groups = defaultGroupsClaim
+	}
+	if len(a.mappings.Entity) == 0 {
+		a.mappings.Entity = defaultIntrospectionEntityClaim
+	}
+	if len(a.mappings.IDD) == 0 {
+		a.mappings.IDD = defaultIDDClaim
+	}
+	return &a, nil
+}
+
+// TokenTypes returns the token types asserted by the introspection asserter
+func (a *IntrospectionAsserter) TokenTypes() []string {
+	return a.tokenTypes
+}
+
+// introspect sends the token to the introspection endpoint, and returns the claims of the token. The
+// trace context in the request headers is propagated to the endpoint.
+func (a *IntrospectionAsserter) introspect(token string, requestHeaders map[string]string) (jwt.MapClaims, error) {
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", a.tokenTypeHint)
+	req, err := http.NewRequest(http.MethodPost, a.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if len(a.clientID) > 0 {
+		req.SetBasicAuth(url.QueryEscape(a.clientID), url.QueryEscape(a.clientSecret))
+	}
+	if traceparent, ok := requestHeaders[tracing.TraceparentHeader]; ok {
+		req.Header.Set(tracing.TraceparentHeader, traceparent)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.AsserterUnavailable, "failed to call the introspection endpoint")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf(errors.AsserterUnavailable, "introspection error, status code: %d", resp.StatusCode)
+	}
+
+	var claims jwt.MapClaims
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&claims); err != nil {
+		return nil, errors.Wrap(err, errors.AsserterUnavailable, "invalid introspection response")
+	}
+	return claims, nil
+}
+
+// AssertToken asserts an opaque token with the introspection endpoint
+func (a *IntrospectionAsserter) AssertToken(token string, idpType string, allowedIDD string, requestHeaders map[string]string) (*AssertResponse, error) {
+	if len(token) == 0 {
+		return nil, fmt.Errorf("token is empty")
+	}
+	var key string
+	if a.cache != nil {
+		key = subjectCacheKey(token, "", allowedIDD)
+		if ar, ok := a.cache.get(key); ok {
+			return ar, nil
+		}
+	}
+
+	claims, err := a.introspect(token, requestHeaders)
+	if err != nil {
+		log.Errorf("introspection error: %v", err)
+		return nil, err
+	}
+	if active, _ := claims["active"].(bool); !active {
+		return nil, fmt.Errorf("token is not active")
+	}
+	exp, hasExp, err := numericClaim(claims, "exp")
+	if err != nil {
+		return nil, err
+	}
+	if hasExp && time.Now().Unix() >= exp {
+		return nil, fmt.Errorf("token is expired")
+	}
+
+	idd, _ := claims[a.mappings.IDD].(string)
+	if len(allowedIDD) > 0 && idd != allowedIDD {
+		return nil, fmt.Errorf("identity domain %q is not allowed", idd)
+	}
+
+	var ar AssertResponse
+	user, _ := claims[a.mappings.User].(string)
+	if len(user) == 0 {
+		user, _ = claims["sub"].(string)
+	}
+	if len(user) > 0 {
+		ar.Principals = append(ar.Principals, &adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_USER, Name: user, IDD: idd})
+	}
+	for _, group := range stringsClaim(claims, a.mappings.Groups) {
+		if len(group) > 0 {
+			ar.Principals = append(ar.Principals, &adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_GROUP, Name: group, IDD: idd})
+		}
+	}
+	if entity, ok := claims[a.mappings.Entity].(string); ok && len(entity) > 0 {
+		ar.Principals = append(ar.Principals, &adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: entity, IDD: idd})
+	}
+	if len(ar.Principals) == 0 {
+		return nil, fmt.Errorf("no principal in token")
+	}
+
+	ar.Attributes = make(map[string]interface{})
+	if scope, ok := claims["scope"].(string); ok {
+		scopes := []interface{}{}
+		for _, s := range strings.Fields(scope) {
+			scopes = append(scopes, s)
+		}
+		ar.Attributes[scopeAttribute] = scopes
+	}
+	for attr, claim := range a.mappings.Attributes {
+		if v, ok := claims[claim]; ok {
+			ar.Attributes[attr] = claimValue(v)
+		}
+	}
+	if hasExp {
+		ar.ExpiresAt = exp
+	}
+
+	if a.cache != nil {
+		a.cache.add(key, &ar)
+	}
+	log.Debugf("asserted: %v", ar)
+	return &ar, nil
+}
+
+type introspectionAsserterBuilder struct{}
+
+func (introspectionAsserterBuilder) NewTokenAsserter(props map[string]interface{}) (TokenAsserter, error) {
+	var conf IntrospectionAsserterConfig
+	if err := decodeProps(props, &conf); err != nil {
+		return nil, err
+	}
+	return NewIntrospectionAsserter(&conf)
+}
+
+func init() {
+	Register(IntrospectionAsserterType, introspectionAsserterBuilder{})
+}
@@ -0,0 +1,141 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+)
+
+// newTestAuthorizationServer starts an authorization server introspecting the tokens
+func newTestAuthorizationServer(t *testing.T, tokens map[string]map[string]interface{}, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		// client credentials are form encoded (RFC 6749 section 2.3.1)
+		clientID, secret, ok := r.BasicAuth()
+		clientID, _ = url.QueryUnescape(clientID)
+		secret, _ = url.QueryUnescape(secret)
+		if !ok || clientID != "speedle" || secret != "s3cret:&" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodPost || r.PostFormValue("token_type_hint") != "access_token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		token := r.PostFormValue("token")
+		if token == "error" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		resp, ok := tokens[token]
+		if !ok {
+			resp = map[string]interface{}{"active": false}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestIntrospectionAsserter(t *testing.T) {
+	exp := time.Now().Unix() + 600
+	tokens := map[string]map[string]interface{}{
+		"alice-token": {
+			"active":    true,
+			"username":  "alice",
+			"sub":       "u-1234",
+			"client_id": "portal",
+			"scope":     "read write",
+			"tenant":    "acme",
+			"dept":      "eng",
+			"exp":       exp,
+		},
+		"service-token": {
+			"active":    true,
+			"sub":       "u-5678",
+			"client_id": "batch",
+			"exp":       time.Now().Unix() + 1,
+		},
+		"expired-token": {
+			"active":   true,
+			"username": "bob",
+			"exp":      time.Now().Unix() - 10,
+		},
+	}
+	var calls int32
+	server := newTestAuthorizationServer(t, tokens, &calls)
+	defer server.Close()
+
+	asserter, err := NewIntrospectionAsserter(&IntrospectionAsserterConfig{
+		Endpoint:      server.URL,
+		ClientID:      "speedle",
+		ClientSecret:  "s3cret:&",
+		ClaimMappings: &ClaimMappings{Attributes: map[string]string{"department": "dept"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ar, err := asserter.AssertToken("alice-token", TokenTypeOpaque, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &AssertResponse{
+		Principals: []*adsapi.Principal{
+			{Type: adsapi.PRINCIPAL_TYPE_USER, Name: "alice", IDD: "acme"},
+			{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: "portal", IDD: "acme"},
+		},
+		Attributes: map[string]interface{}{"scope": []interface{}{"read", "write"}, "department": "eng"},
+		ExpiresAt:  exp,
+	}
+	if !reflect.DeepEqual(ar, expected) {
+		t.Errorf("expected %+v, got %+v", expected, ar)
+	}
+
+	// Active tokens are cached until they expire
+	if _, err := asserter.AssertToken("alice-token", TokenTypeOpaque, "", nil); err != nil || atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("active token should be cached, got %v, %d calls", err, calls)
+	}
+
+	// sub is the user if there is no username
+	ar, err = asserter.AssertToken("service-token", TokenTypeOpaque, "", nil)
+	if err != nil || ar.Principals[0].Name != "u-5678" || ar.Principals[1].Name != "batch" {
+		t.Errorf("unexpected subject %v, %v", ar, err)
+	}
+	time.Sleep(1100 * time.Millisecond)
+	callsBefore := atomic.LoadInt32(&calls)
+	asserter.AssertToken("service-token", TokenTypeOpaque, "", nil)
+	if atomic.LoadInt32(&calls) != callsBefore+1 {
+		t.Error("expired token should be introspected again")
+	}
+
+	for _, token := range []string{"unknown-token", "expired-token", "error"} {
+		if _, err := asserter.AssertToken(token, TokenTypeOpaque, "", nil); err == nil {
+			t.Errorf("%s should be rejected", token)
+		}
+	}
+	if _, err := asserter.AssertToken("alice-token", TokenTypeOpaque, "globex", nil); err == nil {
+		t.Error("token of other identity domain should be rejected")
+	}
+
+	unauthorized, err := NewIntrospectionAsserter(&IntrospectionAsserterConfig{
+		Endpoint:     server.URL,
+		ClientID:     "speedle",
+		ClientSecret: "guess",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := unauthorized.AssertToken("alice-token", TokenTypeOpaque, "", nil); err == nil {
+		t.Error("introspection with wrong client credentials should fail")
+	}
+}
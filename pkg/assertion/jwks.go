@@ -0,0 +1,302 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	log "github.com/sirupsen/logrus"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+)
+
+const (
+	defaultJWKSRefreshInterval = 3600
+	// unknown key ids trigger a refresh of the key set at most once in this interval
+	minJWKSRefreshInterval = 30 * time.Second
+)
+
+// JSONWebKey is a key in a JSON web key set (RFC 7517)
+type JSONWebKey struct {
+	Kid string `json:"kid,omitempty"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	// symmetric
+	K string `json:"k,omitempty"`
+}
+
+// JSONWebKeySet is a JSON web key set
+type JSONWebKeySet struct {
+	Keys []*JSONWebKey `json:"keys"`
+}
+
+// verificationKey is a key used to verify token signatures
+type verificationKey struct {
+	kid string
+	alg string
+	key interface{} // *rsa.PublicKey, *ecdsa.PublicKey or []byte
+}
+
+// compatible returns whether the key can verify signatures of the signing method
+func (k *verificationKey) compatible(method jwt.SigningMethod) bool {
+	if len(k.alg) > 0 && k.alg != method.Alg() {
+		return false
+	}
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, ok := k.key.(*rsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodECDSA:
+		_, ok := k.key.(*ecdsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodHMAC:
+		_, ok := k.key.([]byte)
+		return ok
+	}
+	return false
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(trimPadding(s))
+}
+
+func trimPadding(s string) string {
+	for len(s) > 0 && s[len(s)-1] == '=' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := decodeSegment(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// parseJSONWebKey converts a JSON web key to a verification key
+func parseJSONWebKey(jwk *JSONWebKey) (*verificationKey, error) {
+	if len(jwk.Use) > 0 && jwk.Use != "sig" {
+		return nil, fmt.Errorf("key %q is not a signature key", jwk.Kid)
+	}
+	vk := verificationKey{kid: jwk.Kid, alg: jwk.Alg}
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus of RSA key %q: %v", jwk.Kid, err)
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent of RSA key %q: %v", jwk.Kid, err)
+		}
+		if n.Sign() == 0 || !e.IsInt64() || e.Int64() <= 1 {
+			return nil, fmt.Errorf("invalid RSA key %q", jwk.Kid)
+		}
+		vk.key = &rsa.PublicKey{N: n, E: int(e.Int64())}
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q of EC key %q", jwk.Crv, jwk.Kid)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate of EC key %q: %v", jwk.Kid, err)
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate of EC key %q: %v", jwk.Kid, err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("invalid EC key %q", jwk.Kid)
+		}
+		vk.key = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+	case "oct":
+		k, err := decodeSegment(jwk.K)
+		if err != nil || len(k) == 0 {
+			return nil, fmt.Errorf("invalid symmetric key %q", jwk.Kid)
+		}
+		vk.key = k
+	default:
+		return nil, fmt.Errorf("unsupported key type %q of key %q", jwk.Kty, jwk.Kid)
+	}
+	return &vk, nil
+}
+
+// parseJSONWebKeySet parses a JSON web key set. Keys that can't be used are skipped.
+func parseJSONWebKeySet(raw []byte) ([]*verificationKey, error) {
+	var set JSONWebKeySet
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("invalid JSON web key set: %v", err)
+	}
+	keys := make([]*verificationKey, 0, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := parseJSONWebKey(jwk)
+		if err != nil {
+			log.Warningf("skip JSON web key: %v", err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// keySet is a JSON web key set loaded from a file or an URL. The key set is reloaded
+// periodically, and when a token is signed by an unknown key, so that rotated keys are
+// picked up.
+type keySet struct {
+	source             string
+	fetch              func() ([]byte, error)
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+
+	mutex       sync.RWMutex
+	keys        []*verificationKey
+	loadedAt    time.Time
+	lastAttempt time.Time
+}
+
+func newFileKeySet(path string, refreshInterval time.Duration) *keySet {
+	return &keySet{
+		source:             path,
+		fetch:              func() ([]byte, error) { return ioutil.ReadFile(path) },
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: minJWKSRefreshInterval,
+	}
+}
+
+func newURLKeySet(url string, caCert string, timeout time.Duration, refreshInterval time.Duration) (*keySet, error) {
+	tr := http.Transport{
+		IdleConnTimeout: 60 * time.Second,
+		Proxy:           http.ProxyFromEnvironment,
+	}
+	if len(caCert) > 0 {
+		pem, err := ioutil.ReadFile(caCert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(pem)
+		tr.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	client := &http.Client{Transport: &tr, Timeout: timeout}
+
+	fetch := func() ([]byte, error) {
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch JSON web key set from %s, status code: %d", url, resp.StatusCode)
+		}
+		return ioutil.ReadAll(resp.Body)
+	}
+	return &keySet{
+		source:             url,
+		fetch:              fetch,
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: minJWKSRefreshInterval,
+	}, nil
+}
+
+// refresh reloads the key set. If force is false, the key set is only reloaded when it
+// is older than the refresh interval.
+func (s *keySet) refresh(force bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if !force && !s.loadedAt.IsZero() && now.Sub(s.loadedAt) < s.refreshInterval {
+		return nil
+	}
+	if !s.lastAttempt.IsZero() && now.Sub(s.lastAttempt) < s.minRefreshInterval {
+		return nil
+	}
+	s.lastAttempt = now
+
+	raw, err := s.fetch()
+	if err != nil {
+		return errors.Wrapf(err, errors.AsserterUnavailable, "failed to load JSON web key set from %s", s.source)
+	}
+	keys, err := parseJSONWebKeySet(raw)
+	if err != nil {
+		return errors.Wrapf(err, errors.AsserterUnavailable, "failed to load JSON web key set from %s", s.source)
+	}
+	s.keys = keys
+	s.loadedAt = now
+	log.Debugf("loaded %d keys from %s", len(keys), s.source)
+	return nil
+}
+
+// find returns the keys which may have signed a token
+func (s *keySet) find(kid string, method jwt.SigningMethod) []*verificationKey {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return findKeys(s.keys, kid, method)
+}
+
+func findKeys(keys []*verificationKey, kid string, method jwt.SigningMethod) []*verificationKey {
+	var found []*verificationKey
+	for _, key := range keys {
+		if len(kid) > 0 && key.kid != kid {
+			continue
+		}
+		if key.compatible(method) {
+			found = append(found, key)
+		}
+	}
+	return found
+}
+
+// lookup returns the keys which may have signed a token. The key set is reloaded if it is
+// stale, or if the key id is unknown.
+func (s *keySet) lookup(kid string, method jwt.SigningMethod) ([]*verificationKey, error) {
+	if err := s.refresh(false); err != nil {
+		s.mutex.RLock()
+		loaded := !s.loadedAt.IsZero()
+		s.mutex.RUnlock()
+		if !loaded {
+			return nil, err
+		}
+		// keep using the keys loaded before
+		log.Warning(err)
+	}
+	keys := s.find(kid, method)
+	if len(keys) == 0 && len(kid) > 0 {
+		if err := s.refresh(true); err != nil {
+			log.Warning(err)
+		}
+		keys = s.find(kid, method)
+	}
+	return keys, nil
+}
@@ -0,0 +1,446 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// TokenTypeJWT is the token type asserted by the JWT asserter by default
+	TokenTypeJWT = "jwt"
+	// JWTAsserterType verifies JSON web tokens locally
+	JWTAsserterType = "jwt"
+
+	defaultJWTClockSkew = 60
+
+	defaultUserClaim   = "sub"
+	defaultGroupsClaim = "groups"
+	defaultEntityClaim = "azp"
+	defaultIDDClaim    = "tenant"
+)
+
+// StaticKey is a key configured for the JWT asserter
+type StaticKey struct {
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// PEM encoded RSA or EC public key or certificate
+	PublicKey     string `json:"publicKey,omitempty"`
+	PublicKeyFile string `json:"publicKeyFile,omitempty"`
+	// HMAC secret
+	Secret     string `json:"secret,omitempty"`
+	SecretFile string `json:"secretFile,omitempty"`
+}
+
+// ClaimMappings maps token claims to principals and attributes
+type ClaimMappings struct {
+	User       string            `json:"user,omitempty"`       //"sub" by default
+	Groups     string            `json:"groups,omitempty"`     //"groups" by default
+	Entity     string            `json:"entity,omitempty"`     //"azp" by default
+	IDD        string            `json:"idd,omitempty"`        //"tenant" by default
+	Attributes map[string]string `json:"attributes,omitempty"` //attribute name to claim name
+}
+
+// JWTAsserterConfig JWT asserter configuration
+type JWTAsserterConfig struct {
+	TokenTypes          []string       `json:"tokenTypes,omitempty"` //token types asserted by the JWT asserter, "jwt" by default
+	JWKSFile            string         `json:"jwksFile,omitempty"`
+	JWKSURL             string         `json:"jwksURL,omitempty"`
+	JWKSCACert          string         `json:"jwksCACert,omitempty"`
+	JWKSRefreshInterval int            `json:"jwksRefreshInterval,omitempty"` //in seconds, 3600 by default
+	HTTPTimeout         int            `json:"httpTimeout,omitempty"`
+	StaticKeys          []*StaticKey   `json:"staticKeys,omitempty"`
+	Algorithms          []string       `json:"algorithms,omitempty"` //accepted signing algorithms, all supported algorithms by default
+	Issuers             []string       `json:"issuers,omitempty"`    //accepted issuers, any issuer by default
+	Audiences           []string       `json:"audiences,omitempty"`  //the token must have one of the audiences if set
+	ClockSkew           int            `json:"clockSkew,omitempty"`  //in seconds, 60 by default
+	ClaimMappings       *ClaimMappings `json:"claimMappings,omitempty"`
+}
+
+// JWTAsserter verifies JSON web tokens locally and maps their claims to principals and attributes
+type JWTAsserter struct {
+	tokenTypes []string
+	staticKeys []*verificationKey
+	keySets    []*keySet
+	parser     *jwt.Parser
+	issuers    []string
+	audiences  []string
+	clockSkew  time.Duration
+	mappings   ClaimMappings
+}
+
+// NewJWTAsserter creates a JWT asserter
+func NewJWTAsserter(conf *JWTAsserterConfig) (*JWTAsserter, error) {
+	if conf == nil {
+		return nil, fmt.Errorf("JWT asserter configuration is nil")
+	}
+	a := JWTAsserter{
+		tokenTypes: conf.TokenTypes,
+		parser: &jwt.Parser{
+			ValidMethods:         conf.Algorithms,
+			UseJSONNumber:        true,
+			SkipClaimsValidation: true,
+		},
+		issuers:   conf.Issuers,
+		audiences: conf.Audiences,
+		clockSkew: time.Duration(conf.ClockSkew) * time.Second,
+	}
+	if len(a.tokenTypes) == 0 {
+		a.tokenTypes = []string{TokenTypeJWT}
+	}
+	if conf.ClockSkew == 0 {
+		a.clockSkew = defaultJWTClockSkew * time.Second
+	} else if conf.ClockSkew < 0 {
+		a.clockSkew = 0
+	}
+	for _, alg := range conf.Algorithms {
+		if jwt.GetSigningMethod(alg) == nil || alg == "none" {
+			return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+		}
+	}
+
+	for _, sk := range conf.StaticKeys {
+		key, err := loadStaticKey(sk)
+		if err != nil {
+			return nil, err
+		}
+		a.staticKeys = append(a.staticKeys, key)
+	}
+
+	refreshInterval := time.Duration(conf.JWKSRefreshInterval) * time.Second
+	if conf.JWKSRefreshInterval <= 0 {
+		refreshInterval = defaultJWKSRefreshInterval * time.Second
+	}
+	if len(conf.JWKSFile) > 0 {
+		ks := newFileKeySet(conf.JWKSFile, refreshInterval)
+		if err := ks.refresh(true); err != nil {
+			return nil, err
+		}
+		a.keySets = append(a.keySets, ks)
+	}
+	if len(conf.JWKSURL) > 0 {
+		timeout := conf.HTTPTimeout
+		if timeout <= 0 {
+			timeout = 10
+		}
+		ks, err := newURLKeySet(conf.JWKSURL, conf.JWKSCACert, time.Duration(timeout)*time.Second, refreshInterval)
+		if err != nil {
+			return nil, err
+		}
+		// The JWKS endpoint may not be available yet, keys are loaded again on first use
+		if err := ks.refresh(true); err != nil {
+			log.Warning(err)
+		}
+		a.keySets = append(a.keySets, ks)
+	}
+	if len(a.staticKeys) == 0 && len(a.keySets) == 0 {
+		return nil, fmt.Errorf("no key is configured for JWT asserter")
+	}
+
+	if conf.ClaimMappings != nil {
+		a.mappings = *conf.ClaimMappings
+	}
+	if len(a.mappings.User) == 0 {
+		a.mappings.User = defaultUserClaim
+	}
+	if len(a.mappings.Groups) == 0 {
+		a.mappings.Groups = defaultGroupsClaim
+	}
+	if len(a.mappings.Entity) == 0 {
+		a.mappings.Entity = defaultEntityClaim
+	}
+	if len(a.mappings.IDD) == 0 {
+		a.mappings.IDD = defaultIDDClaim
+	}
+
+	return &a, nil
+}
+
+func loadStaticKey(sk *StaticKey) (*verificationKey, error) {
+	key := verificationKey{kid: sk.Kid, alg: sk.Alg}
+	secret := []byte(sk.Secret)
+	if len(sk.SecretFile) > 0 {
+		raw, err := ioutil.ReadFile(sk.SecretFile)
+		if err != nil {
+			return nil, err
+		}
+		secret = []byte(strings.TrimSpace(string(raw)))
+	}
+	pem := []byte(sk.PublicKey)
+	if len(sk.PublicKeyFile) > 0 {
+		raw, err := ioutil.ReadFile(sk.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		pem = raw
+	}
+
+	switch {
+	case len(secret) > 0 && len(pem) > 0:
+		return nil, fmt.Errorf("static key %q has both secret and public key", sk.Kid)
+	case len(secret) > 0:
+		key.key = secret
+	case len(pem) > 0:
+		if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(pem); err == nil {
+			key.key = rsaKey
+		} else if ecKey, err := jwt.ParseECPublicKeyFromPEM(pem); err == nil {
+			key.key = ecKey
+		} else {
+			return nil, fmt.Errorf("invalid public key of static key %q", sk.Kid)
+		}
+	default:
+		return nil, fmt.Errorf("static key %q has neither secret nor public key", sk.Kid)
+	}
+	return &key, nil
+}
+
+// TokenTypes returns the token types asserted by the JWT asserter
+func (a *JWTAsserter) TokenTypes() []string {
+	return a.tokenTypes
+}
+
+// keys returns the keys which may have signed a token
+func (a *JWTAsserter) keys(kid string, method jwt.SigningMethod) ([]*verificationKey, error) {
+	keys := findKeys(a.staticKeys, kid, method)
+	var lastErr error
+	for _, ks := range a.keySets {
+		found, err := ks.lookup(kid, method)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		keys = append(keys, found...)
+	}
+	if len(keys) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, fmt.Errorf("no key found for kid %q and algorithm %s", kid, method.Alg())
+	}
+	return keys, nil
+}
+
+// verify verifies the signature of a token, and returns its claims
+func (a *JWTAsserter) verify(tokenString string) (jwt.MapClaims, error) {
+	token, parts, err := a.parser.ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return nil, err
+	}
+	if len(a.parser.ValidMethods) > 0 {
+		valid := false
+		for _, alg := range a.parser.ValidMethods {
+			if alg == token.Method.Alg() {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("signing algorithm %s is not accepted", token.Method.Alg())
+		}
+	}
+	kid, _ := token.Header["kid"].(string)
+	keys, err := a.keys(kid, token.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	signingString := strings.Join(parts[0:2], ".")
+	for _, key := range keys {
+		if err = token.Method.Verify(signingString, parts[2], key.key); err == nil {
+			return token.Claims.(jwt.MapClaims), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid token signature: %v", err)
+}
+
+// numericClaim returns a NumericDate claim as seconds since epoch
+func numericClaim(claims jwt.MapClaims, name string) (int64, bool, error) {
+	v, ok := claims[name]
+	if !ok {
+		return 0, false, nil
+	}
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i, true, nil
+		}
+		if f, err := n.Float64(); err == nil {
+			return int64(f), true, nil
+		}
+	}
+	return 0, false, fmt.Errorf("invalid claim %s", name)
+}
+
+// validate validates the registered claims of a token
+func (a *JWTAsserter) validate(claims jwt.MapClaims) error {
+	now := time.Now().Unix()
+	skew := int64(a.clockSkew / time.Second)
+
+	exp, ok, err := numericClaim(claims, "exp")
+	if err != nil {
+		return err
+	}
+	if ok && now > exp+skew {
+		return fmt.Errorf("token is expired")
+	}
+	nbf, ok, err := numericClaim(claims, "nbf")
+	if err != nil {
+		return err
+	}
+	if ok && now < nbf-skew {
+		return fmt.Errorf("token is not valid yet")
+	}
+	iat, ok, err := numericClaim(claims, "iat")
+	if err != nil {
+		return err
+	}
+	if ok && now < iat-skew {
+		return fmt.Errorf("token is issued in the future")
+	}
+
+	if len(a.issuers) > 0 {
+		iss, _ := claims["iss"].(string)
+		if !containsString(a.issuers, iss) {
+			return fmt.Errorf("issuer %q is not accepted", iss)
+		}
+	}
+	if len(a.audiences) > 0 {
+		accepted := false
+		for _, aud := range stringsClaim(claims, "aud") {
+			if containsString(a.audiences, aud) {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			return fmt.Errorf("audience %v is not accepted", claims["aud"])
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// stringsClaim returns a claim which is either a string or an array of strings
+func stringsClaim(claims jwt.MapClaims, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// claimValue converts JSON numbers in a claim to float64, like other attributes
+func claimValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		f, err := value.Float64()
+		if err != nil {
+			return value.String()
+		}
+		return f
+	case []interface{}:
+		values := make([]interface{}, len(value))
+		for i, item := range value {
+			values[i] = claimValue(item)
+		}
+		return values
+	case map[string]interface{}:
+		values := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			values[k] = claimValue(item)
+		}
+		return values
+	}
+	return v
+}
+
+// AssertToken verifies a JSON web token and maps its claims to principals and attributes
+func (a *JWTAsserter) AssertToken(token string, idpType string, allowedIDD string, requestHeaders map[string]string) (*AssertResponse, error) {
+	if len(token) == 0 {
+		return nil, fmt.Errorf("token is empty")
+	}
+	claims, err := a.verify(token)
+	if err != nil {
+		log.Errorf("JWT verification error: %v", err)
+		return nil, err
+	}
+	if err := a.validate(claims); err != nil {
+		log.Errorf("JWT validation error: %v", err)
+		return nil, err
+	}
+
+	idd, _ := claims[a.mappings.IDD].(string)
+	if len(allowedIDD) > 0 && idd != allowedIDD {
+		return nil, fmt.Errorf("identity domain %q is not allowed", idd)
+	}
+
+	var ar AssertResponse
+	if user, ok := claims[a.mappings.User].(string); ok && len(user) > 0 {
+		ar.Principals = append(ar.Principals, &adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_USER, Name: user, IDD: idd})
+	}
+	for _, group := range stringsClaim(claims, a.mappings.Groups) {
+		if len(group) > 0 {
+			ar.Principals = append(ar.Principals, &adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_GROUP, Name: group, IDD: idd})
+		}
+	}
+	if entity, ok := claims[a.mappings.Entity].(string); ok && len(entity) > 0 {
+		ar.Principals = append(ar.Principals, &adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: entity, IDD: idd})
+	}
+	if len(ar.Principals) == 0 {
+		return nil, fmt.Errorf("no principal in token")
+	}
+
+	if exp, ok, _ := numericClaim(claims, "exp"); ok {
+		ar.ExpiresAt = exp
+	}
+	for attr, claim := range a.mappings.Attributes {
+		if v, ok := claims[claim]; ok {
+			if ar.Attributes == nil {
+				ar.Attributes = make(map[string]interface{})
+			}
+			ar.Attributes[attr] = claimValue(v)
+		}
+	}
+
+	log.Debugf("asserted: %v", ar)
+	return &ar, nil
+}
+
+type jwtAsserterBuilder struct{}
+
+func (jwtAsserterBuilder) NewTokenAsserter(props map[string]interface{}) (TokenAsserter, error) {
+	var conf JWTAsserterConfig
+	if err := decodeProps(props, &conf); err != nil {
+		return nil, err
+	}
+	return NewJWTAsserter(&conf)
+}
+
+func init() {
+	Register(JWTAsserterType, jwtAsserterBuilder{})
+}
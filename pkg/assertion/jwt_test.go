@@ -0,0 +1,228 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+)
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) *JSONWebKey {
+	return &JSONWebKey{Kid: kid, Kty: "RSA", Use: "sig", N: encodeBigInt(key.N), E: encodeBigInt(big.NewInt(int64(key.E)))}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) *JSONWebKey {
+	return &JSONWebKey{Kid: kid, Kty: "EC", Crv: "P-256", X: encodeBigInt(key.X), Y: encodeBigInt(key.Y)}
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if len(kid) > 0 {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func validClaims() jwt.MapClaims {
+	now := time.Now().Unix()
+	return jwt.MapClaims{
+		"iss":    "https://idp.example.com",
+		"aud":    []string{"speedle", "other"},
+		"sub":    "alice",
+		"groups": []string{"admins", "eng"},
+		"azp":    "portal",
+		"tenant": "acme",
+		"dept":   "eng",
+		"level":  3,
+		"iat":    now,
+		"nbf":    now,
+		"exp":    now + 300,
+	}
+}
+
+func TestJWTAsserter(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	jwks, _ := json.Marshal(JSONWebKeySet{Keys: []*JSONWebKey{rsaJWK("rsa1", &rsaKey.PublicKey), ecJWK("ec1", &ecKey.PublicKey)}})
+	jwksFile := filepath.Join(dir, "jwks.json")
+	if err := ioutil.WriteFile(jwksFile, jwks, 0600); err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&otherKey.PublicKey)
+	otherPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	asserter, err := NewJWTAsserter(&JWTAsserterConfig{
+		JWKSFile: jwksFile,
+		StaticKeys: []*StaticKey{
+			{Kid: "hs1", Secret: "top-secret"},
+			{PublicKey: otherPEM},
+		},
+		Issuers:   []string{"https://idp.example.com"},
+		Audiences: []string{"speedle"},
+		ClockSkew: 30,
+		ClaimMappings: &ClaimMappings{
+			Attributes: map[string]string{"dept": "dept", "level": "level"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ar, err := asserter.AssertToken(signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, validClaims()), "jwt", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []adsapi.Principal{
+		{Type: adsapi.PRINCIPAL_TYPE_USER, Name: "alice", IDD: "acme"},
+		{Type: adsapi.PRINCIPAL_TYPE_GROUP, Name: "admins", IDD: "acme"},
+		{Type: adsapi.PRINCIPAL_TYPE_GROUP, Name: "eng", IDD: "acme"},
+		{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: "portal", IDD: "acme"},
+	}
+	if len(ar.Principals) != len(expected) {
+		t.Fatalf("expected principals %v, got %v", expected, ar.Principals)
+	}
+	for i, p := range ar.Principals {
+		if *p != expected[i] {
+			t.Errorf("expected principal %v, got %v", expected[i], p)
+		}
+	}
+	if ar.Attributes["dept"] != "eng" || ar.Attributes["level"] != 3.0 || len(ar.Attributes) != 2 {
+		t.Errorf("unexpected attributes %v", ar.Attributes)
+	}
+
+	now := time.Now().Unix()
+	withClaims := func(claims map[string]interface{}) jwt.MapClaims {
+		c := validClaims()
+		for k, v := range claims {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+	testCases := []struct {
+		name       string
+		token      string
+		allowedIDD string
+		valid      bool
+	}{
+		{"ES256 from JWKS file", signToken(t, jwt.SigningMethodES256, "ec1", ecKey, validClaims()), "", true},
+		{"HS256 static secret", signToken(t, jwt.SigningMethodHS256, "hs1", []byte("top-secret"), validClaims()), "", true},
+		{"RS256 static key without kid", signToken(t, jwt.SigningMethodRS256, "", otherKey, validClaims()), "", true},
+		{"wrong secret", signToken(t, jwt.SigningMethodHS256, "hs1", []byte("guess"), validClaims()), "", false},
+		{"unknown kid", signToken(t, jwt.SigningMethodRS256, "rsa2", rsaKey, validClaims()), "", false},
+		{"signed by another key", signToken(t, jwt.SigningMethodRS256, "rsa1", otherKey, validClaims()), "", false},
+		{"expired within clock skew", signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, withClaims(map[string]interface{}{"exp": now - 10})), "", true},
+		{"expired", signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, withClaims(map[string]interface{}{"exp": now - 60})), "", false},
+		{"not valid yet", signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, withClaims(map[string]interface{}{"nbf": now + 60})), "", false},
+		{"wrong issuer", signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, withClaims(map[string]interface{}{"iss": "https://evil.example.com"})), "", false},
+		{"audience string", signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, withClaims(map[string]interface{}{"aud": "speedle"})), "", true},
+		{"wrong audience", signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, withClaims(map[string]interface{}{"aud": "other"})), "", false},
+		{"no principal", signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, withClaims(map[string]interface{}{"sub": nil, "groups": nil, "azp": nil})), "", false},
+		{"allowed IDD", signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, validClaims()), "acme", true},
+		{"other IDD", signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, validClaims()), "globex", false},
+		{"unsigned", signToken(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, validClaims()), "", false},
+		{"malformed", "not-a-token", "", false},
+	}
+	for _, tc := range testCases {
+		_, err := asserter.AssertToken(tc.token, "jwt", tc.allowedIDD, nil)
+		if (err == nil) != tc.valid {
+			t.Errorf("%s: expected valid %v, got error %v", tc.name, tc.valid, err)
+		}
+	}
+}
+
+func TestJWTAsserterKeyRotation(t *testing.T) {
+	key1, _ := rsa.GenerateKey(rand.Reader, 2048)
+	key2, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	var mutex sync.Mutex
+	var fetches int32
+	current := JSONWebKeySet{Keys: []*JSONWebKey{rsaJWK("k1", &key1.PublicKey)}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		mutex.Lock()
+		defer mutex.Unlock()
+		json.NewEncoder(w).Encode(current)
+	}))
+	defer server.Close()
+
+	asserter, err := NewJWTAsserter(&JWTAsserterConfig{
+		TokenTypes: []string{"oidc"},
+		JWKSURL:    server.URL,
+		Algorithms: []string{"RS256"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserter.keySets[0].minRefreshInterval = 0
+
+	if _, err := asserter.AssertToken(signToken(t, jwt.SigningMethodRS256, "k1", key1, validClaims()), "oidc", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Errorf("key set should be cached, fetched %d times", n)
+	}
+
+	// rotate keys, the new key is picked up when a token signed by it is asserted
+	mutex.Lock()
+	current = JSONWebKeySet{Keys: []*JSONWebKey{rsaJWK("k2", &key2.PublicKey)}}
+	mutex.Unlock()
+	if _, err := asserter.AssertToken(signToken(t, jwt.SigningMethodRS256, "k2", key2, validClaims()), "oidc", "", nil); err != nil {
+		t.Errorf("rotated key should be used, got %v", err)
+	}
+	if _, err := asserter.AssertToken(signToken(t, jwt.SigningMethodRS256, "k1", key1, validClaims()), "oidc", "", nil); err == nil {
+		t.Error("removed key should not be used")
+	}
+
+	// algorithms not accepted are rejected
+	if _, err := asserter.AssertToken(signToken(t, jwt.SigningMethodRS512, "k2", key2, validClaims()), "oidc", "", nil); err == nil {
+		t.Error("RS512 should not be accepted")
+	}
+}
@@ -0,0 +1,89 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	asserterBuildersMu = &sync.RWMutex{}
+	asserterBuilders   = make(map[string]AsserterBuilder)
+)
+
+// AsserterBuilder creates token asserters of a type
+type AsserterBuilder interface {
+	NewTokenAsserter(props map[string]interface{}) (TokenAsserter, error)
+}
+
+// AsserterDefinition is the configuration of a named token asserter
+type AsserterDefinition struct {
+	Name  string                 `json:"name"`
+	Type  string                 `json:"type"` // jwt, webhook, apikey or introspection
+	Props map[string]interface{} `json:"props,omitempty"`
+}
+
+// Register makes a type of token asserter available by the provided name.
+// If Register is called twice with the same name or if asserterBuilder is nil,
+// it panics.
+func Register(asserterType string, asserterBuilder AsserterBuilder) {
+	asserterBuildersMu.Lock()
+	defer asserterBuildersMu.Unlock()
+	if asserterBuilder == nil {
+		panic("speedle: Register asserterBuilder is nil")
+	}
+	if _, dup := asserterBuilders[asserterType]; dup {
+		panic("speedle: Register called twice for asserterBuilder " + asserterType)
+	}
+	asserterBuilders[asserterType] = asserterBuilder
+}
+
+// AsserterBuilders returns a sorted list of the names of the registered asserter types.
+func AsserterBuilders() []string {
+	asserterBuildersMu.RLock()
+	defer asserterBuildersMu.RUnlock()
+	var list []string
+	for asserterType := range asserterBuilders {
+		list = append(list, asserterType)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// NewTokenAsserter creates a token asserter of the type
+func NewTokenAsserter(asserterType string, props map[string]interface{}) (TokenAsserter, error) {
+	asserterBuildersMu.RLock()
+	asserterBuilder, ok := asserterBuilders[asserterType]
+	asserterBuildersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown asserter type %q", asserterType)
+	}
+	return asserterBuilder.NewTokenAsserter(props)
+}
+
+// decodeProps decodes asserter properties into a configuration struct
+func decodeProps(props map[string]interface{}, conf interface{}) error {
+	raw, err := json.Marshal(props)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, conf); err != nil {
+		return fmt.Errorf("invalid asserter properties: %v", err)
+	}
+	return nil
+}
+
+// Props converts an asserter configuration to asserter properties
+func Props(conf interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(conf)
+	if err != nil {
+		return nil, err
+	}
+	var props map[string]interface{}
+	err = json.Unmarshal(raw, &props)
+	return props, err
+}
@@ -0,0 +1,174 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RouterConfig configures the token asserters and which of them assert each token type
+type RouterConfig struct {
+	Asserters    []*AsserterDefinition `json:"asserters"`
+	Chains       map[string][]string   `json:"chains,omitempty"`       //token type to names of the asserters tried in order
+	DefaultChain []string              `json:"defaultChain,omitempty"` //asserters of token types without chain
+	Cache        *SubjectCacheConfig   `json:"cache,omitempty"`
+}
+
+// instrumentedAsserter counts the calls, failures and latency of an asserter
+type instrumentedAsserter struct {
+	name     string
+	typ      string
+	asserter TokenAsserter
+
+	calls        int64
+	failures     int64
+	totalLatency int64 //in nanoseconds
+	maxLatency   int64
+}
+
+func (a *instrumentedAsserter) AssertToken(token string, idpType string, allowedIDD string, requestHeaders map[string]string) (*AssertResponse, error) {
+	start := time.Now()
+	ar, err := a.asserter.AssertToken(token, idpType, allowedIDD, requestHeaders)
+	latency := int64(time.Since(start))
+
+	atomic.AddInt64(&a.calls, 1)
+	atomic.AddInt64(&a.totalLatency, latency)
+	for {
+		max := atomic.LoadInt64(&a.maxLatency)
+		if latency <= max || atomic.CompareAndSwapInt64(&a.maxLatency, max, latency) {
+			break
+		}
+	}
+	if err != nil {
+		atomic.AddInt64(&a.failures, 1)
+	}
+	return ar, err
+}
+
+func (a *instrumentedAsserter) stats() *adsapi.AsserterStats {
+	stats := adsapi.AsserterStats{
+		Name:         a.name,
+		Type:         a.typ,
+		Calls:        atomic.LoadInt64(&a.calls),
+		Failures:     atomic.LoadInt64(&a.failures),
+		MaxLatencyMs: float64(atomic.LoadInt64(&a.maxLatency)) / float64(time.Millisecond),
+	}
+	if stats.Calls > 0 {
+		stats.AvgLatencyMs = float64(atomic.LoadInt64(&a.totalLatency)) / float64(stats.Calls) / float64(time.Millisecond)
+	}
+	return &stats
+}
+
+// Router asserts tokens with the chain of asserters configured for their token type. The
+// asserters of a chain are tried in order until one of them asserts the token. Asserted
+// subjects are cached by the hash of the token until the token expires.
+type Router struct {
+	asserters    []*instrumentedAsserter
+	chains       map[string][]*instrumentedAsserter
+	defaultChain []*instrumentedAsserter
+	cache        *subjectCache
+}
+
+// NewRouter creates the asserters and chains of the configuration
+func NewRouter(conf *RouterConfig) (*Router, error) {
+	if conf == nil {
+		return nil, fmt.Errorf("asserter router configuration is nil")
+	}
+	r := Router{
+		chains: make(map[string][]*instrumentedAsserter),
+		cache:  newSubjectCache(conf.Cache),
+	}
+	byName := make(map[string]*instrumentedAsserter)
+	for _, def := range conf.Asserters {
+		if len(def.Name) == 0 {
+			return nil, fmt.Errorf("asserter name is empty")
+		}
+		if _, dup := byName[def.Name]; dup {
+			return nil, fmt.Errorf("duplicated asserter %q", def.Name)
+		}
+		asserter, err := NewTokenAsserter(def.Type, def.Props)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create asserter %q: %v", def.Name, err)
+		}
+		a := &instrumentedAsserter{name: def.Name, typ: def.Type, asserter: asserter}
+		byName[def.Name] = a
+		r.asserters = append(r.asserters, a)
+	}
+
+	chain := func(names []string) ([]*instrumentedAsserter, error) {
+		var asserters []*instrumentedAsserter
+		for _, name := range names {
+			a, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown asserter %q in chain", name)
+			}
+			asserters = append(asserters, a)
+		}
+		return asserters, nil
+	}
+	for tokenType, names := range conf.Chains {
+		asserters, err := chain(names)
+		if err != nil {
+			return nil, err
+		}
+		r.chains[strings.ToLower(tokenType)] = asserters
+	}
+	var err error
+	if r.defaultChain, err = chain(conf.DefaultChain); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// AssertToken asserts token with the asserters configured for the token type
+func (r *Router) AssertToken(token string, idpType string, allowedIDD string, requestHeaders map[string]string) (*AssertResponse, error) {
+	chain, ok := r.chains[strings.ToLower(idpType)]
+	if !ok {
+		chain = r.defaultChain
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no asserter for token type %s", idpType)
+	}
+
+	var key string
+	if r.cache != nil {
+		key = subjectCacheKey(token, strings.ToLower(idpType), allowedIDD)
+		if ar, ok := r.cache.get(key); ok {
+			return ar, nil
+		}
+	}
+
+	var err error
+	for _, a := range chain {
+		var ar *AssertResponse
+		ar, err = a.AssertToken(token, idpType, allowedIDD, requestHeaders)
+		if err == nil {
+			if r.cache != nil {
+				r.cache.add(key, ar)
+			}
+			return ar, nil
+		}
+		log.Debugf("asserter %s failed to assert token of type %s: %v", a.name, idpType, err)
+	}
+	return nil, err
+}
+
+// Status returns the statistics of the asserters and of the subject cache
+func (r *Router) Status() *adsapi.AsserterStatus {
+	status := adsapi.AsserterStatus{Asserters: []*adsapi.AsserterStats{}}
+	for _, a := range r.asserters {
+		status.Asserters = append(status.Asserters, a.stats())
+	}
+	if r.cache != nil {
+		status.CacheEntries, status.CacheHits, status.CacheMisses = r.cache.stats()
+	}
+	return &status
+}
@@ -0,0 +1,156 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package assertion
+
+import (
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+)
+
+func TestRouter(t *testing.T) {
+	server := NewTestServer(t, nil)
+	defer server.Close()
+
+	router, err := NewRouter(&RouterConfig{
+		Asserters: []*AsserterDefinition{
+			{
+				Name:  "idp",
+				Type:  JWTAsserterType,
+				Props: map[string]interface{}{"staticKeys": []interface{}{map[string]interface{}{"secret": "top-secret"}}},
+			},
+			{
+				Name: "keys",
+				Type: APIKeyAsserterType,
+				Props: map[string]interface{}{
+					"keys": map[string]interface{}{
+						"key-1": map[string]interface{}{
+							"principals": []interface{}{map[string]interface{}{"type": "entity", "name": "batch"}},
+						},
+						apiKeyHashPrefix + hashAPIKey("key-2"): map[string]interface{}{
+							"principals": []interface{}{map[string]interface{}{"type": "entity", "name": "cron"}},
+						},
+					},
+				},
+			},
+			{
+				Name:  "webhook",
+				Type:  WebhookAsserterType,
+				Props: map[string]interface{}{"endpoint": server.URL + "/assert"},
+			},
+		},
+		Chains: map[string][]string{
+			"JWT":    {"idp"},
+			"bearer": {"idp", "keys"},
+		},
+		DefaultChain: []string{"webhook"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := signToken(t, jwt.SigningMethodHS256, "", []byte("top-secret"), validClaims())
+	testCases := []struct {
+		token     string
+		tokenType string
+		principal string
+	}{
+		{token, "jwt", "alice"},
+		{token, "bearer", "alice"},
+		// falls back to API keys
+		{"key-1", "bearer", "batch"},
+		{"key-2", "bearer", "cron"},
+		{"key-1", "jwt", ""},
+		{"testtoken", "WERCKER", "testUser"},
+		{"test-token", "WERCKER", ""},
+	}
+	for i, tc := range testCases {
+		ar, err := router.AssertToken(tc.token, tc.tokenType, "", nil)
+		if len(tc.principal) == 0 {
+			if err == nil {
+				t.Errorf("case %d: token should be rejected, got %v", i, ar)
+			}
+			continue
+		}
+		if err != nil || ar.Principals[0].Name != tc.principal {
+			t.Errorf("case %d: expected %s, got %v, %v", i, tc.principal, ar, err)
+		}
+	}
+
+	// All assertions above are cached except the failures
+	for i, tc := range testCases {
+		if len(tc.principal) > 0 {
+			if ar, err := router.AssertToken(tc.token, tc.tokenType, "", nil); err != nil || ar.Principals[0].Name != tc.principal {
+				t.Errorf("case %d: expected cached %s, got %v, %v", i, tc.principal, ar, err)
+			}
+		}
+	}
+	status := router.Status()
+	expected := map[string]adsapi.AsserterStats{
+		"idp":     {Calls: 5, Failures: 3},
+		"keys":    {Calls: 2, Failures: 0},
+		"webhook": {Calls: 2, Failures: 1},
+	}
+	for _, stats := range status.Asserters {
+		if stats.Calls != expected[stats.Name].Calls || stats.Failures != expected[stats.Name].Failures {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	}
+	if status.CacheEntries != 5 || status.CacheHits != 5 || status.CacheMisses != 7 {
+		t.Errorf("unexpected cache stats %+v", status)
+	}
+
+	// Cached subjects can't be modified by callers
+	ar, _ := router.AssertToken("key-1", "bearer", "", nil)
+	ar.Principals[0].Name = "admin"
+	if ar, _ := router.AssertToken("key-1", "bearer", "", nil); ar.Principals[0].Name != "batch" {
+		t.Errorf("cached subject is modified: %v", ar.Principals[0])
+	}
+
+	if _, err := NewRouter(&RouterConfig{DefaultChain: []string{"missing"}}); err == nil {
+		t.Error("unknown asserter in chain should be rejected")
+	}
+	if _, err := NewRouter(&RouterConfig{Asserters: []*AsserterDefinition{{Name: "a", Type: "saml"}}}); err == nil {
+		t.Error("unknown asserter type should be rejected")
+	}
+}
+
+func TestSubjectCacheExpiry(t *testing.T) {
+	cache := newSubjectCache(&SubjectCacheConfig{MaxTTL: 60, MaxEntries: 2})
+	subject := func(name string, expiresAt int64) *AssertResponse {
+		return &AssertResponse{
+			Principals: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_USER, Name: name}},
+			ExpiresAt:  expiresAt,
+		}
+	}
+
+	cache.add("expired", subject("a", time.Now().Unix()-1))
+	if _, ok := cache.get("expired"); ok {
+		t.Error("expired subject should not be cached")
+	}
+
+	cache.add("b", subject("b", 0))
+	cache.add("c", subject("c", time.Now().Unix()+3600))
+	cache.get("b")
+	cache.add("d", subject("d", 0))
+	if _, ok := cache.get("c"); ok {
+		t.Error("least recently used subject should be evicted")
+	}
+	if _, ok := cache.get("b"); !ok {
+		t.Error("recently used subject should be kept")
+	}
+
+	// Subjects expire with tokens
+	cache.add("e", subject("e", time.Now().Unix()+1))
+	time.Sleep(1100 * time.Millisecond)
+	if _, ok := cache.get("e"); ok {
+		t.Error("subject should expire with the token")
+	}
+
+	if newSubjectCache(&SubjectCacheConfig{Disabled: true}) != nil {
+		t.Error("disabled cache should be nil")
+	}
+}
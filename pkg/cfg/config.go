@@ -7,9 +7,14 @@ import (
 	"encoding/json"
 	"io/ioutil"
 
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
 	"github.com/teramoby/speedle-plus/pkg/assertion"
+	"github.com/teramoby/speedle-plus/pkg/decisionlog"
 	"github.com/teramoby/speedle-plus/pkg/errors"
 	"github.com/teramoby/speedle-plus/pkg/logging"
+	"github.com/teramoby/speedle-plus/pkg/pip"
+	"github.com/teramoby/speedle-plus/pkg/store"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
 )
 
 const (
@@ -22,23 +27,86 @@ type StoreConfig struct {
 }
 
 type ServerConfig struct {
-	Endpoint        string `json:"endpoint,omitempty"`
-	Insecure        string `json:"insecure,omitempty"`
-	EnableAuthz     string `json:"enableAuthz,omitempty"`
-	KeyPath         string `json:"keyPath,omitempty"`
-	CertPath        string `json:"certPath,omitempty"`
-	ClientCertPath  string `json:"clientCertPath,omitempty"`
-	ForceClientCert bool   `json:"forceClientCert,omitempty"`
+	Endpoint           string                        `json:"endpoint,omitempty"`
+	Insecure           string                        `json:"insecure,omitempty"`
+	EnableAuthz        string                        `json:"enableAuthz,omitempty"`
+	KeyPath            string                        `json:"keyPath,omitempty"`
+	CertPath           string                        `json:"certPath,omitempty"`
+	ClientCertPath     string                        `json:"clientCertPath,omitempty"`
+	ForceClientCert    bool                          `json:"forceClientCert,omitempty"`
+	ClientCertIdentity *assertion.CertIdentityConfig `json:"clientCertIdentity,omitempty"` //maps verified client certificates to entities
+}
+
+// TokenAttributesConfig controls how attributes returned by the token asserter are used in conditions
+type TokenAttributesConfig struct {
+	Disabled      bool   `json:"disabled,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`     //prefix of asserted attribute names, "token" by default
+	Separator     string `json:"separator,omitempty"`     //between namespace and attribute name, "." by default
+	AllowOverride bool   `json:"allowOverride,omitempty"` //whether attributes sent by the caller override asserted attributes
+}
+
+// PMSAuthzConfig controls authentication and authorization of policy management callers, which is
+// enabled with ServerConfig.EnableAuthz. Operations are authorized by the policies of the admin service.
+type PMSAuthzConfig struct {
+	AdminService       string                        `json:"adminService,omitempty"`       //"speedle-admin" by default
+	Admins             []*adsapi.Principal           `json:"admins,omitempty"`             //principals allowed all operations, e.g. to bootstrap the admin service
+	Asserters          *assertion.RouterConfig       `json:"asserters,omitempty"`          //asserter chains of bearer and static tokens, by the scheme of the Authorization header
+	ClientCertIdentity *assertion.CertIdentityConfig `json:"clientCertIdentity,omitempty"` //maps verified client certificates to entities
+}
+
+// ADSAuthzConfig controls authentication and permissions of callers of the authorization decision service,
+// which is enabled with ServerConfig.EnableAuthz
+type ADSAuthzConfig struct {
+	Asserters          *assertion.RouterConfig       `json:"asserters,omitempty"`          //asserter chains of API keys and bearer tokens, by the scheme of the Authorization header
+	ClientCertIdentity *assertion.CertIdentityConfig `json:"clientCertIdentity,omitempty"` //maps verified client certificates to entities
+	Clients            []*ADSClientConfig            `json:"clients,omitempty"`
+}
+
+// ADSClientConfig is the permissions of a client of the authorization decision service
+type ADSClientConfig struct {
+	Name       string              `json:"name,omitempty"`
+	Principals []*adsapi.Principal `json:"principals"`           //caller principals identifying the client
+	Services   []string            `json:"services,omitempty"`   //services the client may query, "*" for all services
+	Operations []string            `json:"operations,omitempty"` //"check" by default, also "enumerate", "diagnose", "discover", "status" and "admin"
+}
+
+// ChangeRequestConfig controls the review of change requests of the policy management service
+type ChangeRequestConfig struct {
+	RequiredApprovals int      `json:"requiredApprovals,omitempty"` //approvals of reviewers other than the author before publishing, 1 by default
+	ProtectedServices []string `json:"protectedServices,omitempty"` //services whose policies are only changed by publishing change requests, other services are not reviewed
+}
+
+// DefaultDecisionConfig is the decision of services which don't declare their default effect or failure mode,
+// and of unknown services
+type DefaultDecisionConfig struct {
+	DefaultEffect string `json:"defaultEffect,omitempty"` //"deny" by default
+	FailureMode   string `json:"failureMode,omitempty"`   //"open" or "closed", errors deny requests without a failure mode
 }
 
 type Config struct {
-	StoreConfig           *StoreConfig              `json:"storeConfig"`
-	EnableWatch           bool                      `json:"enableWatch,omitempty"`
-	AsserterWebhookConfig *assertion.AsserterConfig `json:"asserterWebhookConfig,omitempty"`
-	FuncsvcEndpoint       string                    `json:"funcsvcEndpoint,omitempty"`
-	ServerConfig          *ServerConfig             `json:"serverConfig,omitempty"`
-	LogConfig             *logging.LogConfig        `json:"logConfig,omitempty"`
-	AuditLogConfig        *logging.LogConfig        `json:"auditLogConfig,omitempty"`
+	StoreConfig                 *StoreConfig                           `json:"storeConfig"`
+	EnableWatch                 bool                                   `json:"enableWatch,omitempty"`
+	AsserterWebhookConfig       *assertion.AsserterConfig              `json:"asserterWebhookConfig,omitempty"`
+	JWTAsserterConfig           *assertion.JWTAsserterConfig           `json:"jwtAsserterConfig,omitempty"`           //verifies JSON web tokens locally
+	IntrospectionAsserterConfig *assertion.IntrospectionAsserterConfig `json:"introspectionAsserterConfig,omitempty"` //asserts opaque OAuth tokens with token introspection
+	AsserterRouterConfig        *assertion.RouterConfig                `json:"asserterRouterConfig,omitempty"`        //asserter chains by token type, takes precedence over other asserter configurations
+	TokenAttributes             *TokenAttributesConfig                 `json:"tokenAttributes,omitempty"`
+	FuncsvcEndpoint             string                                 `json:"funcsvcEndpoint,omitempty"`
+	FuncClientCertPath          string                                 `json:"funcClientCertPath,omitempty"` //client certificate presented to grpcs customer functions
+	FuncClientKeyPath           string                                 `json:"funcClientKeyPath,omitempty"`
+	FuncCacheMaxEntries         int                                    `json:"funcCacheMaxEntries,omitempty"` //default max number of cached results per customer function
+	FuncCacheMaxBytes           int64                                  `json:"funcCacheMaxBytes,omitempty"`   //max estimated memory of all cached function results
+	AttributeProviders          []*pip.ProviderConfig                  `json:"attributeProviders,omitempty"`  //policy information points supplying attributes referenced by conditions
+	ServerConfig                *ServerConfig                          `json:"serverConfig,omitempty"`
+	PMSAuthzConfig              *PMSAuthzConfig                        `json:"pmsAuthzConfig,omitempty"`
+	ADSAuthzConfig              *ADSAuthzConfig                        `json:"adsAuthzConfig,omitempty"`
+	ChangeRequestConfig         *ChangeRequestConfig                   `json:"changeRequestConfig,omitempty"`
+	DefaultDecision             *DefaultDecisionConfig                 `json:"defaultDecision,omitempty"` //default effect and failure mode of the ADS
+	LogConfig                   *logging.LogConfig                     `json:"logConfig,omitempty"`
+	AuditLogConfig              *logging.LogConfig                     `json:"auditLogConfig,omitempty"`
+	TracingConfig               *tracing.Config                        `json:"tracingConfig,omitempty"`     //exporter and sampling of distributed tracing
+	DecisionLogConfig           *decisionlog.Config                    `json:"decisionLogConfig,omitempty"` //sinks, sampling and redaction of the ADS decision log
+	DiscoverConfig              *store.DiscoverConfig                  `json:"discoverConfig,omitempty"`    //retention of discover requests
 }
 
 func ReadConfig(configFileLocation string) (*Config, error) {
@@ -49,6 +49,9 @@ type Parameters struct {
 
 	// AsserterParameters asserter webhook configuration
 	AsserterConf AsserterParameters
+
+	// configuration read from the config file, keeps the sections without flags
+	fileConfig *cfg.Config
 }
 
 // LogParameters is the parameters for log configuration
@@ -120,6 +123,39 @@ func (k *Parameters) NewHTTPServer(handler http.Handler) (*http.Server, error) {
 }
 
 func (k *Parameters) newTLSServer(handler http.Handler) (*http.Server, error) {
+	tlsConfig, err := k.newTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	server := http.Server{
+		Addr:      k.Endpoint.Value,
+		Handler:   handler,
+		TLSConfig: tlsConfig,
+	}
+	return &server, nil
+}
+
+// NewGRPCTLSConfig returns the TLS configuration of gRPC servers, which has the same certificates
+// and client authentication as the REST server. It returns nil in insecure mode.
+func (k *Parameters) NewGRPCTLSConfig() (*tls.Config, error) {
+	insecure, _ := strconv.ParseBool(k.Insecure.Value)
+	if insecure {
+		return nil, nil
+	}
+	tlsConfig, err := k.newTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(k.CertPath.Value, k.KeyPath.Value)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ConfigError, "unable to load server certificate from files %s and %s", k.CertPath.Value, k.KeyPath.Value)
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return tlsConfig, nil
+}
+
+func (k *Parameters) newTLSConfig() (*tls.Config, error) {
 	// Set HTTPS client
 	tlsConfig := &tls.Config{}
 
@@ -146,13 +182,7 @@ func (k *Parameters) newTLSServer(handler http.Handler) (*http.Server, error) {
 	}
 
 	tlsConfig.BuildNameToCertificate()
-
-	server := http.Server{
-		Addr:      k.Endpoint.Value,
-		Handler:   handler,
-		TLSConfig: tlsConfig,
-	}
-	return &server, nil
+	return tlsConfig, nil
 }
 
 func (k *Parameters) ListenAndServe(s *http.Server) error {
@@ -271,6 +301,7 @@ func (k *Parameters) ParseFlags(defaultEndpoint string, printVersionInfoFun func
 			fmt.Fprintf(os.Stderr, "Fail to parse config file %s, error is %v. \n", k.ConfigFile.Value, err)
 			k.usage()
 		}
+		k.fileConfig = conf
 	} else {
 		conf = nil
 	}
@@ -488,6 +519,10 @@ func (k *Parameters) ValidateFlags() {
 func (k *Parameters) Param2Config(storeParamsMap map[string]string) (*cfg.Config, error) {
 
 	conf := cfg.Config{}
+	// Sections without flags are taken from the config file
+	if k.fileConfig != nil {
+		conf = *k.fileConfig
+	}
 
 	var storeConf cfg.StoreConfig
 	storeConf.StoreType = k.StoreType.Value
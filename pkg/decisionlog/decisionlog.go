@@ -0,0 +1,285 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+// Package decisionlog records the decisions of the authorization decision service as structured
+// records. Records are sampled per service, sensitive attributes and tokens are redacted, and the
+// records are written in batches to pluggable sinks like rotating files, webhooks and stdout.
+package decisionlog
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/suid"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Decisions of records
+const (
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
+)
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = 1 // in seconds
+)
+
+// Subject is the subject of a decision
+type Subject struct {
+	Principals []*adsapi.Principal `json:"principals,omitempty"`
+	TokenType  string              `json:"tokenType,omitempty"`
+	Token      string              `json:"token,omitempty"`
+}
+
+// Record is a decision of the authorization decision service
+type Record struct {
+	DecisionID   string                 `json:"decisionId"`
+	Timestamp    time.Time              `json:"timestamp"`
+	Service      string                 `json:"service"`
+	Subject      *Subject               `json:"subject,omitempty"`
+	Resource     string                 `json:"resource"`
+	Action       string                 `json:"action"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Decision     string                 `json:"decision"`
+	Reason       string                 `json:"reason"`
+	Error        string                 `json:"error,omitempty"`
+	Policies     []string               `json:"policies,omitempty"`     //IDs of the matched policies
+	RolePolicies []string               `json:"rolePolicies,omitempty"` //IDs of the matched role policies
+	LatencyMs    float64                `json:"latencyMs"`
+	TraceID      string                 `json:"traceId,omitempty"`
+}
+
+// Config is the decision log configuration of the authorization decision service
+type Config struct {
+	// Sinks the records are written to, the decision log is disabled if there is no sink
+	Sinks []*SinkConfig `json:"sinks,omitempty"`
+	// SampleRate is the ratio of decisions which are logged, 0 means 1
+	SampleRate float64 `json:"sampleRate,omitempty"`
+	// ServiceSampleRates overrides the sample rate of services, 0 disables the decision log of a service
+	ServiceSampleRates map[string]float64 `json:"serviceSampleRates,omitempty"`
+	// Redaction of attributes and tokens
+	Redaction     *RedactionConfig `json:"redaction,omitempty"`
+	BatchSize     int              `json:"batchSize,omitempty"`     //max number of records written to sinks at once, 100 by default
+	FlushInterval int              `json:"flushInterval,omitempty"` //in seconds, 1 by default. Pending records are written at least this often
+}
+
+// Logger samples and redacts decision records, and writes them to its sinks in the background
+type Logger struct {
+	dropped       uint64 // first for the alignment of atomic operations
+	sinks         []Sink
+	sampleRate    float64
+	serviceRates  map[string]float64
+	redactor      *redactor
+	batchSize     int
+	flushInterval time.Duration
+	records       chan *Record
+	done          chan struct{}
+	// guards records, which is closed when the logger is shut down
+	lock     sync.RWMutex
+	stopped  bool
+	stopOnce sync.Once
+}
+
+// NewLogger creates a logger writing to the sinks, with the sampling, redaction and batching of the
+// configuration
+func NewLogger(config *Config, sinks []Sink) (*Logger, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	if err := checkSampleRate(config.SampleRate); err != nil {
+		return nil, err
+	}
+	for _, rate := range config.ServiceSampleRates {
+		if err := checkSampleRate(rate); err != nil {
+			return nil, err
+		}
+	}
+	redactor, err := newRedactor(config.Redaction)
+	if err != nil {
+		return nil, err
+	}
+	l := &Logger{
+		sinks:         sinks,
+		sampleRate:    config.SampleRate,
+		serviceRates:  config.ServiceSampleRates,
+		redactor:      redactor,
+		batchSize:     config.BatchSize,
+		flushInterval: time.Duration(config.FlushInterval) * time.Second,
+		done:          make(chan struct{}),
+	}
+	if l.sampleRate == 0 {
+		l.sampleRate = 1
+	}
+	if l.batchSize <= 0 {
+		l.batchSize = defaultBatchSize
+	}
+	if l.flushInterval <= 0 {
+		l.flushInterval = defaultFlushInterval * time.Second
+	}
+	l.records = make(chan *Record, l.batchSize*10)
+	go l.run()
+	return l, nil
+}
+
+func checkSampleRate(rate float64) error {
+	if rate < 0 || rate > 1 {
+		return errors.Errorf(errors.ConfigError, "sample rate %v of decision log is not between 0 and 1", rate)
+	}
+	return nil
+}
+
+// Sampled decides whether a decision of the service is logged. It returns false for a nil logger, so
+// that callers only build records which are logged.
+func (l *Logger) Sampled(service string) bool {
+	if l == nil {
+		return false
+	}
+	rate, ok := l.serviceRates[service]
+	if !ok {
+		rate = l.sampleRate
+	}
+	if rate >= 1 {
+		return true
+	}
+	return rate > 0 && rand.Float64() < rate
+}
+
+// Log redacts the record and queues it to be written to the sinks. Records are dropped if the queue is
+// full, so that a slow sink doesn't slow down decisions.
+func (l *Logger) Log(record *Record) {
+	if l == nil {
+		return
+	}
+	if record.DecisionID == "" {
+		record.DecisionID = suid.New().String()
+	}
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+	l.redactor.redact(record)
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	if l.stopped {
+		return
+	}
+	select {
+	case l.records <- record:
+	default:
+		if atomic.AddUint64(&l.dropped, 1)%1000 == 1 {
+			log.Warnf("Decision log queue is full, %d records are dropped so far.", atomic.LoadUint64(&l.dropped))
+		}
+	}
+}
+
+// Dropped returns the number of records dropped because the queue is full
+func (l *Logger) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+func (l *Logger) run() {
+	defer close(l.done)
+	ticker := time.NewTicker(l.flushInterval)
+	defer ticker.Stop()
+	batch := make([]*Record, 0, l.batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			l.write(batch)
+			batch = make([]*Record, 0, l.batchSize)
+		}
+	}
+	for {
+		select {
+		case record, ok := <-l.records:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) >= l.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (l *Logger) write(records []*Record) {
+	for _, sink := range l.sinks {
+		if err := sink.Write(records); err != nil {
+			log.Warnf("Failed to write %d decision records: %v.", len(records), err)
+		}
+	}
+}
+
+// Shutdown writes the pending records and closes the sinks
+func (l *Logger) Shutdown() {
+	if l == nil {
+		return
+	}
+	l.stopOnce.Do(func() {
+		l.lock.Lock()
+		l.stopped = true
+		close(l.records)
+		l.lock.Unlock()
+		<-l.done
+		for _, sink := range l.sinks {
+			if err := sink.Close(); err != nil {
+				log.Warnf("Failed to close decision log sink: %v.", err)
+			}
+		}
+	})
+}
+
+var globalLogger atomic.Value // *Logger
+
+// SetLogger sets the decision logger of the process, nil disables the decision log
+func SetLogger(l *Logger) {
+	globalLogger.Store(&l)
+}
+
+// GetLogger returns the decision logger of the process, or nil if the decision log is disabled
+func GetLogger() *Logger {
+	if l, ok := globalLogger.Load().(**Logger); ok {
+		return *l
+	}
+	return nil
+}
+
+// Init sets the decision logger of the process from the configuration. The decision log is disabled
+// if the configuration is nil or has no sink. The returned logger should be shut down when the process
+// exits, it is nil if the decision log is disabled.
+func Init(config *Config) (*Logger, error) {
+	if config == nil || len(config.Sinks) == 0 {
+		SetLogger(nil)
+		return nil, nil
+	}
+	sinks := make([]Sink, 0, len(config.Sinks))
+	closeSinks := func() {
+		for _, sink := range sinks {
+			sink.Close()
+		}
+	}
+	for _, sinkConfig := range config.Sinks {
+		sink, err := NewSink(sinkConfig)
+		if err != nil {
+			closeSinks()
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	logger, err := NewLogger(config, sinks)
+	if err != nil {
+		closeSinks()
+		return nil, err
+	}
+	SetLogger(logger)
+	log.Infof("Decision log enabled, sinks: %d, sample rate: %v.", len(sinks), logger.sampleRate)
+	return logger, nil
+}
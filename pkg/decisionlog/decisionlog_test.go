@@ -0,0 +1,177 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package decisionlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/natefinch/lumberjack"
+)
+
+func TestSampling(t *testing.T) {
+	logger, err := NewLogger(&Config{SampleRate: 0.5, ServiceSampleRates: map[string]float64{"all": 1, "none": 0}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.Shutdown()
+	sampled := 0
+	for i := 0; i < 1000; i++ {
+		if !logger.Sampled("all") || logger.Sampled("none") {
+			t.Fatal("service sample rates should override the sample rate")
+		}
+		if logger.Sampled("other") {
+			sampled++
+		}
+	}
+	if sampled < 400 || sampled > 600 {
+		t.Errorf("about half of the decisions should be sampled, got %d of 1000", sampled)
+	}
+	if (*Logger)(nil).Sampled("all") {
+		t.Error("nothing should be sampled when the decision log is disabled")
+	}
+	if _, err := NewLogger(&Config{ServiceSampleRates: map[string]float64{"crm": 2}}, nil); err == nil {
+		t.Error("sample rates above 1 should be rejected")
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	newRecord := func() *Record {
+		return &Record{
+			Subject:    &Subject{TokenType: "jwt", Token: "secret-token"},
+			Attributes: map[string]interface{}{"ssn": "123-45-6789", "api_token": "t1", "dept": "eng"},
+		}
+	}
+	tests := []struct {
+		config *RedactionConfig
+		token  string
+		ssn    string // empty if the attribute is removed
+	}{
+		{nil, redactedValue, "123-45-6789"},
+		{&RedactionConfig{Attributes: []string{"ssn", "*_token"}}, redactedValue, redactedValue},
+		{&RedactionConfig{Attributes: []string{"ssn"}, Mode: RedactRemove, KeepTokens: true}, "secret-token", ""},
+		{&RedactionConfig{Attributes: []string{"ssn"}, Mode: RedactHash}, "sha256:", "sha256:"},
+	}
+	for i, test := range tests {
+		r, err := newRedactor(test.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record := newRecord()
+		attributes := record.Attributes
+		r.redact(record)
+		if !strings.HasPrefix(record.Subject.Token, test.token) {
+			t.Errorf("case %d: unexpected token %q", i, record.Subject.Token)
+		}
+		ssn, ok := record.Attributes["ssn"].(string)
+		if ok == (test.ssn == "") || !strings.HasPrefix(ssn, test.ssn) {
+			t.Errorf("case %d: unexpected ssn %v", i, ssn)
+		}
+		if record.Attributes["dept"] != "eng" || attributes["ssn"] != "123-45-6789" {
+			t.Errorf("case %d: only the attributes of the record should be redacted, %v", i, record.Attributes)
+		}
+	}
+	if _, err := newRedactor(&RedactionConfig{Mode: "scramble"}); err == nil {
+		t.Error("unsupported redaction mode should be rejected")
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var requests, received int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first request fails
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer siem" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var records []*Record
+		if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		atomic.AddInt32(&received, int32(len(records)))
+	}))
+	defer server.Close()
+
+	logger, err := Init(&Config{Sinks: []*SinkConfig{{
+		Type:    SinkWebhook,
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer siem"},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetLogger(nil)
+	for i := 0; i < 3; i++ {
+		GetLogger().Log(&Record{Service: "crm", Decision: DecisionAllow})
+	}
+	logger.Shutdown()
+	if received != 3 || requests != 2 {
+		t.Errorf("the batch should be retried, received %d records in %d requests", received, requests)
+	}
+
+	// Client errors aren't retried
+	atomic.StoreInt32(&requests, 1)
+	sink, _ := NewWebhookSink(&SinkConfig{URL: server.URL})
+	if err := sink.Write([]*Record{{Service: "crm"}}); err == nil || requests != 2 {
+		t.Errorf("unauthorized request shouldn't be retried, %d requests, err: %v", requests, err)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "decisionlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "decisions.log")
+
+	logger, err := Init(&Config{Sinks: []*SinkConfig{{Type: SinkFile, RotationConfig: &lumberjack.Logger{Filename: file}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetLogger(nil)
+	logger.Log(&Record{Service: "crm", Decision: DecisionAllow})
+	logger.Log(&Record{Service: "crm", Decision: DecisionDeny})
+	logger.Shutdown()
+	// records logged after the shutdown are dropped
+	logger.Log(&Record{Service: "crm"})
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var decisions []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("unexpected line %s", scanner.Text())
+		}
+		if record.DecisionID == "" || record.Timestamp.IsZero() {
+			t.Errorf("decision ID and timestamp should be set, %+v", record)
+		}
+		decisions = append(decisions, record.Decision)
+	}
+	if strings.Join(decisions, ",") != "allow,deny" {
+		t.Errorf("unexpected decisions %v", decisions)
+	}
+
+	if _, err := Init(&Config{Sinks: []*SinkConfig{{Type: "kafka"}}}); err == nil {
+		t.Error("unsupported sink should be rejected")
+	}
+}
@@ -0,0 +1,100 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package decisionlog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+
+	"github.com/teramoby/speedle-plus/pkg/errors"
+)
+
+// Redaction modes
+const (
+	RedactMask   = "mask"
+	RedactHash   = "hash"
+	RedactRemove = "remove"
+)
+
+const redactedValue = "***"
+
+// RedactionConfig is the redaction of the sensitive values of records
+type RedactionConfig struct {
+	// Attributes are the names of the attributes to redact, * matches any characters, like "*_token"
+	Attributes []string `json:"attributes,omitempty"`
+	// Mode is how values are redacted: mask (default) replaces them with ***, hash replaces them with
+	// their SHA-256 digests so that they can still be correlated, remove removes them
+	Mode string `json:"mode,omitempty"`
+	// KeepTokens keeps identity tokens in records, tokens are redacted with the mode by default
+	KeepTokens bool `json:"keepTokens,omitempty"`
+}
+
+type redactor struct {
+	patterns   []string
+	mode       string
+	keepTokens bool
+}
+
+func newRedactor(config *RedactionConfig) (*redactor, error) {
+	if config == nil {
+		config = &RedactionConfig{}
+	}
+	r := &redactor{patterns: config.Attributes, mode: config.Mode, keepTokens: config.KeepTokens}
+	switch r.mode {
+	case "":
+		r.mode = RedactMask
+	case RedactMask, RedactHash, RedactRemove:
+	default:
+		return nil, errors.Errorf(errors.ConfigError, "unsupported decision log redaction mode %q", r.mode)
+	}
+	for _, pattern := range r.patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, errors.ConfigError, "invalid redacted attribute %q", pattern)
+		}
+	}
+	return r, nil
+}
+
+// redact redacts the record in place. The attributes are copied before they are redacted, as they
+// may be shared with the request.
+func (r *redactor) redact(record *Record) {
+	if record.Subject != nil && record.Subject.Token != "" && !r.keepTokens {
+		if r.mode == RedactRemove {
+			record.Subject.Token = ""
+		} else {
+			record.Subject.Token = r.redactValue(record.Subject.Token)
+		}
+	}
+	if len(r.patterns) == 0 || len(record.Attributes) == 0 {
+		return
+	}
+	attributes := make(map[string]interface{}, len(record.Attributes))
+	for name, value := range record.Attributes {
+		if !r.matches(name) {
+			attributes[name] = value
+		} else if r.mode != RedactRemove {
+			attributes[name] = r.redactValue(value)
+		}
+	}
+	record.Attributes = attributes
+}
+
+func (r *redactor) matches(name string) bool {
+	for _, pattern := range r.patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *redactor) redactValue(value interface{}) string {
+	if r.mode == RedactHash {
+		sum := sha256.Sum256([]byte(fmt.Sprint(value)))
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return redactedValue
+}
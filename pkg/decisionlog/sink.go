@@ -0,0 +1,215 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package decisionlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/teramoby/speedle-plus/pkg/errors"
+
+	"github.com/natefinch/lumberjack"
+	log "github.com/sirupsen/logrus"
+)
+
+// Sinks shipped with speedle
+const (
+	SinkFile    = "file"
+	SinkWebhook = "webhook"
+	SinkStdout  = "stdout"
+)
+
+const (
+	defaultWebhookTimeout    = 10 // in seconds
+	defaultWebhookMaxRetries = 3
+	webhookMinBackoff        = 500 * time.Millisecond
+)
+
+// SinkConfig is the configuration of a sink
+type SinkConfig struct {
+	// Type is the name of the sink, like file, webhook or stdout
+	Type string `json:"type"`
+	// RotationConfig is the file and its rotation of the file sink
+	RotationConfig *lumberjack.Logger `json:"rotationConfig,omitempty"`
+	// URL the webhook sink posts batches of records to, as JSON arrays
+	URL string `json:"url,omitempty"`
+	// Headers are sent with each request of the webhook sink, for example authorization headers
+	Headers    map[string]string `json:"headers,omitempty"`
+	Timeout    int               `json:"timeout,omitempty"`    //in seconds, 10 by default
+	MaxRetries int               `json:"maxRetries,omitempty"` //retries of a failed batch, 3 by default, negative disables retries
+}
+
+// Sink writes decision records
+type Sink interface {
+	// Write writes a batch of records
+	Write(records []*Record) error
+	// Close releases the resources of the sink
+	Close() error
+}
+
+// SinkBuilder builds a sink from the configuration
+type SinkBuilder func(config *SinkConfig) (Sink, error)
+
+var (
+	sinksLock sync.RWMutex
+	sinks     = map[string]SinkBuilder{
+		SinkFile: func(config *SinkConfig) (Sink, error) {
+			return NewFileSink(config.RotationConfig)
+		},
+		SinkWebhook: func(config *SinkConfig) (Sink, error) {
+			return NewWebhookSink(config)
+		},
+		SinkStdout: func(config *SinkConfig) (Sink, error) {
+			return NewWriterSink(os.Stdout), nil
+		},
+	}
+)
+
+// RegisterSink registers a sink, which can then be set in the configuration by its type
+func RegisterSink(typ string, builder SinkBuilder) {
+	sinksLock.Lock()
+	defer sinksLock.Unlock()
+	sinks[typ] = builder
+}
+
+// NewSink builds the sink of the configuration
+func NewSink(config *SinkConfig) (Sink, error) {
+	if config == nil {
+		return nil, errors.New(errors.ConfigError, "decision log sink is not configured")
+	}
+	sinksLock.RLock()
+	builder, ok := sinks[config.Type]
+	sinksLock.RUnlock()
+	if !ok {
+		return nil, errors.Errorf(errors.ConfigError, "unsupported decision log sink %q", config.Type)
+	}
+	return builder(config)
+}
+
+// WriterSink writes records as JSON lines
+type WriterSink struct {
+	sync.Mutex
+	w       io.Writer
+	encoder *json.Encoder
+}
+
+// NewWriterSink creates a sink writing records to the writer, which is closed with the sink if it is
+// an io.Closer other than stdout
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w, encoder: json.NewEncoder(w)}
+}
+
+// NewFileSink creates a sink writing records to a file, which is rotated with the configuration
+func NewFileSink(rotation *lumberjack.Logger) (*WriterSink, error) {
+	if rotation == nil || rotation.Filename == "" {
+		return nil, errors.New(errors.ConfigError, "file name of the decision log file sink is not set")
+	}
+	return NewWriterSink(rotation), nil
+}
+
+// Write writes the records
+func (s *WriterSink) Write(records []*Record) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, record := range records {
+		if err := s.encoder.Encode(record); err != nil {
+			return errors.Wrap(err, errors.LoggingError, "failed to write decision record")
+		}
+	}
+	return nil
+}
+
+// Close closes the writer
+func (s *WriterSink) Close() error {
+	if c, ok := s.w.(io.Closer); ok && s.w != os.Stdout {
+		return c.Close()
+	}
+	return nil
+}
+
+// WebhookSink posts batches of records as JSON arrays, failed batches are retried with backoff
+type WebhookSink struct {
+	url        string
+	headers    map[string]string
+	maxRetries int
+	client     *http.Client
+}
+
+// NewWebhookSink creates a webhook sink
+func NewWebhookSink(config *SinkConfig) (*WebhookSink, error) {
+	if config.URL == "" {
+		return nil, errors.New(errors.ConfigError, "URL of the decision log webhook sink is not set")
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	maxRetries := config.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultWebhookMaxRetries
+	} else if maxRetries < 0 {
+		maxRetries = 0
+	}
+	return &WebhookSink{
+		url:        config.URL,
+		headers:    config.Headers,
+		maxRetries: maxRetries,
+		client:     &http.Client{Timeout: time.Duration(timeout) * time.Second},
+	}, nil
+}
+
+// Write posts the records. Server errors and connection failures are retried, client errors are not.
+func (s *WebhookSink) Write(records []*Record) error {
+	body, err := json.Marshal(records)
+	if err != nil {
+		return errors.Wrap(err, errors.SerializationError, "failed to marshal decision records")
+	}
+	backoff := webhookMinBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= s.maxRetries {
+			return err
+		}
+		log.Debugf("Failed to post decision records (attempt %d), retrying in %v: %v", attempt+1, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post posts the body once, it returns whether a failure should be retried
+func (s *WebhookSink) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, errors.Wrap(err, errors.LoggingError, "failed to create decision log webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, errors.Wrap(err, errors.LoggingError, "failed to post decision records")
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, errors.Errorf(errors.LoggingError, "decision log webhook returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// Close does nothing
+func (s *WebhookSink) Close() error {
+	return nil
+}
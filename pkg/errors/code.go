@@ -15,6 +15,8 @@ const (
 	ServerError    ErrorCode = "SPDL-0002"
 	LoggingError   ErrorCode = "SPDL-0003"
 	InvalidRequest ErrorCode = "SPDL-0004"
+	Unauthorized   ErrorCode = "SPDL-0005"
+	Forbidden      ErrorCode = "SPDL-0006"
 )
 
 // For policy management errors
@@ -24,6 +26,7 @@ const (
 	EntityAlreadyExists ErrorCode = "SPDL-1003"
 	ExceedLimit         ErrorCode = "SPDL-1004"
 	SerializationError  ErrorCode = "SPDL-1005"
+	Conflict            ErrorCode = "SPDL-1006"
 )
 
 // For evaluator errors
@@ -33,4 +36,7 @@ const (
 	BuiltInFuncError  ErrorCode = "SPDL-2003"
 	CustomerFuncError ErrorCode = "SPDL-2004"
 	DiscoverError     ErrorCode = "SPDL-2005"
+	AttrProviderError ErrorCode = "SPDL-2006"
+	// the token asserter can't be reached or fails, unlike tokens rejected by the asserter
+	AsserterUnavailable ErrorCode = "SPDL-2007"
 )
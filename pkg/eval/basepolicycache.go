@@ -61,6 +61,54 @@ func (p *ResourceToPolicyMap) isEmpty() bool {
 	return true
 }
 
+// clone returns a deep copy of the index
+func (p *ResourceToPolicyMap) clone() *ResourceToPolicyMap {
+	if p == nil {
+		return nil
+	}
+	return &ResourceToPolicyMap{
+		ResourceToPolicies:           clonePolicyIDSets(p.ResourceToPolicies),
+		PrefixResourceExpressionTree: clonePolicyIDTree(p.PrefixResourceExpressionTree),
+		SuffixResourceExpressionTree: clonePolicyIDTree(p.SuffixResourceExpressionTree),
+		ResourceExpressionToPolicies: clonePolicyIDSets(p.ResourceExpressionToPolicies),
+		NilResourceToPolicies:        clonePolicyIDSet(p.NilResourceToPolicies),
+	}
+}
+
+func clonePolicyIDSet(set map[string]bool) map[string]bool {
+	if set == nil {
+		return nil
+	}
+	ret := make(map[string]bool, len(set))
+	for id := range set {
+		ret[id] = true
+	}
+	return ret
+}
+
+func clonePolicyIDSets(sets map[string]map[string]bool) map[string]map[string]bool {
+	if sets == nil {
+		return nil
+	}
+	ret := make(map[string]map[string]bool, len(sets))
+	for key, set := range sets {
+		ret[key] = clonePolicyIDSet(set)
+	}
+	return ret
+}
+
+func clonePolicyIDTree(tree *radix.Tree) *radix.Tree {
+	if tree == nil {
+		return nil
+	}
+	ret := radix.New()
+	tree.Walk(func(key string, value interface{}) bool {
+		ret.Insert(key, clonePolicyIDSet(value.(map[string]bool)))
+		return false
+	})
+	return ret
+}
+
 type BasePolicyCacheData struct {
 	/*
 		In current cache, we don't distinguish andPrincipals and orPrincipals.
@@ -88,6 +136,28 @@ func (p *BasePolicyCacheData) isEmpty() bool {
 	return true
 }
 
+// clone returns a deep copy of the index, the compiled conditions are shared
+func (p *BasePolicyCacheData) clone() BasePolicyCacheData {
+	ret := BasePolicyCacheData{
+		PrincipalToPolicies:    make(map[string]*ResourceToPolicyMap, len(p.PrincipalToPolicies)),
+		NilPrincipalToPolicies: p.NilPrincipalToPolicies.clone(),
+		Conditions:             copyConditions(p.Conditions),
+	}
+	for principal, resourceToPolicyMap := range p.PrincipalToPolicies {
+		ret.PrincipalToPolicies[principal] = resourceToPolicyMap.clone()
+	}
+	return ret
+}
+
+// copyConditions returns a copy of the compiled conditions of the policies
+func copyConditions(conditions map[string]*govaluate.EvaluableExpression) map[string]*govaluate.EvaluableExpression {
+	ret := make(map[string]*govaluate.EvaluableExpression, len(conditions))
+	for id, condition := range conditions {
+		ret[id] = condition
+	}
+	return ret
+}
+
 func (p *BasePolicyCacheData) clearConditions() {
 	p.Conditions = make(map[string]*govaluate.EvaluableExpression)
 }
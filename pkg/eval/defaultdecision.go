@@ -0,0 +1,58 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultDecision returns the default effect and the failure mode of a service. Services inherit the
+// global defaults of the ADS, which also apply to unknown services.
+func (p *PolicyEvalImpl) defaultDecision(serviceName string) (string, string) {
+	var defaultEffect, failureMode string
+	if p.DefaultDecision != nil {
+		defaultEffect, failureMode = p.DefaultDecision.DefaultEffect, p.DefaultDecision.FailureMode
+	}
+
+	p.RuntimePolicyStore.RLock()
+	defer p.RuntimePolicyStore.RUnlock()
+	if service, ok := p.RuntimePolicyStore.RuntimeServices[serviceName]; ok {
+		if len(service.DefaultEffect) > 0 {
+			defaultEffect = service.DefaultEffect
+		}
+		if len(service.FailureMode) > 0 {
+			failureMode = service.FailureMode
+		}
+	}
+	return defaultEffect, failureMode
+}
+
+// serviceMode returns the mode of a service, unknown services are enforced
+func (p *PolicyEvalImpl) serviceMode(serviceName string) string {
+	p.RuntimePolicyStore.RLock()
+	defer p.RuntimePolicyStore.RUnlock()
+	if service, ok := p.RuntimePolicyStore.RuntimeServices[serviceName]; ok && len(service.Mode) > 0 {
+		return service.Mode
+	}
+	return pms.ModeEnforce
+}
+
+// applyDefaultDecision replaces the decision when no policy applies, or when the evaluation fails
+func (p *PolicyEvalImpl) applyDefaultDecision(serviceName string, allowed bool, reason adsapi.Reason, err error) (bool, adsapi.Reason, error) {
+	switch reason {
+	case adsapi.NO_APPLICABLE_POLICIES, adsapi.SERVICE_NOT_FOUND:
+		if defaultEffect, _ := p.defaultDecision(serviceName); defaultEffect == pms.Grant {
+			return true, adsapi.DEFAULT_GRANT, nil
+		}
+	case adsapi.ERROR_IN_EVALUATION:
+		if _, failureMode := p.defaultDecision(serviceName); failureMode == pms.FailOpen {
+			log.Warnf("Evaluation of service %q failed open, err: %v", serviceName, err)
+			return true, adsapi.FAIL_OPEN, nil
+		}
+	}
+	return allowed, reason, err
+}
@@ -4,16 +4,40 @@ import (
 	"github.com/teramoby/speedle-plus/api/ads"
 	"github.com/teramoby/speedle-plus/pkg/errors"
 	"github.com/teramoby/speedle-plus/pkg/store"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
 	log "github.com/sirupsen/logrus"
 )
 
 func (p *PolicyEvalImpl) Discover(ctx ads.RequestContext) (bool, ads.Reason, error) {
+	// the shadow reason is only set for the requests of services in shadow mode
+	ctx.ShadowReason = ""
+	return true, ads.DISCOVER_MODE, p.saveDiscoverRequest(&ctx)
+}
+
+// shadow records a request of a service in shadow mode which would have been denied, with the reason of
+// the denial, and allows it
+func (p *PolicyEvalImpl) shadow(ctx *ads.RequestContext, reason ads.Reason, err error) (bool, ads.Reason, error) {
+	ctx.ShadowReason = reason.String()
+	if err != nil {
+		ctx.ShadowReason += ": " + err.Error()
+	}
+	if err := p.saveDiscoverRequest(ctx); err != nil {
+		log.Warnf("Request of service %q in shadow mode is not recorded, err: %v", ctx.ServiceName, err)
+	}
+	return true, ads.SHADOW_MODE, nil
+}
+
+func (p *PolicyEvalImpl) saveDiscoverRequest(ctx *ads.RequestContext) error {
 	if d, ok := p.Store.(store.DiscoverRequestManager); ok {
-		err := d.SaveDiscoverRequest(&ctx)
+		_, span := tracing.StartSpan(ctx.TraceContext, "discover-store", tracing.SpanKindClient)
+		span.SetAttribute("store.type", p.Store.Type())
+		err := d.SaveDiscoverRequest(ctx)
+		span.RecordError(err)
+		span.End()
 		if err != nil {
 			log.Warn("error in saving discover request, ", err)
 		}
-		return true, ads.DISCOVER_MODE, err
+		return err
 	}
-	return true, ads.DISCOVER_MODE, errors.Errorf(errors.DiscoverError, "unsupported store type of discovery function:%s", p.Store.Type())
+	return errors.Errorf(errors.DiscoverError, "unsupported store type of discovery function:%s", p.Store.Type())
 }
@@ -4,17 +4,26 @@
 package eval
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/teramoby/speedle-plus/3rdparty/github.com/Knetic/govaluate"
 	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/assertion"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/decisionlog"
 	"github.com/teramoby/speedle-plus/pkg/errors"
 	"github.com/teramoby/speedle-plus/pkg/eval/function"
+	"github.com/teramoby/speedle-plus/pkg/health"
+	"github.com/teramoby/speedle-plus/pkg/metrics"
+	"github.com/teramoby/speedle-plus/pkg/pip"
 	"github.com/teramoby/speedle-plus/pkg/subjectutils"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
 
 	"github.com/teramoby/speedle-plus/api/pms"
 
@@ -35,11 +44,26 @@ type TokenAsserter interface {
 	SetAsserterFunc(f func(ctx *adsapi.RequestContext) error)
 	// AssertToken assert token and generate subject to represent the identity
 	AssertToken(ctx *adsapi.RequestContext) error
+	// GetAsserterStatus returns call counters and latency of token asserters
+	GetAsserterStatus() *adsapi.AsserterStatus
+}
+
+type FunctionMonitor interface {
+	// GetFunctionStatus returns circuit breaker state and call counters of customer functions
+	GetFunctionStatus() []*adsapi.FunctionStatus
+	// GetFunctionCacheStats returns result cache statistics of customer functions
+	GetFunctionCacheStats() []*adsapi.FunctionCacheStats
+	// GetFunctionCacheStatsByName returns result cache statistics of a customer function
+	GetFunctionCacheStatsByName(funcName string) (*adsapi.FunctionCacheStats, error)
+	// FlushFunctionCache removes all cached results of a customer function
+	FlushFunctionCache(funcName string) error
 }
 
 type InternalEvaluator interface {
 	adsapi.PolicyEvaluator
 	TokenAsserter
+	FunctionMonitor
+	health.Checker
 }
 
 type internalRequestContext struct {
@@ -49,6 +73,14 @@ type internalRequestContext struct {
 	Resource      string
 	Action        string
 	Attributes    map[string]interface{}
+	// parameters conditions are evaluated with, which look up missing attributes from attribute providers
+	AttributeParams govaluate.Parameters
+	// errors of evaluating conditions, like failed customer functions
+	ConditionErrors []error
+	// carries the current span of the request
+	TraceContext context.Context
+	// collects the matched policies for the decision log, nil if the decision isn't logged
+	Matched *matchedPolicies
 }
 
 type subject struct {
@@ -64,6 +96,14 @@ type PolicyEvalImpl struct {
 	RuntimePolicyStore *RuntimePolicyStore //This is runtime policy store
 	Store              pms.PolicyStoreManagerADS
 	AsserterFunc       func(ctx *adsapi.RequestContext) error
+	Asserters          *assertion.Router
+	AttributeResolver  *pip.Resolver
+	TokenAttributes    *cfg.TokenAttributesConfig
+	DefaultDecision    *cfg.DefaultDecisionConfig
+	health             cacheHealth
+	// closed when the watch is stopped, so that it is not re-established
+	watchStop     chan struct{}
+	stopWatchOnce sync.Once
 }
 
 func (p *PolicyEvalImpl) deleteService(serviceName string) {
@@ -72,12 +112,21 @@ func (p *PolicyEvalImpl) deleteService(serviceName string) {
 }
 
 func (p *PolicyEvalImpl) fullReloadRuntimeCache() {
+	if err := p.reloadRuntimeCache(); err != nil {
+		log.Errorf("Fail to full reload runtime cache, err:%v", err)
+	}
+}
+
+// reloadRuntimeCache rebuilds the runtime cache from the policy store
+func (p *PolicyEvalImpl) reloadRuntimeCache() error {
 	ps, err := p.Store.ReadPolicyStore()
 	if err != nil {
-		log.Errorf("Fail to full reload runtime cache, err:%v", err)
-		return
+		p.health.cacheBuilt(err)
+		return err
 	}
 	p.RuntimePolicyStore.reloadPolicyStore(ps)
+	p.health.cacheBuilt(nil)
+	return nil
 }
 
 func (p *PolicyEvalImpl) Refresh() error {
@@ -126,7 +175,16 @@ func (p *PolicyEvalImpl) AssertToken(ctx *adsapi.RequestContext) error {
 		p.AsserterFunc != nil &&
 		len(ctx.Subject.TokenType) != 0 &&
 		len(ctx.Subject.Token) != 0 && !ctx.Subject.Asserted {
+		traceCtx := ctx.TraceContext
+		var span *tracing.Span
+		ctx.TraceContext, span = tracing.StartSpan(traceCtx, "token-assertion", tracing.SpanKindInternal)
+		span.SetAttribute("token.type", ctx.Subject.TokenType)
+		start := time.Now()
 		err := p.AsserterFunc(ctx)
+		metrics.ObserveAssertion(ctx.Subject.TokenType, start, err)
+		ctx.TraceContext = traceCtx
+		span.RecordError(err)
+		span.End()
 		if err == nil {
 			ctx.Subject.Asserted = true
 		}
@@ -137,7 +195,7 @@ func (p *PolicyEvalImpl) AssertToken(ctx *adsapi.RequestContext) error {
 }
 
 func (p *PolicyEvalImpl) populateContext(ctx *adsapi.RequestContext) (*internalRequestContext, error) {
-	service, err := p.getService(ctx.ServiceName)
+	service, globalService, err := p.snapshotServices(ctx.ServiceName)
 	if err != nil {
 		return nil, err
 	}
@@ -148,17 +206,13 @@ func (p *PolicyEvalImpl) populateContext(ctx *adsapi.RequestContext) (*internalR
 		return nil, err
 	}
 
-	var globalService *RuntimeService
-	if ctx.ServiceName != pms.GlobalService {
-		globalService, _ = p.getService(pms.GlobalService)
-	}
-
 	newCtx := internalRequestContext{
 		Resource:      ctx.Resource,
 		Action:        ctx.Action,
 		Service:       service,
 		GlobalService: globalService,
 		Attributes:    make(map[string]interface{}),
+		TraceContext:  ctx.TraceContext,
 	}
 
 	now := time.Now()
@@ -228,32 +282,73 @@ func (p *PolicyEvalImpl) populateContext(ctx *adsapi.RequestContext) (*internalR
 	for key, value := range ctx.Attributes {
 		newCtx.Attributes[key] = value
 	}
+	p.mergeAssertedAttributes(ctx, newCtx.Attributes)
 
 	updateSubjectWithBuiltInRoles(newCtx.Subject)
+	newCtx.AttributeParams = p.newAttributeParameters(ctx, &newCtx)
 
 	return &newCtx, nil
 }
 
 func (p *PolicyEvalImpl) IsAllowed(ctx adsapi.RequestContext) (bool, adsapi.Reason, error) {
+	start := time.Now()
+	mode := p.serviceMode(ctx.ServiceName)
+	if mode == pms.ModeDiscover {
+		return p.Discover(ctx)
+	}
+	logger := decisionlog.GetLogger()
+	var matched *matchedPolicies
+	if logger.Sampled(ctx.ServiceName) {
+		matched = &matchedPolicies{}
+	}
 	//IsAllowed don't need return EvaluationResult, so pass nil
-	return p.InternalIsAllowed(&ctx, nil)
+	allowed, reason, err := p.evaluate(&ctx, nil, matched)
+	allowed, reason, err = p.applyDefaultDecision(ctx.ServiceName, allowed, reason, err)
+	if mode == pms.ModeShadow && !allowed {
+		allowed, reason, err = p.shadow(&ctx, reason, err)
+	}
+	metrics.ObserveDecision(p.serviceLabel(ctx.ServiceName), allowed, reason)
+	if matched != nil {
+		logger.Log(newDecisionRecord(&ctx, allowed, reason, err, matched, start))
+	}
+	return allowed, reason, err
+}
+
+// IsAllowedByPolicies makes the decision of the policies of a service. Unlike IsAllowed, the request isn't
+// allowed by the shadow or discover mode, by the default effect or by failing open, and it isn't recorded.
+func (p *PolicyEvalImpl) IsAllowedByPolicies(ctx adsapi.RequestContext) (bool, adsapi.Reason, error) {
+	return p.evaluate(&ctx, nil, nil)
 }
 
 func (p *PolicyEvalImpl) InternalIsAllowed(ctx *adsapi.RequestContext, evaluationResult *adsapi.EvaluationResult) (bool, adsapi.Reason, error) {
-	p.RuntimePolicyStore.RLock()
-	defer p.RuntimePolicyStore.RUnlock()
+	allowed, reason, err := p.evaluate(ctx, evaluationResult, nil)
+	return p.applyDefaultDecision(ctx.ServiceName, allowed, reason, err)
+}
+
+// evaluate makes the decision of the policies of a service, the matched policies are collected if
+// matched isn't nil. The decision is made on a snapshot of the service, so that customer functions
+// are called without holding the locks of the policy store.
+func (p *PolicyEvalImpl) evaluate(ctx *adsapi.RequestContext, evaluationResult *adsapi.EvaluationResult, matched *matchedPolicies) (bool, adsapi.Reason, error) {
 	newCtx, err := p.populateContext(ctx)
 	if err != nil {
+		if p.hasService(ctx.ServiceName) {
+			// failed to assert the token, only an unavailable asserter may fail open
+			if errors.Code(err) == errors.AsserterUnavailable {
+				return false, adsapi.ERROR_IN_EVALUATION, err
+			}
+			return false, adsapi.TOKEN_REJECTED, err
+		}
 		return false, adsapi.SERVICE_NOT_FOUND, err
 	}
-	newCtx.Service.RLock()
-	defer newCtx.Service.RUnlock()
-	if newCtx.Service.PoliciesCache.isEmpty() {
+	newCtx.Matched = matched
+	// Diagnose still reports the inactive policies of a service without active policies
+	if newCtx.Service.PoliciesCache.isEmpty() && (evaluationResult == nil || len(newCtx.Service.PoliciesCache.InactivePolicyMap) == 0) {
 		return false, adsapi.NO_APPLICABLE_POLICIES, nil
 	}
 
 	if evaluationResult != nil {
 		evaluationResult.Attributes = newCtx.Attributes
+		p.recordAttributeSources(ctx, newCtx, evaluationResult)
 	}
 
 	if err := p.resolveSubject(newCtx, evaluationResult); err != nil {
@@ -264,8 +359,19 @@ func (p *PolicyEvalImpl) InternalIsAllowed(ctx *adsapi.RequestContext, evaluatio
 	if err != nil {
 		return false, adsapi.ERROR_IN_EVALUATION, err
 	}
+	if evaluationResult != nil {
+		addInactivePolicies(newCtx, evaluationResult)
+	}
+	matched.addPolicies(deniedPolicies)
+	matched.addPolicies(grantedPolicies)
 
 	allowed, reason := denyOverwriteCombiner(grantedPolicies, deniedPolicies, newCtx, evaluationResult)
+	// Failed conditions of services with a failure mode only leave the decisions of deny policies
+	if len(newCtx.ConditionErrors) > 0 && len(newCtx.Service.FailureMode) > 0 && reason != adsapi.DENY_POLICY_FOUND {
+		if newCtx.Service.FailureMode == pms.FailClosed || reason != adsapi.GRANT_POLICY_FOUND {
+			return false, adsapi.ERROR_IN_EVALUATION, errors.Wrap(newCtx.ConditionErrors[0], errors.EvalEngineError, "failed to evaluate condition")
+		}
+	}
 	return allowed, reason, nil
 }
 
@@ -284,19 +390,88 @@ func (p *PolicyEvalImpl) Diagnose(ctx adsapi.RequestContext) (*adsapi.Evaluation
 	allowed, reason, err := p.InternalIsAllowed(&ctx, &evaResult)
 	evaResult.Allowed = allowed
 	evaResult.Reason = reason
+	evaResult.Functions = p.getReferencedFunctionStatus(&evaResult)
 
 	return &evaResult, err
 }
 
-func (p *PolicyEvalImpl) GetAllGrantedRoles(ctx adsapi.RequestContext) ([]string, error) {
+func (p *PolicyEvalImpl) GetAsserterStatus() *adsapi.AsserterStatus {
+	if p.Asserters == nil {
+		return &adsapi.AsserterStatus{Asserters: []*adsapi.AsserterStats{}}
+	}
+	return p.Asserters.Status()
+}
+
+func (p *PolicyEvalImpl) GetFunctionStatus() []*adsapi.FunctionStatus {
+	return p.RuntimePolicyStore.FunctionBreakers.Status()
+}
+
+func (p *PolicyEvalImpl) GetFunctionCacheStats() []*adsapi.FunctionCacheStats {
+	return p.functionResultCache().Stats()
+}
+
+func (p *PolicyEvalImpl) GetFunctionCacheStatsByName(funcName string) (*adsapi.FunctionCacheStats, error) {
+	if stats, ok := p.functionResultCache().FuncStats(funcName); ok {
+		return stats, nil
+	}
+	if !p.hasFunction(funcName) {
+		return nil, errors.Errorf(errors.EntityNotFound, "customer function %q is not found", funcName)
+	}
+	// Nothing is cached yet
+	return &adsapi.FunctionCacheStats{Name: funcName}, nil
+}
+
+func (p *PolicyEvalImpl) FlushFunctionCache(funcName string) error {
+	if !p.functionResultCache().Flush(funcName) && !p.hasFunction(funcName) {
+		return errors.Errorf(errors.EntityNotFound, "customer function %q is not found", funcName)
+	}
+	return nil
+}
+
+func (p *PolicyEvalImpl) functionResultCache() *FuncResultCache {
 	p.RuntimePolicyStore.RLock()
 	defer p.RuntimePolicyStore.RUnlock()
+	return p.RuntimePolicyStore.FunctionResultCache
+}
+
+func (p *PolicyEvalImpl) hasFunction(funcName string) bool {
+	p.RuntimePolicyStore.RLock()
+	defer p.RuntimePolicyStore.RUnlock()
+	_, ok := p.RuntimePolicyStore.Functions[funcName]
+	return ok && builtinFunctions[funcName] == nil
+}
+
+// getReferencedFunctionStatus returns status of customer functions used in conditions of the evaluated policies
+func (p *PolicyEvalImpl) getReferencedFunctionStatus(evaResult *adsapi.EvaluationResult) []*adsapi.FunctionStatus {
+	conditions := []string{}
+	for _, policy := range evaResult.Policies {
+		if policy.Condition != nil {
+			conditions = append(conditions, policy.Condition.ConditionExpression)
+		}
+	}
+	for _, rolePolicy := range evaResult.RolePolicies {
+		if rolePolicy.Condition != nil {
+			conditions = append(conditions, rolePolicy.Condition.ConditionExpression)
+		}
+	}
+
+	ret := []*adsapi.FunctionStatus{}
+	for _, status := range p.GetFunctionStatus() {
+		for _, condition := range conditions {
+			if isFunctionReferenced(condition, status.Name) {
+				ret = append(ret, status)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+func (p *PolicyEvalImpl) GetAllGrantedRoles(ctx adsapi.RequestContext) ([]string, error) {
 	newCtx, err := p.populateContext(&ctx)
 	if err != nil {
 		return nil, err
 	}
-	newCtx.Service.RLock()
-	defer newCtx.Service.RUnlock()
 
 	ret, err := p.getGrantedRolesFromService(newCtx, nil)
 	return ret, err
@@ -304,15 +479,11 @@ func (p *PolicyEvalImpl) GetAllGrantedRoles(ctx adsapi.RequestContext) ([]string
 
 //Limitations: This function only calculate granted permissions with resource, will not calculate granted permissions with resource expression.
 func (p *PolicyEvalImpl) GetAllGrantedPermissions(ctx adsapi.RequestContext) ([]pms.Permission, error) {
-	p.RuntimePolicyStore.RLock()
-	defer p.RuntimePolicyStore.RUnlock()
 	newCtx, err := p.populateContext(&ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	newCtx.Service.RLock()
-	defer newCtx.Service.RUnlock()
 	if newCtx.Service.PoliciesCache.isEmpty() {
 		return []pms.Permission{}, nil
 	}
@@ -366,6 +537,32 @@ func (p *PolicyEvalImpl) getService(serviceName string) (*RuntimeService, error)
 	return nil, errors.Errorf(errors.EvalEngineError, "Application %s is not found ", serviceName)
 }
 
+// snapshotServices returns the snapshots of a service and of the global service, which evaluations
+// read without holding the locks of the policy store
+func (p *PolicyEvalImpl) snapshotServices(serviceName string) (*RuntimeService, *RuntimeService, error) {
+	p.RuntimePolicyStore.RLock()
+	defer p.RuntimePolicyStore.RUnlock()
+	service, err := p.getService(serviceName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var globalService *RuntimeService
+	if serviceName != pms.GlobalService {
+		if global, err := p.getService(pms.GlobalService); err == nil {
+			globalService = global.snapshot()
+		}
+	}
+	return service.snapshot(), globalService, nil
+}
+
+func (p *PolicyEvalImpl) hasService(serviceName string) bool {
+	p.RuntimePolicyStore.RLock()
+	defer p.RuntimePolicyStore.RUnlock()
+	_, exist := p.RuntimePolicyStore.RuntimeServices[serviceName]
+	return exist
+}
+
 func (p *PolicyEvalImpl) resolveSubject(ctx *internalRequestContext, evaluationResult *adsapi.EvaluationResult) error {
 	roles, err := p.getGrantedRolesFromService(ctx, evaluationResult)
 	if err != nil {
@@ -390,12 +587,12 @@ func (p *PolicyEvalImpl) getDirectRolePolices(principals []string,
 
 	grantedRolePolicies := make([]*pms.RolePolicy, 0)
 	deniedRolePolicies := make([]*pms.RolePolicy, 0)
-	grantedRolePolicies, deniedRolePolicies, err := p.getDirectRolePolicesInService(principals, ctx.Service, ctx.Resource, ctx.Attributes, policyIDMap, evaluationResult, grantedRolePolicies, deniedRolePolicies)
+	grantedRolePolicies, deniedRolePolicies, err := p.getDirectRolePolicesInService(principals, ctx.Service, ctx, policyIDMap, evaluationResult, grantedRolePolicies, deniedRolePolicies)
 	if err != nil {
 		return nil, nil, err
 	}
 	if ctx.GlobalService != nil {
-		grantedRolePolicies, deniedRolePolicies, err = p.getDirectRolePolicesInService(principals, ctx.GlobalService, ctx.Resource, ctx.Attributes, policyIDMap, evaluationResult, grantedRolePolicies, deniedRolePolicies)
+		grantedRolePolicies, deniedRolePolicies, err = p.getDirectRolePolicesInService(principals, ctx.GlobalService, ctx, policyIDMap, evaluationResult, grantedRolePolicies, deniedRolePolicies)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -404,7 +601,8 @@ func (p *PolicyEvalImpl) getDirectRolePolices(principals []string,
 }
 
 func (p *PolicyEvalImpl) getDirectRolePolicesInService(principals []string,
-	service *RuntimeService, resource string, attributes map[string]interface{}, policyIDMap map[string]bool, evaluationResult *adsapi.EvaluationResult, grantedRolePolicies []*pms.RolePolicy, deniedRolePolicies []*pms.RolePolicy) ([]*pms.RolePolicy, []*pms.RolePolicy, error) {
+	service *RuntimeService, ctx *internalRequestContext, policyIDMap map[string]bool, evaluationResult *adsapi.EvaluationResult, grantedRolePolicies []*pms.RolePolicy, deniedRolePolicies []*pms.RolePolicy) ([]*pms.RolePolicy, []*pms.RolePolicy, error) {
+	resource := ctx.Resource
 	for _, policy := range service.GetRelatedRolePolicyMap(principals, resource) {
 
 		if policyIDMap[policy.ID] {
@@ -425,13 +623,17 @@ func (p *PolicyEvalImpl) getDirectRolePolicesInService(principals []string,
 				}
 			}
 			if condition != nil {
-				result, _ = evaluateCondition(condition, attributes)
+				var err error
+				if result, err = p.RuntimePolicyStore.evaluateCondition(ctx.TraceContext, policy.ID, condition, ctx.AttributeParams); err != nil {
+					ctx.ConditionErrors = append(ctx.ConditionErrors, err)
+				}
 			}
 
 			if evaluationResult != nil {
 				evaluationResult.AddRolePolicy(policy, result)
 			}
 			if result {
+				ctx.Matched.addRolePolicy(policy)
 				switch policy.Effect {
 				case pms.Grant:
 					grantedRolePolicies = append(grantedRolePolicies, policy)
@@ -462,10 +664,9 @@ type Role struct {
 //assume ctx.Subject.Principals does not contain user defined roles,
 //assume built-in role like anonymous role and authenticated role can't be used in role policy
 func (p *PolicyEvalImpl) getGrantedRolesFromService(ctx *internalRequestContext, evaluationResult *adsapi.EvaluationResult) ([]string, error) {
-	if ctx.GlobalService != nil {
-		ctx.GlobalService.RLock()
-		defer ctx.GlobalService.RUnlock()
-	}
+	span, endSpan := ctx.startSpan("role-resolution")
+	defer endSpan()
+	span.SetAttribute("service", ctx.Service.Name)
 
 	relatedRolesMap := make(map[string]*Role) //contain all role info related to the role calculation
 	policyIDMap := make(map[string]bool)      // this is to avoid repeat processing of same policy.
@@ -859,10 +1060,35 @@ func selfOrAncestorsBeDenied(deniedByRole string, relatedRoleMap map[string]*Rol
 	return true
 }
 
+// addInactivePolicies adds the inactive policies and role policies which would match the request to
+// the evaluation result of Diagnose
+func addInactivePolicies(ctx *internalRequestContext, evaluationResult *adsapi.EvaluationResult) {
+	principals := ctx.Subject.Principals
+	for _, service := range []*RuntimeService{ctx.Service, ctx.GlobalService} {
+		if service == nil {
+			continue
+		}
+		for _, rolePolicy := range service.RolePoliciesCache.InactivePolicyMap {
+			if (len(rolePolicy.Principals) == 0 || matchRolePolicyPrincipals(principals, rolePolicy.Principals)) &&
+				matchResource(ctx.Resource, rolePolicy.Resources, rolePolicy.ResourceExpressions) {
+				evaluationResult.AddInactiveRolePolicy(rolePolicy)
+			}
+		}
+	}
+	for _, policy := range ctx.Service.PoliciesCache.InactivePolicyMap {
+		if (len(policy.Principals) == 0 || matchPrincipals(principals, policy.Principals)) && matchResourceAction(policy, ctx) {
+			evaluationResult.AddInactivePolicy(policy)
+		}
+	}
+}
+
 // Returns granted and denied policies
 // The first returned value is granted policies
 // The second returned value is denied policies
 func (p *PolicyEvalImpl) getPolicyList(ctx *internalRequestContext, matchResource bool, matchCondition bool, evaluationResult *adsapi.EvaluationResult) ([]*pms.Policy, []*pms.Policy, error) {
+	span, endSpan := ctx.startSpan("policy-lookup")
+	defer endSpan()
+	span.SetAttribute("service", ctx.Service.Name)
 	var grantedPolicyList []*pms.Policy
 	var deniedPolicyList []*pms.Policy
 
@@ -884,7 +1110,10 @@ func (p *PolicyEvalImpl) getPolicyList(ctx *internalRequestContext, matchResourc
 					}
 				}
 				if condition != nil {
-					result, _ = evaluateCondition(condition, ctx.Attributes)
+					var err error
+					if result, err = p.RuntimePolicyStore.evaluateCondition(ctx.TraceContext, policy.ID, condition, ctx.AttributeParams); err != nil {
+						ctx.ConditionErrors = append(ctx.ConditionErrors, err)
+					}
 				}
 
 				if result {
@@ -1038,7 +1267,7 @@ func (p *PolicyEvalImpl) getPolicySetInCache() (map[int]string, []int, error) {
 	resultSet := make(map[int]string)
 	var idx []int
 	for _, rService := range p.RuntimePolicyStore.RuntimeServices {
-		for pId := range rService.PoliciesCache.PolicyMap {
+		for pId := range rService.PoliciesCache.allPolicyIDs() {
 			id, err := strconv.Atoi(pId)
 			if err != nil {
 				return nil, nil, errors.Wrapf(err, errors.EvalCacheError, "unable to convert policy ID %q", pId)
@@ -1055,7 +1284,7 @@ func (p *PolicyEvalImpl) getRolePolicySetInCache() (map[int]string, []int, error
 	resultSet := make(map[int]string)
 	var idx []int
 	for _, rService := range p.RuntimePolicyStore.RuntimeServices {
-		for pId := range rService.RolePoliciesCache.PolicyMap {
+		for pId := range rService.RolePoliciesCache.allPolicyIDs() {
 			id, err := strconv.Atoi(pId)
 			if err != nil {
 				return nil, nil, errors.Wrapf(err, errors.EvalCacheError, "unable to convert policy ID %q", pId)
@@ -1078,8 +1307,12 @@ func (p *PolicyEvalImpl) getCustFunctionSetInCache() ([]string, error) {
 	return resultSet, nil
 }
 
-func (p *PolicyEvalImpl) updateRuntimeCacheWithStoreChange(updateChan pms.StorageChangeChannel) {
+// updateRuntimeCacheWithStoreChange applies the changes of the policy store to the runtime cache
+// until the channel is closed, it returns the last applied change
+func (p *PolicyEvalImpl) updateRuntimeCacheWithStoreChange(updateChan pms.StorageChangeChannel) *pms.StoreChangeEvent {
+	var last *pms.StoreChangeEvent
 	for e := range updateChan {
+		metrics.ObserveWatchEvent(e.Type.String())
 		switch e.Type {
 		case pms.SERVICE_ADD: ///Event content: StoreUpdateData{ParentID:serviceName, Data:*service}
 			serviceGot := e.Content.(*pms.Service)
@@ -1119,6 +1352,7 @@ func (p *PolicyEvalImpl) updateRuntimeCacheWithStoreChange(updateChan pms.Storag
 			if err != nil {
 				log.Error("failed to reload cache data. ", err)
 			}
+			p.health.cacheBuilt(err)
 		case pms.FUNCTION_ADD:
 			f := e.Content.(*pms.Function)
 			p.AddFunctionInRuntimeCache(f)
@@ -1130,7 +1364,11 @@ func (p *PolicyEvalImpl) updateRuntimeCacheWithStoreChange(updateChan pms.Storag
 		case pms.FULL_RELOAD:
 			p.fullReloadRuntimeCache()
 		}
+		p.health.eventApplied(&e)
+		event := e
+		last = &event
 	}
+	return last
 }
 
 func (p *PolicyEvalImpl) cleanExpiredFunctionResultPeriodically() {
@@ -1148,6 +1386,11 @@ func (p *PolicyEvalImpl) cleanExpiredFunctionResultPeriodically() {
 // StopWatch stops watching policy store.
 // After stopping watching, policy changes will not be updated automatically
 func (p *PolicyEvalImpl) StopWatch() {
+	p.stopWatchOnce.Do(func() {
+		if p.watchStop != nil {
+			close(p.watchStop)
+		}
+	})
 	p.Store.StopWatch()
 }
 
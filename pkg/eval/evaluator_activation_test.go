@@ -0,0 +1,90 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+)
+
+func TestPolicyActivationWindow(t *testing.T) {
+	notBefore := time.Now().Add(300 * time.Millisecond).UTC().Format(time.RFC3339Nano)
+	notAfter := time.Now().Add(900 * time.Millisecond).UTC().Format(time.RFC3339Nano)
+	appStream := fmt.Sprintf(`
+	{
+		"services": [
+		{
+			"name": "pager",
+			"policies": [
+			{
+				"id": "on-call",
+				"effect": "grant",
+				"permissions": [{"resource": "/alerts", "actions": ["ack"]}],
+				"principals": [["user:alice"]],
+				"notBefore": %q,
+				"notAfter": %q
+			},
+			{
+				"id": "disabled",
+				"effect": "grant",
+				"permissions": [{"resource": "/alerts", "actions": ["ack"]}],
+				"principals": [["user:alice"]],
+				"disabled": true
+			}
+			]
+		}
+		]
+	}
+	`, notBefore, notAfter)
+	preparePolicyDataInStore([]byte(appStream), t)
+	evaluator, err := NewWithStore(conf, testPS)
+	if err != nil {
+		t.Fatalf("Unable to initialize evaluator due to error [%v].", err)
+	}
+
+	ctx := adsapi.RequestContext{
+		Subject: &adsapi.Subject{
+			Principals: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_USER, Name: "alice"}},
+		},
+		ServiceName: "pager",
+		Resource:    "/alerts",
+		Action:      "ack",
+	}
+	isAllowed := func() bool {
+		allowed, _, err := evaluator.IsAllowed(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error %v.", err)
+		}
+		return allowed
+	}
+
+	if isAllowed() {
+		t.Error("policy should not take effect before notBefore")
+	}
+	result, err := evaluator.Diagnose(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error %v.", err)
+	}
+	if len(result.Policies) != 2 {
+		t.Fatalf("Diagnose should report 2 inactive policies, got %d", len(result.Policies))
+	}
+	for _, policy := range result.Policies {
+		if policy.Status != adsapi.Evaluation_Inactive {
+			t.Errorf("policy %s should be %s, but is %s", policy.ID, adsapi.Evaluation_Inactive, policy.Status)
+		}
+	}
+
+	// activated and expired without any store event
+	time.Sleep(600 * time.Millisecond)
+	if !isAllowed() {
+		t.Error("policy should take effect between notBefore and notAfter")
+	}
+	time.Sleep(600 * time.Millisecond)
+	if isAllowed() {
+		t.Error("policy should not take effect after notAfter")
+	}
+}
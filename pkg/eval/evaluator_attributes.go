@@ -0,0 +1,94 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"github.com/teramoby/speedle-plus/3rdparty/github.com/Knetic/govaluate"
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/pip"
+)
+
+// requestAttributes are the parameters conditions are evaluated with. Attributes not supplied
+// by the request are looked up from attribute providers when a condition references them,
+// and are kept for the rest of the request.
+type requestAttributes struct {
+	attributes map[string]interface{}
+	notFound   map[string]bool
+	resolver   *pip.Resolver
+	request    *pip.Request
+	sources    map[string]*adsapi.AttributeSource // only recorded in Diagnose
+}
+
+func (p *PolicyEvalImpl) newAttributeParameters(ctx *adsapi.RequestContext, newCtx *internalRequestContext) govaluate.Parameters {
+	if p.AttributeResolver == nil {
+		return govaluate.MapParameters(newCtx.Attributes)
+	}
+
+	req := pip.Request{
+		Subject:     ctx.Subject,
+		ServiceName: ctx.ServiceName,
+		Resource:    ctx.Resource,
+		Action:      ctx.Action,
+	}
+	if user, ok := newCtx.Attributes[adsapi.BuiltIn_Attr_RequestUser].(string); ok {
+		req.User = user
+	}
+	if entity, ok := newCtx.Attributes[adsapi.BuiltIn_Attr_RequestEntity].(string); ok {
+		req.Entity = entity
+	}
+	if groups, ok := newCtx.Attributes[adsapi.BuiltIn_Attr_RequestGroups].([]interface{}); ok {
+		for _, group := range groups {
+			if name, ok := group.(string); ok {
+				req.Groups = append(req.Groups, name)
+			}
+		}
+	}
+	return &requestAttributes{
+		attributes: newCtx.Attributes,
+		notFound:   make(map[string]bool),
+		resolver:   p.AttributeResolver,
+		request:    &req,
+	}
+}
+
+func (a *requestAttributes) Get(name string) (interface{}, error) {
+	if _, ok := a.attributes[name]; !ok && !a.notFound[name] {
+		attrs, source := a.resolver.Resolve(name, a.request)
+		if _, ok := attrs[name]; !ok {
+			a.notFound[name] = true
+		}
+		for attrName, value := range attrs {
+			// Attributes sent by the caller are never overridden
+			if _, ok := a.attributes[attrName]; ok {
+				continue
+			}
+			a.attributes[attrName] = value
+			if a.sources != nil {
+				a.sources[attrName] = source
+			}
+		}
+		if a.notFound[name] && a.sources != nil && source != nil {
+			a.sources[name] = source
+		}
+	}
+	return govaluate.MapParameters(a.attributes).Get(name)
+}
+
+// recordAttributeSources records where the attributes come from in evaluation result
+func (p *PolicyEvalImpl) recordAttributeSources(ctx *adsapi.RequestContext, newCtx *internalRequestContext, evaluationResult *adsapi.EvaluationResult) {
+	sources := make(map[string]*adsapi.AttributeSource)
+	for name := range newCtx.Attributes {
+		if p.isAssertedAttribute(ctx, name) {
+			sources[name] = &adsapi.AttributeSource{Source: adsapi.AttributeSource_Token}
+		} else if _, ok := ctx.Attributes[name]; ok {
+			sources[name] = &adsapi.AttributeSource{Source: adsapi.AttributeSource_Request}
+		} else {
+			sources[name] = &adsapi.AttributeSource{Source: adsapi.AttributeSource_BuiltIn}
+		}
+	}
+	evaluationResult.AttributeSources = sources
+	if params, ok := newCtx.AttributeParams.(*requestAttributes); ok {
+		params.sources = sources
+	}
+}
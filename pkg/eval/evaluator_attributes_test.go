@@ -0,0 +1,127 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/pip"
+)
+
+func TestAttributeProviders(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path != "/users/alice" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"dept": "eng", "level": 3})
+	}))
+	defer server.Close()
+
+	ps := pms.PolicyStore{
+		Services: []*pms.Service{
+			{
+				Name: "crm",
+				Policies: []*pms.Policy{
+					{
+						ID:          "p1",
+						Effect:      pms.Grant,
+						Permissions: []*pms.Permission{{Resource: "/report", Actions: []string{"read"}}},
+						Principals:  [][]string{{"role:employee"}},
+						Condition:   "dept == 'eng' && level > 2",
+					},
+				},
+				RolePolicies: []*pms.RolePolicy{
+					{
+						ID:         "rp1",
+						Effect:     pms.Grant,
+						Roles:      []string{"employee"},
+						Principals: []string{"user:alice", "user:bob"},
+						Condition:  "scope == 'internal'",
+					},
+				},
+			},
+		},
+	}
+	testPS.WritePolicyStore(&ps)
+
+	attrConf := *conf
+	attrConf.AttributeProviders = []*pip.ProviderConfig{
+		{
+			Name:       "token",
+			Type:       pip.TokenProviderType,
+			Attributes: []string{"scope"},
+		},
+		{
+			Name:       "directory",
+			Type:       pip.HTTPProviderType,
+			Attributes: []string{"dept", "level"},
+			Props:      map[string]interface{}{pip.HTTPPropURL: server.URL + "/users/{user}"},
+		},
+	}
+	eval, err := NewWithStore(&attrConf, testPS)
+	if err != nil {
+		t.Fatalf("error creating evaluator : %v", err)
+	}
+
+	newCtx := func(user string, attrs map[string]interface{}) adsapi.RequestContext {
+		return adsapi.RequestContext{
+			Subject: &adsapi.Subject{
+				Principals:         []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_USER, Name: user}},
+				AssertedAttributes: map[string]interface{}{"scope": "internal"},
+			},
+			ServiceName: "crm",
+			Resource:    "/report",
+			Action:      "read",
+			Attributes:  attrs,
+		}
+	}
+
+	if allowed, _, err := eval.IsAllowed(newCtx("alice", nil)); !allowed || err != nil {
+		t.Errorf("alice should be allowed with looked up attributes, err: %v", err)
+	}
+	// dept and level are looked up once for the request
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("attribute provider should be called once, got %d", calls)
+	}
+	if allowed, _, _ := eval.IsAllowed(newCtx("bob", nil)); allowed {
+		t.Error("bob should not be allowed")
+	}
+	// Attributes sent by the caller are used as is
+	atomic.StoreInt32(&calls, 0)
+	if allowed, _, _ := eval.IsAllowed(newCtx("bob", map[string]interface{}{"dept": "eng", "level": 5.0})); !allowed || atomic.LoadInt32(&calls) != 0 {
+		t.Errorf("bob should be allowed with attributes in request, %d calls", calls)
+	}
+
+	result, err := eval.Diagnose(newCtx("alice", map[string]interface{}{"level": 1.0}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Allowed {
+		t.Error("level in request should override looked up attribute")
+	}
+	expected := map[string]string{
+		"scope":                         "token",
+		"dept":                          "directory",
+		"level":                         adsapi.AttributeSource_Request,
+		adsapi.BuiltIn_Attr_RequestUser: adsapi.AttributeSource_BuiltIn,
+	}
+	for name, source := range expected {
+		if s, ok := result.AttributeSources[name]; !ok || s.Source != source {
+			payload, _ := json.Marshal(result.AttributeSources)
+			t.Errorf("attribute %s should come from %s, got %s", name, source, payload)
+		}
+	}
+	if result.Attributes["dept"] != "eng" {
+		t.Errorf("looked up attributes should be in diagnose result, got %v", result.Attributes)
+	}
+}
@@ -4,6 +4,7 @@ package eval
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -11,7 +12,6 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/teramoby/speedle-plus/api/ext"
@@ -19,6 +19,8 @@ import (
 
 	"github.com/teramoby/speedle-plus/3rdparty/github.com/Knetic/govaluate"
 	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/metrics"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -26,98 +28,24 @@ const (
 	defaultCustomerFunctionCallTimeout = 5 * time.Second
 )
 
-type Request2Delegator struct {
-	Function *pms.Function                `json:"function"`
-	Request  *ext.CustomerFunctionRequest `json:"request"`
-}
-
-type FuncResult struct {
-	Result interface{}
-	TTL    int64
-}
-
-type FuncResultCache struct {
-	sync.RWMutex
-	Results map[string]FuncResult
-}
-
-func (frc *FuncResultCache) get(key string) (FuncResult, bool) {
-	frc.RLock()
-	defer frc.RUnlock()
-	result, ok := frc.Results[key]
-	return result, ok
-}
-func (frc *FuncResultCache) delete(key string) {
-	frc.Lock()
-	defer frc.Unlock()
-	delete(frc.Results, key)
-}
-func (frc *FuncResultCache) add(key string, value FuncResult) {
-	frc.Lock()
-	defer frc.Unlock()
-	frc.Results[key] = value
-}
-
-func (frc *FuncResultCache) AddToCache(key string, cf *pms.Function, result interface{}) {
-	if cf.ResultCachable {
-		ttl := int64(0)
-		if cf.ResultTTL > 0 {
-			ttl = time.Now().Unix() + cf.ResultTTL
-		}
-		cachedResult := FuncResult{
-			Result: result,
-			TTL:    ttl,
-		}
-		frc.add(key, cachedResult)
-	}
-}
-
-func (frc *FuncResultCache) ReadFromCache(key string, cf *pms.Function) interface{} {
-	if cf.ResultCachable {
-		ret, ok := frc.get(key)
-		if ok {
-			if ret.TTL == 0 || time.Now().Unix() <= ret.TTL {
-				return ret.Result
-			}
-			frc.deleteIfExpired(key)
-		}
-	}
-	return nil
-}
-
-func (frc *FuncResultCache) DeleteFromCache(funcName string) {
-	frc.Lock()
-	defer frc.Unlock()
-	for key := range frc.Results {
-		if isFunc(key, funcName) {
-			delete(frc.Results, key)
-		}
-	}
-}
+// customerFunction is a customer function called by conditions, the context carries the span of the
+// condition evaluation calling it
+type customerFunction func(traceCtx context.Context, arguments ...interface{}) (interface{}, error)
 
-func (frc *FuncResultCache) deleteIfExpired(key string) {
-	frc.Lock()
-	defer frc.Unlock()
-	ret, ok := frc.Results[key]
-	if ok {
-		if ret.TTL != 0 && time.Now().Unix() > ret.TTL {
-			delete(frc.Results, key)
-		}
+// bind returns the expression function calling the customer function with the context
+func (cf customerFunction) bind(traceCtx context.Context) govaluate.ExpressionFunction {
+	return func(arguments ...interface{}) (interface{}, error) {
+		return cf(traceCtx, arguments...)
 	}
 }
 
-func (frc *FuncResultCache) CleanExpiredResult() {
-	frc.Lock()
-	defer frc.Unlock()
-	for key, value := range frc.Results {
-		if value.TTL > 0 && time.Now().Unix() > value.TTL {
-			delete(frc.Results, key)
-		}
-	}
+type Request2Delegator struct {
+	Function *pms.Function                `json:"function"`
+	Request  *ext.CustomerFunctionRequest `json:"request"`
 }
 
-func (frc *FuncResultCache) generateCustomerExpressionFunction(cfdUrl *string, cf *pms.Function) (govaluate.ExpressionFunction, error) {
-	return func(arguments ...interface{}) (interface{}, error) {
+func (frc *FuncResultCache) generateCustomerFunction(cfdUrl *string, cf *pms.Function, breakers *FuncBreakerRegistry, conns *funcConnPool) (customerFunction, error) {
+	return func(traceCtx context.Context, arguments ...interface{}) (interface{}, error) {
 		params := []interface{}{}
 		for _, param := range arguments {
 			params = append(params, param)
@@ -126,18 +54,35 @@ func (frc *FuncResultCache) generateCustomerExpressionFunction(cfdUrl *string, c
 			Params: params,
 		}
 		key := getKey(cf.Name, arguments)
-		var result interface{}
-		var err error
-		if result = frc.ReadFromCache(key, cf); result != nil {
-			return result, nil
-		}
-		if *cfdUrl == "" { //no delegator configured, request goes directly to customer function service
-			result, err = CallCustomerFunction(cf, request)
-		} else { //delegator configured, send request to delegator over http, and delegator sends request to customer function service over https
-			result, err = CallCustomerFunctionViaDelegator(*cfdUrl, cf, request)
-		}
-		if err == nil {
-			frc.AddToCache(key, cf, result)
+		if cached, ok := frc.lookup(key, cf); ok {
+			return cached.Result, cached.Err
+		}
+		call := func(timeout time.Duration) (result interface{}, err error) {
+			ctx, span := tracing.StartSpan(traceCtx, "function-call", tracing.SpanKindClient)
+			span.SetAttribute("function", cf.Name)
+			defer func(start time.Time) {
+				metrics.ObserveFunctionCall(cf.Name, start, err)
+				span.RecordError(err)
+				span.End()
+			}(time.Now())
+			if isGRPCFunction(cf) { //gRPC function, request goes directly to customer function service as delegator only speaks http
+				return callCustomerFunctionViaGRPC(ctx, conns, cf, request, timeout)
+			} else if *cfdUrl == "" { //no delegator configured, request goes directly to customer function service
+				return callCustomerFunction(ctx, conns, cf, request, timeout)
+			}
+			//delegator configured, send request to delegator over http, and delegator sends request to customer function service over https
+			return callCustomerFunctionViaDelegator(ctx, conns, *cfdUrl, cf, request, timeout)
+		}
+		readStale := func() (interface{}, bool) {
+			return frc.readStale(cf.Name, key)
+		}
+		result, isFallback, err := callWithResilience(cf, breakers.get(cf.Name), call, readStale)
+		if !isFallback {
+			if err == nil {
+				frc.AddToCache(key, cf, result)
+			} else {
+				frc.addErrorToCache(key, cf, err)
+			}
 		}
 		return result, err
 	}, nil
@@ -149,80 +94,114 @@ func getKey(funcName string, arguments []interface{}) string {
 	return key
 }
 
-func isFunc(key, funcName string) bool {
-	return strings.HasPrefix(key, funcName+"(")
-}
+// defaultFuncConns is the pool of the customer function calls which aren't made by an evaluator
+var defaultFuncConns = newFuncConnPool()
 
 func CallCustomerFunctionViaDelegator(delegatorUrl string, cf *pms.Function, request *ext.CustomerFunctionRequest) (interface{}, error) {
+	return callCustomerFunctionViaDelegator(context.Background(), defaultFuncConns, delegatorUrl, cf, request, defaultCustomerFunctionCallTimeout)
+}
+
+func callCustomerFunctionViaDelegator(traceCtx context.Context, pool *funcConnPool, delegatorUrl string, cf *pms.Function, request *ext.CustomerFunctionRequest, timeout time.Duration) (interface{}, error) {
 	req2Delegator := Request2Delegator{
 		Function: cf,
 		Request:  request,
 	}
-	var client *http.Client
-	//assume that http is used when communicate with delegator.
-	client = &http.Client{
-		Timeout: defaultCustomerFunctionCallTimeout,
-	}
 	buf, err := json.Marshal(req2Delegator)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", delegatorUrl, bytes.NewBuffer(buf))
+	//assume that http is used when communicate with delegator.
+	return postFunctionRequest(traceCtx, pool.plainClient, delegatorUrl, buf, cf, timeout)
+}
+
+func CallCustomerFunction(cf *pms.Function, request *ext.CustomerFunctionRequest) (interface{}, error) {
+	return callCustomerFunction(context.Background(), defaultFuncConns, cf, request, defaultCustomerFunctionCallTimeout)
+}
+
+func callCustomerFunction(traceCtx context.Context, pool *funcConnPool, cf *pms.Function, request *ext.CustomerFunctionRequest, timeout time.Duration) (interface{}, error) {
+	client, err := pool.httpClient(cf)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	return getFunctionResp(client, req, cf)
+	buf, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	return postFunctionRequest(traceCtx, client, cf.FuncURL, buf, cf, timeout)
 }
 
-func CallCustomerFunction(cf *pms.Function, request *ext.CustomerFunctionRequest) (interface{}, error) {
-	var client *http.Client
-	if strings.HasPrefix(strings.ToLower(cf.FuncURL), "https:") {
-		//TODO: load sphinx cert in case func server verifies client
-		/*var cert tls.Certificate
-		cert, err := tls.LoadX509KeyPair("./client.crt",	"./client.key")
-		if err != nil {
-			log.Fatal(err)
-		}*/
-
-		caCertPool := x509.NewCertPool()
-		if len(cf.CA) > 0 { //this is only required if func server use certificate which is signed by unknown CA
-			caCertPool.AppendCertsFromPEM([]byte(cf.CA))
-		}
+// funcClient is a pooled HTTP client of the server of one customer function over https
+type funcClient struct {
+	funcURL string
+	ca      string
+	client  *http.Client
+}
 
-		// Setup HTTPS client
-		tlsConfig := &tls.Config{
-			//Certificates: []tls.Certificate{cert},
-			RootCAs: caCertPool,
-		}
-		transport := &http.Transport{
-			TLSClientConfig: tlsConfig,
-			Proxy:           http.ProxyFromEnvironment,
-		}
-		client = &http.Client{
-			Transport: transport,
-			Timeout:   defaultCustomerFunctionCallTimeout,
-		}
+// httpClient returns the HTTP client of a customer function, functions over https have their own
+// client trusting the CA of the function
+func (pool *funcConnPool) httpClient(cf *pms.Function) (*http.Client, error) {
+	funcURL := strings.ToLower(cf.FuncURL)
+	if strings.HasPrefix(funcURL, "http:") {
+		return pool.plainClient, nil
+	} else if !strings.HasPrefix(funcURL, "https:") {
+		return nil, errors.Errorf(errors.CustomerFuncError, "URL of customer function %q is not supported", cf.FuncURL)
+	}
 
-	} else if strings.HasPrefix(strings.ToLower(cf.FuncURL), "http:") {
-		client = &http.Client{
-			Timeout: defaultCustomerFunctionCallTimeout,
+	pool.Lock()
+	defer pool.Unlock()
+	if fc, ok := pool.clients[cf.Name]; ok {
+		if fc.funcURL == cf.FuncURL && fc.ca == cf.CA {
+			return fc.client, nil
 		}
-	} else {
-		return nil, errors.Errorf(errors.CustomerFuncError, "URL of customer function %q is not supported", cf.FuncURL)
+		// Function definition has changed, drop the stale client
+		fc.client.CloseIdleConnections()
+		delete(pool.clients, cf.Name)
 	}
 
-	buf, err := json.Marshal(request)
+	//TODO: load sphinx cert in case func server verifies client
+	/*var cert tls.Certificate
+	cert, err := tls.LoadX509KeyPair("./client.crt",	"./client.key")
 	if err != nil {
-		return nil, err
+		log.Fatal(err)
+	}*/
+
+	caCertPool := x509.NewCertPool()
+	if len(cf.CA) > 0 { //this is only required if func server use certificate which is signed by unknown CA
+		caCertPool.AppendCertsFromPEM([]byte(cf.CA))
+	}
+
+	// Setup HTTPS client
+	tlsConfig := &tls.Config{
+		//Certificates: []tls.Certificate{cert},
+		RootCAs: caCertPool,
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+			Proxy:           http.ProxyFromEnvironment,
+		},
+	}
+	pool.clients[cf.Name] = &funcClient{
+		funcURL: cf.FuncURL,
+		ca:      cf.CA,
+		client:  client,
 	}
-	req, err := http.NewRequest("POST", cf.FuncURL, bytes.NewBuffer(buf))
+	return client, nil
+}
+
+// postFunctionRequest posts a request to a customer function or the delegator, the timeout applies to
+// the request rather than to the shared client
+func postFunctionRequest(traceCtx context.Context, client *http.Client, url string, body []byte, cf *pms.Function, timeout time.Duration) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(tracing.Detach(traceCtx), timeout)
+	defer cancel()
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", "application/json")
+	tracing.Inject(ctx, req.Header)
 	return getFunctionResp(client, req, cf)
-
 }
 
 func getFunctionResp(client *http.Client, request *http.Request, cf *pms.Function) (interface{}, error) {
@@ -0,0 +1,189 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teramoby/speedle-plus/api/ext"
+	"github.com/teramoby/speedle-plus/api/ext/pb"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	grpcFuncScheme       = "grpc"
+	grpcSecureFuncScheme = "grpcs"
+)
+
+// funcConn is a pooled gRPC connection to the server of one customer function
+type funcConn struct {
+	funcURL string
+	ca      string
+	conn    *grpc.ClientConn
+}
+
+// funcConnPool keeps one gRPC connection per customer function, and one HTTP
+// client per customer function over https, so that connections and TLS
+// sessions are reused across evaluations. Each evaluator has its own pool,
+// with the client certificate of its configuration.
+type funcConnPool struct {
+	sync.Mutex
+	clientCert *tls.Certificate
+	conns      map[string]*funcConn
+	clients    map[string]*funcClient
+	// client of the functions over http and of the delegator
+	plainClient *http.Client
+}
+
+func newFuncConnPool() *funcConnPool {
+	return &funcConnPool{
+		conns:   make(map[string]*funcConn),
+		clients: make(map[string]*funcClient),
+		plainClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+			},
+		},
+	}
+}
+
+// setClientCertificate sets the client certificate presented to customer
+// function servers over grpcs, which enables mutual TLS. Existing connections
+// are closed so that new ones pick up the certificate.
+func (pool *funcConnPool) setClientCertificate(cert *tls.Certificate) {
+	pool.Lock()
+	defer pool.Unlock()
+	pool.clientCert = cert
+	pool.closeWithoutLock()
+}
+
+func (pool *funcConnPool) closeWithoutLock() {
+	for name, fc := range pool.conns {
+		fc.conn.Close()
+		delete(pool.conns, name)
+	}
+	for name, fc := range pool.clients {
+		fc.client.CloseIdleConnections()
+		delete(pool.clients, name)
+	}
+	pool.plainClient.CloseIdleConnections()
+}
+
+func (pool *funcConnPool) get(cf *pms.Function) (*grpc.ClientConn, error) {
+	pool.Lock()
+	defer pool.Unlock()
+	if fc, ok := pool.conns[cf.Name]; ok {
+		if fc.funcURL == cf.FuncURL && fc.ca == cf.CA {
+			return fc.conn, nil
+		}
+		// Function definition has changed, drop the stale connection
+		fc.conn.Close()
+		delete(pool.conns, cf.Name)
+	}
+
+	conn, err := pool.dial(cf)
+	if err != nil {
+		return nil, err
+	}
+	pool.conns[cf.Name] = &funcConn{
+		funcURL: cf.FuncURL,
+		ca:      cf.CA,
+		conn:    conn,
+	}
+	return conn, nil
+}
+
+func (pool *funcConnPool) dial(cf *pms.Function) (*grpc.ClientConn, error) {
+	u, err := url.Parse(cf.FuncURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CustomerFuncError, "invalid URL %q of customer function %q", cf.FuncURL, cf.Name)
+	}
+
+	var opt grpc.DialOption
+	switch strings.ToLower(u.Scheme) {
+	case grpcFuncScheme:
+		opt = grpc.WithInsecure()
+	case grpcSecureFuncScheme:
+		tlsConfig := &tls.Config{}
+		if len(cf.CA) > 0 { //this is only required if func server use certificate which is signed by unknown CA
+			caCertPool := x509.NewCertPool()
+			caCertPool.AppendCertsFromPEM([]byte(cf.CA))
+			tlsConfig.RootCAs = caCertPool
+		}
+		if pool.clientCert != nil {
+			tlsConfig.Certificates = []tls.Certificate{*pool.clientCert}
+		}
+		opt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	default:
+		return nil, errors.Errorf(errors.CustomerFuncError, "URL of customer function %q is not supported", cf.FuncURL)
+	}
+
+	conn, err := grpc.Dial(u.Host, opt)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CustomerFuncError, "failed to connect to customer function %q", cf.Name)
+	}
+	return conn, nil
+}
+
+func (pool *funcConnPool) remove(funcName string) {
+	pool.Lock()
+	defer pool.Unlock()
+	if fc, ok := pool.conns[funcName]; ok {
+		fc.conn.Close()
+		delete(pool.conns, funcName)
+	}
+	if fc, ok := pool.clients[funcName]; ok {
+		fc.client.CloseIdleConnections()
+		delete(pool.clients, funcName)
+	}
+}
+
+func isGRPCFunction(cf *pms.Function) bool {
+	funcURL := strings.ToLower(cf.FuncURL)
+	return strings.HasPrefix(funcURL, grpcFuncScheme+":") || strings.HasPrefix(funcURL, grpcSecureFuncScheme+":")
+}
+
+func callCustomerFunctionViaGRPC(traceCtx context.Context, pool *funcConnPool, cf *pms.Function, request *ext.CustomerFunctionRequest, timeout time.Duration) (interface{}, error) {
+	conn, err := pool.get(cf)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.FunctionRequest{
+		Name: cf.Name,
+	}
+	for _, param := range request.Params {
+		value, err := pb.NewValue(param)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CustomerFuncError, "invalid parameter for customer function %q", cf.Name)
+		}
+		req.Params = append(req.Params, value)
+	}
+
+	ctx, cancel := context.WithTimeout(tracing.InjectGRPC(tracing.Detach(traceCtx)), timeout)
+	defer cancel()
+	resp, err := pb.NewCustomerFunctionClient(conn).Call(ctx, req)
+	if err != nil {
+		log.Errorf("error happens when calling customer function %s, err is: %v\n", cf.Name, err)
+		return nil, errors.Wrapf(err, errors.CustomerFuncError, "failed to do customer function request for customer function %q", cf.Name)
+	}
+	if resp.Error != "" {
+		log.Errorf("error in response from customer function %s, err is: %v\n", cf.Name, resp.Error)
+		return nil, errors.Errorf(errors.CustomerFuncError, "customer function %q returns error %q", cf.Name, resp.Error)
+	}
+	return resp.GetResult().AsInterface(), nil
+}
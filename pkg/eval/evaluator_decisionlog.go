@@ -0,0 +1,98 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/decisionlog"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
+)
+
+// matchedPolicies are the IDs of the policies and role policies matching a request. Methods of a nil
+// matchedPolicies do nothing, which is the one of requests whose decisions aren't logged.
+type matchedPolicies struct {
+	policies     []string
+	rolePolicies []string
+}
+
+func (m *matchedPolicies) addPolicies(policies []*pms.Policy) {
+	if m == nil {
+		return
+	}
+	for _, policy := range policies {
+		m.policies = appendUnique(m.policies, policy.ID)
+	}
+}
+
+func (m *matchedPolicies) addRolePolicy(policy *pms.RolePolicy) {
+	if m == nil {
+		return
+	}
+	m.rolePolicies = appendUnique(m.rolePolicies, policy.ID)
+}
+
+func appendUnique(ids []string, id string) []string {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+// newDecisionRecord creates the decision log record of a request. The subject of the request context
+// has been populated by the token assertion.
+func newDecisionRecord(ctx *adsapi.RequestContext, allowed bool, reason adsapi.Reason, err error,
+	matched *matchedPolicies, start time.Time) *decisionlog.Record {
+	record := &decisionlog.Record{
+		Timestamp:    start,
+		Service:      ctx.ServiceName,
+		Resource:     ctx.Resource,
+		Action:       ctx.Action,
+		Attributes:   ctx.Attributes,
+		Decision:     decisionlog.DecisionDeny,
+		Reason:       reason.String(),
+		Policies:     matched.policies,
+		RolePolicies: matched.rolePolicies,
+		LatencyMs:    float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond),
+	}
+	if allowed {
+		record.Decision = decisionlog.DecisionAllow
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if ctx.Subject != nil {
+		record.Subject = &decisionlog.Subject{
+			Principals: ctx.Subject.Principals,
+			TokenType:  ctx.Subject.TokenType,
+			Token:      ctx.Subject.Token,
+		}
+	}
+	if sc := tracing.SpanContextFromContext(ctx.TraceContext); sc.IsValid() {
+		record.TraceID = sc.TraceIDString()
+	}
+	return record
+}
+
+// Decide evaluates the request like IsAllowed, and returns its decision record without logging it.
+// It is used to replay logged decisions against other policies. The mode of the service applies, but
+// the request isn't recorded in the discover store.
+func (p *PolicyEvalImpl) Decide(ctx adsapi.RequestContext) *decisionlog.Record {
+	start := time.Now()
+	matched := &matchedPolicies{}
+	mode := p.serviceMode(ctx.ServiceName)
+	if mode == pms.ModeDiscover {
+		return newDecisionRecord(&ctx, true, adsapi.DISCOVER_MODE, nil, matched, start)
+	}
+	allowed, reason, err := p.evaluate(&ctx, nil, matched)
+	allowed, reason, err = p.applyDefaultDecision(ctx.ServiceName, allowed, reason, err)
+	if mode == pms.ModeShadow && !allowed {
+		allowed, reason, err = true, adsapi.SHADOW_MODE, nil
+	}
+	return newDecisionRecord(&ctx, allowed, reason, err, matched, start)
+}
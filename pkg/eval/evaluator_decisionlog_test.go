@@ -0,0 +1,125 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/decisionlog"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
+)
+
+type memorySink struct {
+	sync.Mutex
+	records []*decisionlog.Record
+}
+
+func (s *memorySink) Write(records []*decisionlog.Record) error {
+	s.Lock()
+	defer s.Unlock()
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func TestDecisionLog(t *testing.T) {
+	ps := pms.PolicyStore{
+		Services: []*pms.Service{
+			{
+				Name: "crm",
+				Policies: []*pms.Policy{
+					{
+						ID:          "p1",
+						Effect:      pms.Grant,
+						Permissions: []*pms.Permission{{Resource: "/report", Actions: []string{"read"}}},
+						Principals:  [][]string{{"role:employee"}},
+					},
+					{
+						ID:          "p2",
+						Effect:      pms.Deny,
+						Permissions: []*pms.Permission{{Resource: "/report", Actions: []string{"read"}}},
+						Principals:  [][]string{{"user:bob"}},
+					},
+				},
+				RolePolicies: []*pms.RolePolicy{
+					{
+						ID:         "rp1",
+						Effect:     pms.Grant,
+						Roles:      []string{"employee"},
+						Principals: []string{"user:alice", "user:bob"},
+					},
+				},
+			},
+			{Name: "hr"},
+		},
+	}
+	testPS.WritePolicyStore(&ps)
+	eval, err := NewWithStore(conf, testPS)
+	if err != nil {
+		t.Fatalf("error creating evaluator : %v", err)
+	}
+
+	sink := &memorySink{}
+	logger, err := decisionlog.NewLogger(&decisionlog.Config{
+		ServiceSampleRates: map[string]float64{"hr": 0},
+		Redaction:          &decisionlog.RedactionConfig{Attributes: []string{"ssn"}},
+	}, []decisionlog.Sink{sink})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decisionlog.SetLogger(logger)
+	defer decisionlog.SetLogger(nil)
+
+	newCtx := func(service, user string) adsapi.RequestContext {
+		return adsapi.RequestContext{
+			Subject:     &adsapi.Subject{Principals: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_USER, Name: user}}},
+			ServiceName: service,
+			Resource:    "/report",
+			Action:      "read",
+			Attributes:  map[string]interface{}{"ssn": "123-45-6789", "dept": "eng"},
+		}
+	}
+	parent, _ := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	aliceCtx := newCtx("crm", "alice")
+	aliceCtx.TraceContext = tracing.ContextWithRemoteParent(context.Background(), parent)
+	if allowed, _, _ := eval.IsAllowed(aliceCtx); !allowed {
+		t.Error("alice should be allowed")
+	}
+	if allowed, _, _ := eval.IsAllowed(newCtx("crm", "bob")); allowed {
+		t.Error("bob should be denied")
+	}
+	// Decisions of hr aren't sampled
+	eval.IsAllowed(newCtx("hr", "alice"))
+	logger.Shutdown()
+
+	if len(sink.records) != 2 {
+		t.Fatalf("expected 2 decision records, got %d", len(sink.records))
+	}
+	alice, bob := sink.records[0], sink.records[1]
+	if alice.DecisionID == "" || alice.DecisionID == bob.DecisionID {
+		t.Errorf("unexpected decision IDs %q and %q", alice.DecisionID, bob.DecisionID)
+	}
+	if alice.Decision != decisionlog.DecisionAllow || alice.Reason != adsapi.GRANT_POLICY_FOUND.String() ||
+		!reflect.DeepEqual(alice.Policies, []string{"p1"}) || !reflect.DeepEqual(alice.RolePolicies, []string{"rp1"}) {
+		t.Errorf("unexpected record of alice %+v", alice)
+	}
+	if alice.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || alice.Subject.Principals[0].Name != "alice" {
+		t.Errorf("unexpected trace ID %q or subject %+v", alice.TraceID, alice.Subject)
+	}
+	if alice.Attributes["ssn"] != "***" || alice.Attributes["dept"] != "eng" {
+		t.Errorf("ssn should be redacted, attributes %v", alice.Attributes)
+	}
+	if bob.Decision != decisionlog.DecisionDeny || bob.Reason != adsapi.DENY_POLICY_FOUND.String() ||
+		!reflect.DeepEqual(bob.Policies, []string{"p2", "p1"}) {
+		t.Errorf("unexpected record of bob %+v", bob)
+	}
+}
@@ -0,0 +1,103 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+)
+
+func TestDefaultDecisionAndFailureMode(t *testing.T) {
+	appStream := `
+	{
+		"services": [
+		{
+			"name": "wiki",
+			"defaultEffect": "grant",
+			"failureMode": "open",
+			"policies": [
+			{
+				"id": "level",
+				"effect": "grant",
+				"permissions": [{"resource": "/pages", "actions": ["edit"]}],
+				"principals": [["user:alice"]],
+				"condition": "level > 2"
+			},
+			{
+				"id": "archive",
+				"effect": "deny",
+				"permissions": [{"resource": "/archive", "actions": ["edit"]}],
+				"principals": [["user:alice"]]
+			}
+			]
+		},
+		{
+			"name": "payment",
+			"failureMode": "closed",
+			"policies": [
+			{
+				"id": "pay",
+				"effect": "grant",
+				"permissions": [{"resource": "/orders", "actions": ["pay"]}],
+				"principals": [["user:alice"]]
+			},
+			{
+				"id": "limit",
+				"effect": "grant",
+				"permissions": [{"resource": "/orders", "actions": ["pay"]}],
+				"principals": [["user:alice"]],
+				"condition": "amount < 100"
+			}
+			]
+		}
+		]
+	}
+	`
+	preparePolicyDataInStore([]byte(appStream), t)
+	globalConf := *conf
+	globalConf.DefaultDecision = &cfg.DefaultDecisionConfig{DefaultEffect: "grant"}
+	evaluator, err := NewWithStore(&globalConf, testPS)
+	if err != nil {
+		t.Fatalf("Unable to initialize evaluator due to error [%v].", err)
+	}
+
+	testCases := []struct {
+		name       string
+		service    string
+		resource   string
+		action     string
+		attributes map[string]interface{}
+		allowed    bool
+		reason     adsapi.Reason
+		decision   string
+	}{
+		{"condition is true", "wiki", "/pages", "edit", map[string]interface{}{"level": 3}, true, adsapi.GRANT_POLICY_FOUND, adsapi.Decision_Policy},
+		{"no applicable policy", "wiki", "/pages", "edit", map[string]interface{}{"level": 1}, true, adsapi.DEFAULT_GRANT, adsapi.Decision_Default},
+		{"failed condition", "wiki", "/pages", "edit", map[string]interface{}{"level": "high"}, true, adsapi.FAIL_OPEN, adsapi.Decision_Failure},
+		{"deny policy", "wiki", "/archive", "edit", nil, false, adsapi.DENY_POLICY_FOUND, adsapi.Decision_Policy},
+		{"grant policy", "payment", "/orders", "pay", map[string]interface{}{"amount": 10}, true, adsapi.GRANT_POLICY_FOUND, adsapi.Decision_Policy},
+		{"failed condition of fail-closed service", "payment", "/orders", "pay", map[string]interface{}{"amount": "all"}, false, adsapi.ERROR_IN_EVALUATION, adsapi.Decision_Failure},
+		{"unknown service", "unknown", "/orders", "pay", nil, true, adsapi.DEFAULT_GRANT, adsapi.Decision_Default},
+	}
+	for _, tc := range testCases {
+		ctx := adsapi.RequestContext{
+			Subject: &adsapi.Subject{
+				Principals: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_USER, Name: "alice"}},
+			},
+			ServiceName: tc.service,
+			Resource:    tc.resource,
+			Action:      tc.action,
+			Attributes:  tc.attributes,
+		}
+		allowed, reason, _ := evaluator.IsAllowed(ctx)
+		if allowed != tc.allowed || reason != tc.reason {
+			t.Errorf("%s: expected %v/%s, got %v/%s", tc.name, tc.allowed, tc.reason, allowed, reason)
+		}
+		if reason.Decision() != tc.decision {
+			t.Errorf("%s: expected decision %s, got %s", tc.name, tc.decision, reason.Decision())
+		}
+	}
+}
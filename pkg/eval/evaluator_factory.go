@@ -4,7 +4,13 @@
 package eval
 
 import (
+	"crypto/tls"
+
+	"github.com/teramoby/speedle-plus/pkg/assertion"
 	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/metrics"
+	"github.com/teramoby/speedle-plus/pkg/pip"
 	"github.com/teramoby/speedle-plus/pkg/store"
 
 	adsapi "github.com/teramoby/speedle-plus/api/ads"
@@ -65,20 +71,118 @@ func NewWithStore(conf *cfg.Config, s pms.PolicyStoreManagerADS) (InternalEvalua
 	}
 
 	runtimePolicyStore := NewRuntimePolicyStore()
+	if len(conf.FuncClientCertPath) > 0 && len(conf.FuncClientKeyPath) > 0 {
+		cert, err := tls.LoadX509KeyPair(conf.FuncClientCertPath, conf.FuncClientKeyPath)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ConfigError, "failed to load client certificate for customer functions")
+		}
+		runtimePolicyStore.SetFunctionClientCertificate(&cert)
+	}
+	runtimePolicyStore.FunctionResultCache = NewFuncResultCache(conf.FuncCacheMaxEntries, conf.FuncCacheMaxBytes)
 	runtimePolicyStore.init(ps, conf.FuncsvcEndpoint)
 
 	p := &PolicyEvalImpl{
 		RuntimePolicyStore: runtimePolicyStore,
 		Store:              s,
+		TokenAttributes:    conf.TokenAttributes,
+		DefaultDecision:    conf.DefaultDecision,
+	}
+	p.health.cacheBuilt(nil)
+	if p.Asserters, err = newAsserterRouter(conf); err != nil {
+		return nil, err
+	}
+	if p.Asserters != nil {
+		p.SetAsserterFunc(NewAsserterFunc(p.Asserters))
+	}
+	if len(conf.AttributeProviders) > 0 {
+		if p.AttributeResolver, err = pip.NewResolver(conf.AttributeProviders); err != nil {
+			return nil, err
+		}
 	}
 
 	// start a goroutine watching to the channel for update events and
 	// refresh runtime cache accordingly once receiving any events
 	if updateChan != nil {
-		go p.updateRuntimeCacheWithStoreChange(updateChan)
+		p.watchStop = make(chan struct{})
+		p.health.watchStarted()
+		go p.superviseWatch(updateChan)
 	}
 
 	p.cleanExpiredFunctionResultPeriodically()
+	metrics.SetCacheSource(p)
 
 	return p, nil
 }
+
+// newAsserterRouter creates the asserters of identity tokens. If asserterRouterConfig is not
+// configured, tokens of the types served by the JWT and introspection asserters are
+// asserted by them, and other tokens are sent to the asserter webhook.
+func newAsserterRouter(conf *cfg.Config) (*assertion.Router, error) {
+	routerConf := conf.AsserterRouterConfig
+	if routerConf == nil {
+		routerConf = &assertion.RouterConfig{Chains: make(map[string][]string)}
+		if conf.AsserterWebhookConfig != nil {
+			log.Info("Loading asserters.")
+			if _, err := assertion.NewAsserter(conf.AsserterWebhookConfig, nil); err != nil {
+				log.Warningf("load asserter error: %v", err)
+			} else {
+				props, err := assertion.Props(conf.AsserterWebhookConfig)
+				if err != nil {
+					return nil, errors.Wrap(err, errors.ConfigError, "invalid asserter webhook configuration")
+				}
+				routerConf.Asserters = append(routerConf.Asserters, &assertion.AsserterDefinition{
+					Name:  assertion.WebhookAsserterType,
+					Type:  assertion.WebhookAsserterType,
+					Props: props,
+				})
+				routerConf.DefaultChain = []string{assertion.WebhookAsserterType}
+			}
+		}
+		if conf.JWTAsserterConfig != nil {
+			log.Info("Loading JWT asserter.")
+			err := addTokenTypeAsserter(routerConf, assertion.JWTAsserterType, conf.JWTAsserterConfig,
+				conf.JWTAsserterConfig.TokenTypes, assertion.TokenTypeJWT)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if conf.IntrospectionAsserterConfig != nil {
+			log.Info("Loading introspection asserter.")
+			err := addTokenTypeAsserter(routerConf, assertion.IntrospectionAsserterType, conf.IntrospectionAsserterConfig,
+				conf.IntrospectionAsserterConfig.TokenTypes, assertion.TokenTypeOpaque)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(routerConf.Asserters) == 0 {
+			return nil, nil
+		}
+	}
+
+	router, err := assertion.NewRouter(routerConf)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ConfigError, "failed to load token asserters")
+	}
+	return router, nil
+}
+
+// addTokenTypeAsserter adds an asserter serving the token types to the router configuration
+func addTokenTypeAsserter(routerConf *assertion.RouterConfig, asserterType string, asserterConf interface{},
+	tokenTypes []string, defaultTokenType string) error {
+	props, err := assertion.Props(asserterConf)
+	if err != nil {
+		return errors.Wrapf(err, errors.ConfigError, "invalid %s asserter configuration", asserterType)
+	}
+	routerConf.Asserters = append(routerConf.Asserters, &assertion.AsserterDefinition{
+		Name:  asserterType,
+		Type:  asserterType,
+		Props: props,
+	})
+	if len(tokenTypes) == 0 {
+		tokenTypes = []string{defaultTokenType}
+	}
+	for _, tokenType := range tokenTypes {
+		routerConf.Chains[tokenType] = append(routerConf.Chains[tokenType], asserterType)
+	}
+	return nil
+}
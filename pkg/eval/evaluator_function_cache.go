@@ -0,0 +1,398 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+)
+
+const (
+	defaultFuncCacheMaxEntries = 10000    // per customer function
+	defaultFuncCacheMaxBytes   = 64 << 20 // all customer functions
+)
+
+type FuncResult struct {
+	Result interface{}
+	Err    error // not nil if a failed call is cached
+	TTL    int64
+}
+
+func (r *FuncResult) expired(now int64) bool {
+	return r.TTL > 0 && now > r.TTL
+}
+
+type funcCacheEntry struct {
+	key   string
+	value FuncResult
+	size  int64
+}
+
+// funcLRU is the bounded result cache of one customer function.
+// Each function has its own lock so that busy functions do not block each other.
+type funcLRU struct {
+	sync.Mutex
+	name       string
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+	bytes      int64
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// FuncResultCache caches customer function results in a LRU list per function.
+// The number of results of a function is bounded by the cacheSize of the function,
+// and the estimated memory of all results is bounded by maxBytes.
+type FuncResultCache struct {
+	sync.RWMutex // guards funcs only
+	funcs        map[string]*funcLRU
+	maxEntries   int
+	maxBytes     int64
+	usedBytes    int64 // accessed atomically
+}
+
+// NewFuncResultCache creates a function result cache, zero values mean default limits
+func NewFuncResultCache(maxEntries int, maxBytes int64) *FuncResultCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultFuncCacheMaxEntries
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultFuncCacheMaxBytes
+	}
+	return &FuncResultCache{
+		funcs:      make(map[string]*funcLRU),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+	}
+}
+
+// lru returns the LRU list of a function, it is created if not exists
+func (frc *FuncResultCache) lru(cf *pms.Function) *funcLRU {
+	frc.RLock()
+	l, ok := frc.funcs[cf.Name]
+	frc.RUnlock()
+	if !ok {
+		frc.Lock()
+		if l, ok = frc.funcs[cf.Name]; !ok {
+			l = &funcLRU{
+				name:       cf.Name,
+				maxEntries: frc.maxEntriesOf(cf),
+				ll:         list.New(),
+				items:      make(map[string]*list.Element),
+			}
+			frc.funcs[cf.Name] = l
+		}
+		frc.Unlock()
+	}
+	return l
+}
+
+func (frc *FuncResultCache) maxEntriesOf(cf *pms.Function) int {
+	if cf.CacheSize > 0 {
+		return cf.CacheSize
+	}
+	return frc.maxEntries
+}
+
+func (frc *FuncResultCache) lruIfExists(funcName string) *funcLRU {
+	frc.RLock()
+	defer frc.RUnlock()
+	return frc.funcs[funcName]
+}
+
+func (frc *FuncResultCache) lruList() []*funcLRU {
+	frc.RLock()
+	defer frc.RUnlock()
+	ret := make([]*funcLRU, 0, len(frc.funcs))
+	for _, l := range frc.funcs {
+		ret = append(ret, l)
+	}
+	return ret
+}
+
+// lookup returns the cached result of a call, which may be a cached error
+func (frc *FuncResultCache) lookup(key string, cf *pms.Function) (FuncResult, bool) {
+	if !cf.ResultCachable && cf.ErrorTTL <= 0 {
+		return FuncResult{}, false
+	}
+	l := frc.lru(cf)
+	// Expired results are kept until the periodical cleanup if they may serve as stale fallback
+	result, ok, freed := l.get(key, !hasStaleFallback(cf))
+	atomic.AddInt64(&frc.usedBytes, -freed)
+	return result, ok
+}
+
+func (frc *FuncResultCache) add(key string, cf *pms.Function, value FuncResult) {
+	l := frc.lru(cf)
+	delta := l.add(key, value, frc.maxEntriesOf(cf))
+	if atomic.AddInt64(&frc.usedBytes, delta) > frc.maxBytes {
+		frc.shrink()
+	}
+}
+
+// shrink evicts least recently used results from the largest function caches until
+// the memory cap is met.
+func (frc *FuncResultCache) shrink() {
+	for atomic.LoadInt64(&frc.usedBytes) > frc.maxBytes {
+		var largest *funcLRU
+		var largestBytes int64
+		for _, l := range frc.lruList() {
+			l.Lock()
+			if l.bytes > largestBytes {
+				largest, largestBytes = l, l.bytes
+			}
+			l.Unlock()
+		}
+		if largest == nil {
+			return
+		}
+		atomic.AddInt64(&frc.usedBytes, -largest.evictOldest())
+	}
+}
+
+func (frc *FuncResultCache) AddToCache(key string, cf *pms.Function, result interface{}) {
+	if cf.ResultCachable {
+		ttl := int64(0)
+		if cf.ResultTTL > 0 {
+			ttl = time.Now().Unix() + cf.ResultTTL
+		}
+		frc.add(key, cf, FuncResult{
+			Result: result,
+			TTL:    ttl,
+		})
+	}
+}
+
+// addErrorToCache caches a failed call for errorTTL seconds, so that a failing function
+// server is not called again for the same arguments.
+func (frc *FuncResultCache) addErrorToCache(key string, cf *pms.Function, err error) {
+	// Do not overwrite the result which could serve as stale fallback
+	if cf.ErrorTTL > 0 && !hasStaleFallback(cf) {
+		frc.add(key, cf, FuncResult{
+			Err: err,
+			TTL: time.Now().Unix() + cf.ErrorTTL,
+		})
+	}
+}
+
+func (frc *FuncResultCache) ReadFromCache(key string, cf *pms.Function) interface{} {
+	if cf.ResultCachable {
+		if ret, ok := frc.lookup(key, cf); ok && ret.Err == nil {
+			return ret.Result
+		}
+	}
+	return nil
+}
+
+// readStale returns the cached result regardless of its TTL
+func (frc *FuncResultCache) readStale(funcName, key string) (interface{}, bool) {
+	l := frc.lruIfExists(funcName)
+	if l == nil {
+		return nil, false
+	}
+	l.Lock()
+	defer l.Unlock()
+	elem, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*funcCacheEntry)
+	if entry.value.Err != nil {
+		return nil, false
+	}
+	return entry.value.Result, true
+}
+
+func hasStaleFallback(cf *pms.Function) bool {
+	return cf.Resilience != nil && cf.Resilience.Fallback != nil && cf.Resilience.Fallback.Type == pms.FallbackStale
+}
+
+// DeleteFromCache flushes all cached results of a function
+func (frc *FuncResultCache) DeleteFromCache(funcName string) {
+	frc.Lock()
+	l, ok := frc.funcs[funcName]
+	delete(frc.funcs, funcName)
+	frc.Unlock()
+	if ok {
+		l.Lock()
+		atomic.AddInt64(&frc.usedBytes, -l.bytes)
+		l.Unlock()
+	}
+}
+
+// Flush removes all cached results of a function, statistics of the function are kept
+func (frc *FuncResultCache) Flush(funcName string) bool {
+	l := frc.lruIfExists(funcName)
+	if l == nil {
+		return false
+	}
+	atomic.AddInt64(&frc.usedBytes, -l.clear())
+	return true
+}
+
+func (frc *FuncResultCache) CleanExpiredResult() {
+	now := time.Now().Unix()
+	for _, l := range frc.lruList() {
+		atomic.AddInt64(&frc.usedBytes, -l.removeExpired(now))
+	}
+}
+
+// Stats returns the cache statistics of all functions sorted by name
+func (frc *FuncResultCache) Stats() []*adsapi.FunctionCacheStats {
+	ret := []*adsapi.FunctionCacheStats{}
+	for _, l := range frc.lruList() {
+		ret = append(ret, l.stats())
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// FuncStats returns the cache statistics of one function
+func (frc *FuncResultCache) FuncStats(funcName string) (*adsapi.FunctionCacheStats, bool) {
+	l := frc.lruIfExists(funcName)
+	if l == nil {
+		return nil, false
+	}
+	return l.stats(), true
+}
+
+// get returns the cached value of key and the memory freed by dropping expired entry
+func (l *funcLRU) get(key string, dropExpired bool) (FuncResult, bool, int64) {
+	l.Lock()
+	defer l.Unlock()
+	elem, ok := l.items[key]
+	if !ok {
+		l.misses++
+		return FuncResult{}, false, 0
+	}
+	entry := elem.Value.(*funcCacheEntry)
+	if entry.value.expired(time.Now().Unix()) {
+		l.misses++
+		if dropExpired || entry.value.Err != nil {
+			return FuncResult{}, false, l.removeElement(elem)
+		}
+		return FuncResult{}, false, 0
+	}
+	l.hits++
+	l.ll.MoveToFront(elem)
+	return entry.value, true, 0
+}
+
+// add inserts or replaces a value, and returns the change of memory used by the cache
+func (l *funcLRU) add(key string, value FuncResult, maxEntries int) int64 {
+	l.Lock()
+	defer l.Unlock()
+	l.maxEntries = maxEntries
+	size := int64(len(key)) + estimateSize(value.Result)
+	if value.Err != nil {
+		size += int64(len(value.Err.Error()))
+	}
+
+	var delta int64
+	if elem, ok := l.items[key]; ok {
+		entry := elem.Value.(*funcCacheEntry)
+		delta = size - entry.size
+		entry.value, entry.size = value, size
+		l.bytes += delta
+		l.ll.MoveToFront(elem)
+		return delta
+	}
+
+	l.items[key] = l.ll.PushFront(&funcCacheEntry{key: key, value: value, size: size})
+	l.bytes += size
+	delta = size
+	for l.ll.Len() > l.maxEntries {
+		delta -= l.removeElement(l.ll.Back())
+		l.evictions++
+	}
+	return delta
+}
+
+func (l *funcLRU) evictOldest() int64 {
+	l.Lock()
+	defer l.Unlock()
+	elem := l.ll.Back()
+	if elem == nil {
+		return 0
+	}
+	l.evictions++
+	return l.removeElement(elem)
+}
+
+func (l *funcLRU) removeExpired(now int64) int64 {
+	l.Lock()
+	defer l.Unlock()
+	var freed int64
+	for elem := l.ll.Back(); elem != nil; {
+		prev := elem.Prev()
+		if elem.Value.(*funcCacheEntry).value.expired(now) {
+			freed += l.removeElement(elem)
+		}
+		elem = prev
+	}
+	return freed
+}
+
+func (l *funcLRU) clear() int64 {
+	l.Lock()
+	defer l.Unlock()
+	freed := l.bytes
+	l.ll.Init()
+	l.items = make(map[string]*list.Element)
+	l.bytes = 0
+	return freed
+}
+
+// removeElement must be called with the lock held
+func (l *funcLRU) removeElement(elem *list.Element) int64 {
+	entry := l.ll.Remove(elem).(*funcCacheEntry)
+	delete(l.items, entry.key)
+	l.bytes -= entry.size
+	return entry.size
+}
+
+func (l *funcLRU) stats() *adsapi.FunctionCacheStats {
+	l.Lock()
+	defer l.Unlock()
+	return &adsapi.FunctionCacheStats{
+		Name:       l.name,
+		Entries:    l.ll.Len(),
+		MaxEntries: l.maxEntries,
+		Bytes:      l.bytes,
+		Hits:       l.hits,
+		Misses:     l.misses,
+		Evictions:  l.evictions,
+	}
+}
+
+// estimateSize roughly estimates the memory used by a function result
+func estimateSize(v interface{}) int64 {
+	switch val := v.(type) {
+	case string:
+		return int64(len(val)) + 16
+	case []string:
+		size := int64(24)
+		for _, s := range val {
+			size += int64(len(s)) + 16
+		}
+		return size
+	case []interface{}:
+		size := int64(24)
+		for _, item := range val {
+			size += estimateSize(item)
+		}
+		return size
+	default:
+		return 16
+	}
+}
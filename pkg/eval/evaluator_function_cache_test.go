@@ -0,0 +1,153 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+)
+
+func TestFunctionCacheLRU(t *testing.T) {
+	frc := NewFuncResultCache(0, 0)
+	cf := &pms.Function{Name: "lru", ResultCachable: true, CacheSize: 2}
+
+	frc.AddToCache("k1", cf, 1.0)
+	frc.AddToCache("k2", cf, 2.0)
+	if frc.ReadFromCache("k1", cf) != 1.0 {
+		t.Error("k1 should be cached")
+	}
+	// k2 is the least recently used one
+	frc.AddToCache("k3", cf, 3.0)
+	if frc.ReadFromCache("k2", cf) != nil {
+		t.Error("k2 should be evicted")
+	}
+	if frc.ReadFromCache("k1", cf) != 1.0 || frc.ReadFromCache("k3", cf) != 3.0 {
+		t.Error("k1 and k3 should be cached")
+	}
+
+	stats, ok := frc.FuncStats(cf.Name)
+	if !ok {
+		t.Fatal("stats of function should exist")
+	}
+	expected := adsapi.FunctionCacheStats{Name: "lru", Entries: 2, MaxEntries: 2, Bytes: stats.Bytes, Hits: 3, Misses: 1, Evictions: 1}
+	if *stats != expected {
+		t.Errorf("got stats %+v, want %+v", stats, expected)
+	}
+
+	if !frc.Flush(cf.Name) {
+		t.Fatal("function cache should be flushed")
+	}
+	stats, _ = frc.FuncStats(cf.Name)
+	if stats.Entries != 0 || stats.Bytes != 0 || stats.Hits != 3 || frc.usedBytes != 0 {
+		t.Errorf("unexpected stats after flush %+v", stats)
+	}
+	if frc.Flush("unknown") {
+		t.Error("unknown function should not be flushed")
+	}
+}
+
+func TestFunctionCacheMemoryCap(t *testing.T) {
+	frc := NewFuncResultCache(0, 1000)
+	small := &pms.Function{Name: "small", ResultCachable: true}
+	large := &pms.Function{Name: "large", ResultCachable: true}
+
+	frc.AddToCache("s1", small, "x")
+	for i := 0; i < 10; i++ {
+		frc.AddToCache(fmt.Sprintf("l%d", i), large, strings.Repeat("x", 200))
+	}
+
+	if frc.usedBytes > 1000 {
+		t.Errorf("used memory %d exceeds the cap", frc.usedBytes)
+	}
+	// Results are evicted from the largest function first
+	if frc.ReadFromCache("s1", small) != "x" {
+		t.Error("result of small function should be kept")
+	}
+	if frc.ReadFromCache("l0", large) != nil || frc.ReadFromCache("l9", large) == nil {
+		t.Error("oldest results of large function should be evicted")
+	}
+
+	var total int64
+	for _, stats := range frc.Stats() {
+		total += stats.Bytes
+	}
+	if total != frc.usedBytes {
+		t.Errorf("used memory %d does not match the sum of functions %d", frc.usedBytes, total)
+	}
+}
+
+func TestFunctionCacheConcurrency(t *testing.T) {
+	frc := NewFuncResultCache(50, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cf := &pms.Function{Name: fmt.Sprintf("f%d", i%4), ResultCachable: true}
+			for j := 0; j < 500; j++ {
+				key := fmt.Sprintf("%s(%d)", cf.Name, j%80)
+				if frc.ReadFromCache(key, cf) == nil {
+					frc.AddToCache(key, cf, float64(j))
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var total int64
+	for _, stats := range frc.Stats() {
+		if stats.Entries > 50 {
+			t.Errorf("function %s has %d entries", stats.Name, stats.Entries)
+		}
+		total += stats.Bytes
+	}
+	if total != frc.usedBytes {
+		t.Errorf("used memory %d does not match the sum of functions %d", frc.usedBytes, total)
+	}
+}
+
+func TestFunctionNegativeCache(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cf := &pms.Function{Name: "failing", FuncURL: server.URL, ErrorTTL: 60}
+	frc := NewFuncResultCache(0, 0)
+	funcSvcEndpoint := ""
+	function, err := frc.generateCustomerFunction(&funcSvcEndpoint, cf, NewFuncBreakerRegistry(), newFuncConnPool())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ef := function.bind(context.Background())
+
+	for i := 0; i < 3; i++ {
+		if _, err := ef(1.0); err == nil {
+			t.Error("function call should fail")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("function server should be called once, got %d", calls)
+	}
+	// Errors are never returned as results
+	if frc.ReadFromCache(getKey(cf.Name, []interface{}{1.0}), cf) != nil {
+		t.Error("cached error should not be read as result")
+	}
+
+	// Other arguments are not affected
+	if _, err := ef(2.0); err == nil || calls != 2 {
+		t.Errorf("function server should be called for new arguments, got %d calls", calls)
+	}
+}
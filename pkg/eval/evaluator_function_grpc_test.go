@@ -0,0 +1,198 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/ext/pb"
+	"github.com/teramoby/speedle-plus/api/pms"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+type sumFunctionServer struct{}
+
+func (s *sumFunctionServer) Call(ctx context.Context, req *pb.FunctionRequest) (*pb.FunctionResponse, error) {
+	sum := float64(0)
+	for _, param := range req.Params {
+		number, ok := param.AsInterface().(float64)
+		if !ok {
+			return &pb.FunctionResponse{Error: "number expected"}, nil
+		}
+		sum += number
+	}
+	result, _ := pb.NewValue(sum)
+	return &pb.FunctionResponse{Result: result}, nil
+}
+
+// generateTestCert generates a self-signed certificate for localhost
+func generateTestCert(t *testing.T) (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, string(certPEM)
+}
+
+func startGRPCFunctionService(t *testing.T, opts ...grpc.ServerOption) (string, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(opts...)
+	pb.RegisterCustomerFunctionServer(server, &sumFunctionServer{})
+	go server.Serve(lis)
+	return lis.Addr().String(), server.Stop
+}
+
+func TestGRPCFunctions(t *testing.T) {
+	serverCert, serverCA := generateTestCert(t)
+	clientCert, clientCA := generateTestCert(t)
+	clientCAPool := x509.NewCertPool()
+	clientCAPool.AppendCertsFromPEM([]byte(clientCA))
+
+	plainAddr, stopPlain := startGRPCFunctionService(t)
+	defer stopPlain()
+	tlsAddr, stopTLS := startGRPCFunctionService(t, grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+	})))
+	defer stopTLS()
+	mtlsAddr, stopMTLS := startGRPCFunctionService(t, grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	defer stopMTLS()
+
+	testCases := []struct {
+		function   pms.Function
+		clientCert bool
+		want       bool
+	}{
+		{
+			function: pms.Function{Name: "grpcsum", FuncURL: "grpc://" + plainAddr},
+			want:     true,
+		},
+		{
+			function: pms.Function{Name: "grpcssum", FuncURL: "grpcs://" + tlsAddr, CA: serverCA},
+			want:     true,
+		},
+		{
+			function:   pms.Function{Name: "mtlssum", FuncURL: "grpcs://" + mtlsAddr, CA: serverCA},
+			clientCert: true,
+			want:       true,
+		},
+		{
+			// The client certificate of another evaluator isn't presented
+			function: pms.Function{Name: "mtlssum", FuncURL: "grpcs://" + mtlsAddr, CA: serverCA},
+			want:     false,
+		},
+		{
+			// Server certificate can't be verified without CA
+			function: pms.Function{Name: "untrustedsum", FuncURL: "grpcs://" + tlsAddr},
+			want:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		function := tc.function
+		ps := pms.PolicyStore{
+			Functions: []*pms.Function{&function},
+			Services: []*pms.Service{
+				{
+					Name: "crm",
+					Policies: []*pms.Policy{
+						{
+							ID:          "p1",
+							Effect:      pms.Grant,
+							Permissions: []*pms.Permission{{Resource: "/node1", Actions: []string{"get"}}},
+							Condition:   function.Name + "(1, 2, x) < 4",
+						},
+					},
+				},
+			},
+		}
+		testPS.WritePolicyStore(&ps)
+		eval, err := NewWithStore(conf, testPS)
+		if err != nil {
+			t.Errorf("error creating evaluator : %v", err)
+			continue
+		}
+		if tc.clientCert {
+			eval.(*PolicyEvalImpl).RuntimePolicyStore.SetFunctionClientCertificate(&clientCert)
+		}
+		// Run 3 times to reuse the pooled connection
+		for i := 0; i < 3; i++ {
+			ctx := adsapi.RequestContext{ServiceName: "crm", Resource: "/node1", Action: "get", Attributes: map[string]interface{}{"x": 0.5}}
+			got, _, _ := eval.IsAllowed(ctx)
+			if got != tc.want {
+				t.Errorf("function: %s, got %v, want %v", function.FuncURL, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestValueConversion(t *testing.T) {
+	values := []interface{}{nil, true, 1.5, "str", []interface{}{"a", 2.0, false}}
+	for _, v := range values {
+		pbValue, err := pb.NewValue(v)
+		if err != nil {
+			t.Fatalf("unexpected error converting %v: %v", v, err)
+		}
+		got := pbValue.AsInterface()
+		if gotList, ok := got.([]interface{}); ok {
+			wantList := v.([]interface{})
+			if len(gotList) != len(wantList) {
+				t.Errorf("got %v, want %v", got, v)
+			}
+			continue
+		}
+		if got != v {
+			t.Errorf("got %v, want %v", got, v)
+		}
+	}
+	if _, err := pb.NewValue(map[string]string{}); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+}
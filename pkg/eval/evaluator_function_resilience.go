@@ -0,0 +1,237 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultBreakerOpenDuration = 30 * time.Second
+)
+
+// funcCircuitBreaker tracks the health of one customer function.
+// When the number of consecutive failed calls reaches the failure threshold, the breaker
+// opens and calls are short-circuited to the fallback. After the open duration a single
+// trial call is let through (half open), which either closes or re-opens the breaker.
+type funcCircuitBreaker struct {
+	sync.Mutex
+	name                string
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	trialInFlight       bool
+
+	calls     int64
+	failures  int64
+	retries   int64
+	rejected  int64
+	fallbacks int64
+}
+
+// FuncBreakerRegistry keeps the circuit breakers of all customer functions
+type FuncBreakerRegistry struct {
+	sync.Mutex
+	breakers map[string]*funcCircuitBreaker
+}
+
+func NewFuncBreakerRegistry() *FuncBreakerRegistry {
+	return &FuncBreakerRegistry{
+		breakers: make(map[string]*funcCircuitBreaker),
+	}
+}
+
+func (r *FuncBreakerRegistry) get(funcName string) *funcCircuitBreaker {
+	r.Lock()
+	defer r.Unlock()
+	b, ok := r.breakers[funcName]
+	if !ok {
+		b = &funcCircuitBreaker{
+			name:  funcName,
+			state: adsapi.Breaker_Closed,
+		}
+		r.breakers[funcName] = b
+	}
+	return b
+}
+
+func (r *FuncBreakerRegistry) remove(funcName string) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.breakers, funcName)
+}
+
+// Status returns the breaker state and counters of all customer functions sorted by name
+func (r *FuncBreakerRegistry) Status() []*adsapi.FunctionStatus {
+	r.Lock()
+	breakers := make([]*funcCircuitBreaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.Unlock()
+
+	ret := make([]*adsapi.FunctionStatus, 0, len(breakers))
+	for _, b := range breakers {
+		ret = append(ret, b.status())
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+func (b *funcCircuitBreaker) status() *adsapi.FunctionStatus {
+	b.Lock()
+	defer b.Unlock()
+	status := adsapi.FunctionStatus{
+		Name:                b.name,
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		Calls:               b.calls,
+		Failures:            b.failures,
+		Retries:             b.retries,
+		Rejected:            b.rejected,
+		Fallbacks:           b.fallbacks,
+	}
+	if b.state != adsapi.Breaker_Closed {
+		status.OpenedAt = b.openedAt.Unix()
+	}
+	return &status
+}
+
+// allow returns whether a call could go to the function server
+func (b *funcCircuitBreaker) allow(res *pms.FunctionResilience) bool {
+	b.Lock()
+	defer b.Unlock()
+	switch b.state {
+	case adsapi.Breaker_Open:
+		openDuration := defaultBreakerOpenDuration
+		if res.OpenDuration > 0 {
+			openDuration = time.Duration(res.OpenDuration) * time.Second
+		}
+		if time.Since(b.openedAt) < openDuration {
+			b.rejected++
+			return false
+		}
+		log.Infof("circuit breaker of customer function %s is half open.", b.name)
+		b.state = adsapi.Breaker_HalfOpen
+		b.trialInFlight = true
+	case adsapi.Breaker_HalfOpen:
+		if b.trialInFlight {
+			b.rejected++
+			return false
+		}
+		b.trialInFlight = true
+	}
+	b.calls++
+	return true
+}
+
+func (b *funcCircuitBreaker) onRetry() {
+	b.Lock()
+	defer b.Unlock()
+	b.retries++
+}
+
+func (b *funcCircuitBreaker) onSuccess() {
+	b.Lock()
+	defer b.Unlock()
+	if b.state != adsapi.Breaker_Closed {
+		log.Infof("circuit breaker of customer function %s is closed.", b.name)
+	}
+	b.state = adsapi.Breaker_Closed
+	b.consecutiveFailures = 0
+	b.trialInFlight = false
+}
+
+func (b *funcCircuitBreaker) onFailure(res *pms.FunctionResilience) {
+	b.Lock()
+	defer b.Unlock()
+	b.failures++
+	b.consecutiveFailures++
+	b.trialInFlight = false
+	if b.state == adsapi.Breaker_HalfOpen ||
+		(res.FailureThreshold > 0 && b.consecutiveFailures >= res.FailureThreshold) {
+		if b.state != adsapi.Breaker_Open {
+			log.Warningf("circuit breaker of customer function %s is open after %d consecutive failures.", b.name, b.consecutiveFailures)
+		}
+		b.state = adsapi.Breaker_Open
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *funcCircuitBreaker) onFallback() {
+	b.Lock()
+	defer b.Unlock()
+	b.fallbacks++
+}
+
+// callWithResilience calls a customer function honoring the timeout, retry and circuit breaker
+// settings of the function. The second returned value tells whether the result comes from the
+// fallback, in which case it should not be cached.
+func callWithResilience(cf *pms.Function, b *funcCircuitBreaker,
+	call func(timeout time.Duration) (interface{}, error),
+	readStale func() (interface{}, bool)) (interface{}, bool, error) {
+	res := cf.Resilience
+	if res == nil {
+		res = &pms.FunctionResilience{}
+	}
+
+	if !b.allow(res) {
+		return fallback(cf, b, readStale)
+	}
+
+	timeout := defaultCustomerFunctionCallTimeout
+	if res.Timeout > 0 {
+		timeout = time.Duration(res.Timeout) * time.Millisecond
+	}
+	backoff := time.Duration(res.RetryBackoff) * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		result, err := call(timeout)
+		if err == nil {
+			b.onSuccess()
+			return result, false, nil
+		}
+		if attempt >= res.Retries {
+			b.onFailure(res)
+			return nil, false, err
+		}
+		log.Warningf("retrying customer function %s after error: %v", cf.Name, err)
+		b.onRetry()
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func fallback(cf *pms.Function, b *funcCircuitBreaker, readStale func() (interface{}, bool)) (interface{}, bool, error) {
+	if cf.Resilience == nil || cf.Resilience.Fallback == nil {
+		return nil, true, errors.Errorf(errors.CustomerFuncError, "circuit breaker of customer function %q is open", cf.Name)
+	}
+
+	fb := cf.Resilience.Fallback
+	switch fb.Type {
+	case pms.FallbackValue:
+		b.onFallback()
+		return fb.Value, true, nil
+	case pms.FallbackStale:
+		if result, ok := readStale(); ok {
+			b.onFallback()
+			return result, true, nil
+		}
+		return nil, true, errors.Errorf(errors.CustomerFuncError, "circuit breaker of customer function %q is open and no cached result is found", cf.Name)
+	default:
+		b.onFallback()
+		msg := fb.Error
+		if len(msg) == 0 {
+			msg = "circuit breaker is open"
+		}
+		return nil, true, errors.Errorf(errors.CustomerFuncError, "customer function %q fails with fallback error %q", cf.Name, msg)
+	}
+}
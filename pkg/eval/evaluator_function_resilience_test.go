@@ -0,0 +1,295 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/ext"
+	"github.com/teramoby/speedle-plus/api/pms"
+)
+
+func failingCall(failures int, result interface{}) (func(time.Duration) (interface{}, error), *int) {
+	count := 0
+	return func(timeout time.Duration) (interface{}, error) {
+		count++
+		if count <= failures {
+			return nil, fmt.Errorf("call %d failed", count)
+		}
+		return result, nil
+	}, &count
+}
+
+func noStale() (interface{}, bool) {
+	return nil, false
+}
+
+func TestFunctionRetry(t *testing.T) {
+	cf := &pms.Function{
+		Name:       "retry",
+		Resilience: &pms.FunctionResilience{Retries: 2, RetryBackoff: 1},
+	}
+	b := NewFuncBreakerRegistry().get(cf.Name)
+
+	call, count := failingCall(2, true)
+	result, isFallback, err := callWithResilience(cf, b, call, noStale)
+	if err != nil || isFallback || result != true {
+		t.Fatalf("unexpected result %v, %v, %v", result, isFallback, err)
+	}
+	if *count != 3 {
+		t.Errorf("expected 3 attempts, got %d", *count)
+	}
+
+	call, count = failingCall(3, true)
+	if _, _, err := callWithResilience(cf, b, call, noStale); err == nil {
+		t.Error("expected error after retries are exhausted")
+	}
+	if *count != 3 {
+		t.Errorf("expected 3 attempts, got %d", *count)
+	}
+
+	status := b.status()
+	if status.Calls != 2 || status.Failures != 1 || status.Retries != 4 || status.State != adsapi.Breaker_Closed {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestFunctionRetryWithoutLock(t *testing.T) {
+	var eval *PolicyEvalImpl
+	var calls int32
+	locked := make(chan bool, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		// The policy store and the service can be updated while the call is retried
+		acquired := make(chan struct{})
+		go func() {
+			eval.RuntimePolicyStore.Lock()
+			service := eval.RuntimePolicyStore.RuntimeServices["crm"]
+			service.Lock()
+			service.Unlock()
+			eval.RuntimePolicyStore.Unlock()
+			close(acquired)
+		}()
+		select {
+		case <-acquired:
+			locked <- false
+		case <-time.After(time.Second):
+			locked <- true
+		}
+		json.NewEncoder(w).Encode(ext.CustomerFunctionResponse{Result: true})
+	}))
+	defer server.Close()
+
+	function := pms.Function{
+		Name:       "flaky",
+		FuncURL:    server.URL,
+		Resilience: &pms.FunctionResilience{Retries: 1, RetryBackoff: 1},
+	}
+	ps := pms.PolicyStore{
+		Functions: []*pms.Function{&function},
+		Services: []*pms.Service{
+			{
+				Name: "crm",
+				Policies: []*pms.Policy{
+					{
+						ID:          "p1",
+						Effect:      pms.Grant,
+						Permissions: []*pms.Permission{{Resource: "/node1", Actions: []string{"get"}}},
+						Condition:   "flaky()",
+					},
+				},
+			},
+		},
+	}
+	testPS.WritePolicyStore(&ps)
+	evaluator, err := NewWithStore(conf, testPS)
+	if err != nil {
+		t.Fatalf("error creating evaluator : %v", err)
+	}
+	eval = evaluator.(*PolicyEvalImpl)
+	var assertions int32
+	eval.SetAsserterFunc(func(ctx *adsapi.RequestContext) error {
+		atomic.AddInt32(&assertions, 1)
+		return nil
+	})
+
+	subject := &adsapi.Subject{TokenType: "bearer", Token: "token"}
+	allowed, reason, err := eval.IsAllowed(adsapi.RequestContext{Subject: subject, ServiceName: "crm", Resource: "/node1", Action: "get"})
+	if !allowed || err != nil {
+		t.Errorf("the retried call should grant the request, got %v, %v, %v", allowed, reason, err)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("function server should be called twice, got %d", calls)
+	}
+	if atomic.LoadInt32(&assertions) != 1 {
+		t.Errorf("token should be asserted once, got %d", assertions)
+	}
+	if <-locked {
+		t.Error("the policy store is locked while the call is retried")
+	}
+}
+
+func TestFunctionCircuitBreaker(t *testing.T) {
+	testCases := []struct {
+		fallback   *pms.FunctionFallback
+		stale      func() (interface{}, bool)
+		wantResult interface{}
+		wantErr    bool
+	}{
+		{
+			fallback: nil,
+			stale:    noStale,
+			wantErr:  true,
+		},
+		{
+			fallback:   &pms.FunctionFallback{Type: pms.FallbackValue, Value: true},
+			stale:      noStale,
+			wantResult: true,
+		},
+		{
+			fallback: &pms.FunctionFallback{Type: pms.FallbackError, Error: "unavailable"},
+			stale:    noStale,
+			wantErr:  true,
+		},
+		{
+			fallback:   &pms.FunctionFallback{Type: pms.FallbackStale},
+			stale:      func() (interface{}, bool) { return 42.0, true },
+			wantResult: 42.0,
+		},
+		{
+			fallback: &pms.FunctionFallback{Type: pms.FallbackStale},
+			stale:    noStale,
+			wantErr:  true,
+		},
+	}
+
+	for i, tc := range testCases {
+		cf := &pms.Function{
+			Name: "breaker",
+			Resilience: &pms.FunctionResilience{
+				FailureThreshold: 2,
+				OpenDuration:     1,
+				Fallback:         tc.fallback,
+			},
+		}
+		b := NewFuncBreakerRegistry().get(cf.Name)
+
+		// Two consecutive failures open the breaker
+		call, count := failingCall(2, false)
+		callWithResilience(cf, b, call, tc.stale)
+		callWithResilience(cf, b, call, tc.stale)
+		if b.status().State != adsapi.Breaker_Open {
+			t.Fatalf("case %d: breaker should be open", i)
+		}
+
+		// Calls are short-circuited to the fallback while the breaker is open
+		result, isFallback, err := callWithResilience(cf, b, call, tc.stale)
+		if !isFallback || *count != 2 {
+			t.Errorf("case %d: call should be short-circuited", i)
+		}
+		if (err != nil) != tc.wantErr || result != tc.wantResult {
+			t.Errorf("case %d: got %v, %v, want %v, error %v", i, result, err, tc.wantResult, tc.wantErr)
+		}
+		if b.status().Rejected != 1 {
+			t.Errorf("case %d: unexpected status %+v", i, b.status())
+		}
+
+		// A successful trial call after the open duration closes the breaker
+		time.Sleep(1100 * time.Millisecond)
+		result, isFallback, err = callWithResilience(cf, b, call, tc.stale)
+		if err != nil || isFallback || result != false {
+			t.Errorf("case %d: trial call returns %v, %v, %v", i, result, isFallback, err)
+		}
+		if b.status().State != adsapi.Breaker_Closed {
+			t.Errorf("case %d: breaker should be closed", i)
+		}
+	}
+}
+
+func TestFunctionStatusInDiagnose(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	function := pms.Function{
+		Name:    "unavailable",
+		FuncURL: server.URL,
+		Resilience: &pms.FunctionResilience{
+			FailureThreshold: 1,
+			OpenDuration:     60,
+			Fallback:         &pms.FunctionFallback{Type: pms.FallbackValue, Value: true},
+		},
+	}
+	ps := pms.PolicyStore{
+		Functions: []*pms.Function{&function},
+		Services: []*pms.Service{
+			{
+				Name: "crm",
+				Policies: []*pms.Policy{
+					{
+						ID:          "p1",
+						Effect:      pms.Grant,
+						Permissions: []*pms.Permission{{Resource: "/node1", Actions: []string{"get"}}},
+						Condition:   "unavailable()",
+					},
+				},
+			},
+		},
+	}
+	testPS.WritePolicyStore(&ps)
+	eval, err := NewWithStore(conf, testPS)
+	if err != nil {
+		t.Fatalf("error creating evaluator : %v", err)
+	}
+
+	ctx := adsapi.RequestContext{ServiceName: "crm", Resource: "/node1", Action: "get"}
+	if allowed, _, _ := eval.IsAllowed(ctx); allowed {
+		t.Error("first call should fail and open the breaker")
+	}
+	result, err := eval.Diagnose(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Allowed {
+		t.Error("fallback value should be used while breaker is open")
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("function server should be called once, got %d", calls)
+	}
+	if len(result.Functions) != 1 || result.Functions[0].State != adsapi.Breaker_Open || result.Functions[0].Fallbacks != 1 {
+		payload, _ := json.Marshal(result.Functions)
+		t.Errorf("unexpected function status %s", payload)
+	}
+}
+
+func TestStaleFunctionResult(t *testing.T) {
+	frc := NewFuncResultCache(0, 0)
+	cf := &pms.Function{
+		Name:           "stale",
+		ResultCachable: true,
+		Resilience:     &pms.FunctionResilience{Fallback: &pms.FunctionFallback{Type: pms.FallbackStale}},
+	}
+	key := getKey(cf.Name, []interface{}{1})
+	frc.add(key, cf, FuncResult{Result: "old", TTL: time.Now().Unix() - 1})
+
+	if result := frc.ReadFromCache(key, cf); result != nil {
+		t.Errorf("expired result should not be read, got %v", result)
+	}
+	if result, ok := frc.readStale(cf.Name, key); !ok || result != "old" {
+		t.Errorf("stale result should be kept, got %v", result)
+	}
+}
@@ -4,15 +4,22 @@
 package eval
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	adsapi "github.com/teramoby/speedle-plus/api/ads"
 	"github.com/teramoby/speedle-plus/api/ext"
+	"github.com/teramoby/speedle-plus/api/pms"
 )
 
 var (
@@ -203,3 +210,41 @@ func TestFunctions(t *testing.T) {
 		}
 	}
 }
+
+func TestHTTPFunctionConnectionReuse(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ext.CustomerFunctionResponse{Result: 3.0})
+	})
+	var newConns int32
+	countConns := func(server *httptest.Server) *httptest.Server {
+		server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				atomic.AddInt32(&newConns, 1)
+			}
+		}
+		return server
+	}
+	plainServer := countConns(httptest.NewUnstartedServer(handler))
+	plainServer.Start()
+	defer plainServer.Close()
+	tlsServer := countConns(httptest.NewUnstartedServer(handler))
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw}))
+
+	pool := newFuncConnPool()
+	for _, server := range []*httptest.Server{plainServer, tlsServer} {
+		atomic.StoreInt32(&newConns, 0)
+		cf := &pms.Function{Name: "sum", FuncURL: server.URL, CA: serverCA}
+		// Run 3 times to reuse the pooled connection
+		for i := 0; i < 3; i++ {
+			got, err := callCustomerFunction(context.Background(), pool, cf, &ext.CustomerFunctionRequest{Params: []interface{}{1, 2}}, time.Second)
+			if err != nil || got != 3.0 {
+				t.Errorf("function: %s, got %v, %v, want 3", cf.FuncURL, got, err)
+			}
+		}
+		if got := atomic.LoadInt32(&newConns); got != 1 {
+			t.Errorf("function: %s, got %d connections, want 1", cf.FuncURL, got)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"sync"
+	"time"
+
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/health"
+	"github.com/teramoby/speedle-plus/pkg/store"
+)
+
+// cacheHealth tracks the watch of the policy store and the builds of the runtime cache
+type cacheHealth struct {
+	sync.RWMutex
+	watch     health.WatchStatus
+	builtAt   time.Time
+	lastError string
+}
+
+func (h *cacheHealth) watchStarted() {
+	h.Lock()
+	defer h.Unlock()
+	h.watch.Enabled = true
+	h.setWatchState(health.WatchStateWatching)
+	h.watch.StartedAt = time.Now().Unix()
+}
+
+func (h *cacheHealth) watchStopped() {
+	h.Lock()
+	defer h.Unlock()
+	h.setWatchState(health.WatchStateStopped)
+	h.watch.StoppedAt = time.Now().Unix()
+}
+
+func (h *cacheHealth) watchLost() {
+	h.Lock()
+	defer h.Unlock()
+	h.setWatchState(health.WatchStateReconnecting)
+	h.watch.StoppedAt = time.Now().Unix()
+}
+
+func (h *cacheHealth) watchRetryFailed(err error) {
+	h.Lock()
+	defer h.Unlock()
+	h.watch.LastError = err.Error()
+}
+
+// watchRestarted records a re-established watch, resumed is false if the cache was resynchronized
+func (h *cacheHealth) watchRestarted(resumed bool) {
+	h.Lock()
+	defer h.Unlock()
+	h.setWatchState(health.WatchStateWatching)
+	h.watch.StartedAt = time.Now().Unix()
+	h.watch.Reconnects++
+	if !resumed {
+		h.watch.Resyncs++
+	}
+	h.watch.LastError = ""
+}
+
+func (h *cacheHealth) setWatchState(state string) {
+	h.watch.State = state
+	h.watch.Alive = state == health.WatchStateWatching
+}
+
+func (h *cacheHealth) eventApplied(e *pms.StoreChangeEvent) {
+	h.Lock()
+	defer h.Unlock()
+	h.watch.Events++
+	h.watch.LastEventType = e.Type.String()
+	h.watch.LastEventID = e.ID
+	if e.Revision != 0 {
+		h.watch.LastRevision = e.Revision
+	}
+	h.watch.LastEventAt = time.Now().Unix()
+}
+
+// cacheBuilt records a build of the runtime cache, err is the error of a failed build
+func (h *cacheHealth) cacheBuilt(err error) {
+	h.Lock()
+	defer h.Unlock()
+	if err != nil {
+		h.lastError = err.Error()
+		return
+	}
+	h.builtAt = time.Now()
+	h.lastError = ""
+}
+
+// CheckHealth checks the health of the evaluator. It is not live if its watch of the policy store has
+// stopped, because policy changes are no longer applied. It is not ready if the watch is being
+// re-established, because its cache may be stale, or if the policy store is unreachable.
+func (p *PolicyEvalImpl) CheckHealth() *health.Status {
+	status := &health.Status{Live: true, Store: store.CheckStore(p.Store)}
+
+	p.health.RLock()
+	watch := p.health.watch
+	status.Cache = &health.CacheStatus{BuiltAt: p.health.builtAt.Unix(), LastError: p.health.lastError}
+	p.health.RUnlock()
+	status.Watch = &watch
+
+	stats := p.GetRuntimeCacheStats()
+	status.Cache.Services = stats.Services
+	status.Cache.Policies = stats.Policies
+	status.Cache.RolePolicies = stats.RolePolicies
+
+	switch watch.State {
+	case health.WatchStateStopped:
+		status.Live = false
+		status.Reasons = append(status.Reasons, "watch of the policy store has stopped, policy changes are no longer applied")
+	case health.WatchStateReconnecting:
+		status.Reasons = append(status.Reasons, "watch of the policy store is being re-established, policies may be stale")
+	}
+	if !status.Store.Reachable {
+		status.Reasons = append(status.Reasons, "policy store is unreachable: "+status.Store.Error)
+	}
+	status.Ready = status.Live && watch.State != health.WatchStateReconnecting && status.Store.Reachable
+	return status
+}
@@ -0,0 +1,49 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"github.com/teramoby/speedle-plus/pkg/metrics"
+)
+
+// unknownServiceLabel is the service of decisions of unknown services in metrics, so that callers
+// can't add metrics by sending arbitrary service names
+const unknownServiceLabel = "unknown"
+
+// GetRuntimeCacheStats returns the size of the runtime policy cache
+func (p *PolicyEvalImpl) GetRuntimeCacheStats() *metrics.RuntimeCacheStats {
+	p.RuntimePolicyStore.RLock()
+	defer p.RuntimePolicyStore.RUnlock()
+	stats := &metrics.RuntimeCacheStats{Services: len(p.RuntimePolicyStore.RuntimeServices)}
+	for _, service := range p.RuntimePolicyStore.RuntimeServices {
+		service.RLock()
+		stats.Policies += len(service.PoliciesCache.PolicyMap) + len(service.PoliciesCache.InactivePolicyMap)
+		stats.RolePolicies += len(service.RolePoliciesCache.PolicyMap) + len(service.RolePoliciesCache.InactivePolicyMap)
+		stats.RegexExpressions += service.PoliciesCache.regexExpressionCount() + service.RolePoliciesCache.regexExpressionCount()
+		service.RUnlock()
+	}
+	return stats
+}
+
+// regexExpressionCount returns the number of resource expressions which are matched one by one
+func (p *BasePolicyCacheData) regexExpressionCount() int {
+	count := 0
+	if p.NilPrincipalToPolicies != nil {
+		count += len(p.NilPrincipalToPolicies.ResourceExpressionToPolicies)
+	}
+	for _, resourceToPolicies := range p.PrincipalToPolicies {
+		count += len(resourceToPolicies.ResourceExpressionToPolicies)
+	}
+	return count
+}
+
+// serviceLabel returns the service of a decision in metrics
+func (p *PolicyEvalImpl) serviceLabel(serviceName string) string {
+	p.RuntimePolicyStore.RLock()
+	defer p.RuntimePolicyStore.RUnlock()
+	if _, ok := p.RuntimePolicyStore.RuntimeServices[serviceName]; ok {
+		return serviceName
+	}
+	return unknownServiceLabel
+}
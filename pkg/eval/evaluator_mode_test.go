@@ -0,0 +1,124 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/decisionlog"
+	"github.com/teramoby/speedle-plus/pkg/store"
+)
+
+func TestServiceMode(t *testing.T) {
+	appStream := `
+	{
+		"services": [
+		{
+			"name": "shop",
+			"mode": "shadow",
+			"policies": [
+			{
+				"id": "buy",
+				"effect": "grant",
+				"permissions": [{"resource": "/items", "actions": ["buy"]}],
+				"principals": [["user:alice"]]
+			},
+			{
+				"id": "refund",
+				"effect": "deny",
+				"permissions": [{"resource": "/items", "actions": ["refund"]}],
+				"principals": [["user:alice"]]
+			}
+			]
+		},
+		{
+			"name": "blog",
+			"mode": "discover"
+		}
+		]
+	}
+	`
+	// the discover requests are recorded next to the policy file
+	dir, err := ioutil.TempDir("", "servicemode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	storeFile := filepath.Join(dir, "ps.json")
+	if err := ioutil.WriteFile(storeFile, []byte(appStream), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ps, err := store.NewStore(cfg.StorageTypeFile, map[string]interface{}{"FileLocation": storeFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	evaluator, err := NewWithStore(conf, ps)
+	if err != nil {
+		t.Fatalf("Unable to initialize evaluator due to error [%v].", err)
+	}
+	d := ps.(store.DiscoverRequestManager)
+
+	alice := &adsapi.Subject{Principals: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_USER, Name: "alice"}}}
+	testCases := []struct {
+		service        string
+		action         string
+		reason         adsapi.Reason
+		policyDecision bool
+		policyReason   adsapi.Reason
+	}{
+		{"shop", "buy", adsapi.GRANT_POLICY_FOUND, true, adsapi.GRANT_POLICY_FOUND},
+		{"shop", "refund", adsapi.SHADOW_MODE, false, adsapi.DENY_POLICY_FOUND},
+		{"shop", "sell", adsapi.SHADOW_MODE, false, adsapi.NO_APPLICABLE_POLICIES},
+		{"blog", "post", adsapi.DISCOVER_MODE, false, adsapi.NO_APPLICABLE_POLICIES},
+	}
+	for _, tc := range testCases {
+		allowed, reason, err := evaluator.IsAllowed(adsapi.RequestContext{
+			Subject: alice, ServiceName: tc.service, Resource: "/items", Action: tc.action,
+			// callers can't set the shadow reason
+			ShadowReason: "injected",
+		})
+		if !allowed || reason != tc.reason || err != nil {
+			t.Errorf("%s %s: expected allowed with reason %s, got %v, %s, %v", tc.service, tc.action, tc.reason, allowed, reason, err)
+		}
+
+		// Replayed decisions apply the mode too
+		ctx := adsapi.RequestContext{Subject: alice, ServiceName: tc.service, Resource: "/items", Action: tc.action}
+		if record := evaluator.(*PolicyEvalImpl).Decide(ctx); record.Decision != decisionlog.DecisionAllow || record.Reason != tc.reason.String() {
+			t.Errorf("%s %s: expected decision allow with reason %s, got %s, %s", tc.service, tc.action, tc.reason, record.Decision, record.Reason)
+		}
+
+		// Internal authorization ignores the mode
+		allowed, reason, _ = evaluator.IsAllowedByPolicies(ctx)
+		if allowed != tc.policyDecision || reason != tc.policyReason {
+			t.Errorf("%s %s: expected %v with reason %s by policies, got %v, %s", tc.service, tc.action, tc.policyDecision, tc.policyReason, allowed, reason)
+		}
+	}
+
+	// Only the requests which would have been denied are recorded in shadow mode, and only by IsAllowed
+	requests, _, err := d.GetDiscoverRequests("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	shadowReasons := map[string]string{}
+	for _, request := range requests {
+		shadowReasons[request.Action] = request.ShadowReason
+	}
+	if len(shadowReasons) != 2 || shadowReasons["refund"] != adsapi.DENY_POLICY_FOUND.String() ||
+		shadowReasons["sell"] != adsapi.NO_APPLICABLE_POLICIES.String() {
+		t.Errorf("unexpected requests recorded in shadow mode: %v", shadowReasons)
+	}
+
+	requests, _, err = d.GetDiscoverRequests("blog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 || requests[0].Action != "post" || requests[0].ShadowReason != "" {
+		t.Errorf("request should be recorded in discover mode, got %v", requests)
+	}
+}
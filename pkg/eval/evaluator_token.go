@@ -0,0 +1,112 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"strings"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/assertion"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTokenAttributeNamespace = "token"
+	defaultTokenAttributeSeparator = "."
+)
+
+// NewAsserterFunc returns the function asserting the identity token of a request with the
+// given asserter. The asserted principals are added to the subject, and the asserted
+// attributes are kept in the subject for condition evaluation.
+func NewAsserterFunc(as assertion.TokenAsserter) func(ctx *adsapi.RequestContext) error {
+	return func(ctx *adsapi.RequestContext) error {
+		if ctx.Subject != nil &&
+			len(ctx.Subject.TokenType) != 0 &&
+			len(ctx.Subject.Token) != 0 {
+			tokenType := ctx.Subject.TokenType
+			token := ctx.Subject.Token
+			log.Debugf("Asserting token %s with token type %s.", token, tokenType)
+			s, err := as.AssertToken(token, tokenType, "", tracing.Headers(ctx.TraceContext))
+			if err == nil {
+				for _, p := range s.Principals {
+					ctx.Subject.Principals = append(ctx.Subject.Principals, p)
+				}
+				ctx.Subject.AssertedAttributes = s.Attributes
+			} else {
+				log.Errorf("Failed to assert due to error %v.", err)
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// tokenAttributeName returns the name of an asserted attribute in conditions
+func (p *PolicyEvalImpl) tokenAttributeName(name string) string {
+	namespace := defaultTokenAttributeNamespace
+	separator := defaultTokenAttributeSeparator
+	if p.TokenAttributes != nil {
+		if len(p.TokenAttributes.Namespace) > 0 {
+			namespace = p.TokenAttributes.Namespace
+		}
+		if len(p.TokenAttributes.Separator) > 0 {
+			separator = p.TokenAttributes.Separator
+		}
+	}
+	return namespace + separator + name
+}
+
+func (p *PolicyEvalImpl) tokenAttributesDisabled() bool {
+	return p.TokenAttributes != nil && p.TokenAttributes.Disabled
+}
+
+func (p *PolicyEvalImpl) allowTokenAttributeOverride() bool {
+	return p.TokenAttributes != nil && p.TokenAttributes.AllowOverride
+}
+
+// mergeAssertedAttributes adds the attributes returned by the token asserter to the
+// evaluation attributes under the configured namespace. Unless overriding is allowed, the
+// attributes sent by the caller under the namespace are dropped, including those the token
+// doesn't carry, so that callers can't forge them.
+func (p *PolicyEvalImpl) mergeAssertedAttributes(ctx *adsapi.RequestContext, attributes map[string]interface{}) {
+	if p.tokenAttributesDisabled() {
+		return
+	}
+	if !p.allowTokenAttributeOverride() {
+		prefix := p.tokenAttributeName("")
+		for name := range ctx.Attributes {
+			if strings.HasPrefix(name, prefix) {
+				delete(attributes, name)
+			}
+		}
+	}
+	if ctx.Subject == nil {
+		return
+	}
+	for name, value := range ctx.Subject.AssertedAttributes {
+		attrName := p.tokenAttributeName(name)
+		if _, ok := ctx.Attributes[attrName]; ok && p.allowTokenAttributeOverride() {
+			continue
+		}
+		attributes[attrName] = value
+	}
+}
+
+// isAssertedAttribute returns whether an evaluation attribute comes from the token asserter
+func (p *PolicyEvalImpl) isAssertedAttribute(ctx *adsapi.RequestContext, attrName string) bool {
+	if ctx.Subject == nil || len(ctx.Subject.AssertedAttributes) == 0 || p.tokenAttributesDisabled() {
+		return false
+	}
+	prefix := p.tokenAttributeName("")
+	if !strings.HasPrefix(attrName, prefix) {
+		return false
+	}
+	if _, ok := ctx.Subject.AssertedAttributes[attrName[len(prefix):]]; !ok {
+		return false
+	}
+	_, sentByCaller := ctx.Attributes[attrName]
+	return !sentByCaller || !p.allowTokenAttributeOverride()
+}
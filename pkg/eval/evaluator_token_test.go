@@ -0,0 +1,358 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/assertion"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+)
+
+type fakeAsserter struct {
+	subjects map[string]*assertion.AssertResponse
+}
+
+func (a *fakeAsserter) AssertToken(token string, idpType string, allowedIDD string, requestHeaders map[string]string) (*assertion.AssertResponse, error) {
+	if s, ok := a.subjects[token]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("invalid token")
+}
+
+func TestAssertedAttributes(t *testing.T) {
+	ps := pms.PolicyStore{
+		Services: []*pms.Service{
+			{
+				Name: "crm",
+				Policies: []*pms.Policy{
+					{
+						ID:          "p1",
+						Effect:      pms.Grant,
+						Permissions: []*pms.Permission{{Resource: "/report", Actions: []string{"read"}}},
+						Principals:  [][]string{{"user:alice"}},
+						Condition:   "[token.dept] == 'eng' && [token.clearance] >= 2",
+					},
+					{
+						ID:          "p2",
+						Effect:      pms.Grant,
+						Permissions: []*pms.Permission{{Resource: "/plan", Actions: []string{"read"}}},
+						Principals:  [][]string{{"user:alice"}},
+						Condition:   "idp_dept == 'eng'",
+					},
+					{
+						ID:          "p3",
+						Effect:      pms.Grant,
+						Permissions: []*pms.Permission{{Resource: "/budget", Actions: []string{"read"}}},
+						Condition:   "[token.role] == 'manager'",
+					},
+				},
+			},
+		},
+	}
+	testPS.WritePolicyStore(&ps)
+
+	asserter := &fakeAsserter{
+		subjects: map[string]*assertion.AssertResponse{
+			"alice-token": {
+				Principals: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_USER, Name: "alice"}},
+				Attributes: map[string]interface{}{"dept": "eng", "clearance": 3.0},
+			},
+		},
+	}
+
+	testCases := []struct {
+		tokenAttributes *cfg.TokenAttributesConfig
+		resource        string
+		attributes      map[string]interface{}
+		allowed         bool
+	}{
+		{nil, "/report", nil, true},
+		// Caller can't override asserted attributes by default
+		{nil, "/report", map[string]interface{}{"token.dept": "sales"}, true},
+		{&cfg.TokenAttributesConfig{AllowOverride: true}, "/report", map[string]interface{}{"token.dept": "sales"}, false},
+		{&cfg.TokenAttributesConfig{Disabled: true}, "/report", nil, false},
+		{&cfg.TokenAttributesConfig{Namespace: "idp", Separator: "_"}, "/plan", nil, true},
+		{&cfg.TokenAttributesConfig{Namespace: "idp", Separator: "_"}, "/report", nil, false},
+		// Caller can't send attributes under the namespace which the token doesn't carry
+		{nil, "/budget", map[string]interface{}{"token.role": "manager"}, false},
+		{&cfg.TokenAttributesConfig{AllowOverride: true}, "/budget", map[string]interface{}{"token.role": "manager"}, true},
+	}
+
+	for i, tc := range testCases {
+		tokenConf := *conf
+		tokenConf.TokenAttributes = tc.tokenAttributes
+		eval, err := NewWithStore(&tokenConf, testPS)
+		if err != nil {
+			t.Fatalf("error creating evaluator : %v", err)
+		}
+		eval.SetAsserterFunc(NewAsserterFunc(asserter))
+
+		ctx := adsapi.RequestContext{
+			Subject:     &adsapi.Subject{TokenType: "jwt", Token: "alice-token"},
+			ServiceName: "crm",
+			Resource:    tc.resource,
+			Action:      "read",
+			Attributes:  tc.attributes,
+		}
+		if allowed, _, err := eval.IsAllowed(ctx); allowed != tc.allowed || err != nil {
+			t.Errorf("case %d: got %v, %v, want %v", i, allowed, err, tc.allowed)
+		}
+		if tc.resource == "/budget" {
+			// Nor without a token
+			ctx.Subject = nil
+			if allowed, _, err := eval.IsAllowed(ctx); allowed != tc.allowed || err != nil {
+				t.Errorf("case %d without token: got %v, %v, want %v", i, allowed, err, tc.allowed)
+			}
+		}
+	}
+
+	eval, err := NewWithStore(conf, testPS)
+	if err != nil {
+		t.Fatalf("error creating evaluator : %v", err)
+	}
+	eval.SetAsserterFunc(NewAsserterFunc(asserter))
+	result, err := eval.Diagnose(adsapi.RequestContext{
+		Subject:     &adsapi.Subject{TokenType: "jwt", Token: "alice-token"},
+		ServiceName: "crm",
+		Resource:    "/report",
+		Action:      "read",
+		Attributes:  map[string]interface{}{"token.dept": "sales", "region": "eu"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"token.dept":      adsapi.AttributeSource_Token,
+		"token.clearance": adsapi.AttributeSource_Token,
+		"region":          adsapi.AttributeSource_Request,
+	}
+	for name, source := range expected {
+		if s, ok := result.AttributeSources[name]; !ok || s.Source != source {
+			t.Errorf("attribute %s should come from %s, got %v", name, source, s)
+		}
+	}
+	if !result.Allowed || result.Attributes["token.dept"] != "eng" {
+		t.Errorf("asserted attributes should be used, got %v", result.Attributes)
+	}
+}
+
+func TestJWTAsserterConfig(t *testing.T) {
+	ps := pms.PolicyStore{
+		Services: []*pms.Service{
+			{
+				Name: "crm",
+				Policies: []*pms.Policy{
+					{
+						ID:          "p1",
+						Effect:      pms.Grant,
+						Permissions: []*pms.Permission{{Resource: "/report", Actions: []string{"read"}}},
+						Principals:  [][]string{{"group:sales"}},
+						Condition:   "[token.region] == 'eu'",
+					},
+				},
+			},
+		},
+	}
+	testPS.WritePolicyStore(&ps)
+
+	jwtConf := *conf
+	jwtConf.JWTAsserterConfig = &assertion.JWTAsserterConfig{
+		TokenTypes: []string{"bearer"},
+		StaticKeys: []*assertion.StaticKey{{Secret: "top-secret"}},
+		ClaimMappings: &assertion.ClaimMappings{
+			Attributes: map[string]string{"region": "region"},
+		},
+	}
+	eval, err := NewWithStore(&jwtConf, testPS)
+	if err != nil {
+		t.Fatalf("error creating evaluator : %v", err)
+	}
+
+	sign := func(secret string, region string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":    "bob",
+			"groups": []string{"sales"},
+			"region": region,
+			"exp":    time.Now().Unix() + 60,
+		})
+		s, err := token.SignedString([]byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	testCases := []struct {
+		tokenType string
+		token     string
+		allowed   bool
+	}{
+		{"bearer", sign("top-secret", "eu"), true},
+		{"bearer", sign("top-secret", "us"), false},
+		{"bearer", sign("guess", "eu"), false},
+		// no asserter for the token type
+		{"saml", sign("top-secret", "eu"), false},
+	}
+	for i, tc := range testCases {
+		ctx := adsapi.RequestContext{
+			Subject:     &adsapi.Subject{TokenType: tc.tokenType, Token: tc.token},
+			ServiceName: "crm",
+			Resource:    "/report",
+			Action:      "read",
+		}
+		if allowed, _, _ := eval.IsAllowed(ctx); allowed != tc.allowed {
+			t.Errorf("case %d: got %v, want %v", i, allowed, tc.allowed)
+		}
+	}
+
+	jwtConf.JWTAsserterConfig = &assertion.JWTAsserterConfig{}
+	if _, err := NewWithStore(&jwtConf, testPS); err == nil {
+		t.Error("JWT asserter without keys should be rejected")
+	}
+}
+
+func TestTokenAssertionWithFailureMode(t *testing.T) {
+	ps := pms.PolicyStore{
+		Services: []*pms.Service{
+			{
+				Name:        "crm",
+				FailureMode: pms.FailOpen,
+				Policies: []*pms.Policy{
+					{
+						ID:          "p1",
+						Effect:      pms.Grant,
+						Permissions: []*pms.Permission{{Resource: "/report", Actions: []string{"read"}}},
+						Principals:  [][]string{{"group:sales"}},
+					},
+				},
+			},
+		},
+	}
+	testPS.WritePolicyStore(&ps)
+
+	jwtConf := *conf
+	jwtConf.JWTAsserterConfig = &assertion.JWTAsserterConfig{StaticKeys: []*assertion.StaticKey{{Secret: "top-secret"}}}
+	eval, err := NewWithStore(&jwtConf, testPS)
+	if err != nil {
+		t.Fatalf("error creating evaluator : %v", err)
+	}
+	sign := func(secret string, exp int64) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "bob", "groups": []string{"sales"}, "exp": exp})
+		s, err := token.SignedString([]byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	// Tokens rejected by the asserter are denied even if the service fails open
+	testCases := []struct {
+		name    string
+		token   string
+		allowed bool
+		reason  adsapi.Reason
+	}{
+		{"valid token", sign("top-secret", time.Now().Unix()+60), true, adsapi.GRANT_POLICY_FOUND},
+		{"forged token", sign("guess", time.Now().Unix()+60), false, adsapi.TOKEN_REJECTED},
+		{"expired token", sign("top-secret", time.Now().Unix()-3600), false, adsapi.TOKEN_REJECTED},
+		{"malformed token", "not-a-jwt", false, adsapi.TOKEN_REJECTED},
+	}
+	for _, tc := range testCases {
+		ctx := adsapi.RequestContext{
+			Subject:     &adsapi.Subject{TokenType: assertion.TokenTypeJWT, Token: tc.token},
+			ServiceName: "crm",
+			Resource:    "/report",
+			Action:      "read",
+		}
+		if allowed, reason, _ := eval.IsAllowed(ctx); allowed != tc.allowed || reason != tc.reason {
+			t.Errorf("%s: expected %v/%s, got %v/%s", tc.name, tc.allowed, tc.reason, allowed, reason)
+		}
+	}
+
+	// The service fails open if the asserter is unavailable
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	introspectionConf := *conf
+	introspectionConf.IntrospectionAsserterConfig = &assertion.IntrospectionAsserterConfig{Endpoint: server.URL}
+	eval, err = NewWithStore(&introspectionConf, testPS)
+	if err != nil {
+		t.Fatalf("error creating evaluator : %v", err)
+	}
+	ctx := adsapi.RequestContext{
+		Subject:     &adsapi.Subject{TokenType: assertion.TokenTypeOpaque, Token: "opaque-token"},
+		ServiceName: "crm",
+		Resource:    "/report",
+		Action:      "read",
+	}
+	if allowed, reason, _ := eval.IsAllowed(ctx); !allowed || reason != adsapi.FAIL_OPEN {
+		t.Errorf("unavailable asserter: expected true/%s, got %v/%s", adsapi.FAIL_OPEN, allowed, reason)
+	}
+}
+
+func TestIntrospectionAsserterConfig(t *testing.T) {
+	ps := pms.PolicyStore{
+		Services: []*pms.Service{
+			{
+				Name: "crm",
+				Policies: []*pms.Policy{
+					{
+						ID:          "p1",
+						Effect:      pms.Grant,
+						Permissions: []*pms.Permission{{Resource: "/report", Actions: []string{"write"}}},
+						Principals:  [][]string{{"entity:portal"}},
+						Condition:   "'write' IN [token.scope]",
+					},
+				},
+			},
+		},
+	}
+	testPS.WritePolicyStore(&ps)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.PostFormValue("token") {
+		case "rw-token":
+			json.NewEncoder(w).Encode(map[string]interface{}{"active": true, "client_id": "portal", "scope": "read write"})
+		case "ro-token":
+			json.NewEncoder(w).Encode(map[string]interface{}{"active": true, "client_id": "portal", "scope": "read"})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"active": false})
+		}
+	}))
+	defer server.Close()
+
+	introspectionConf := *conf
+	introspectionConf.IntrospectionAsserterConfig = &assertion.IntrospectionAsserterConfig{Endpoint: server.URL}
+	eval, err := NewWithStore(&introspectionConf, testPS)
+	if err != nil {
+		t.Fatalf("error creating evaluator : %v", err)
+	}
+
+	testCases := []struct {
+		token   string
+		allowed bool
+	}{
+		{"rw-token", true},
+		{"ro-token", false},
+		{"revoked-token", false},
+	}
+	for _, tc := range testCases {
+		ctx := adsapi.RequestContext{
+			Subject:     &adsapi.Subject{TokenType: assertion.TokenTypeOpaque, Token: tc.token},
+			ServiceName: "crm",
+			Resource:    "/report",
+			Action:      "write",
+		}
+		if allowed, _, _ := eval.IsAllowed(ctx); allowed != tc.allowed {
+			t.Errorf("%s: got %v, want %v", tc.token, allowed, tc.allowed)
+		}
+	}
+}
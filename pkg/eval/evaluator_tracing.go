@@ -0,0 +1,20 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"github.com/teramoby/speedle-plus/pkg/tracing"
+)
+
+// startSpan starts a child span of the current span of the request, the new span is the current span
+// until the returned function ends it
+func (ctx *internalRequestContext) startSpan(name string) (*tracing.Span, func()) {
+	parent := ctx.TraceContext
+	var span *tracing.Span
+	ctx.TraceContext, span = tracing.StartSpan(parent, name, tracing.SpanKindInternal)
+	return span, func() {
+		span.End()
+		ctx.TraceContext = parent
+	}
+}
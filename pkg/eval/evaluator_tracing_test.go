@@ -0,0 +1,160 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/teramoby/speedle-plus/3rdparty/github.com/Knetic/govaluate"
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/ext"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/assertion"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
+)
+
+func TestTracing(t *testing.T) {
+	var lock sync.Mutex
+	traceparents := map[string]string{}
+	record := func(name string, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		traceparents[name] = r.Header.Get(tracing.TraceparentHeader)
+	}
+	funcServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record("function", r)
+		json.NewEncoder(w).Encode(&ext.CustomerFunctionResponse{Result: true})
+	}))
+	defer funcServer.Close()
+	introspectionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record("assertion", r)
+		json.NewEncoder(w).Encode(map[string]interface{}{"active": true, "client_id": "portal"})
+	}))
+	defer introspectionServer.Close()
+
+	ps := pms.PolicyStore{
+		Functions: []*pms.Function{{Name: "traced", FuncURL: funcServer.URL}},
+		Services: []*pms.Service{
+			{
+				Name: "crm",
+				Policies: []*pms.Policy{
+					{
+						ID:          "p1",
+						Effect:      pms.Grant,
+						Permissions: []*pms.Permission{{Resource: "/report", Actions: []string{"read"}}},
+						Condition:   "Sqrt(4) == 2 && traced(request_resource)",
+					},
+				},
+			},
+		},
+	}
+	testPS.WritePolicyStore(&ps)
+	tracingConf := *conf
+	tracingConf.IntrospectionAsserterConfig = &assertion.IntrospectionAsserterConfig{Endpoint: introspectionServer.URL}
+	eval, err := NewWithStore(&tracingConf, testPS)
+	if err != nil {
+		t.Fatalf("error creating evaluator : %v", err)
+	}
+
+	exporter := &tracing.InMemoryExporter{}
+	tracing.SetTracer(tracing.NewTracer(exporter, 1, false))
+	defer tracing.SetTracer(nil)
+
+	parent, _ := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	traceCtx, span := tracing.StartSpan(tracing.ContextWithRemoteParent(context.Background(), parent), "is-allowed", tracing.SpanKindServer)
+	ctx := adsapi.RequestContext{
+		Subject:      &adsapi.Subject{TokenType: assertion.TokenTypeOpaque, Token: "alice-token"},
+		ServiceName:  "crm",
+		Resource:     "/report",
+		Action:       "read",
+		TraceContext: traceCtx,
+	}
+	allowed, reason, err := eval.IsAllowed(ctx)
+	span.End()
+	if err != nil || !allowed {
+		t.Fatalf("got %v, %v, %v, want allowed", allowed, reason, err)
+	}
+
+	spans := map[string]*tracing.SpanData{}
+	for _, s := range exporter.Spans() {
+		if s.TraceID != parent.TraceIDString() {
+			t.Errorf("span %s is in trace %s, want %s", s.Name, s.TraceID, parent.TraceIDString())
+		}
+		spans[s.Name] = s
+	}
+	serverSpanID := span.SpanContext().SpanIDString()
+	parents := map[string]string{
+		"is-allowed":           parent.SpanIDString(),
+		"token-assertion":      serverSpanID,
+		"role-resolution":      serverSpanID,
+		"policy-lookup":        serverSpanID,
+		"condition-evaluation": spans["policy-lookup"].SpanID,
+		"function-call":        spans["condition-evaluation"].SpanID,
+	}
+	for name, parentID := range parents {
+		s, ok := spans[name]
+		if !ok {
+			t.Errorf("span %s is not exported", name)
+			continue
+		}
+		if s.ParentSpanID != parentID {
+			t.Errorf("parent of span %s is %s, want %s", name, s.ParentSpanID, parentID)
+		}
+	}
+	if attrs := spans["function-call"].Attributes; attrs["function"] != "traced" {
+		t.Errorf("unexpected attributes of function call span %v", attrs)
+	}
+
+	// The trace context is propagated to the function and the introspection endpoint
+	wants := map[string]string{
+		"function":  "00-" + parent.TraceIDString() + "-" + spans["function-call"].SpanID + "-01",
+		"assertion": "00-" + parent.TraceIDString() + "-" + spans["token-assertion"].SpanID + "-01",
+	}
+	for name, want := range wants {
+		if traceparents[name] != want {
+			t.Errorf("traceparent of %s request is %q, want %q", name, traceparents[name], want)
+		}
+	}
+}
+
+func TestBindCondition(t *testing.T) {
+	type traceKey struct{}
+	var received []interface{}
+	risk := customerFunction(func(traceCtx context.Context, arguments ...interface{}) (interface{}, error) {
+		received = append(received, traceCtx.Value(traceKey{}))
+		return float64(len(arguments)), nil
+	})
+	rtps := NewRuntimePolicyStore()
+	rtps.Functions = map[string]govaluate.ExpressionFunction{
+		"risk": risk.bind(context.Background()),
+		"Sqrt": builtinFunctions["Sqrt"],
+	}
+	rtps.customerFunctions = map[string]customerFunction{"risk": risk}
+
+	condition, err := compileCondition(`risk(a, 'risk(x)') == 2 && Sqrt(4) == 2`, rtps.Functions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bound, err := rtps.bindCondition(context.WithValue(context.Background(), traceKey{}, "evaluation"), condition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bound.String() != condition.String() {
+		t.Errorf("bound condition is %q, want %q", bound.String(), condition.String())
+	}
+	parameters := govaluate.MapParameters{"a": 1}
+	for _, exp := range []*govaluate.EvaluableExpression{bound, condition} {
+		if res, err := exp.Eval(parameters); err != nil || res != true {
+			t.Errorf("condition %q evaluates to %v, %v", exp.String(), res, err)
+		}
+	}
+	if len(received) != 2 || received[0] != "evaluation" || received[1] != nil {
+		t.Errorf("contexts received by the function are %v", received)
+	}
+}
@@ -4,12 +4,14 @@
 package eval
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
 	"github.com/teramoby/speedle-plus/3rdparty/github.com/Knetic/govaluate"
 	adsapi "github.com/teramoby/speedle-plus/api/ads"
 	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -171,8 +173,27 @@ func calculatePermissions(grantedPermissions, deniedPermissions []pms.Permission
 
 }
 
-func evaluateCondition(condition *govaluate.EvaluableExpression, attributes map[string]interface{}) (bool, error) {
-	res, err := condition.Evaluate(attributes)
+func (rtps *RuntimePolicyStore) evaluateCondition(traceCtx context.Context, policyID string, condition *govaluate.EvaluableExpression, attributes govaluate.Parameters) (result bool, err error) {
+	if tracing.Enabled() {
+		var span *tracing.Span
+		traceCtx, span = tracing.StartSpan(traceCtx, "condition-evaluation", tracing.SpanKindInternal)
+		span.SetAttribute("policy.id", policyID)
+		span.SetAttribute("condition", condition.String())
+		defer func() {
+			span.SetAttribute("result", result)
+			span.RecordError(err)
+			span.End()
+		}()
+	}
+	evaluable := condition
+	if tracing.SpanContextFromContext(traceCtx).IsValid() {
+		// functions called by the condition are children of the span
+		if evaluable, err = rtps.bindCondition(traceCtx, condition); err != nil {
+			log.Errorf("Error happens in parsing condition (%s): %v", condition.String(), err)
+			return false, err
+		}
+	}
+	res, err := evaluable.Eval(attributes)
 	if err != nil || res != true {
 		if err != nil {
 			log.Errorf("Error happens in evaluating condition (%s): %v", condition.String(), err)
@@ -182,6 +203,22 @@ func evaluateCondition(condition *govaluate.EvaluableExpression, attributes map[
 	return true, nil
 }
 
+// bindCondition compiles the condition again with the customer functions bound to the context of the
+// evaluation. Conditions are compiled once with the functions called without context, so only traced
+// evaluations pay for the compilation.
+func (rtps *RuntimePolicyStore) bindCondition(traceCtx context.Context, condition *govaluate.EvaluableExpression) (*govaluate.EvaluableExpression, error) {
+	rtps.RLock()
+	functions := make(map[string]govaluate.ExpressionFunction, len(rtps.Functions))
+	for name, function := range rtps.Functions {
+		functions[name] = function
+	}
+	for name, cf := range rtps.customerFunctions {
+		functions[name] = cf.bind(traceCtx)
+	}
+	rtps.RUnlock()
+	return govaluate.NewEvaluableExpressionWithFunctions(condition.String(), functions)
+}
+
 func matchRolePolicyPrincipals(subjectPrincipalList []string, rolePolicyPrincipalList []string) bool {
 	if subjectPrincipalList == nil || len(subjectPrincipalList) == 0 {
 		return false
@@ -244,3 +281,9 @@ func matchPrincipals(subjectPrincipalList []string, policyPrincipalList [][]stri
 
 	return false
 }
+
+// isFunctionReferenced returns whether a condition expression calls the function
+func isFunctionReferenced(condition string, funcName string) bool {
+	matched, err := regexp.MatchString(`\b`+regexp.QuoteMeta(funcName)+`\s*\(`, condition)
+	return err == nil && matched
+}
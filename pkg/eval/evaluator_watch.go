@@ -0,0 +1,115 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"time"
+
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Backoff of re-establishing a failed watch of the policy store
+var (
+	watchMinBackoff = 500 * time.Millisecond
+	watchMaxBackoff = 30 * time.Second
+)
+
+// superviseWatch applies the changes of the policy store to the runtime cache. When the watch fails,
+// it is re-established with exponential backoff. The new watch resumes after the last applied change
+// if the store supports it, otherwise the runtime cache is resynchronized with the store.
+func (p *PolicyEvalImpl) superviseWatch(updateChan pms.StorageChangeChannel) {
+	var last *pms.StoreChangeEvent
+	for {
+		if event := p.updateRuntimeCacheWithStoreChange(updateChan); event != nil {
+			last = event
+		}
+		if p.watchStopped() {
+			log.Info("Watch of the policy store is stopped.")
+			p.health.watchStopped()
+			return
+		}
+
+		log.Warning("Watch of the policy store is lost, policies may be stale until it is re-established.")
+		p.health.watchLost()
+		var resumed bool
+		backoff := watchMinBackoff
+		for attempt := 1; ; attempt++ {
+			var err error
+			if updateChan, resumed, err = p.rewatch(last); err == nil {
+				break
+			}
+			log.Warningf("Failed to re-establish the watch of the policy store (attempt %d), retrying in %v: %v", attempt, backoff, err)
+			p.health.watchRetryFailed(err)
+			select {
+			case <-time.After(backoff):
+			case <-p.watchStop:
+				p.health.watchStopped()
+				return
+			}
+			if backoff *= 2; backoff > watchMaxBackoff {
+				backoff = watchMaxBackoff
+			}
+		}
+		if p.watchStopped() {
+			// stopped while the watch was being re-established
+			p.stopStoreWatch(updateChan)
+			p.health.watchStopped()
+			return
+		}
+		if resumed {
+			log.Infof("Watch of the policy store is resumed after revision %d.", last.Revision)
+		} else {
+			log.Info("Watch of the policy store is re-established, the runtime cache is resynchronized.")
+		}
+		p.health.watchRestarted(resumed)
+	}
+}
+
+// rewatch re-establishes the watch of the policy store. It returns true if the watch resumes after
+// the last change. If the store is reachable but can't resume, the runtime cache is resynchronized
+// with the store once a new watch is established, so that no change is missed in between.
+func (p *PolicyEvalImpl) rewatch(last *pms.StoreChangeEvent) (pms.StorageChangeChannel, bool, error) {
+	if watcher, ok := p.Store.(store.ResumableWatcher); ok && last != nil {
+		updateChan, err := watcher.ResumeWatch(last)
+		if err == nil {
+			return updateChan, true, nil
+		}
+		if !store.CheckStore(p.Store).Reachable {
+			// try to resume again when the store is back
+			return nil, false, err
+		}
+		log.Warningf("Failed to resume the watch of the policy store, the runtime cache will be resynchronized: %v", err)
+	}
+
+	updateChan, err := p.Store.Watch()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := p.reloadRuntimeCache(); err != nil {
+		p.stopStoreWatch(updateChan)
+		return nil, false, err
+	}
+	return updateChan, false, nil
+}
+
+// stopStoreWatch stops the watch of the store and discards its pending changes
+func (p *PolicyEvalImpl) stopStoreWatch(updateChan pms.StorageChangeChannel) {
+	go func() {
+		for range updateChan {
+		}
+	}()
+	p.Store.StopWatch()
+}
+
+func (p *PolicyEvalImpl) watchStopped() bool {
+	select {
+	case <-p.watchStop:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,103 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package eval
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// timeNow returns the current time, tests replace it to move the clock
+var timeNow = time.Now
+
+// isActive checks whether a policy or role policy takes effect at an instant. notBefore is inclusive
+// and notAfter is exclusive.
+func isActive(disabled bool, notBefore, notAfter *time.Time, now time.Time) bool {
+	if disabled {
+		return false
+	}
+	if notBefore != nil && now.Before(*notBefore) {
+		return false
+	}
+	if notAfter != nil && !now.Before(*notAfter) {
+		return false
+	}
+	return true
+}
+
+// nextActivationChange returns the first instant after now at which a policy or role policy is
+// activated or expires, or zero time if it never changes any more
+func nextActivationChange(disabled bool, notBefore, notAfter *time.Time, now time.Time) time.Time {
+	if disabled {
+		return time.Time{}
+	}
+	if notBefore != nil && now.Before(*notBefore) {
+		if notAfter != nil && !notBefore.Before(*notAfter) {
+			// never active
+			return time.Time{}
+		}
+		return *notBefore
+	}
+	if notAfter != nil && now.Before(*notAfter) {
+		return *notAfter
+	}
+	return time.Time{}
+}
+
+// earlier returns the earlier of two instants, zero time means never
+func earlier(t1, t2 time.Time) time.Time {
+	if t1.IsZero() || (!t2.IsZero() && t2.Before(t1)) {
+		return t2
+	}
+	return t1
+}
+
+// refreshActivation moves policies and role policies between the index and the inactive policies
+// when they are activated or expire, and schedules the next change. The service must be locked.
+func (svc *RuntimeService) refreshActivation(now time.Time) {
+	if svc.activationStopped {
+		return
+	}
+	policies := svc.PoliciesCache.clone()
+	policies.refreshActivation(now)
+	svc.PoliciesCache = policies
+	rolePolicies := svc.RolePoliciesCache.clone()
+	rolePolicies.refreshActivation(now)
+	svc.RolePoliciesCache = rolePolicies
+	svc.scheduleActivation(now)
+}
+
+// scheduleActivation sets the timer refreshing the service when its next policy or role policy is
+// activated or expires. The service must be locked.
+func (svc *RuntimeService) scheduleActivation(now time.Time) {
+	if svc.activationStopped {
+		return
+	}
+	next := earlier(svc.PoliciesCache.nextActivationChange(now), svc.RolePoliciesCache.nextActivationChange(now))
+	if svc.activationTimer != nil {
+		svc.activationTimer.Stop()
+		svc.activationTimer = nil
+	}
+	if next.IsZero() {
+		return
+	}
+	log.Debugf("Next policy activation change of service %q at %s.", svc.Name, next)
+	svc.activationTimer = time.AfterFunc(next.Sub(now), func() {
+		svc.Lock()
+		defer svc.Unlock()
+		svc.refreshActivation(timeNow())
+	})
+}
+
+// stopActivation stops refreshing a service which is removed from the runtime cache
+func (svc *RuntimeService) stopActivation() {
+	svc.Lock()
+	defer svc.Unlock()
+	svc.activationStopped = true
+	if svc.activationTimer != nil {
+		svc.activationTimer.Stop()
+		svc.activationTimer = nil
+	}
+}
@@ -5,6 +5,7 @@ package eval
 
 import (
 	"regexp"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -15,6 +16,8 @@ import (
 type PolicyCacheData struct {
 	BasePolicyCacheData
 	PolicyMap map[string]*pms.Policy
+	// disabled, not yet active or expired policies, which are not indexed
+	InactivePolicyMap map[string]*pms.Policy
 }
 
 func NewPolicyCacheData() (p *PolicyCacheData) {
@@ -24,10 +27,28 @@ func NewPolicyCacheData() (p *PolicyCacheData) {
 			NilPrincipalToPolicies: &ResourceToPolicyMap{},
 			Conditions:             make(map[string]*govaluate.EvaluableExpression),
 		},
-		PolicyMap: make(map[string]*pms.Policy),
+		PolicyMap:         make(map[string]*pms.Policy),
+		InactivePolicyMap: make(map[string]*pms.Policy),
 	}
 }
 
+// clone returns a deep copy of the cache. The caches of a service are copied on write, so that
+// evaluations read a snapshot of the policies without locking the service.
+func (p *PolicyCacheData) clone() *PolicyCacheData {
+	ret := &PolicyCacheData{
+		BasePolicyCacheData: p.BasePolicyCacheData.clone(),
+		PolicyMap:           make(map[string]*pms.Policy, len(p.PolicyMap)),
+		InactivePolicyMap:   make(map[string]*pms.Policy, len(p.InactivePolicyMap)),
+	}
+	for id, policy := range p.PolicyMap {
+		ret.PolicyMap[id] = policy
+	}
+	for id, policy := range p.InactivePolicyMap {
+		ret.InactivePolicyMap[id] = policy
+	}
+	return ret
+}
+
 func nilPrincipalPolicy(policy *pms.Policy) (result bool) {
 	if policy.Principals == nil || len(policy.Principals) == 0 {
 		return true
@@ -37,11 +58,26 @@ func nilPrincipalPolicy(policy *pms.Policy) (result bool) {
 }
 
 func (p *PolicyCacheData) AddPolicyToCache(policy *pms.Policy, condition *govaluate.EvaluableExpression) {
-	//First add to PolicyMap
-	p.PolicyMap[policy.ID] = policy
+	p.addPolicyToCache(policy, condition, timeNow())
+}
+
+func (p *PolicyCacheData) addPolicyToCache(policy *pms.Policy, condition *govaluate.EvaluableExpression, now time.Time) {
+	active := isActive(policy.Disabled, policy.NotBefore, policy.NotAfter, now)
+	if _, indexed := p.PolicyMap[policy.ID]; indexed && !active {
+		p.DeletePolicyFromCache(policy.ID)
+	}
+	delete(p.InactivePolicyMap, policy.ID)
 	if condition != nil {
 		p.Conditions[policy.ID] = condition
 	}
+	//Inactive policies are not indexed
+	if !active {
+		p.InactivePolicyMap[policy.ID] = policy
+		return
+	}
+
+	//First add to PolicyMap
+	p.PolicyMap[policy.ID] = policy
 
 	//No principal defined. that means the permissions are granted to any principal
 	if nilPrincipalPolicy(policy) {
@@ -115,6 +151,13 @@ func (p *PolicyCacheData) addPolicyToResourceToPolicyMap(resourceToPolicyMap *Re
 }
 
 func (p *PolicyCacheData) DeletePolicyFromCache(policyID string) {
+	if policy, ok := p.InactivePolicyMap[policyID]; ok {
+		delete(p.InactivePolicyMap, policyID)
+		if len(policy.Condition) > 0 {
+			delete(p.Conditions, policyID)
+		}
+		return
+	}
 
 	//First delete from PolicyMap
 	policy := p.PolicyMap[policyID]
@@ -180,6 +223,36 @@ func (p *PolicyCacheData) deletePolicyFromResourceToPolicyMap(resourceToPolicyMa
 	}
 }
 
+// refreshActivation indexes the policies activated at now, and removes the expired ones from the index
+func (p *PolicyCacheData) refreshActivation(now time.Time) {
+	for id, policy := range p.PolicyMap {
+		if !isActive(policy.Disabled, policy.NotBefore, policy.NotAfter, now) {
+			condition := p.Conditions[id]
+			p.DeletePolicyFromCache(id)
+			p.addPolicyToCache(policy, condition, now)
+		}
+	}
+	for id, policy := range p.InactivePolicyMap {
+		if isActive(policy.Disabled, policy.NotBefore, policy.NotAfter, now) {
+			condition := p.Conditions[id]
+			delete(p.InactivePolicyMap, id)
+			p.addPolicyToCache(policy, condition, now)
+		}
+	}
+}
+
+// nextActivationChange returns the first instant after now at which a policy is activated or expires
+func (p *PolicyCacheData) nextActivationChange(now time.Time) time.Time {
+	var next time.Time
+	for _, policy := range p.PolicyMap {
+		next = earlier(next, nextActivationChange(policy.Disabled, policy.NotBefore, policy.NotAfter, now))
+	}
+	for _, policy := range p.InactivePolicyMap {
+		next = earlier(next, nextActivationChange(policy.Disabled, policy.NotBefore, policy.NotAfter, now))
+	}
+	return next
+}
+
 func (p *PolicyCacheData) GetRelatedPolicyMap(subjectPrincipals []string, resource string, matchResource bool) map[string]*pms.Policy {
 	resultPolicyMap := make(map[string]*pms.Policy)
 
@@ -302,3 +375,15 @@ func (p *PolicyCacheData) getPoliciesFromResourceExpressionMap(resourceToPolicyM
 	}
 
 }
+
+// allPolicyIDs returns the IDs of active and inactive policies
+func (p *PolicyCacheData) allPolicyIDs() map[string]bool {
+	ids := make(map[string]bool, len(p.PolicyMap)+len(p.InactivePolicyMap))
+	for id := range p.PolicyMap {
+		ids[id] = true
+	}
+	for id := range p.InactivePolicyMap {
+		ids[id] = true
+	}
+	return ids
+}
@@ -6,6 +6,7 @@ package eval
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/teramoby/speedle-plus/api/pms"
 )
@@ -235,3 +236,94 @@ func TestPolicyCacheAboutResourceExpression(t *testing.T) {
 		t.Errorf("The cache should be empty after delete all the policies")
 	}
 }
+
+func TestPolicyCacheActivation(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	cache := NewPolicyCacheData()
+
+	onCall := &pms.Policy{
+		ID:          "onCall",
+		Effect:      "grant",
+		Principals:  [][]string{{"user:alice"}},
+		Permissions: []*pms.Permission{{Resource: "/pager", Actions: []string{"ack"}}},
+		NotBefore:   &now,
+		NotAfter:    &later,
+	}
+	disabled := &pms.Policy{
+		ID:          "disabled",
+		Effect:      "grant",
+		Principals:  [][]string{{"user:alice"}},
+		Permissions: []*pms.Permission{{Resource: "/pager", Actions: []string{"ack"}}},
+		Disabled:    true,
+	}
+	cache.addPolicyToCache(onCall, nil, now.Add(-time.Minute))
+	cache.addPolicyToCache(disabled, nil, now.Add(-time.Minute))
+
+	if results := cache.GetRelatedPolicyMap([]string{"user:alice"}, "/pager", true); len(results) != 0 {
+		t.Errorf("inactive policies should not be indexed, got %v", results)
+	}
+	if next := cache.nextActivationChange(now.Add(-time.Minute)); !next.Equal(now) {
+		t.Errorf("next activation change should be %s, got %s", now, next)
+	}
+
+	cache.refreshActivation(now)
+	if results := cache.GetRelatedPolicyMap([]string{"user:alice"}, "/pager", true); len(results) != 1 || results["onCall"] == nil {
+		t.Errorf("activated policy should be indexed, got %v", results)
+	}
+	if next := cache.nextActivationChange(now); !next.Equal(later) {
+		t.Errorf("next activation change should be %s, got %s", later, next)
+	}
+
+	cache.refreshActivation(later)
+	if results := cache.GetRelatedPolicyMap([]string{"user:alice"}, "/pager", true); len(results) != 0 {
+		t.Errorf("expired policy should not be indexed, got %v", results)
+	}
+	if next := cache.nextActivationChange(later); !next.IsZero() {
+		t.Errorf("no activation change is expected, got %s", next)
+	}
+	if len(cache.InactivePolicyMap) != 2 {
+		t.Errorf("there should be 2 inactive policies, got %d", len(cache.InactivePolicyMap))
+	}
+
+	cache.DeletePolicyFromCache(onCall.ID)
+	if _, ok := cache.InactivePolicyMap[onCall.ID]; ok {
+		t.Errorf("deleted policy should be removed from inactive policies")
+	}
+}
+
+func TestPolicyCacheClone(t *testing.T) {
+	cache := NewPolicyCacheData()
+	cache.AddPolicyToCache(&pms.Policy{
+		ID:          "policy1",
+		Effect:      "grant",
+		Principals:  [][]string{{"user:Bill"}},
+		Permissions: []*pms.Permission{{Resource: "/node1", Actions: []string{"get"}}},
+	}, nil)
+	cache.AddPolicyToCache(&pms.Policy{
+		ID:          "policy2",
+		Effect:      "grant",
+		Principals:  [][]string{{"user:Bill"}},
+		Permissions: []*pms.Permission{{ResourceExpression: "/node.*", Actions: []string{"get"}}},
+	}, nil)
+
+	// Updates of the clone aren't visible in the original cache
+	clone := cache.clone()
+	clone.DeletePolicyFromCache("policy1")
+	clone.DeletePolicyFromCache("policy2")
+	clone.AddPolicyToCache(&pms.Policy{
+		ID:          "policy3",
+		Effect:      "grant",
+		Principals:  [][]string{{"user:Bill"}},
+		Permissions: []*pms.Permission{{Resource: "/node1", Actions: []string{"get"}}},
+	}, nil)
+
+	policies := cache.GetRelatedPolicyMap([]string{"user:Bill"}, "/node1", true)
+	if len(policies) != 2 || policies["policy1"] == nil || policies["policy2"] == nil {
+		t.Errorf("original cache is updated, got %v", policies)
+	}
+	policies = clone.GetRelatedPolicyMap([]string{"user:Bill"}, "/node1", true)
+	if len(policies) != 1 || policies["policy3"] == nil {
+		t.Errorf("unexpected policies of the clone %v", policies)
+	}
+}
@@ -5,6 +5,7 @@ package eval
 
 import (
 	"regexp"
+	"time"
 
 	"github.com/teramoby/speedle-plus/api/pms"
 
@@ -16,6 +17,8 @@ import (
 type RolePolicyCacheData struct {
 	BasePolicyCacheData
 	PolicyMap map[string]*pms.RolePolicy
+	// disabled, not yet active or expired role policies, which are not indexed
+	InactivePolicyMap map[string]*pms.RolePolicy
 }
 
 func NewRolePolicyCacheData() (p *RolePolicyCacheData) {
@@ -25,10 +28,28 @@ func NewRolePolicyCacheData() (p *RolePolicyCacheData) {
 			NilPrincipalToPolicies: &ResourceToPolicyMap{},
 			Conditions:             make(map[string]*govaluate.EvaluableExpression),
 		},
-		PolicyMap: make(map[string]*pms.RolePolicy),
+		PolicyMap:         make(map[string]*pms.RolePolicy),
+		InactivePolicyMap: make(map[string]*pms.RolePolicy),
 	}
 }
 
+// clone returns a deep copy of the cache. The caches of a service are copied on write, so that
+// evaluations read a snapshot of the role policies without locking the service.
+func (p *RolePolicyCacheData) clone() *RolePolicyCacheData {
+	ret := &RolePolicyCacheData{
+		BasePolicyCacheData: p.BasePolicyCacheData.clone(),
+		PolicyMap:           make(map[string]*pms.RolePolicy, len(p.PolicyMap)),
+		InactivePolicyMap:   make(map[string]*pms.RolePolicy, len(p.InactivePolicyMap)),
+	}
+	for id, policy := range p.PolicyMap {
+		ret.PolicyMap[id] = policy
+	}
+	for id, policy := range p.InactivePolicyMap {
+		ret.InactivePolicyMap[id] = policy
+	}
+	return ret
+}
+
 func nilPrincipalRolePolicy(rolePolicy *pms.RolePolicy) (result bool) {
 	if rolePolicy.Principals == nil || len(rolePolicy.Principals) == 0 {
 		return true
@@ -38,11 +59,26 @@ func nilPrincipalRolePolicy(rolePolicy *pms.RolePolicy) (result bool) {
 }
 
 func (p *RolePolicyCacheData) AddRolePolicyToCache(policy *pms.RolePolicy, condition *govaluate.EvaluableExpression) {
-	//First add role policy to PolicyMap
-	p.PolicyMap[policy.ID] = policy
+	p.addRolePolicyToCache(policy, condition, timeNow())
+}
+
+func (p *RolePolicyCacheData) addRolePolicyToCache(policy *pms.RolePolicy, condition *govaluate.EvaluableExpression, now time.Time) {
+	active := isActive(policy.Disabled, policy.NotBefore, policy.NotAfter, now)
+	if _, indexed := p.PolicyMap[policy.ID]; indexed && !active {
+		p.DeleteRolePolicyFromCache(policy.ID)
+	}
+	delete(p.InactivePolicyMap, policy.ID)
 	if condition != nil {
 		p.Conditions[policy.ID] = condition
 	}
+	//Inactive role policies are not indexed
+	if !active {
+		p.InactivePolicyMap[policy.ID] = policy
+		return
+	}
+
+	//First add role policy to PolicyMap
+	p.PolicyMap[policy.ID] = policy
 
 	//No principal defined. that means the roles are granted to any user
 	if nilPrincipalRolePolicy(policy) {
@@ -104,6 +140,13 @@ func (p *RolePolicyCacheData) addRolePolicyToResourceToRolePolicyMap(resourceToR
 }
 
 func (p *RolePolicyCacheData) DeleteRolePolicyFromCache(policyID string) {
+	if policy, ok := p.InactivePolicyMap[policyID]; ok {
+		delete(p.InactivePolicyMap, policyID)
+		if len(policy.Condition) > 0 {
+			delete(p.Conditions, policyID)
+		}
+		return
+	}
 
 	//First delete from PolicyMap
 	policy := p.PolicyMap[policyID]
@@ -169,6 +212,36 @@ func (p *RolePolicyCacheData) deleteRolePolicyFromResourceToRolePolicyMap(resour
 	}
 }
 
+// refreshActivation indexes the role policies activated at now, and removes the expired ones from the index
+func (p *RolePolicyCacheData) refreshActivation(now time.Time) {
+	for id, policy := range p.PolicyMap {
+		if !isActive(policy.Disabled, policy.NotBefore, policy.NotAfter, now) {
+			condition := p.Conditions[id]
+			p.DeleteRolePolicyFromCache(id)
+			p.addRolePolicyToCache(policy, condition, now)
+		}
+	}
+	for id, policy := range p.InactivePolicyMap {
+		if isActive(policy.Disabled, policy.NotBefore, policy.NotAfter, now) {
+			condition := p.Conditions[id]
+			delete(p.InactivePolicyMap, id)
+			p.addRolePolicyToCache(policy, condition, now)
+		}
+	}
+}
+
+// nextActivationChange returns the first instant after now at which a role policy is activated or expires
+func (p *RolePolicyCacheData) nextActivationChange(now time.Time) time.Time {
+	var next time.Time
+	for _, policy := range p.PolicyMap {
+		next = earlier(next, nextActivationChange(policy.Disabled, policy.NotBefore, policy.NotAfter, now))
+	}
+	for _, policy := range p.InactivePolicyMap {
+		next = earlier(next, nextActivationChange(policy.Disabled, policy.NotBefore, policy.NotAfter, now))
+	}
+	return next
+}
+
 func (p *RolePolicyCacheData) GetRelatedRolePolicyMap(subjectPrincipals []string, resource string) map[string]*pms.RolePolicy {
 	resultRolePolicyMap := make(map[string]*pms.RolePolicy)
 
@@ -248,3 +321,15 @@ func (p *RolePolicyCacheData) getRolePoliciesFromResourceExpressionMap(resourceT
 		}
 	}
 }
+
+// allPolicyIDs returns the IDs of active and inactive policies
+func (p *RolePolicyCacheData) allPolicyIDs() map[string]bool {
+	ids := make(map[string]bool, len(p.PolicyMap)+len(p.InactivePolicyMap))
+	for id := range p.PolicyMap {
+		ids[id] = true
+	}
+	for id := range p.InactivePolicyMap {
+		ids[id] = true
+	}
+	return ids
+}
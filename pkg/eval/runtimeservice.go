@@ -4,8 +4,11 @@
 package eval
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/teramoby/speedle-plus/3rdparty/github.com/Knetic/govaluate"
 	"github.com/teramoby/speedle-plus/api/pms"
@@ -15,20 +18,32 @@ import (
 type RuntimePolicyStore struct {
 	sync.RWMutex
 	Functions           map[string]govaluate.ExpressionFunction
+	customerFunctions   map[string]customerFunction
 	RuntimeServices     map[string]*RuntimeService
 	FunctionResultCache *FuncResultCache
+	FunctionBreakers    *FuncBreakerRegistry
 	FuncSvcEndpoint     string //endpoint in sphinx side to call external customer function
+	functionConns       *funcConnPool
 }
 
 func NewRuntimePolicyStore() *RuntimePolicyStore {
 	return &RuntimePolicyStore{
-		RuntimeServices: make(map[string]*RuntimeService),
-		FunctionResultCache: &FuncResultCache{
-			Results: make(map[string]FuncResult),
-		},
+		RuntimeServices:     make(map[string]*RuntimeService),
+		FunctionResultCache: NewFuncResultCache(0, 0),
+		FunctionBreakers:    NewFuncBreakerRegistry(),
+		functionConns:       newFuncConnPool(),
 	}
 }
 
+// SetFunctionClientCertificate sets the client certificate presented to customer function servers over grpcs,
+// which enables mutual TLS
+func (rtps *RuntimePolicyStore) SetFunctionClientCertificate(cert *tls.Certificate) {
+	rtps.functionConns.setClientCertificate(cert)
+}
+
+// RuntimeService is the runtime cache of a service. Its caches are copied on write, the lock of the
+// service protects the references to them, so that evaluations take a snapshot of the service and
+// evaluate it without the lock.
 type RuntimeService struct {
 	sync.RWMutex
 	Name              string
@@ -36,6 +51,12 @@ type RuntimeService struct {
 	PoliciesCache     *PolicyCacheData
 	RolePoliciesCache *RolePolicyCacheData
 	Functions         map[string]govaluate.ExpressionFunction
+	DefaultEffect     string
+	FailureMode       string
+	Mode              string
+	// refreshes the caches when policies are activated or expire
+	activationTimer   *time.Timer
+	activationStopped bool
 }
 
 func NewRuntimeService() *RuntimeService {
@@ -50,7 +71,7 @@ func (rtps *RuntimePolicyStore) init(ps *pms.PolicyStore, funcSvcEndpoint string
 		rtps.FuncSvcEndpoint = funcSvcEndpoint
 	}
 	// No need to lock, because this is a init method, evaluator should not be ready at this point
-	rtps.Functions = convertFunctions(ps.Functions, rtps.FunctionResultCache, &rtps.FuncSvcEndpoint)
+	rtps.Functions, rtps.customerFunctions = convertFunctions(ps.Functions, rtps.FunctionResultCache, rtps.FunctionBreakers, rtps.functionConns, &rtps.FuncSvcEndpoint)
 	for _, service := range ps.Services {
 		rtps.RuntimeServices[service.Name] = convertService(service, rtps.Functions)
 	}
@@ -58,10 +79,8 @@ func (rtps *RuntimePolicyStore) init(ps *pms.PolicyStore, funcSvcEndpoint string
 
 func (rtps *RuntimePolicyStore) reloadPolicyStore(ps *pms.PolicyStore) {
 	// Clear all cached data first
-	fncsResultCache := FuncResultCache{
-		Results: make(map[string]FuncResult),
-	}
-	functions := convertFunctions(ps.Functions, &fncsResultCache, &rtps.FuncSvcEndpoint)
+	fncsResultCache := NewFuncResultCache(rtps.FunctionResultCache.maxEntries, rtps.FunctionResultCache.maxBytes)
+	functions, customerFunctions := convertFunctions(ps.Functions, fncsResultCache, rtps.FunctionBreakers, rtps.functionConns, &rtps.FuncSvcEndpoint)
 	services := make(map[string]*RuntimeService)
 
 	for _, service := range ps.Services {
@@ -71,9 +90,13 @@ func (rtps *RuntimePolicyStore) reloadPolicyStore(ps *pms.PolicyStore) {
 	// New cache items are ready here, replace all the caches.
 	rtps.Lock()
 	defer rtps.Unlock()
+	for _, rtService := range rtps.RuntimeServices {
+		rtService.stopActivation()
+	}
 	rtps.Functions = functions
+	rtps.customerFunctions = customerFunctions
 	rtps.RuntimeServices = services
-	rtps.FunctionResultCache = &fncsResultCache
+	rtps.FunctionResultCache = fncsResultCache
 }
 
 func (rtps *RuntimePolicyStore) addService(service *pms.Service) {
@@ -81,18 +104,26 @@ func (rtps *RuntimePolicyStore) addService(service *pms.Service) {
 
 	rtps.Lock()
 	defer rtps.Unlock()
+	if oldService, ok := rtps.RuntimeServices[service.Name]; ok {
+		oldService.stopActivation()
+	}
 	rtps.RuntimeServices[service.Name] = rtService
 }
 
 func (rtps *RuntimePolicyStore) deleteService(serviceName string) {
 	rtps.Lock()
 	defer rtps.Unlock()
+	if rtService, ok := rtps.RuntimeServices[serviceName]; ok {
+		rtService.stopActivation()
+	}
 	delete(rtps.RuntimeServices, serviceName)
 }
 
 func (rtps *RuntimePolicyStore) recompilePolicyConditionAtRuntime(serviceName string, policy *pms.Policy) (*govaluate.EvaluableExpression, error) {
 	fmt.Println("recompile condition for policy:", policy)
+	rtps.RLock()
 	condition, err := compileCondition(policy.Condition, rtps.Functions)
+	rtps.RUnlock()
 	if err == nil {
 		fmt.Println("updating condition for policy in another goroutine:", policy)
 		go updatePolicyCondition(rtps, serviceName, policy, condition)
@@ -111,12 +142,17 @@ func updatePolicyCondition(rtps *RuntimePolicyStore, serviceName string, policy
 	}
 	rtService.Lock()
 	defer rtService.Unlock()
-	rtService.PoliciesCache.Conditions[policy.ID] = condition
+	policies := *rtService.PoliciesCache
+	policies.Conditions = copyConditions(policies.Conditions)
+	policies.Conditions[policy.ID] = condition
+	rtService.PoliciesCache = &policies
 }
 
 func (rtps *RuntimePolicyStore) recompileRolePolicyConditionAtRuntime(serviceName string, policy *pms.RolePolicy) (*govaluate.EvaluableExpression, error) {
 	fmt.Println("recompile condition for role policy:", policy)
+	rtps.RLock()
 	condition, err := compileCondition(policy.Condition, rtps.Functions)
+	rtps.RUnlock()
 	if err == nil {
 		fmt.Println("updating condition for role policy in another goroutine:", policy)
 		go updateRolePolicyCondition(rtps, serviceName, policy, condition)
@@ -135,7 +171,10 @@ func updateRolePolicyCondition(rtps *RuntimePolicyStore, serviceName string, pol
 	}
 	rtService.Lock()
 	defer rtService.Unlock()
-	rtService.RolePoliciesCache.Conditions[policy.ID] = condition
+	rolePolicies := *rtService.RolePoliciesCache
+	rolePolicies.Conditions = copyConditions(rolePolicies.Conditions)
+	rolePolicies.Conditions[policy.ID] = condition
+	rtService.RolePoliciesCache = &rolePolicies
 }
 
 func (rtps *RuntimePolicyStore) addPolicy(serviceName string, policy *pms.Policy) {
@@ -153,7 +192,10 @@ func (rtps *RuntimePolicyStore) addPolicy(serviceName string, policy *pms.Policy
 	// Golang garantees rtService.Unlock() is executed before rtps.RUnlock()
 	defer rtService.Unlock()
 
-	rtService.PoliciesCache.AddPolicyToCache(policy, condition)
+	policies := rtService.PoliciesCache.clone()
+	policies.AddPolicyToCache(policy, condition)
+	rtService.PoliciesCache = policies
+	rtService.scheduleActivation(timeNow())
 }
 
 func (rtps *RuntimePolicyStore) deletePolicy(serviceName string, policyID string) {
@@ -170,7 +212,10 @@ func (rtps *RuntimePolicyStore) deletePolicy(serviceName string, policyID string
 	// Golang garantees rtService.Unlock() is executed before rtps.RUnlock()
 	defer rtService.Unlock()
 
-	rtService.PoliciesCache.DeletePolicyFromCache(policyID)
+	policies := rtService.PoliciesCache.clone()
+	policies.DeletePolicyFromCache(policyID)
+	rtService.PoliciesCache = policies
+	rtService.scheduleActivation(timeNow())
 }
 
 func (rtps *RuntimePolicyStore) addRolePolicy(serviceName string, rolePolicy *pms.RolePolicy) {
@@ -188,7 +233,10 @@ func (rtps *RuntimePolicyStore) addRolePolicy(serviceName string, rolePolicy *pm
 	// Golang garantees rtService.Unlock() is executed before rtps.RUnlock()
 	defer rtService.Unlock()
 
-	rtService.RolePoliciesCache.AddRolePolicyToCache(rolePolicy, condition)
+	rolePolicies := rtService.RolePoliciesCache.clone()
+	rolePolicies.AddRolePolicyToCache(rolePolicy, condition)
+	rtService.RolePoliciesCache = rolePolicies
+	rtService.scheduleActivation(timeNow())
 }
 
 func (rtps *RuntimePolicyStore) deleteRolePolicy(serviceName string, rolePolicyID string) {
@@ -205,16 +253,20 @@ func (rtps *RuntimePolicyStore) deleteRolePolicy(serviceName string, rolePolicyI
 	// Golang garantees rtService.Unlock() is executed before rtps.RUnlock()
 	defer rtService.Unlock()
 
-	rtService.RolePoliciesCache.DeleteRolePolicyFromCache(rolePolicyID)
+	rolePolicies := rtService.RolePoliciesCache.clone()
+	rolePolicies.DeleteRolePolicyFromCache(rolePolicyID)
+	rtService.RolePoliciesCache = rolePolicies
+	rtService.scheduleActivation(timeNow())
 }
 
 func (rtps *RuntimePolicyStore) addFunction(function *pms.Function) {
 	rtps.Lock()
 	defer rtps.Unlock()
 
-	ef, err := rtps.FunctionResultCache.generateCustomerExpressionFunction(&rtps.FuncSvcEndpoint, function)
+	cf, err := rtps.FunctionResultCache.generateCustomerFunction(&rtps.FuncSvcEndpoint, function, rtps.FunctionBreakers, rtps.functionConns)
 	if err == nil {
-		rtps.Functions[function.Name] = ef
+		rtps.Functions[function.Name] = cf.bind(context.Background())
+		rtps.customerFunctions[function.Name] = cf
 		log.Infof("loaded customer function %q.\n", function.Name)
 	} else {
 		log.Errorf("fail to load customer function %q, err is %v. \n", function.Name, err)
@@ -231,7 +283,10 @@ func (rtps *RuntimePolicyStore) delFunc_rtps(name string) {
 	defer rtps.Unlock()
 
 	delete(rtps.Functions, name)
+	delete(rtps.customerFunctions, name)
 	rtps.FunctionResultCache.DeleteFromCache(name)
+	rtps.FunctionBreakers.remove(name)
+	rtps.functionConns.remove(name)
 }
 
 func (rtps *RuntimePolicyStore) delFunc_rtsvc() {
@@ -275,6 +330,9 @@ func convertService(service *pms.Service,
 	rtService := RuntimeService{
 		Name:              service.Name,
 		Type:              service.Type,
+		DefaultEffect:     service.DefaultEffect,
+		FailureMode:       service.FailureMode,
+		Mode:              service.Mode,
 		PoliciesCache:     NewPolicyCacheData(),
 		RolePoliciesCache: NewRolePolicyCacheData(),
 		Functions:         functions,
@@ -287,12 +345,18 @@ func convertService(service *pms.Service,
 		condition, _ := compileCondition(rolePolicy.Condition, functions)
 		rtService.RolePoliciesCache.AddRolePolicyToCache(rolePolicy, condition)
 	}
+	rtService.Lock()
+	rtService.scheduleActivation(timeNow())
+	rtService.Unlock()
 
 	return &rtService
 }
 
-func convertFunctions(functions []*pms.Function, resultCache *FuncResultCache, funcSvcEndpoint *string) map[string]govaluate.ExpressionFunction {
+// convertFunctions returns the functions compiled into conditions, and the customer functions among them
+// which are bound to the context of traced evaluations
+func convertFunctions(functions []*pms.Function, resultCache *FuncResultCache, breakers *FuncBreakerRegistry, conns *funcConnPool, funcSvcEndpoint *string) (map[string]govaluate.ExpressionFunction, map[string]customerFunction) {
 	funcs := map[string]govaluate.ExpressionFunction{}
+	customerFuncs := map[string]customerFunction{}
 
 	//loading builtin functions
 	for key, value := range builtinFunctions {
@@ -301,22 +365,43 @@ func convertFunctions(functions []*pms.Function, resultCache *FuncResultCache, f
 
 	//loading customer functions
 	for _, function := range functions {
-		ef, err := resultCache.generateCustomerExpressionFunction(funcSvcEndpoint, function)
+		cf, err := resultCache.generateCustomerFunction(funcSvcEndpoint, function, breakers, conns)
 		if err == nil {
-			funcs[function.Name] = ef
+			funcs[function.Name] = cf.bind(context.Background())
+			customerFuncs[function.Name] = cf
 			log.Infof("loaded customer function %q.\n", function.Name)
 		} else {
 			log.Errorf("fail to load customer function %q, err is %v. \n", function.Name, err)
 		}
 	}
-	return funcs
+	return funcs, customerFuncs
 }
 
 func (svc *RuntimeService) clearConditionsCache() {
 	svc.Lock()
 	defer svc.Unlock()
-	svc.PoliciesCache.clearConditions()
-	svc.RolePoliciesCache.clearConditions()
+	policies := *svc.PoliciesCache
+	policies.clearConditions()
+	svc.PoliciesCache = &policies
+	rolePolicies := *svc.RolePoliciesCache
+	rolePolicies.clearConditions()
+	svc.RolePoliciesCache = &rolePolicies
+}
+
+// snapshot returns a copy of the service sharing its caches, which are not updated any more
+func (svc *RuntimeService) snapshot() *RuntimeService {
+	svc.RLock()
+	defer svc.RUnlock()
+	return &RuntimeService{
+		Name:              svc.Name,
+		Type:              svc.Type,
+		PoliciesCache:     svc.PoliciesCache,
+		RolePoliciesCache: svc.RolePoliciesCache,
+		Functions:         svc.Functions,
+		DefaultEffect:     svc.DefaultEffect,
+		FailureMode:       svc.FailureMode,
+		Mode:              svc.Mode,
+	}
 }
 
 func (svc *RuntimeService) GetRelatedPolicyMap(subjectPrincipals []string, resource string,
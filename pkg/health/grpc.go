@@ -0,0 +1,93 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package health
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// grpcHealthService is the name of the gRPC health service
+	grpcHealthService = "grpc.health.v1.Health"
+	// grpcUpdateInterval is the interval of updating the status sent to the callers of Watch
+	grpcUpdateInterval = 10 * time.Second
+)
+
+// IsGRPCHealthMethod checks whether a full gRPC method name is a method of the health service,
+// which is called by probes without credentials
+func IsGRPCHealthMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+grpcHealthService+"/")
+}
+
+// GRPCServer implements the gRPC health protocol. The server (the empty service name) and the
+// registered services are SERVING when the service is live and ready.
+type GRPCServer struct {
+	*health.Server
+	checker  Checker
+	services []string
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// RegisterGRPC registers the gRPC health service in the server, reporting the services which are
+// already registered in it. The returned server should be shut down when the gRPC server stops.
+func RegisterGRPC(server *grpc.Server, checker Checker) *GRPCServer {
+	s := &GRPCServer{
+		Server:  health.NewServer(),
+		checker: checker,
+		stop:    make(chan struct{}),
+	}
+	for service := range server.GetServiceInfo() {
+		s.services = append(s.services, service)
+	}
+	s.update()
+	go s.run()
+	healthpb.RegisterHealthServer(server, s)
+	return s
+}
+
+// Check checks the health of the service before responding
+func (s *GRPCServer) Check(ctx context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	s.update()
+	return s.Server.Check(ctx, in)
+}
+
+// Shutdown stops updating the status and sets all services NOT_SERVING
+func (s *GRPCServer) Shutdown() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	s.Server.Shutdown()
+}
+
+func (s *GRPCServer) run() {
+	ticker := time.NewTicker(grpcUpdateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.update()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *GRPCServer) update() {
+	servingStatus := healthpb.HealthCheckResponse_NOT_SERVING
+	if status := s.checker.CheckHealth(); status.Live && status.Ready {
+		servingStatus = healthpb.HealthCheckResponse_SERVING
+	}
+	s.SetServingStatus("", servingStatus)
+	for _, service := range s.services {
+		s.SetServingStatus(service, servingStatus)
+	}
+}
@@ -0,0 +1,34 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package health
+
+import (
+	"net/http"
+
+	"github.com/teramoby/speedle-plus/pkg/httputils"
+)
+
+// LivenessHandler serves the /healthz endpoint, it responds 503 if the service is not live
+func LivenessHandler(checker Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := checker.CheckHealth()
+		code := http.StatusOK
+		if !status.Live {
+			code = http.StatusServiceUnavailable
+		}
+		httputils.SendResponse(w, code, status)
+	})
+}
+
+// ReadinessHandler serves the /readyz endpoint, it responds 503 if the service is not ready
+func ReadinessHandler(checker Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := checker.CheckHealth()
+		code := http.StatusOK
+		if !status.Live || !status.Ready {
+			code = http.StatusServiceUnavailable
+		}
+		httputils.SendResponse(w, code, status)
+	})
+}
@@ -0,0 +1,88 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+// Package health reports the health of the authorization decision service (ADS) and the policy
+// management service (PMS) in the /healthz and /readyz endpoints and in the gRPC health protocol.
+//
+// A service is live as long as it can recover by itself, and ready when it can serve requests with
+// fresh policies. An ADS whose watch of the policy store has stopped is not live, because its cache
+// will never be updated again; an ADS which is re-establishing its watch, and an ADS or a PMS which
+// can't reach its policy store, are not ready.
+package health
+
+import (
+	"github.com/teramoby/speedle-plus/pkg/store"
+)
+
+// Status is the health of a service
+type Status struct {
+	Live  bool `json:"live"`
+	Ready bool `json:"ready"`
+	// reasons why the service is not live or not ready
+	Reasons []string           `json:"reasons,omitempty"`
+	Store   *store.StoreStatus `json:"store,omitempty"`
+	Watch   *WatchStatus       `json:"watch,omitempty"`
+	Cache   *CacheStatus       `json:"cache,omitempty"`
+}
+
+// States of the watch of the policy store
+const (
+	// WatchStateWatching means changes of the store are applied to the cache
+	WatchStateWatching = "watching"
+	// WatchStateReconnecting means the watch failed and is being re-established, the cache may be stale
+	WatchStateReconnecting = "reconnecting"
+	// WatchStateStopped means the watch was stopped, changes of the store are no longer applied
+	WatchStateStopped = "stopped"
+)
+
+// WatchStatus is the state of the watch of the policy store of an ADS. Times are Unix seconds.
+type WatchStatus struct {
+	Enabled   bool   `json:"enabled"`
+	Alive     bool   `json:"alive"`
+	State     string `json:"state,omitempty"`
+	StartedAt int64  `json:"startedAt,omitempty"`
+	StoppedAt int64  `json:"stoppedAt,omitempty"`
+	// Reconnects is the number of times the watch was re-established after a failure, and Resyncs
+	// the number of times the cache was resynchronized because the watch couldn't be resumed
+	Reconnects int64 `json:"reconnects"`
+	Resyncs    int64 `json:"resyncs"`
+	// LastError is the error of the last failed attempt to re-establish the watch
+	LastError string `json:"lastError,omitempty"`
+	// Events is the number of applied store change events
+	Events        int64  `json:"events"`
+	LastEventType string `json:"lastEventType,omitempty"`
+	LastEventID   int64  `json:"lastEventId,omitempty"`
+	// LastRevision is the store revision of the last event, for stores which have revisions
+	LastRevision int64 `json:"lastRevision,omitempty"`
+	LastEventAt  int64 `json:"lastEventAt,omitempty"`
+}
+
+// CacheStatus is the state of the runtime policy cache of an ADS. Times are Unix seconds.
+type CacheStatus struct {
+	BuiltAt      int64 `json:"builtAt"`
+	Services     int   `json:"services"`
+	Policies     int   `json:"policies"`
+	RolePolicies int   `json:"rolePolicies"`
+	// LastError is the error of the last failed reload of the cache
+	LastError string `json:"lastError,omitempty"`
+}
+
+// Checker checks the health of a service
+type Checker interface {
+	CheckHealth() *Status
+}
+
+// StoreChecker checks the health of a service which only depends on its policy store, like the PMS
+type StoreChecker struct {
+	Store interface{ Type() string }
+}
+
+// CheckHealth checks the connectivity to the store
+func (c *StoreChecker) CheckHealth() *Status {
+	status := &Status{Live: true, Store: store.CheckStore(c.Store)}
+	status.Ready = status.Store.Reachable
+	if !status.Ready {
+		status.Reasons = append(status.Reasons, "policy store is unreachable: "+status.Store.Error)
+	}
+	return status
+}
@@ -0,0 +1,51 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package health
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type fakeChecker struct {
+	status Status
+}
+
+func (c *fakeChecker) CheckHealth() *Status {
+	status := c.status
+	return &status
+}
+
+func TestGRPCServer(t *testing.T) {
+	server := grpc.NewServer()
+	reflection.Register(server)
+	checker := &fakeChecker{Status{Live: true, Ready: true}}
+	s := RegisterGRPC(server, checker)
+	defer s.Shutdown()
+
+	check := func(service string, want healthpb.HealthCheckResponse_ServingStatus) {
+		resp, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("failed to check service %q: %v", service, err)
+		}
+		if resp.Status != want {
+			t.Errorf("service %q: got %v, want %v", service, resp.Status, want)
+		}
+	}
+	check("", healthpb.HealthCheckResponse_SERVING)
+	check("grpc.reflection.v1alpha.ServerReflection", healthpb.HealthCheckResponse_SERVING)
+
+	// The status is checked again on each call
+	checker.status.Ready = false
+	check("", healthpb.HealthCheckResponse_NOT_SERVING)
+	check("grpc.reflection.v1alpha.ServerReflection", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	if !IsGRPCHealthMethod("/grpc.health.v1.Health/Check") || IsGRPCHealthMethod("/pb.PolicyManager/GetService") {
+		t.Error("unexpected health methods")
+	}
+}
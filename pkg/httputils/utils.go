@@ -23,6 +23,8 @@ func errorCodeToHTTPStatus(err error) int {
 		return http.StatusNotFound
 	case errors.EntityAlreadyExists:
 		return http.StatusConflict
+	case errors.Conflict:
+		return http.StatusConflict
 	case errors.SerializationError:
 		return http.StatusInternalServerError
 	case errors.StoreError:
@@ -31,6 +33,10 @@ func errorCodeToHTTPStatus(err error) int {
 		return http.StatusBadRequest
 	case errors.ExceedLimit:
 		return http.StatusForbidden
+	case errors.Unauthorized:
+		return http.StatusUnauthorized
+	case errors.Forbidden:
+		return http.StatusForbidden
 	default:
 		// Unknown status
 		return http.StatusInternalServerError
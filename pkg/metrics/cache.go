@@ -0,0 +1,104 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package metrics
+
+import (
+	"sync"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RuntimeCacheStats is the size of the runtime policy cache of an evaluator
+type RuntimeCacheStats struct {
+	Services     int
+	Policies     int
+	RolePolicies int
+	// resource expressions which are matched one by one
+	RegexExpressions int
+}
+
+// CacheSource reports the runtime policy cache and the function result cache of an evaluator
+type CacheSource interface {
+	GetRuntimeCacheStats() *RuntimeCacheStats
+	GetFunctionCacheStats() []*adsapi.FunctionCacheStats
+}
+
+// cacheCollector collects the cache gauges of the evaluator when metrics are scraped
+type cacheCollector struct {
+	sync.RWMutex
+	source CacheSource
+
+	services         *prometheus.Desc
+	policies         *prometheus.Desc
+	rolePolicies     *prometheus.Desc
+	regexExpressions *prometheus.Desc
+	funcEntries      *prometheus.Desc
+	funcBytes        *prometheus.Desc
+	funcHits         *prometheus.Desc
+	funcMisses       *prometheus.Desc
+	funcHitRatio     *prometheus.Desc
+}
+
+var cache = newCacheCollector()
+
+func newCacheCollector() *cacheCollector {
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "ads", name), help, labels, nil)
+	}
+	return &cacheCollector{
+		services:         desc("runtime_services", "Number of services in the runtime cache."),
+		policies:         desc("runtime_policies", "Number of policies in the runtime cache."),
+		rolePolicies:     desc("runtime_role_policies", "Number of role policies in the runtime cache."),
+		regexExpressions: desc("runtime_regex_expressions", "Number of resource expressions in the runtime cache which are matched one by one."),
+		funcEntries:      desc("function_cache_entries", "Number of cached results of a customer function.", "function"),
+		funcBytes:        desc("function_cache_bytes", "Estimated memory of cached results of a customer function.", "function"),
+		funcHits:         desc("function_cache_hits_total", "Number of cache hits of a customer function.", "function"),
+		funcMisses:       desc("function_cache_misses_total", "Number of cache misses of a customer function.", "function"),
+		funcHitRatio:     desc("function_cache_hit_ratio", "Ratio of cache hits to lookups of a customer function.", "function"),
+	}
+}
+
+// SetCacheSource sets the evaluator whose caches are reported. There is one evaluator in an ADS, the
+// last one set is reported if a process creates more evaluators.
+func SetCacheSource(source CacheSource) {
+	cache.Lock()
+	defer cache.Unlock()
+	cache.source = source
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{c.services, c.policies, c.rolePolicies, c.regexExpressions,
+		c.funcEntries, c.funcBytes, c.funcHits, c.funcMisses, c.funcHitRatio} {
+		ch <- desc
+	}
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	c.RLock()
+	source := c.source
+	c.RUnlock()
+	if source == nil {
+		return
+	}
+
+	stats := source.GetRuntimeCacheStats()
+	ch <- prometheus.MustNewConstMetric(c.services, prometheus.GaugeValue, float64(stats.Services))
+	ch <- prometheus.MustNewConstMetric(c.policies, prometheus.GaugeValue, float64(stats.Policies))
+	ch <- prometheus.MustNewConstMetric(c.rolePolicies, prometheus.GaugeValue, float64(stats.RolePolicies))
+	ch <- prometheus.MustNewConstMetric(c.regexExpressions, prometheus.GaugeValue, float64(stats.RegexExpressions))
+
+	for _, f := range source.GetFunctionCacheStats() {
+		ch <- prometheus.MustNewConstMetric(c.funcEntries, prometheus.GaugeValue, float64(f.Entries), f.Name)
+		ch <- prometheus.MustNewConstMetric(c.funcBytes, prometheus.GaugeValue, float64(f.Bytes), f.Name)
+		ch <- prometheus.MustNewConstMetric(c.funcHits, prometheus.CounterValue, float64(f.Hits), f.Name)
+		ch <- prometheus.MustNewConstMetric(c.funcMisses, prometheus.CounterValue, float64(f.Misses), f.Name)
+		var ratio float64
+		if lookups := f.Hits + f.Misses; lookups > 0 {
+			ratio = float64(f.Hits) / float64(lookups)
+		}
+		ch <- prometheus.MustNewConstMetric(c.funcHitRatio, prometheus.GaugeValue, ratio, f.Name)
+	}
+}
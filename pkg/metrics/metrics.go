@@ -0,0 +1,177 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+// Package metrics defines the Prometheus metrics of the authorization decision service (ADS) and the
+// policy management service (PMS), which are exposed in the /metrics endpoint of both services.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "speedle"
+
+// APIs of the ADS whose latency is observed
+const (
+	APIIsAllowed          = "is-allowed"
+	APIDiagnose           = "diagnose"
+	APIGrantedRoles       = "granted-roles"
+	APIGrantedPermissions = "granted-permissions"
+)
+
+// Protocols of the APIs
+const (
+	ProtocolREST = "rest"
+	ProtocolGRPC = "grpc"
+)
+
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+var (
+	decisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ads",
+		Name:      "decisions_total",
+		Help:      "Number of authorization decisions by service, allowed and reason.",
+	}, []string{"service", "allowed", "reason"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "ads",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the ADS APIs.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"api", "protocol"})
+
+	functionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "ads",
+		Name:      "function_call_duration_seconds",
+		Help:      "Latency of calls of customer functions.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"function"})
+
+	functionErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ads",
+		Name:      "function_call_errors_total",
+		Help:      "Number of failed calls of customer functions.",
+	}, []string{"function"})
+
+	asserterDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "ads",
+		Name:      "assertion_duration_seconds",
+		Help:      "Latency of identity token assertions.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"token_type"})
+
+	asserterErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ads",
+		Name:      "assertion_errors_total",
+		Help:      "Number of failed identity token assertions.",
+	}, []string{"token_type"})
+
+	watchEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ads",
+		Name:      "watch_events_total",
+		Help:      "Number of policy store events received by the ADS, by event type.",
+	}, []string{"type"})
+
+	watchLastEvent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "ads",
+		Name:      "watch_last_event_timestamp_seconds",
+		Help:      "Unix time of the last policy store event applied to the runtime cache, by event type.",
+	}, []string{"type"})
+
+	pmsOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "pms",
+		Name:      "operations_total",
+		Help:      "Number of policy management operations by operation, protocol and result.",
+	}, []string{"operation", "protocol", "result"})
+
+	storeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "pms",
+		Name:      "store_duration_seconds",
+		Help:      "Latency of policy store operations.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(decisions, requestDuration, functionDuration, functionErrors,
+		asserterDuration, asserterErrors, watchEvents, watchLastEvent, pmsOperations, storeDuration, cache)
+}
+
+// Handler returns the handler of the /metrics endpoint
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultFailure
+	}
+	return resultSuccess
+}
+
+// ObserveDecision counts an authorization decision
+func ObserveDecision(service string, allowed bool, reason adsapi.Reason) {
+	decisions.WithLabelValues(service, strconv.FormatBool(allowed), reason.String()).Inc()
+}
+
+// ObserveRequest observes the latency of an ADS API, which started at start
+func ObserveRequest(api, protocol string, start time.Time) {
+	requestDuration.WithLabelValues(api, protocol).Observe(time.Since(start).Seconds())
+}
+
+// ObserveFunctionCall observes the latency and the error of a customer function call
+func ObserveFunctionCall(function string, start time.Time, err error) {
+	functionDuration.WithLabelValues(function).Observe(time.Since(start).Seconds())
+	if err != nil {
+		functionErrors.WithLabelValues(function).Inc()
+	}
+}
+
+// ObserveAssertion observes the latency and the error of an identity token assertion
+func ObserveAssertion(tokenType string, start time.Time, err error) {
+	asserterDuration.WithLabelValues(tokenType).Observe(time.Since(start).Seconds())
+	if err != nil {
+		asserterErrors.WithLabelValues(tokenType).Inc()
+	}
+}
+
+// ObserveWatchEvent counts a policy store event and records when it is received
+func ObserveWatchEvent(eventType string) {
+	watchEvents.WithLabelValues(eventType).Inc()
+	watchLastEvent.WithLabelValues(eventType).SetToCurrentTime()
+}
+
+// ObservePMSOperation counts a policy management operation
+func ObservePMSOperation(operation, protocol string, failed bool) {
+	result := resultSuccess
+	if failed {
+		result = resultFailure
+	}
+	pmsOperations.WithLabelValues(operation, protocol, result).Inc()
+}
+
+// ObserveStoreOperation observes the latency of a policy store operation, which started at start
+func ObserveStoreOperation(operation string, start time.Time, err error) {
+	storeDuration.WithLabelValues(operation, result(err)).Observe(time.Since(start).Seconds())
+}
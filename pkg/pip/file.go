@@ -0,0 +1,134 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pip
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/teramoby/speedle-plus/pkg/errors"
+)
+
+const (
+	FileProviderType = "file"
+
+	// props of file provider
+	FilePropPath  = "path"  // path of a .json or .csv file
+	FilePropKeyBy = "keyBy" // user, entity or resource, user by default
+
+	KeyByUser     = "user"
+	KeyByEntity   = "entity"
+	KeyByResource = "resource"
+)
+
+// fileProvider serves attributes loaded from a static file. A JSON file is an object of
+// attribute objects keyed by user, entity or resource. The first row of a CSV file is the
+// header, the first column is the key and other columns are attributes.
+type fileProvider struct {
+	keyBy   string
+	records map[string]map[string]interface{}
+}
+
+type fileProviderBuilder struct{}
+
+func (fileProviderBuilder) NewProvider(props map[string]interface{}) (AttributeProvider, error) {
+	p := fileProvider{
+		keyBy: stringProp(props, FilePropKeyBy),
+	}
+	switch p.keyBy {
+	case "":
+		p.keyBy = KeyByUser
+	case KeyByUser, KeyByEntity, KeyByResource:
+	default:
+		return nil, errors.Errorf(errors.ConfigError, "unsupported %q %q of file attribute provider", FilePropKeyBy, p.keyBy)
+	}
+
+	path := stringProp(props, FilePropPath)
+	if len(path) == 0 {
+		return nil, errors.Errorf(errors.ConfigError, "%q of file attribute provider is not specified", FilePropPath)
+	}
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		p.records, err = readCSVRecords(path)
+	} else {
+		p.records, err = readJSONRecords(path)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ConfigError, "failed to load attributes from %q", path)
+	}
+	return &p, nil
+}
+
+func readJSONRecords(path string) (map[string]map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	records := make(map[string]map[string]interface{})
+	if err := json.NewDecoder(f).Decode(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func readCSVRecords(path string) (map[string]map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[string]map[string]interface{})
+	if len(rows) == 0 {
+		return records, nil
+	}
+	header := rows[0]
+	for _, row := range rows[1:] {
+		attrs := make(map[string]interface{})
+		for i := 1; i < len(row) && i < len(header); i++ {
+			attrs[header[i]] = parseCSVValue(row[i])
+		}
+		records[row[0]] = attrs
+	}
+	return records, nil
+}
+
+// parseCSVValue converts a cell to the type conditions compare it as
+func parseCSVValue(cell string) interface{} {
+	if b, err := strconv.ParseBool(cell); err == nil {
+		return b
+	}
+	if f, err := strconv.ParseFloat(cell, 64); err == nil {
+		return f
+	}
+	return cell
+}
+
+func (p *fileProvider) GetAttributes(name string, req *Request) (map[string]interface{}, error) {
+	var key string
+	switch p.keyBy {
+	case KeyByUser:
+		key = req.User
+	case KeyByEntity:
+		key = req.Entity
+	case KeyByResource:
+		key = req.Resource
+	}
+	if attrs, ok := p.records[key]; ok {
+		return attrs, nil
+	}
+	return map[string]interface{}{}, nil
+}
+
+func init() {
+	Register(FileProviderType, fileProviderBuilder{})
+}
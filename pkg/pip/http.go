@@ -0,0 +1,123 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pip
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/teramoby/speedle-plus/pkg/errors"
+)
+
+const (
+	HTTPProviderType = "http"
+
+	// props of http provider
+	HTTPPropURL     = "url"     // URL template, see httpProvider
+	HTTPPropHeaders = "headers" // extra request headers
+	HTTPPropCACert  = "caCert"  // path of CA certificate of https server
+	HTTPPropTimeout = "timeout" // request timeout in second
+
+	defaultHTTPProviderTimeout = 5 * time.Second
+)
+
+// httpProvider looks up attributes with a GET request and returns the attributes in the
+// JSON object of the response. The placeholders {attribute}, {user}, {entity}, {service},
+// {resource} and {action} in the URL are replaced with the escaped values of the request.
+type httpProvider struct {
+	urlTemplate string
+	headers     map[string]string
+	client      *http.Client
+}
+
+type httpProviderBuilder struct{}
+
+func (httpProviderBuilder) NewProvider(props map[string]interface{}) (AttributeProvider, error) {
+	p := httpProvider{
+		urlTemplate: stringProp(props, HTTPPropURL),
+		headers:     make(map[string]string),
+	}
+	if len(p.urlTemplate) == 0 {
+		return nil, errors.Errorf(errors.ConfigError, "%q of http attribute provider is not specified", HTTPPropURL)
+	}
+	if headers, ok := props[HTTPPropHeaders].(map[string]interface{}); ok {
+		for k, v := range headers {
+			if s, ok := v.(string); ok {
+				p.headers[k] = s
+			}
+		}
+	}
+
+	timeout := defaultHTTPProviderTimeout
+	if t := intProp(props, HTTPPropTimeout); t > 0 {
+		timeout = time.Duration(t) * time.Second
+	}
+	tr := http.Transport{
+		MaxIdleConns:    100,
+		IdleConnTimeout: 60 * time.Second,
+		Proxy:           http.ProxyFromEnvironment,
+	}
+	if caCertPath := stringProp(props, HTTPPropCACert); len(caCertPath) > 0 {
+		caCert, err := ioutil.ReadFile(caCertPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.ConfigError, "failed to read CA certificate %q", caCertPath)
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(caCert)
+		tr.TLSClientConfig = &tls.Config{RootCAs: caCertPool}
+	}
+	p.client = &http.Client{
+		Transport: &tr,
+		Timeout:   timeout,
+	}
+	return &p, nil
+}
+
+func (p *httpProvider) GetAttributes(name string, req *Request) (map[string]interface{}, error) {
+	replacer := strings.NewReplacer(
+		"{attribute}", url.QueryEscape(name),
+		"{user}", url.QueryEscape(req.User),
+		"{entity}", url.QueryEscape(req.Entity),
+		"{service}", url.QueryEscape(req.ServiceName),
+		"{resource}", url.QueryEscape(req.Resource),
+		"{action}", url.QueryEscape(req.Action),
+	)
+	httpReq, err := http.NewRequest(http.MethodGet, replacer.Replace(p.urlTemplate), nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	for k, v := range p.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		// The provider does not know the user or resource
+		return map[string]interface{}{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf(errors.AttrProviderError, "unexpected status %d from %s", resp.StatusCode, httpReq.URL.Host)
+	}
+
+	attrs := make(map[string]interface{})
+	if err := json.NewDecoder(resp.Body).Decode(&attrs); err != nil {
+		return nil, errors.Wrap(err, errors.AttrProviderError, "invalid attributes in response")
+	}
+	return attrs, nil
+}
+
+func init() {
+	Register(HTTPProviderType, httpProviderBuilder{})
+}
@@ -0,0 +1,107 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+// Package pip implements policy information points, which supply attributes
+// referenced by policy conditions but not sent by the caller.
+package pip
+
+import (
+	"sort"
+	"sync"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+)
+
+var (
+	providerBuildersMu = &sync.RWMutex{}
+	providerBuilders   = make(map[string]ProviderBuilder)
+)
+
+// Request is the part of an authorization request attribute providers look up attributes with
+type Request struct {
+	Subject     *adsapi.Subject
+	User        string
+	Entity      string
+	Groups      []string
+	ServiceName string
+	Resource    string
+	Action      string
+}
+
+// AttributeProvider looks up attributes of a request
+type AttributeProvider interface {
+	// GetAttributes returns attributes of the request. The returned map should contain
+	// the named attribute if the provider knows it, and may contain other attributes
+	// fetched in the same lookup.
+	GetAttributes(name string, req *Request) (map[string]interface{}, error)
+}
+
+type ProviderBuilder interface {
+	NewProvider(props map[string]interface{}) (AttributeProvider, error)
+}
+
+// ProviderConfig is the configuration of a named attribute provider
+type ProviderConfig struct {
+	Name       string                 `json:"name"`
+	Type       string                 `json:"type"`                 // http, file or token
+	Attributes []string               `json:"attributes,omitempty"` // attributes supplied by the provider, empty means any attribute
+	TTL        int64                  `json:"ttl,omitempty"`        // time in second looked up attributes are cached across requests, 0 disables the cache
+	Props      map[string]interface{} `json:"props,omitempty"`
+}
+
+// Register makes a type of attribute provider available by the provided name.
+// If Register is called twice with the same name or if providerBuilder is nil,
+// it panics.
+func Register(providerType string, providerBuilder ProviderBuilder) {
+	providerBuildersMu.Lock()
+	defer providerBuildersMu.Unlock()
+	if providerBuilder == nil {
+		panic("speedle: Register providerBuilder is nil")
+	}
+	if _, dup := providerBuilders[providerType]; dup {
+		panic("speedle: Register called twice for providerBuilder " + providerType)
+	}
+	providerBuilders[providerType] = providerBuilder
+}
+
+// ProviderBuilders returns a sorted list of the names of the registered provider types.
+func ProviderBuilders() []string {
+	providerBuildersMu.RLock()
+	defer providerBuildersMu.RUnlock()
+	var list []string
+	for providerType := range providerBuilders {
+		list = append(list, providerType)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func NewProvider(providerType string, props map[string]interface{}) (AttributeProvider, error) {
+	providerBuildersMu.RLock()
+	providerBuilder, ok := providerBuilders[providerType]
+	providerBuildersMu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf(errors.ConfigError, "unknown attribute provider type %q", providerType)
+	}
+	return providerBuilder.NewProvider(props)
+}
+
+func stringProp(props map[string]interface{}, key string) string {
+	if v, ok := props[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func intProp(props map[string]interface{}, key string) int64 {
+	switch v := props[key].(type) {
+	case float64:
+		return int64(v)
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	}
+	return 0
+}
@@ -0,0 +1,176 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pip
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+)
+
+func writeTempFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	jsonFile := writeTempFile(t, dir, "users.json", `{"alice": {"dept": "eng", "level": 3}}`)
+	csvFile := writeTempFile(t, dir, "resources.csv", "resource,owner,sensitive,size\n/doc1,bob,true,10\n")
+
+	testCases := []struct {
+		props    map[string]interface{}
+		req      Request
+		expected map[string]interface{}
+	}{
+		{
+			props:    map[string]interface{}{FilePropPath: jsonFile},
+			req:      Request{User: "alice"},
+			expected: map[string]interface{}{"dept": "eng", "level": 3.0},
+		},
+		{
+			props:    map[string]interface{}{FilePropPath: jsonFile},
+			req:      Request{User: "bob"},
+			expected: map[string]interface{}{},
+		},
+		{
+			props:    map[string]interface{}{FilePropPath: csvFile, FilePropKeyBy: KeyByResource},
+			req:      Request{User: "alice", Resource: "/doc1"},
+			expected: map[string]interface{}{"owner": "bob", "sensitive": true, "size": 10.0},
+		},
+	}
+	for i, tc := range testCases {
+		p, err := NewProvider(FileProviderType, tc.props)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		attrs, err := p.GetAttributes("any", &tc.req)
+		if err != nil || !reflect.DeepEqual(attrs, tc.expected) {
+			t.Errorf("case %d: got %v, %v, want %v", i, attrs, err, tc.expected)
+		}
+	}
+
+	if _, err := NewProvider(FileProviderType, map[string]interface{}{FilePropPath: jsonFile, FilePropKeyBy: "group"}); err == nil {
+		t.Error("unsupported keyBy should be rejected")
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/users/alice":
+			json.NewEncoder(w).Encode(map[string]interface{}{"dept": "eng", "resource": r.URL.Query().Get("r")})
+		case "/users/carol":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p, err := NewProvider(HTTPProviderType, map[string]interface{}{
+		HTTPPropURL:     server.URL + "/users/{user}?r={resource}",
+		HTTPPropHeaders: map[string]interface{}{"Authorization": "Bearer secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attrs, err := p.GetAttributes("dept", &Request{User: "alice", Resource: "/a b"})
+	expected := map[string]interface{}{"dept": "eng", "resource": "/a b"}
+	if err != nil || !reflect.DeepEqual(attrs, expected) {
+		t.Errorf("got %v, %v, want %v", attrs, err, expected)
+	}
+	if attrs, err := p.GetAttributes("dept", &Request{User: "bob"}); err != nil || len(attrs) != 0 {
+		t.Errorf("unknown user should have no attributes, got %v, %v", attrs, err)
+	}
+	if _, err := p.GetAttributes("dept", &Request{User: "carol"}); err == nil {
+		t.Error("server error should be returned")
+	}
+}
+
+func TestResolver(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"dept": "eng", "level": 3})
+	}))
+	defer server.Close()
+
+	resolver, err := NewResolver([]*ProviderConfig{
+		{
+			Name:       "broken",
+			Type:       HTTPProviderType,
+			Attributes: []string{"dept"},
+			Props:      map[string]interface{}{HTTPPropURL: "http://127.0.0.1:1/{user}"},
+		},
+		{
+			Name:       "token",
+			Type:       TokenProviderType,
+			Attributes: []string{"scope"},
+		},
+		{
+			Name:  "directory",
+			Type:  HTTPProviderType,
+			TTL:   60,
+			Props: map[string]interface{}{HTTPPropURL: server.URL + "/{user}"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := Request{
+		User: "alice",
+		Subject: &adsapi.Subject{
+			AssertedAttributes: map[string]interface{}{"scope": "read"},
+		},
+	}
+
+	// The broken provider is skipped
+	attrs, source := resolver.Resolve("dept", &req)
+	if attrs["dept"] != "eng" || source.Source != "directory" || source.Cached {
+		t.Errorf("unexpected attributes %v from %+v", attrs, source)
+	}
+	attrs, source = resolver.Resolve("dept", &req)
+	if attrs["dept"] != "eng" || !source.Cached || atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("attributes should be cached, got %+v, %d calls", source, calls)
+	}
+
+	attrs, source = resolver.Resolve("scope", &req)
+	if attrs["scope"] != "read" || source.Source != "token" {
+		t.Errorf("unexpected attributes %v from %+v", attrs, source)
+	}
+
+	if attrs, _ := resolver.Resolve("unknown", &req); attrs != nil {
+		t.Errorf("unknown attribute should not be resolved, got %v", attrs)
+	}
+
+	if _, err := NewResolver([]*ProviderConfig{{Name: "a", Type: "ldap"}}); err == nil {
+		t.Error("unknown provider type should be rejected")
+	}
+	if _, err := NewResolver([]*ProviderConfig{{Name: "a", Type: TokenProviderType}, {Name: "a", Type: TokenProviderType}}); err == nil {
+		t.Error("duplicated provider should be rejected")
+	}
+}
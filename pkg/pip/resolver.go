@@ -0,0 +1,148 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pip
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const maxCachedLookups = 10000
+
+type namedProvider struct {
+	conf       *ProviderConfig
+	attributes map[string]bool
+	ttl        time.Duration
+	provider   AttributeProvider
+}
+
+func (np *namedProvider) supplies(name string) bool {
+	return len(np.attributes) == 0 || np.attributes[name]
+}
+
+type cachedLookup struct {
+	attributes map[string]interface{}
+	expireAt   time.Time
+}
+
+// Resolver looks up attributes from the configured providers in order
+type Resolver struct {
+	providers []*namedProvider
+
+	sync.RWMutex // guards cache
+	cache        map[string]*cachedLookup
+}
+
+// NewResolver creates attribute providers from configuration
+func NewResolver(confs []*ProviderConfig) (*Resolver, error) {
+	r := Resolver{
+		cache: make(map[string]*cachedLookup),
+	}
+	names := make(map[string]bool)
+	for _, conf := range confs {
+		if len(conf.Name) == 0 {
+			return nil, errors.New(errors.ConfigError, "name of attribute provider is not specified")
+		}
+		if names[conf.Name] {
+			return nil, errors.Errorf(errors.ConfigError, "duplicated attribute provider %q", conf.Name)
+		}
+		names[conf.Name] = true
+
+		provider, err := NewProvider(conf.Type, conf.Props)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.ConfigError, "failed to create attribute provider %q", conf.Name)
+		}
+		np := namedProvider{
+			conf:       conf,
+			attributes: make(map[string]bool),
+			ttl:        time.Duration(conf.TTL) * time.Second,
+			provider:   provider,
+		}
+		// Token attributes belong to the token rather than the principals cache keys are made of
+		if conf.Type == TokenProviderType && np.ttl > 0 {
+			log.Warningf("ttl of token attribute provider %q is ignored.", conf.Name)
+			np.ttl = 0
+		}
+		for _, attr := range conf.Attributes {
+			np.attributes[attr] = true
+		}
+		r.providers = append(r.providers, &np)
+		log.Infof("loaded attribute provider %q of type %q.", conf.Name, conf.Type)
+	}
+	return &r, nil
+}
+
+// Resolve looks up an attribute from the providers supplying it, the first provider knowing
+// the attribute wins. All attributes returned by that provider are returned, along with
+// where they come from. Failed providers are skipped and reported in the source.
+func (r *Resolver) Resolve(name string, req *Request) (map[string]interface{}, *adsapi.AttributeSource) {
+	var failure *adsapi.AttributeSource
+	for _, np := range r.providers {
+		if !np.supplies(name) {
+			continue
+		}
+		attrs, cached, err := r.lookup(np, name, req)
+		if err != nil {
+			log.Warningf("attribute provider %s fails to look up attribute %s: %v", np.conf.Name, name, err)
+			if failure == nil {
+				failure = &adsapi.AttributeSource{Source: np.conf.Name, Error: err.Error()}
+			}
+			continue
+		}
+		if _, ok := attrs[name]; ok {
+			return attrs, &adsapi.AttributeSource{Source: np.conf.Name, Cached: cached}
+		}
+	}
+	return nil, failure
+}
+
+func (r *Resolver) lookup(np *namedProvider, name string, req *Request) (map[string]interface{}, bool, error) {
+	if np.ttl <= 0 {
+		attrs, err := np.provider.GetAttributes(name, req)
+		return attrs, false, err
+	}
+
+	key := cacheKey(np.conf.Name, name, req)
+	now := time.Now()
+	r.RLock()
+	entry, ok := r.cache[key]
+	r.RUnlock()
+	if ok && now.Before(entry.expireAt) {
+		return entry.attributes, true, nil
+	}
+
+	attrs, err := np.provider.GetAttributes(name, req)
+	if err != nil {
+		// errors are not cached, so that a recovered provider is used immediately
+		return nil, false, err
+	}
+	r.Lock()
+	defer r.Unlock()
+	if len(r.cache) >= maxCachedLookups {
+		for k, v := range r.cache {
+			if now.After(v.expireAt) {
+				delete(r.cache, k)
+			}
+		}
+	}
+	if len(r.cache) < maxCachedLookups {
+		r.cache[key] = &cachedLookup{
+			attributes: attrs,
+			expireAt:   now.Add(np.ttl),
+		}
+	}
+	return attrs, false, nil
+}
+
+// cacheKey identifies a lookup by the attribute and everything a provider may look it up with
+func cacheKey(provider, name string, req *Request) string {
+	return strings.Join([]string{provider, name, req.User, req.Entity, strings.Join(req.Groups, ","),
+		req.ServiceName, req.Resource, req.Action}, "\x00")
+}
@@ -0,0 +1,28 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pip
+
+const (
+	TokenProviderType = "token"
+)
+
+// tokenProvider serves the attributes returned by the token asserter
+type tokenProvider struct{}
+
+type tokenProviderBuilder struct{}
+
+func (tokenProviderBuilder) NewProvider(props map[string]interface{}) (AttributeProvider, error) {
+	return &tokenProvider{}, nil
+}
+
+func (p *tokenProvider) GetAttributes(name string, req *Request) (map[string]interface{}, error) {
+	if req.Subject == nil || req.Subject.AssertedAttributes == nil {
+		return map[string]interface{}{}, nil
+	}
+	return req.Subject.AssertedAttributes, nil
+}
+
+func init() {
+	Register(TokenProviderType, tokenProviderBuilder{})
+}
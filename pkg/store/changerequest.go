@@ -0,0 +1,85 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package store
+
+import (
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/suid"
+)
+
+// ChangeRequestManager stores change requests apart from services, so that staged changes are not
+// seen by the authorization decision service until they are published
+type ChangeRequestManager interface {
+	//Create a change request, its ID is generated
+	CreateChangeRequest(changeRequest *pms.ChangeRequest) (*pms.ChangeRequest, error)
+	//Replace a change request
+	UpdateChangeRequest(changeRequest *pms.ChangeRequest) error
+	GetChangeRequest(serviceName string, id string) (*pms.ChangeRequest, error)
+	//List change requests of a service. List change requests of all services when serviceName is empty.
+	ListChangeRequests(serviceName string) ([]*pms.ChangeRequest, error)
+	//Apply the changes of a change request to its service and save the change request in one operation,
+	//stores emit the watch events of the changed service as for other policy changes. It fails with
+	//Conflict if the stored change request isn't in review any more, or the change request or its
+	//service is changed concurrently, so that the changes are never applied twice.
+	PublishChangeRequest(changeRequest *pms.ChangeRequest) error
+}
+
+// CheckPublishable checks that the stored copy of a change request being published is still in review
+func CheckPublishable(stored *pms.ChangeRequest) error {
+	if stored.Status != pms.ChangeRequestReview {
+		return errors.Errorf(errors.Conflict, "change request %q of service %q is %s, not in review", stored.ID, stored.ServiceName, stored.Status)
+	}
+	return nil
+}
+
+// ApplyChangeRequest applies the changes of a change request to a service. IDs are generated for
+// added policies and role policies. It fails without changing the service if any policy or role
+// policy to delete doesn't exist.
+func ApplyChangeRequest(service *pms.Service, changeRequest *pms.ChangeRequest) error {
+	policies := []*pms.Policy{}
+	deleted := map[string]bool{}
+	for _, id := range changeRequest.DeletePolicies {
+		deleted[id] = true
+	}
+	for _, policy := range service.Policies {
+		if deleted[policy.ID] {
+			delete(deleted, policy.ID)
+			continue
+		}
+		policies = append(policies, policy)
+	}
+	for id := range deleted {
+		return errors.Errorf(errors.Conflict, "policy %q is not found in service %q", id, service.Name)
+	}
+
+	rolePolicies := []*pms.RolePolicy{}
+	for _, id := range changeRequest.DeleteRolePolicies {
+		deleted[id] = true
+	}
+	for _, rolePolicy := range service.RolePolicies {
+		if deleted[rolePolicy.ID] {
+			delete(deleted, rolePolicy.ID)
+			continue
+		}
+		rolePolicies = append(rolePolicies, rolePolicy)
+	}
+	for id := range deleted {
+		return errors.Errorf(errors.Conflict, "role policy %q is not found in service %q", id, service.Name)
+	}
+
+	for _, policy := range changeRequest.AddPolicies {
+		dupPolicy := *policy
+		dupPolicy.ID = suid.New().String()
+		policies = append(policies, &dupPolicy)
+	}
+	for _, rolePolicy := range changeRequest.AddRolePolicies {
+		dupRolePolicy := *rolePolicy
+		dupRolePolicy.ID = suid.New().String()
+		rolePolicies = append(rolePolicies, &dupRolePolicy)
+	}
+	service.Policies = policies
+	service.RolePolicies = rolePolicies
+	return nil
+}
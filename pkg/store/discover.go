@@ -4,10 +4,15 @@
 package store
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"reflect"
+	"time"
 
 	"github.com/teramoby/speedle-plus/api/ads"
 	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/errors"
 	"github.com/teramoby/speedle-plus/pkg/subjectutils"
 )
 
@@ -16,13 +21,85 @@ const (
 	DefaultDeleteNumWhenReachMaxDiscoverRequest = int64(100)
 )
 
+// MaxDiscoverRequestNum is the number of distinct discover requests kept, the least recently seen
+// DeleteNumWhenReachMaxDiscoverRequest requests are deleted when it is reached
 var MaxDiscoverRequestNum, DeleteNumWhenReachMaxDiscoverRequest int64
 
+// DiscoverRequestTTL is the time a discover request is kept after it was last seen, and DiscoverRequestServiceTTLs
+// the ones of services. Requests are kept until they are evicted without TTL.
+var (
+	DiscoverRequestTTL         time.Duration
+	DiscoverRequestServiceTTLs map[string]time.Duration
+)
+
 func init() {
 	MaxDiscoverRequestNum = DefaultMaxDiscoverRequestNum
 	DeleteNumWhenReachMaxDiscoverRequest = DefaultDeleteNumWhenReachMaxDiscoverRequest
 }
 
+// DiscoverConfig is the retention of discover requests
+type DiscoverConfig struct {
+	MaxRequests int64            `json:"maxRequests,omitempty"` //distinct requests kept, 50000 by default
+	TTL         int64            `json:"ttl,omitempty"`         //in seconds, requests not seen for this long are removed, 0 keeps them until they are evicted
+	ServiceTTLs map[string]int64 `json:"serviceTTLs,omitempty"` //TTLs of services, in seconds
+}
+
+// SetDiscoverConfig sets the retention of discover requests
+func SetDiscoverConfig(conf *DiscoverConfig) error {
+	if conf == nil {
+		return nil
+	}
+	if conf.MaxRequests < 0 || conf.TTL < 0 {
+		return errors.New(errors.ConfigError, "maxRequests and ttl of discover requests can't be negative")
+	}
+	if conf.MaxRequests > 0 {
+		MaxDiscoverRequestNum = conf.MaxRequests
+	}
+	DiscoverRequestTTL = time.Duration(conf.TTL) * time.Second
+	DiscoverRequestServiceTTLs = map[string]time.Duration{}
+	for serviceName, ttl := range conf.ServiceTTLs {
+		if ttl < 0 {
+			return errors.Errorf(errors.ConfigError, "ttl of discover requests of service %q can't be negative", serviceName)
+		}
+		DiscoverRequestServiceTTLs[serviceName] = time.Duration(ttl) * time.Second
+	}
+	return nil
+}
+
+// DiscoverRequestExpiration returns when a request of a service seen at lastSeen expires, or nil if it doesn't
+func DiscoverRequestExpiration(serviceName string, lastSeen time.Time) *time.Time {
+	ttl, ok := DiscoverRequestServiceTTLs[serviceName]
+	if !ok {
+		ttl = DiscoverRequestTTL
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	expireAt := lastSeen.Add(ttl)
+	return &expireAt
+}
+
+// DiscoverRequestAggregate is a distinct discover request, with the number of times it was seen
+type DiscoverRequestAggregate struct {
+	Request   *ads.RequestContext `json:"request" bson:"request"`
+	Hits      int64               `json:"hits" bson:"hits"`
+	FirstSeen time.Time           `json:"firstSeen" bson:"firstseen"`
+	LastSeen  time.Time           `json:"lastSeen" bson:"lastseen"`
+	ExpireAt  *time.Time          `json:"expireAt,omitempty" bson:"expireat,omitempty"`
+}
+
+// Expired tells whether the aggregate has expired at now
+func (a *DiscoverRequestAggregate) Expired(now time.Time) bool {
+	return a.ExpireAt != nil && !now.Before(*a.ExpireAt)
+}
+
+// DiscoverRequestKey identifies the same requests, which are aggregated
+func DiscoverRequestKey(request *ads.RequestContext) string {
+	raw, _ := json.Marshal(request)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
 type DiscoverRequestManager interface {
 	//Save discover request
 	SaveDiscoverRequest(discoverRequest *ads.RequestContext) error
@@ -36,6 +113,8 @@ type DiscoverRequestManager interface {
 	ResetDiscoverRequests(serviceName string) error
 	//Generate policies for principal based on existing request logs. Generate policies for all principals when principalXXX are empty.
 	GeneratePolicies(serviceName, principalType, principalName, principalIDD string) (map[string]*pms.Service, int64, error)
+	//Get the distinct requests of a service with their hit counts. Get the ones of all services when serviceName is empty.
+	GetDiscoverRequestAggregates(serviceName string) ([]*DiscoverRequestAggregate, int64, error)
 }
 
 func GeneratePoliciesFromDiscoverRequests(requests []*ads.RequestContext, principalType, principalName, principalIDD string) (map[string]*pms.Service, error) {
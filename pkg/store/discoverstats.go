@@ -0,0 +1,103 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/subjectutils"
+)
+
+// DefaultDiscoverStatisticsTop is the number of top principals, resources and actions of the statistics
+const DefaultDiscoverStatisticsTop = 10
+
+// DiscoverHitCount is the number of requests of a principal, a resource or an action
+type DiscoverHitCount struct {
+	Name string `json:"name"`
+	Hits int64  `json:"hits"`
+}
+
+// DiscoverStatistics are the statistics of the discover requests of a service
+type DiscoverStatistics struct {
+	ServiceName   string              `json:"serviceName"`
+	Requests      int                 `json:"requests"` //distinct requests
+	Hits          int64               `json:"hits"`
+	FirstSeen     time.Time           `json:"firstSeen"`
+	LastSeen      time.Time           `json:"lastSeen"`
+	TopPrincipals []*DiscoverHitCount `json:"topPrincipals"`
+	TopResources  []*DiscoverHitCount `json:"topResources"`
+	TopActions    []*DiscoverHitCount `json:"topActions"`
+}
+
+// ComputeDiscoverStatistics computes the statistics of the services of aggregated discover requests, with
+// their top principals, resources and actions by hits. Anonymous requests are counted for the anonymous role.
+func ComputeDiscoverStatistics(aggregates []*DiscoverRequestAggregate, top int) map[string]*DiscoverStatistics {
+	if top <= 0 {
+		top = DefaultDiscoverStatisticsTop
+	}
+	type counters struct {
+		stats                          *DiscoverStatistics
+		principals, resources, actions map[string]int64
+	}
+	services := map[string]*counters{}
+	for _, aggregate := range aggregates {
+		req := aggregate.Request
+		c, ok := services[req.ServiceName]
+		if !ok {
+			c = &counters{
+				stats:      &DiscoverStatistics{ServiceName: req.ServiceName, FirstSeen: aggregate.FirstSeen, LastSeen: aggregate.LastSeen},
+				principals: map[string]int64{},
+				resources:  map[string]int64{},
+				actions:    map[string]int64{},
+			}
+			services[req.ServiceName] = c
+		}
+		c.stats.Requests++
+		c.stats.Hits += aggregate.Hits
+		if aggregate.FirstSeen.Before(c.stats.FirstSeen) {
+			c.stats.FirstSeen = aggregate.FirstSeen
+		}
+		if aggregate.LastSeen.After(c.stats.LastSeen) {
+			c.stats.LastSeen = aggregate.LastSeen
+		}
+		if req.Subject == nil || len(req.Subject.Principals) == 0 {
+			c.principals["role:"+ads.BuiltIn_Role_Anonymous] += aggregate.Hits
+		} else {
+			for _, principal := range req.Subject.Principals {
+				c.principals[subjectutils.EncodePrincipal(principal)] += aggregate.Hits
+			}
+		}
+		c.resources[req.Resource] += aggregate.Hits
+		c.actions[req.Action] += aggregate.Hits
+	}
+
+	statistics := map[string]*DiscoverStatistics{}
+	for serviceName, c := range services {
+		c.stats.TopPrincipals = topHitCounts(c.principals, top)
+		c.stats.TopResources = topHitCounts(c.resources, top)
+		c.stats.TopActions = topHitCounts(c.actions, top)
+		statistics[serviceName] = c.stats
+	}
+	return statistics
+}
+
+// topHitCounts returns the top hit counts, by hits then name
+func topHitCounts(hits map[string]int64, top int) []*DiscoverHitCount {
+	counts := make([]*DiscoverHitCount, 0, len(hits))
+	for name, n := range hits {
+		counts = append(counts, &DiscoverHitCount{Name: name, Hits: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Hits != counts[j].Hits {
+			return counts[i].Hits > counts[j].Hits
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	if len(counts) > top {
+		counts = counts[:top]
+	}
+	return counts
+}
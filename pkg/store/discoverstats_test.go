@@ -0,0 +1,50 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package store
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/teramoby/speedle-plus/api/ads"
+)
+
+func TestComputeDiscoverStatistics(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	newAggregate := func(serviceName, user, resource, action string, hits int64, days int) *DiscoverRequestAggregate {
+		req := &ads.RequestContext{ServiceName: serviceName, Resource: resource, Action: action, Subject: &ads.Subject{}}
+		if user != "" {
+			req.Subject.Principals = []*ads.Principal{{Type: ads.PRINCIPAL_TYPE_USER, Name: user}}
+		}
+		return &DiscoverRequestAggregate{Request: req, Hits: hits, FirstSeen: start.AddDate(0, 0, days), LastSeen: start.AddDate(0, 0, days+1)}
+	}
+	statistics := ComputeDiscoverStatistics([]*DiscoverRequestAggregate{
+		newAggregate("crm", "alice", "/reports", "read", 5, 2),
+		newAggregate("crm", "bob", "/reports", "write", 2, 0),
+		newAggregate("crm", "", "/home", "read", 2, 1),
+		newAggregate("wiki", "alice", "/pages", "edit", 1, 0),
+	}, 2)
+
+	crm := statistics["crm"]
+	if len(statistics) != 2 || crm.Requests != 3 || crm.Hits != 9 || !crm.FirstSeen.Equal(start) || !crm.LastSeen.Equal(start.AddDate(0, 0, 3)) {
+		t.Fatalf("unexpected statistics %+v", crm)
+	}
+	format := func(counts []*DiscoverHitCount) string {
+		var s string
+		for _, count := range counts {
+			s += fmt.Sprintf("%s=%d ", count.Name, count.Hits)
+		}
+		return s
+	}
+	if principals := format(crm.TopPrincipals); principals != "user:alice=5 role:anonymous_role=2 " {
+		t.Errorf("unexpected top principals %s", principals)
+	}
+	if resources := format(crm.TopResources); resources != "/reports=7 /home=2 " {
+		t.Errorf("unexpected top resources %s", resources)
+	}
+	if actions := format(crm.TopActions); actions != "read=7 write=2 " {
+		t.Errorf("unexpected top actions %s", actions)
+	}
+}
@@ -0,0 +1,192 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package etcd
+
+import (
+	"encoding/json"
+
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/store"
+	"github.com/teramoby/speedle-plus/pkg/suid"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/embed"
+	"golang.org/x/net/context"
+)
+
+const (
+	// ChangeRequestsKey is not under the services key, so that the watch ignores change requests
+	ChangeRequestsKey = "change_requests"
+)
+
+func (s *Store) changeRequestKey(serviceName string, id string) string {
+	return s.KeyPrefix + ChangeRequestsKey + KeySeparator + serviceName + KeySeparator + id
+}
+
+// CreateChangeRequest creates a change request
+func (s *Store) CreateChangeRequest(changeRequest *pms.ChangeRequest) (*pms.ChangeRequest, error) {
+	dupChangeRequest := *changeRequest
+	dupChangeRequest.ID = suid.New().String()
+	value, err := json.Marshal(dupChangeRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.SerializationError, "failed to marshal change request")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	if _, err := s.client.Put(ctx, s.changeRequestKey(dupChangeRequest.ServiceName, dupChangeRequest.ID), string(value)); err != nil {
+		return nil, errors.Wrap(err, errors.StoreError, "failed to create change request in etcd server")
+	}
+	return &dupChangeRequest, nil
+}
+
+// UpdateChangeRequest replaces a change request
+func (s *Store) UpdateChangeRequest(changeRequest *pms.ChangeRequest) error {
+	key := s.changeRequestKey(changeRequest.ServiceName, changeRequest.ID)
+	value, err := json.Marshal(changeRequest)
+	if err != nil {
+		return errors.Wrap(err, errors.SerializationError, "failed to marshal change request")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	txnResp, err := s.client.KV.Txn(ctx).If(
+		clientv3.Compare(clientv3.Version(key), ">", 0), //key exist
+	).Then(
+		clientv3.OpPut(key, string(value)),
+	).Commit()
+	if err != nil {
+		return errors.Wrap(err, errors.StoreError, "failed to update change request in etcd server")
+	}
+	if !txnResp.Succeeded {
+		return errors.Errorf(errors.EntityNotFound, "change request %q is not found in service %q", changeRequest.ID, changeRequest.ServiceName)
+	}
+	return nil
+}
+
+// GetChangeRequest gets a change request of a service
+func (s *Store) GetChangeRequest(serviceName string, id string) (*pms.ChangeRequest, error) {
+	resp, err := s.timeOutGet(s.changeRequestKey(serviceName, id))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.StoreError, "failed to get change request from etcd server")
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.Errorf(errors.EntityNotFound, "change request %q is not found in service %q", id, serviceName)
+	}
+	var changeRequest pms.ChangeRequest
+	if err := json.Unmarshal(resp.Kvs[0].Value, &changeRequest); err != nil {
+		return nil, errors.Wrap(err, errors.SerializationError, "failed to unmarshal change request")
+	}
+	return &changeRequest, nil
+}
+
+// ListChangeRequests lists the change requests of a service, or of all services when serviceName is empty
+func (s *Store) ListChangeRequests(serviceName string) ([]*pms.ChangeRequest, error) {
+	prefix := s.KeyPrefix + ChangeRequestsKey + KeySeparator
+	if len(serviceName) > 0 {
+		prefix += serviceName + KeySeparator
+	}
+	responses, err := s.prefixGet(prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.StoreError, "failed to list change requests from etcd server")
+	}
+	changeRequests := []*pms.ChangeRequest{}
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		for _, kv := range resp.Kvs {
+			var changeRequest pms.ChangeRequest
+			if err := json.Unmarshal(kv.Value, &changeRequest); err != nil {
+				return nil, errors.Wrap(err, errors.SerializationError, "failed to unmarshal change request")
+			}
+			changeRequests = append(changeRequests, &changeRequest)
+		}
+	}
+	return changeRequests, nil
+}
+
+// PublishChangeRequest applies a change request to its service and saves the change request in one transaction.
+// The transaction only succeeds if neither the change request nor the service is changed since they are read,
+// every policy change puts the service key.
+func (s *Store) PublishChangeRequest(changeRequest *pms.ChangeRequest) error {
+	serviceKey := s.KeyPrefix + ServicesKey + KeySeparator + changeRequest.ServiceName + KeySeparator
+	changeRequestKey := s.changeRequestKey(changeRequest.ServiceName, changeRequest.ID)
+	resp, err := s.timeOutGet(changeRequestKey)
+	if err != nil {
+		return errors.Wrap(err, errors.StoreError, "failed to get change request from etcd server")
+	}
+	if len(resp.Kvs) == 0 {
+		return errors.Errorf(errors.EntityNotFound, "change request %q is not found in service %q", changeRequest.ID, changeRequest.ServiceName)
+	}
+	var stored pms.ChangeRequest
+	if err := json.Unmarshal(resp.Kvs[0].Value, &stored); err != nil {
+		return errors.Wrap(err, errors.SerializationError, "failed to unmarshal change request")
+	}
+	if err := store.CheckPublishable(&stored); err != nil {
+		return err
+	}
+	changeRequestRev := resp.Kvs[0].ModRevision
+	resp, err = s.timeOutGet(serviceKey)
+	if err != nil {
+		return errors.Wrap(err, errors.StoreError, "failed to get service from etcd server")
+	}
+	if len(resp.Kvs) == 0 {
+		return errors.Errorf(errors.EntityNotFound, "service %q is not found", changeRequest.ServiceName)
+	}
+	conditions := []clientv3.Cmp{
+		clientv3.Compare(clientv3.ModRevision(changeRequestKey), "=", changeRequestRev),
+		clientv3.Compare(clientv3.ModRevision(serviceKey), "=", resp.Kvs[0].ModRevision),
+	}
+	var ops []clientv3.Op
+	for _, id := range changeRequest.DeletePolicies {
+		key := serviceKey + PoliciesKey + KeySeparator + id
+		conditions = append(conditions, clientv3.Compare(clientv3.Version(key), ">", 0))
+		ops = append(ops, clientv3.OpDelete(key))
+	}
+	for _, id := range changeRequest.DeleteRolePolicies {
+		key := serviceKey + RolePoliciesKey + KeySeparator + id
+		conditions = append(conditions, clientv3.Compare(clientv3.Version(key), ">", 0))
+		ops = append(ops, clientv3.OpDelete(key))
+	}
+	for _, policy := range changeRequest.AddPolicies {
+		dupPolicy := *policy
+		dupPolicy.ID = suid.New().String()
+		value, err := json.Marshal(dupPolicy)
+		if err != nil {
+			return errors.Wrap(err, errors.SerializationError, "failed to marshal policy")
+		}
+		ops = append(ops, clientv3.OpPut(serviceKey+PoliciesKey+KeySeparator+dupPolicy.ID, string(value)))
+	}
+	for _, rolePolicy := range changeRequest.AddRolePolicies {
+		dupRolePolicy := *rolePolicy
+		dupRolePolicy.ID = suid.New().String()
+		value, err := json.Marshal(dupRolePolicy)
+		if err != nil {
+			return errors.Wrap(err, errors.SerializationError, "failed to marshal role policy")
+		}
+		ops = append(ops, clientv3.OpPut(serviceKey+RolePoliciesKey+KeySeparator+dupRolePolicy.ID, string(value)))
+	}
+	value, err := json.Marshal(changeRequest)
+	if err != nil {
+		return errors.Wrap(err, errors.SerializationError, "failed to marshal change request")
+	}
+	ops = append(ops, clientv3.OpPut(changeRequestKey, string(value)))
+	//make sure updating service key is the last operation, so watch could work correctly
+	ops = append(ops, clientv3.OpPut(serviceKey, ""))
+
+	//changes must be published in one transaction, which only supports up to 128 operations
+	if len(ops) > int(embed.DefaultMaxTxnOps) || len(conditions) > int(embed.DefaultMaxTxnOps) {
+		return errors.Errorf(errors.ExceedLimit, "change request %q has too many changes to publish at once", changeRequest.ID)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	txnResp, err := s.client.KV.Txn(ctx).If(conditions...).Then(ops...).Commit()
+	if err != nil {
+		return errors.Wrapf(err, errors.StoreError, "failed to publish change request %q", changeRequest.ID)
+	}
+	if !txnResp.Succeeded {
+		return errors.Errorf(errors.Conflict, "change request %q or service %q is changed concurrently, or policies or role policies deleted by the change request are not found", changeRequest.ID, changeRequest.ServiceName)
+	}
+	return nil
+}
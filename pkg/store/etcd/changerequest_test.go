@@ -0,0 +1,50 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package etcd
+
+import (
+	"testing"
+
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/store"
+)
+
+func TestPublishChangeRequestOnce(t *testing.T) {
+	s, err := store.NewStore(storeConfig.StoreType, storeConfig.StoreProps)
+	if err != nil {
+		t.Fatal("fail to new etcd store:", err)
+	}
+	defer s.(*Store).destroy()
+	changes := s.(store.ChangeRequestManager)
+
+	if err := s.CreateService(&pms.Service{Name: "crservice", Type: pms.TypeApplication}); err != nil {
+		t.Fatal("fail to create service:", err)
+	}
+	changeRequest, err := changes.CreateChangeRequest(&pms.ChangeRequest{
+		ServiceName: "crservice",
+		Status:      pms.ChangeRequestReview,
+		AddPolicies: []*pms.Policy{{Name: "p1", Effect: pms.Grant, Principals: [][]string{{"user:alice"}}}},
+	})
+	if err != nil {
+		t.Fatal("fail to create change request:", err)
+	}
+
+	published := *changeRequest
+	published.Status = pms.ChangeRequestPublished
+	if err := changes.PublishChangeRequest(&published); err != nil {
+		t.Fatal("fail to publish change request:", err)
+	}
+	// publishing the copy read before the first publish again
+	if err := changes.PublishChangeRequest(&published); errors.Code(err) != errors.Conflict {
+		t.Errorf("publishing a published change request should conflict, but %v", err)
+	}
+	if count, err := s.GetPolicyCount("crservice"); err != nil || count != 1 {
+		t.Errorf("service should have 1 policy, but %d, %v", count, err)
+	}
+	stored, err := changes.GetChangeRequest("crservice", changeRequest.ID)
+	if err != nil || stored.Status != pms.ChangeRequestPublished {
+		t.Errorf("change request should be published, but %v, %v", stored, err)
+	}
+}
@@ -6,6 +6,7 @@ package etcd
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 
 	"github.com/teramoby/speedle-plus/api/ads"
 	"github.com/teramoby/speedle-plus/api/pms"
@@ -28,25 +29,52 @@ func (s *Store) SaveDiscoverRequest(request *ads.RequestContext) error {
 	return err
 }
 
+// PutRequest saves a request, the same requests are aggregated in a key whose value counts their hits. Requests
+// expire with leases, and the least recently seen ones are removed when the max number of requests is reached.
 func (s *Store) PutRequest(request *ads.RequestContext) (int64, error) {
-	value, err := json.Marshal(request)
-	if err != nil {
-		return -1, errors.Wrap(err, errors.SerializationError, "failed to marshal request")
-	}
+	key := DiscoverPrefix + request.ServiceName + KeySeparator + store.DiscoverRequestKey(request)
 	succeed := false
 	for !succeed {
-		key := DiscoverPrefix + request.ServiceName + "/" + time.Now().String()
+		getResp, err := s.client.Get(context.TODO(), key)
+		if err != nil {
+			return -1, errors.Wrapf(err, errors.StoreError, "unable to get discover request %q", key)
+		}
+		now := time.Now()
+		aggregate := &store.DiscoverRequestAggregate{Request: request, FirstSeen: now}
+		cmp := clientv3.Compare(clientv3.CreateRevision(key), "=", 0) //key does not exist
+		if len(getResp.Kvs) > 0 {
+			if aggregate, err = decodeAggregate(getResp.Kvs[0].Value); err != nil {
+				return -1, err
+			}
+			cmp = clientv3.Compare(clientv3.ModRevision(key), "=", getResp.Kvs[0].ModRevision) //key is not updated since
+		}
+		aggregate.Hits++
+		aggregate.LastSeen = now
+		aggregate.ExpireAt = store.DiscoverRequestExpiration(request.ServiceName, now)
+		value, err := json.Marshal(aggregate)
+		if err != nil {
+			return -1, errors.Wrap(err, errors.SerializationError, "failed to marshal request")
+		}
+		putOpts := []clientv3.OpOption{}
+		if aggregate.ExpireAt != nil {
+			ttl := int64(math.Ceil(aggregate.ExpireAt.Sub(now).Seconds()))
+			lease, err := s.client.Grant(context.TODO(), ttl)
+			if err != nil {
+				return -1, errors.Wrapf(err, errors.StoreError, "unable to grant lease of discover request %q", key)
+			}
+			putOpts = append(putOpts, clientv3.WithLease(lease.ID))
+		}
 		txnResp, err := s.client.KV.Txn(context.TODO()).If(
-			clientv3.Compare(clientv3.CreateRevision(key), "=", 0), //key does not exist
+			cmp,
 		).Then(
-			clientv3.OpPut(key, string(value)),
+			clientv3.OpPut(key, string(value), putOpts...),
 			clientv3.OpGet(DiscoverPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly()), //get number of requests
-			clientv3.OpGet(DiscoverPrefix, clientv3.WithLimit(store.DeleteNumWhenReachMaxDiscoverRequest), clientv3.WithPrefix(), clientv3.WithKeysOnly(), clientv3.WithSort(clientv3.SortByCreateRevision, clientv3.SortAscend)), //get oldest keys
+			clientv3.OpGet(DiscoverPrefix, clientv3.WithLimit(store.DeleteNumWhenReachMaxDiscoverRequest), clientv3.WithPrefix(), clientv3.WithKeysOnly(), clientv3.WithSort(clientv3.SortByModRevision, clientv3.SortAscend)), //get least recently seen keys
 		).Commit()
 		if err != nil {
 			return -1, err
 		}
-		if txnResp.Succeeded { //if not succeed, the key already exist, try again
+		if txnResp.Succeeded { //if not succeed, the request is saved concurrently, try again
 			succeed = true
 			count := txnResp.Responses[1].GetResponseRange().Count
 			if count >= store.MaxDiscoverRequestNum { //reach Max number of requests, remove the oldest ones.
@@ -58,11 +86,26 @@ func (s *Store) PutRequest(request *ads.RequestContext) (int64, error) {
 			}
 			return count, nil
 		}
-		fmt.Println("key already exist, try with a new key...")
 	}
 	return -1, nil //should not go here
 }
 
+// decodeAggregate decodes an aggregated request, the requests saved before they were aggregated were seen once
+func decodeAggregate(value []byte) (*store.DiscoverRequestAggregate, error) {
+	var aggregate store.DiscoverRequestAggregate
+	if err := json.Unmarshal(value, &aggregate); err != nil {
+		return nil, errors.Wrapf(err, errors.SerializationError, "failed to unmarshal request context %q", value)
+	}
+	if aggregate.Request == nil {
+		var request ads.RequestContext
+		if err := json.Unmarshal(value, &request); err != nil {
+			return nil, errors.Wrapf(err, errors.SerializationError, "failed to unmarshal request context %q", value)
+		}
+		aggregate = store.DiscoverRequestAggregate{Request: &request, Hits: 1}
+	}
+	return &aggregate, nil
+}
+
 func (s *Store) DeleteRequests(keys []string) error {
 	deleteOps := []clientv3.Op{}
 	for _, key := range keys {
@@ -77,7 +120,7 @@ func (s *Store) DeleteRequests(keys []string) error {
 }
 
 func (s *Store) GetLastDiscoverRequest(serviceName string) (*ads.RequestContext, int64, error) {
-	getOpts := append(clientv3.WithLastCreate(), clientv3.WithPrefix())
+	getOpts := append(clientv3.WithLastRev(), clientv3.WithPrefix())
 	keyPrefix4Search := DiscoverPrefix
 	if len(serviceName) > 0 {
 		keyPrefix4Search = keyPrefix4Search + serviceName + KeySeparator
@@ -89,16 +132,15 @@ func (s *Store) GetLastDiscoverRequest(serviceName string) (*ads.RequestContext,
 	if len(getResp.Kvs) == 0 {
 		return nil, -1, errors.Wrapf(err, errors.EntityNotFound, "no request found for service %q", serviceName)
 	}
-	var request ads.RequestContext
-	err = json.Unmarshal(getResp.Kvs[0].Value, &request)
+	aggregate, err := decodeAggregate(getResp.Kvs[0].Value)
 	if err != nil {
-		return nil, -1, errors.Wrapf(err, errors.SerializationError, "failed to unmarshal request context %q for service %q", getResp.Kvs[0].Value, serviceName)
+		return nil, -1, err
 	}
-	return &request, getResp.Header.Revision, nil
+	return aggregate.Request, getResp.Header.Revision, nil
 }
 
 func (s *Store) GetDiscoverRequestsSinceRevision(serviceName string, revision int64) ([]*ads.RequestContext, int64, error) {
-	getOpts := []clientv3.OpOption{clientv3.WithMinCreateRev(revision + 1), clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByCreateRevision, clientv3.SortAscend)}
+	getOpts := []clientv3.OpOption{clientv3.WithMinModRev(revision + 1), clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByModRevision, clientv3.SortAscend)}
 	keyPrefix4Search := DiscoverPrefix
 	if len(serviceName) > 0 {
 		keyPrefix4Search = keyPrefix4Search + serviceName + KeySeparator
@@ -109,20 +151,32 @@ func (s *Store) GetDiscoverRequestsSinceRevision(serviceName string, revision in
 	}
 	requests := []*ads.RequestContext{}
 	for _, kv := range getResp.Kvs {
-		var req ads.RequestContext
-		err = json.Unmarshal(kv.Value, &req)
+		aggregate, err := decodeAggregate(kv.Value)
 		if err != nil {
-			return nil, -1, errors.Wrapf(err, errors.SerializationError, "failed to unmarshal request context %q for service %q", kv.Value, serviceName)
+			return nil, -1, err
 		}
-		requests = append(requests, &req)
+		requests = append(requests, aggregate.Request)
 	}
 	return requests, getResp.Header.Revision, nil
 
 }
 
 func (s *Store) GetRequests(keyPrefix string, pageSize int64) ([]*ads.RequestContext, int64, error) {
+	aggregates, revision, err := s.GetRequestAggregates(keyPrefix, pageSize)
+	if err != nil {
+		return nil, -1, err
+	}
 	requests := []*ads.RequestContext{}
-	getOpts := []clientv3.OpOption{clientv3.WithPrefix(), clientv3.WithLimit(pageSize), clientv3.WithSort(clientv3.SortByCreateRevision, clientv3.SortAscend)}
+	for _, aggregate := range aggregates {
+		requests = append(requests, aggregate.Request)
+	}
+	return requests, revision, nil
+}
+
+// GetRequestAggregates gets the aggregated requests of a key prefix by pages, from the least recently seen one
+func (s *Store) GetRequestAggregates(keyPrefix string, pageSize int64) ([]*store.DiscoverRequestAggregate, int64, error) {
+	aggregates := []*store.DiscoverRequestAggregate{}
+	getOpts := []clientv3.OpOption{clientv3.WithPrefix(), clientv3.WithLimit(pageSize), clientv3.WithSort(clientv3.SortByModRevision, clientv3.SortAscend)}
 	var revision int64
 	for {
 		getResp, err := s.client.Get(context.TODO(), keyPrefix, getOpts...)
@@ -130,23 +184,22 @@ func (s *Store) GetRequests(keyPrefix string, pageSize int64) ([]*ads.RequestCon
 			return nil, -1, errors.Wrapf(err, errors.StoreError, "unable to get discover requests from etcd server for prefix %q", keyPrefix)
 		}
 		for _, kv := range getResp.Kvs {
-			var req ads.RequestContext
-			err = json.Unmarshal(kv.Value, &req)
+			aggregate, err := decodeAggregate(kv.Value)
 			if err != nil {
-				return nil, -1, errors.Wrapf(err, errors.SerializationError, "failed to unmarshal request context %q for prefix %q", kv.Value, keyPrefix)
+				return nil, -1, err
 			}
-			requests = append(requests, &req)
+			aggregates = append(aggregates, aggregate)
 		}
 		fmt.Println("len=", len(getResp.Kvs), "more:", getResp.More, "revision:", getResp.Header.Revision)
 		if getResp.More {
-			revision := getResp.Kvs[pageSize-1].CreateRevision
-			getOpts = []clientv3.OpOption{clientv3.WithPrefix(), clientv3.WithMinCreateRev(revision + 1), clientv3.WithLimit(pageSize), clientv3.WithSort(clientv3.SortByCreateRevision, clientv3.SortAscend)}
+			revision := getResp.Kvs[pageSize-1].ModRevision
+			getOpts = []clientv3.OpOption{clientv3.WithPrefix(), clientv3.WithMinModRev(revision + 1), clientv3.WithLimit(pageSize), clientv3.WithSort(clientv3.SortByModRevision, clientv3.SortAscend)}
 		} else {
 			revision = getResp.Header.Revision
 			break
 		}
 	}
-	return requests, revision, nil
+	return aggregates, revision, nil
 
 }
 
@@ -159,6 +212,15 @@ func (s *Store) GetDiscoverRequests(serviceName string) ([]*ads.RequestContext,
 
 }
 
+// GetDiscoverRequestAggregates gets the distinct requests of a service with their hit counts.
+// Get the ones of all services when serviceName is empty.
+func (s *Store) GetDiscoverRequestAggregates(serviceName string) ([]*store.DiscoverRequestAggregate, int64, error) {
+	if len(serviceName) == 0 {
+		return s.GetRequestAggregates(DiscoverPrefix, DefaultPageSize)
+	}
+	return s.GetRequestAggregates(DiscoverPrefix+serviceName+KeySeparator, DefaultPageSize)
+}
+
 func (s *Store) ResetDiscoverRequests(serviceName string) error {
 	var err error
 	if len(serviceName) == 0 {
@@ -349,3 +349,33 @@ func TestGeneratePolicies(t *testing.T) {
 	}
 
 }
+
+func TestDiscoverRequestAggregation(t *testing.T) {
+	s, err := store.NewStore(storeConfig.StoreType, storeConfig.StoreProps)
+	if err != nil {
+		t.Fatal("fail to new etcd store")
+	}
+	discover := s.(store.DiscoverRequestManager)
+	if err := discover.ResetDiscoverRequests(""); err != nil {
+		t.Fatal("Fail to reset all requests")
+	}
+	for _, resName := range []string{"/res0", "/res1", "/res0", "/res0"} {
+		user := ads.Principal{Type: "user", Name: "user1"}
+		request := ads.RequestContext{Subject: &ads.Subject{Principals: []*ads.Principal{&user}}, ServiceName: "erp", Resource: resName, Action: "read"}
+		if err := discover.SaveDiscoverRequest(&request); err != nil {
+			t.Fatal("fail to put request in store", err)
+		}
+	}
+	aggregates, _, err := discover.GetDiscoverRequestAggregates("erp")
+	if err != nil {
+		t.Fatal("fail to GetDiscoverRequestAggregates:", err)
+	}
+	if len(aggregates) != 2 || aggregates[0].Request.Resource != "/res1" || aggregates[0].Hits != 1 ||
+		aggregates[1].Request.Resource != "/res0" || aggregates[1].Hits != 3 || !aggregates[1].FirstSeen.Before(aggregates[1].LastSeen) {
+		t.Errorf("requests should be aggregated by the time they were last seen, got %d aggregates", len(aggregates))
+	}
+	request, _, err := discover.GetLastDiscoverRequest("erp")
+	if err != nil || request.Resource != "/res0" {
+		t.Errorf("last seen request should be /res0, got %v, err: %v", request, err)
+	}
+}
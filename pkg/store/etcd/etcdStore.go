@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/teramoby/speedle-plus/pkg/errors"
@@ -23,14 +24,18 @@ import (
 )
 
 const (
-	requestTimeout  = 10 * time.Second
-	KeySeparator    = "/"
-	PoliciesKey     = "policies"
-	RolePoliciesKey = "role_policies"
-	ServicesKey     = "services"
-	FunctionsKey    = "functions"
-	ServiceTypeKey  = "type"
-	pageSize        = 1000
+	requestTimeout   = 10 * time.Second
+	KeySeparator     = "/"
+	PoliciesKey      = "policies"
+	RolePoliciesKey  = "role_policies"
+	ServicesKey      = "services"
+	FunctionsKey     = "functions"
+	ServiceTypeKey   = "type"
+	OwnersKey        = "owners"
+	DefaultEffectKey = "default_effect"
+	FailureModeKey   = "failure_mode"
+	ModeKey          = "mode"
+	pageSize         = 1000
 )
 
 type Store struct {
@@ -38,6 +43,7 @@ type Store struct {
 	Config       *clientv3.Config
 	KeyPrefix    string
 	stop         chan struct{}
+	watchLock    sync.Mutex
 	embeddedInst *embed.Etcd
 	embeddedDir  string
 }
@@ -234,6 +240,40 @@ func (s *Store) GetServiceItself(serviceName string) (*pms.Service, error) {
 		service.Type = string(kv.Value)
 	}
 
+	resp, err = s.client.Get(ctx, serviceKey+KeySeparator+OwnersKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, kv := range resp.Kvs {
+		if err := json.Unmarshal(kv.Value, &service.Owners); err != nil {
+			return nil, errors.Errorf(errors.SerializationError, "failed to unmarshal owners %q", kv.Value)
+		}
+	}
+
+	resp, err = s.client.Get(ctx, serviceKey+KeySeparator+DefaultEffectKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, kv := range resp.Kvs {
+		service.DefaultEffect = string(kv.Value)
+	}
+
+	resp, err = s.client.Get(ctx, serviceKey+KeySeparator+FailureModeKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, kv := range resp.Kvs {
+		service.FailureMode = string(kv.Value)
+	}
+
+	resp, err = s.client.Get(ctx, serviceKey+KeySeparator+ModeKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, kv := range resp.Kvs {
+		service.Mode = string(kv.Value)
+	}
+
 	return &service, nil
 }
 
@@ -254,6 +294,24 @@ func (s *Store) GetService(serviceName string) (*pms.Service, error) {
 				//service type
 				service.Type = string(kv.Value)
 			}
+			if strings.Compare(string(kv.Key), serviceKey+OwnersKey) == 0 {
+				//service owners
+				if err := json.Unmarshal(kv.Value, &service.Owners); err != nil {
+					return nil, errors.Errorf(errors.SerializationError, "failed to unmarshal owners %q", kv.Value)
+				}
+			}
+			if strings.Compare(string(kv.Key), serviceKey+DefaultEffectKey) == 0 {
+				//effect when no policy applies
+				service.DefaultEffect = string(kv.Value)
+			}
+			if strings.Compare(string(kv.Key), serviceKey+FailureModeKey) == 0 {
+				//decision on evaluation errors
+				service.FailureMode = string(kv.Value)
+			}
+			if strings.Compare(string(kv.Key), serviceKey+ModeKey) == 0 {
+				//enforce, shadow or discover
+				service.Mode = string(kv.Value)
+			}
 			if strings.HasPrefix(string(kv.Key), serviceKey+PoliciesKey) {
 				//policies
 				var policy pms.Policy
@@ -332,6 +390,22 @@ func (s *Store) getPutOps(service *pms.Service) ([]clientv3.Op, error) {
 		ops = append(ops, clientv3.OpPut(key, string(value)))
 	}
 	ops = append(ops, clientv3.OpPut(s.KeyPrefix+ServicesKey+KeySeparator+service.Name+KeySeparator+ServiceTypeKey, service.Type))
+	if len(service.Owners) > 0 {
+		value, err := json.Marshal(service.Owners)
+		if err != nil {
+			return nil, errors.Errorf(errors.SerializationError, "failed to marshal owners")
+		}
+		ops = append(ops, clientv3.OpPut(s.KeyPrefix+ServicesKey+KeySeparator+service.Name+KeySeparator+OwnersKey, string(value)))
+	}
+	if len(service.DefaultEffect) > 0 {
+		ops = append(ops, clientv3.OpPut(s.KeyPrefix+ServicesKey+KeySeparator+service.Name+KeySeparator+DefaultEffectKey, service.DefaultEffect))
+	}
+	if len(service.FailureMode) > 0 {
+		ops = append(ops, clientv3.OpPut(s.KeyPrefix+ServicesKey+KeySeparator+service.Name+KeySeparator+FailureModeKey, service.FailureMode))
+	}
+	if len(service.Mode) > 0 {
+		ops = append(ops, clientv3.OpPut(s.KeyPrefix+ServicesKey+KeySeparator+service.Name+KeySeparator+ModeKey, service.Mode))
+	}
 	//make sure updating service key is the last operation, so watch could work correctly
 	ops = append(ops, clientv3.OpPut(s.KeyPrefix+ServicesKey+KeySeparator+service.Name+KeySeparator, ""))
 	return ops, nil
@@ -400,6 +474,31 @@ func (s *Store) DeleteService(serviceName string) error {
 	return nil
 }
 
+// SetServiceOwners replaces the owners of a service
+func (s *Store) SetServiceOwners(serviceName string, owners []string) error {
+	serviceKey := s.KeyPrefix + ServicesKey + KeySeparator + serviceName + KeySeparator
+	op := clientv3.OpDelete(serviceKey + OwnersKey)
+	if len(owners) > 0 {
+		value, err := json.Marshal(owners)
+		if err != nil {
+			return errors.Errorf(errors.SerializationError, "failed to marshal owners")
+		}
+		op = clientv3.OpPut(serviceKey+OwnersKey, string(value))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	txnResp, err := s.client.KV.Txn(ctx).If(
+		clientv3.Compare(clientv3.Version(serviceKey), ">", 0), //service exists
+	).Then(op).Commit()
+	if err != nil {
+		return err
+	}
+	if !txnResp.Succeeded {
+		return errors.Errorf(errors.EntityNotFound, "service %q is not found", serviceName)
+	}
+	return nil
+}
+
 func (s *Store) DeleteServices() error {
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
 	defer cancel()
@@ -544,75 +643,91 @@ func (s *Store) GetFunctionCount() (int64, error) {
 }
 
 func (s *Store) Watch() (pms.StorageChangeChannel, error) {
-	log.Info("Entering Watch...")
+	return s.watchFrom(0)
+}
+
+// ResumeWatch watches the changes after the revision of the last received event, so that no change
+// is missed when a watch is re-established. It fails if the revision has been compacted.
+func (s *Store) ResumeWatch(last *pms.StoreChangeEvent) (pms.StorageChangeChannel, error) {
+	if last == nil || last.Revision == 0 {
+		return nil, errors.New(errors.StoreError, "no revision to resume the watch from")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	if _, err := s.client.Get(ctx, s.KeyPrefix, clientv3.WithRev(last.Revision), clientv3.WithCountOnly()); err != nil {
+		return nil, errors.Wrapf(err, errors.StoreError, "failed to resume watch from revision %d", last.Revision)
+	}
+	return s.watchFrom(last.Revision + 1)
+}
+
+// watchFrom watches the changes from the revision, or from now if the revision is 0. The returned
+// channel is closed when the watch fails or is stopped.
+func (s *Store) watchFrom(rev int64) (pms.StorageChangeChannel, error) {
+	log.Infof("Entering Watch from revision %d...", rev)
+	cli, err := clientv3.New(*s.Config)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.StoreError, "failed to connect to etcd server")
+	}
+	// Session represents a lease kept alive for the lifetime of a client. Fault-tolerant applications may use sessions to reason about liveness.
+	session, err := concurrency.NewSession(cli, concurrency.WithTTL(60))
+	if err != nil {
+		cli.Close()
+		return nil, errors.Wrap(err, errors.StoreError, "failed to create session with etcd server")
+	}
+
+	stop := make(chan struct{})
+	s.watchLock.Lock()
+	s.stop = stop
+	s.watchLock.Unlock()
+
 	evalChan := make(chan pms.StoreChangeEvent)
-	s.stop = make(chan struct{})
-	errChan := make(chan error)
-	stopChan := make(chan struct{})
 	go func() {
 		defer func() {
 			close(evalChan)
-			close(s.stop)
-			close(errChan)
-			close(stopChan)
+			session.Close()
+			cli.Close()
 			log.Info("Exiting Watch...")
 		}()
-	loop:
-		for {
-			//TODO: reload policy store in case missing any changes during watch failure
-			go watch(evalChan, s, errChan, stopChan)
-			select {
-			case err := <-errChan:
-				log.Warningf("Error %v happens, restart watching...\n", err)
-				continue
-			case <-stopChan:
-				log.Warning("Receiving stop signal, stop Watching...")
-				break loop
-			}
+		if err := watch(evalChan, s, cli, session, rev, stop); err != nil {
+			log.Warningf("Error %v happens, stop watching...", err)
 		}
-
 	}()
 	return evalChan, nil
 }
 
-func watch(evalChan chan pms.StoreChangeEvent, s *Store, errChan chan error, stopChan chan struct{}) {
-	watchID := time.Now().Unix()
-	log.Infof("Entering watch %v...", watchID)
-	cli, err := clientv3.New(*s.Config)
-	if err != nil {
-		log.Warningf("Error happens when new etcd client, %v, exiting watch...\n", err)
-		err := errors.Wrapf(err, errors.StoreError, "failed to connect to etcd server")
-		errChan <- err
-		return
+// watch sends the changes to evalChan until the watch fails or is stopped
+func watch(evalChan chan pms.StoreChangeEvent, s *Store, cli *clientv3.Client, session *concurrency.Session, rev int64, stop chan struct{}) error {
+	opts := []clientv3.OpOption{clientv3.WithPrefix()}
+	if rev > 0 {
+		opts = append(opts, clientv3.WithRev(rev))
 	}
-	defer func() {
-		cli.Close()
-		log.Infof("Exiting watch %v...", watchID)
-	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	etcdChan := cli.Watch(ctx, s.KeyPrefix, opts...)
 
-	// Session represents a lease kept alive for the lifetime of a client. Fault-tolerant applications may use sessions to reason about liveness.
-	session, err := concurrency.NewSession(cli, concurrency.WithTTL(60))
-	if err != nil {
-		log.Warningf("Error happens when new session, %v\n", err)
-		err = errors.Wrap(err, errors.StoreError, "failed to create session with etcd server")
-		errChan <- err
-		return
+	// send returns false if the watch is stopped while the event is pending
+	send := func(e pms.StoreChangeEvent) bool {
+		select {
+		case evalChan <- e:
+			return true
+		case <-stop:
+			return false
+		}
 	}
 
-	etcdChan := cli.Watch(context.Background(), s.KeyPrefix, clientv3.WithPrefix())
-
 	for {
 		select {
 		// receive watch response from etcd
-		case resp := <-etcdChan:
+		case resp, ok := <-etcdChan:
+			if !ok {
+				return errors.New(errors.StoreError, "watch channel is closed by etcd client")
+			}
 			if err := resp.Err(); err != nil {
-				log.Warningf("Error happens in watch response, %v\n", err)
-				err = errors.Wrap(err, errors.StoreError, "error found in watch response")
-				errChan <- err
-				return
+				return errors.Wrap(err, errors.StoreError, "error found in watch response")
 			}
 			for _, e := range resp.Events {
 				id := time.Now().Unix()
+				var event *pms.StoreChangeEvent
 				//Note: In each policy/rolePolicy creation/deletion, service node (s.KeyPrefix+serviceName+keySeparator) will be updated.
 				//so we could only check the event on service node.
 				if clientv3.EventTypeDelete == e.Type {
@@ -620,11 +735,11 @@ func watch(evalChan chan pms.StoreChangeEvent, s *Store, errChan chan error, sto
 						serviceName := strings.TrimPrefix(string(e.Kv.Key), s.KeyPrefix+ServicesKey+KeySeparator)
 						serviceName = strings.TrimSuffix(serviceName, KeySeparator)
 						if strings.Index(serviceName, KeySeparator) == -1 {
-							evalChan <- pms.StoreChangeEvent{Type: pms.SERVICE_DELETE, ID: id, Content: []string{serviceName}}
+							event = &pms.StoreChangeEvent{Type: pms.SERVICE_DELETE, ID: id, Content: []string{serviceName}}
 						}
 					} else if strings.HasPrefix(string(e.Kv.Key), s.KeyPrefix+FunctionsKey+KeySeparator) {
 						functionName := strings.TrimPrefix(string(e.Kv.Key), s.KeyPrefix+FunctionsKey+KeySeparator)
-						evalChan <- pms.StoreChangeEvent{Type: pms.FUNCTION_DELETE, ID: id, Content: []string{functionName}}
+						event = &pms.StoreChangeEvent{Type: pms.FUNCTION_DELETE, ID: id, Content: []string{functionName}}
 					}
 
 				} else if clientv3.EventTypePut == e.Type {
@@ -637,7 +752,7 @@ func watch(evalChan chan pms.StoreChangeEvent, s *Store, errChan chan error, sto
 								log.Warningf("Unable get service due to error %v.\n", err)
 								continue
 							}
-							evalChan <- pms.StoreChangeEvent{Type: pms.SERVICE_ADD, ID: id, Content: service}
+							event = &pms.StoreChangeEvent{Type: pms.SERVICE_ADD, ID: id, Content: service}
 						}
 					} else if strings.HasPrefix(string(e.Kv.Key), s.KeyPrefix+FunctionsKey+KeySeparator) {
 						functionName := strings.TrimPrefix(string(e.Kv.Key), s.KeyPrefix+FunctionsKey+KeySeparator)
@@ -645,29 +760,36 @@ func watch(evalChan chan pms.StoreChangeEvent, s *Store, errChan chan error, sto
 						if err != nil {
 							log.Warningf("Unable to get function due to error %v.\n", err)
 						}
-						evalChan <- pms.StoreChangeEvent{Type: pms.FUNCTION_ADD, ID: id, Content: function}
+						event = &pms.StoreChangeEvent{Type: pms.FUNCTION_ADD, ID: id, Content: function}
 
 					}
 				}
+				if event != nil {
+					event.Revision = e.Kv.ModRevision
+					if !send(*event) {
+						return nil
+					}
+				}
 			}
 			// receive the stop signal
-		case <-s.stop:
+		case <-stop:
 			log.Warning("Receiving stop signal")
-			stopChan <- struct{}{}
-			return
+			return nil
 
 		case <-session.Done(): // closed by etcd
 			log.Warning("Session is closed by etcd")
-			errChan <- errors.New(errors.StoreError, "watch session is closed by remote etcd server")
-			return
+			return errors.New(errors.StoreError, "watch session is closed by remote etcd server")
 		}
 	}
-
 }
 
+// StopWatch stops the current watch, its channel is closed
 func (s *Store) StopWatch() {
+	s.watchLock.Lock()
+	defer s.watchLock.Unlock()
 	if s.stop != nil {
-		s.stop <- struct{}{}
+		close(s.stop)
+		s.stop = nil
 	}
 }
 
@@ -153,6 +153,41 @@ func TestWriteReadDeleteService(t *testing.T) {
 	}
 }
 
+func TestServiceDecisionSettings(t *testing.T) {
+	store, err := store.NewStore(storeConfig.StoreType, storeConfig.StoreProps)
+	if err != nil {
+		t.Fatal("fail to new etcd3 store:", err)
+	}
+	defer store.(*Store).destroy()
+
+	app := pms.Service{Name: "decided", Type: pms.TypeApplication, DefaultEffect: pms.Grant, FailureMode: pms.FailClosed, Mode: pms.ModeShadow}
+	if err := store.CreateService(&app); err != nil {
+		t.Fatal("fail to create service:", err)
+	}
+	if err := store.CreateService(&pms.Service{Name: "undecided", Type: pms.TypeApplication}); err != nil {
+		t.Fatal("fail to create service:", err)
+	}
+	for _, get := range []func(string) (*pms.Service, error){store.GetService, store.(*Store).GetServiceItself} {
+		service, err := get("decided")
+		if err != nil {
+			t.Fatal("fail to get service:", err)
+		}
+		if service.DefaultEffect != pms.Grant || service.FailureMode != pms.FailClosed {
+			t.Errorf("default effect and failure mode should be read, but %q and %q", service.DefaultEffect, service.FailureMode)
+		}
+		if service.Mode != pms.ModeShadow {
+			t.Errorf("mode should be read, but %q", service.Mode)
+		}
+		service, err = get("undecided")
+		if err != nil {
+			t.Fatal("fail to get service:", err)
+		}
+		if service.DefaultEffect != "" || service.FailureMode != "" || service.Mode != "" {
+			t.Errorf("default effect, failure mode and mode should be empty, but %q, %q and %q", service.DefaultEffect, service.FailureMode, service.Mode)
+		}
+	}
+}
+
 func TestEtcdStore_GetPolicyByName(t *testing.T) {
 	store, err := store.NewStore(storeConfig.StoreType, storeConfig.StoreProps)
 	if err != nil {
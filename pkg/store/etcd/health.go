@@ -0,0 +1,22 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package etcd
+
+import (
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/store"
+	"golang.org/x/net/context"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// Ping checks the connectivity to the etcd server
+func (s *Store) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), store.PingTimeout)
+	defer cancel()
+	if _, err := s.client.Get(ctx, s.KeyPrefix, clientv3.WithCountOnly()); err != nil {
+		return errors.Wrap(err, errors.StoreError, "failed to connect to etcd server")
+	}
+	return nil
+}
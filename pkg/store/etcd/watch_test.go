@@ -0,0 +1,138 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package etcd
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/eval"
+	"github.com/teramoby/speedle-plus/pkg/health"
+	"github.com/teramoby/speedle-plus/pkg/store"
+	"golang.org/x/net/context"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+// faultyStore injects faults into the watch of an etcd store
+type faultyStore struct {
+	*Store
+	sync.Mutex
+	// the store is unreachable until the time
+	downUntil time.Time
+}
+
+func (s *faultyStore) Watch() (pms.StorageChangeChannel, error) {
+	if err := s.Ping(); err != nil {
+		return nil, err
+	}
+	return s.Store.Watch()
+}
+
+func (s *faultyStore) ResumeWatch(last *pms.StoreChangeEvent) (pms.StorageChangeChannel, error) {
+	if err := s.Ping(); err != nil {
+		return nil, err
+	}
+	return s.Store.ResumeWatch(last)
+}
+
+func (s *faultyStore) Ping() error {
+	s.Lock()
+	defer s.Unlock()
+	if time.Now().Before(s.downUntil) {
+		return errors.New(errors.StoreError, "injected connection failure")
+	}
+	return s.Store.Ping()
+}
+
+// breakWatch closes the watch channel as if the connection to etcd was lost for the outage
+func (s *faultyStore) breakWatch(outage time.Duration) {
+	s.Lock()
+	s.downUntil = time.Now().Add(outage)
+	s.Unlock()
+	s.Store.StopWatch()
+}
+
+func waitForWatch(t *testing.T, evaluator eval.InternalEvaluator, cond func(*health.Status) bool) *health.Status {
+	var status *health.Status
+	for i := 0; i < 100; i++ {
+		if status = evaluator.CheckHealth(); cond(status) {
+			return status
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("unexpected watch status %+v, cache status %+v", status.Watch, status.Cache)
+	return nil
+}
+
+func TestWatchSupervisor(t *testing.T) {
+	ps, err := store.NewStore(storeConfig.StoreType, storeConfig.StoreProps)
+	if err != nil {
+		t.Fatal("fail to new etcd3 store:", err)
+	}
+	defer ps.(*Store).destroy()
+	if err := ps.WritePolicyStore(&pms.PolicyStore{Services: []*pms.Service{{Name: "watched-a", Type: pms.TypeApplication}}}); err != nil {
+		t.Fatal("fail to write policy store:", err)
+	}
+
+	fs := &faultyStore{Store: ps.(*Store)}
+	evaluator, err := eval.NewWithStore(&cfg.Config{StoreConfig: storeConfig, EnableWatch: true}, fs)
+	if err != nil {
+		t.Fatal("fail to create evaluator:", err)
+	}
+	defer evaluator.(*eval.PolicyEvalImpl).StopWatch()
+
+	// The watch resumes after the last revision, the change made while it is lost is applied
+	if err := fs.CreateService(&pms.Service{Name: "watched-b", Type: pms.TypeApplication}); err != nil {
+		t.Fatal("fail to create service:", err)
+	}
+	waitForWatch(t, evaluator, func(status *health.Status) bool {
+		return status.Watch.LastRevision > 0 && status.Cache.Services == 2
+	})
+	fs.breakWatch(700 * time.Millisecond)
+	if err := fs.CreateService(&pms.Service{Name: "watched-c", Type: pms.TypeApplication}); err != nil {
+		t.Fatal("fail to create service:", err)
+	}
+	status := waitForWatch(t, evaluator, func(status *health.Status) bool {
+		return status.Watch.Reconnects == 1 && status.Cache.Services == 3
+	})
+	if status.Watch.State != health.WatchStateWatching || status.Watch.Resyncs != 0 || !status.Ready {
+		t.Errorf("the watch should be resumed, status %+v", status.Watch)
+	}
+
+	// The cache is resynchronized if the revision has been compacted
+	fs.breakWatch(700 * time.Millisecond)
+	if err := fs.DeleteService("watched-a"); err != nil {
+		t.Fatal("fail to delete service:", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	resp, err := fs.client.Get(ctx, fs.KeyPrefix, clientv3.WithCountOnly())
+	if err != nil {
+		t.Fatal("fail to get revision:", err)
+	}
+	if _, err := fs.client.Compact(ctx, resp.Header.Revision); err != nil {
+		t.Fatal("fail to compact:", err)
+	}
+	if status := evaluator.CheckHealth(); status.Watch.State != health.WatchStateReconnecting || status.Watch.LastError == "" ||
+		status.Ready || !status.Live {
+		t.Errorf("the ADS should be live but not ready while the watch is re-established, status %+v", status)
+	}
+	status = waitForWatch(t, evaluator, func(status *health.Status) bool {
+		return status.Watch.Reconnects == 2
+	})
+	if status.Watch.Resyncs != 1 || status.Cache.Services != 2 || status.Watch.LastError != "" {
+		t.Errorf("the cache should be resynchronized, watch status %+v, cache status %+v", status.Watch, status.Cache)
+	}
+
+	// A stopped watch is not re-established
+	evaluator.(*eval.PolicyEvalImpl).StopWatch()
+	waitForWatch(t, evaluator, func(status *health.Status) bool {
+		return status.Watch.State == health.WatchStateStopped && !status.Live
+	})
+}
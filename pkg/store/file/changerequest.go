@@ -0,0 +1,207 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package file
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/store"
+	"github.com/teramoby/speedle-plus/pkg/suid"
+)
+
+const (
+	changeRequestStoreFileName = "speedle_change_requests.json"
+)
+
+// changeRequestStore keeps change requests in a file next to the policy store file,
+// so that the policy store file is only written when change requests are published
+type changeRequestStore struct {
+	FileLocation string
+	rwLock       sync.RWMutex
+}
+
+type changeRequestStoreContent struct {
+	ChangeRequests []*pms.ChangeRequest `json:"changeRequests,omitempty"`
+}
+
+func (s *changeRequestStore) readWithoutLock() (*changeRequestStoreContent, error) {
+	var content changeRequestStoreContent
+	raw, err := ioutil.ReadFile(s.FileLocation)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.StoreError, "unable to read file %q", s.FileLocation)
+	}
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return nil, errors.Wrapf(err, errors.SerializationError, "unable to parse file %q", s.FileLocation)
+	}
+	return &content, nil
+}
+
+func (s *changeRequestStore) writeWithoutLock(content *changeRequestStoreContent) error {
+	raw, err := json.MarshalIndent(content, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, errors.SerializationError, "marshal indent failed")
+	}
+	if err := ioutil.WriteFile(s.FileLocation, raw, 0644); err != nil {
+		return errors.Wrapf(err, errors.StoreError, "unable to write to file %q", s.FileLocation)
+	}
+	return nil
+}
+
+func (s *changeRequestStore) find(content *changeRequestStoreContent, serviceName string, id string) (int, error) {
+	for i, cr := range content.ChangeRequests {
+		if cr.ID == id && cr.ServiceName == serviceName {
+			return i, nil
+		}
+	}
+	return -1, errors.Errorf(errors.EntityNotFound, "change request %q is not found in service %q", id, serviceName)
+}
+
+func (s *changeRequestStore) update(changeRequest *pms.ChangeRequest) error {
+	content, err := s.readWithoutLock()
+	if err != nil {
+		return err
+	}
+	i, err := s.find(content, changeRequest.ServiceName, changeRequest.ID)
+	if err != nil {
+		return err
+	}
+	content.ChangeRequests[i] = changeRequest
+	return s.writeWithoutLock(content)
+}
+
+func getChangeRequestStore(s *Store) (*changeRequestStore, error) {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	if s.changeRequestStore == nil {
+		dir, _ := filepath.Split(s.FileLocation)
+		fileLocation := filepath.Join(dir, changeRequestStoreFileName)
+		if _, err := os.Stat(fileLocation); os.IsNotExist(err) {
+			log.Infof("change request store file %s does not exist, create one...", fileLocation)
+			if err := ioutil.WriteFile(fileLocation, []byte("{}"), 0644); err != nil {
+				return nil, errors.Wrapf(err, errors.StoreError, "unable to create file %q", fileLocation)
+			}
+		}
+		s.changeRequestStore = &changeRequestStore{FileLocation: fileLocation}
+	}
+	return s.changeRequestStore, nil
+}
+
+// CreateChangeRequest creates a change request
+func (s *Store) CreateChangeRequest(changeRequest *pms.ChangeRequest) (*pms.ChangeRequest, error) {
+	crs, err := getChangeRequestStore(s)
+	if err != nil {
+		return nil, err
+	}
+	crs.rwLock.Lock()
+	defer crs.rwLock.Unlock()
+
+	content, err := crs.readWithoutLock()
+	if err != nil {
+		return nil, err
+	}
+	dupChangeRequest := *changeRequest
+	dupChangeRequest.ID = suid.New().String()
+	content.ChangeRequests = append(content.ChangeRequests, &dupChangeRequest)
+	if err := crs.writeWithoutLock(content); err != nil {
+		return nil, err
+	}
+	return &dupChangeRequest, nil
+}
+
+// UpdateChangeRequest replaces a change request
+func (s *Store) UpdateChangeRequest(changeRequest *pms.ChangeRequest) error {
+	crs, err := getChangeRequestStore(s)
+	if err != nil {
+		return err
+	}
+	crs.rwLock.Lock()
+	defer crs.rwLock.Unlock()
+
+	return crs.update(changeRequest)
+}
+
+// GetChangeRequest gets a change request of a service
+func (s *Store) GetChangeRequest(serviceName string, id string) (*pms.ChangeRequest, error) {
+	crs, err := getChangeRequestStore(s)
+	if err != nil {
+		return nil, err
+	}
+	crs.rwLock.RLock()
+	defer crs.rwLock.RUnlock()
+
+	content, err := crs.readWithoutLock()
+	if err != nil {
+		return nil, err
+	}
+	i, err := crs.find(content, serviceName, id)
+	if err != nil {
+		return nil, err
+	}
+	return content.ChangeRequests[i], nil
+}
+
+// ListChangeRequests lists the change requests of a service, or of all services when serviceName is empty
+func (s *Store) ListChangeRequests(serviceName string) ([]*pms.ChangeRequest, error) {
+	crs, err := getChangeRequestStore(s)
+	if err != nil {
+		return nil, err
+	}
+	crs.rwLock.RLock()
+	defer crs.rwLock.RUnlock()
+
+	content, err := crs.readWithoutLock()
+	if err != nil {
+		return nil, err
+	}
+	changeRequests := []*pms.ChangeRequest{}
+	for _, cr := range content.ChangeRequests {
+		if len(serviceName) == 0 || cr.ServiceName == serviceName {
+			changeRequests = append(changeRequests, cr)
+		}
+	}
+	return changeRequests, nil
+}
+
+// PublishChangeRequest applies a change request to its service with one write of the policy store file,
+// and saves the change request
+func (s *Store) PublishChangeRequest(changeRequest *pms.ChangeRequest) error {
+	crs, err := getChangeRequestStore(s)
+	if err != nil {
+		return err
+	}
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	crs.rwLock.Lock()
+	defer crs.rwLock.Unlock()
+
+	content, err := crs.readWithoutLock()
+	if err != nil {
+		return err
+	}
+	i, err := crs.find(content, changeRequest.ServiceName, changeRequest.ID)
+	if err != nil {
+		return err
+	}
+	if err := store.CheckPublishable(content.ChangeRequests[i]); err != nil {
+		return err
+	}
+	service, err := s.getServiceWithoutLock(changeRequest.ServiceName)
+	if err != nil {
+		return err
+	}
+	if err := store.ApplyChangeRequest(service, changeRequest); err != nil {
+		return err
+	}
+	if err := s.writeServiceWithoutLock(service); err != nil {
+		return err
+	}
+	return crs.update(changeRequest)
+}
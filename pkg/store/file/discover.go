@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/teramoby/speedle-plus/api/ads"
 	"github.com/teramoby/speedle-plus/api/pms"
@@ -23,9 +24,26 @@ type discoverRequestStore struct {
 	rwLock       sync.RWMutex
 }
 
+// RequestItem is a distinct request, its index is updated whenever the request is seen again
 type RequestItem struct {
-	Index   int64               `json:"index"`
-	Request *ads.RequestContext `json:"request"`
+	Index int64  `json:"index"`
+	Key   string `json:"key,omitempty"`
+	store.DiscoverRequestAggregate
+}
+
+// hits returns the hits of the request, the requests saved before they were aggregated were seen once
+func (item *RequestItem) hits() int64 {
+	if item.Hits == 0 {
+		return 1
+	}
+	return item.Hits
+}
+
+func (item *RequestItem) key() string {
+	if len(item.Key) == 0 {
+		item.Key = store.DiscoverRequestKey(item.Request)
+	}
+	return item.Key
 }
 
 type StoreContent struct {
@@ -129,7 +147,33 @@ func (s *discoverRequestStore) saveDiscoverRequest(discoverRequest *ads.RequestC
 		idx = sContent.Requests[len(sContent.Requests)-1].Index + 1
 
 	}
-	sContent.Requests = append(sContent.Requests, &RequestItem{Index: idx, Request: discoverRequest})
+
+	// the same request is moved to the end with its new index, so that the requests stay sorted by index
+	now := time.Now()
+	key := store.DiscoverRequestKey(discoverRequest)
+	item := &RequestItem{Key: key, DiscoverRequestAggregate: store.DiscoverRequestAggregate{Request: discoverRequest, FirstSeen: now}}
+	requests := make([]*RequestItem, 0, len(sContent.Requests)+1)
+	for _, existing := range sContent.Requests {
+		switch {
+		case existing.key() == key:
+			item = existing
+			item.Hits = item.hits()
+		case !existing.Expired(now):
+			requests = append(requests, existing)
+		}
+	}
+	item.Index = idx
+	item.Hits++
+	item.LastSeen = now
+	item.ExpireAt = store.DiscoverRequestExpiration(discoverRequest.ServiceName, now)
+	if int64(len(requests)) >= store.MaxDiscoverRequestNum { //reach Max number of requests, remove the least recently seen ones.
+		deleteNum := store.DeleteNumWhenReachMaxDiscoverRequest
+		if deleteNum > int64(len(requests)) {
+			deleteNum = int64(len(requests))
+		}
+		requests = requests[deleteNum:]
+	}
+	sContent.Requests = append(requests, item)
 	return s.writeDiscoverRequestStoreWithoutLock(sContent)
 }
 
@@ -149,13 +193,13 @@ func (s *discoverRequestStore) getLastDiscoverRequest(serviceName string) (*ads.
 		return nil, -1, err
 	}
 
+	now := time.Now()
 	if sContent.Requests != nil && len(sContent.Requests) > 0 {
-		if serviceName == "" {
-			reqItem := sContent.Requests[len(sContent.Requests)-1]
-			return reqItem.Request, reqItem.Index, nil
-		}
 		for i := len(sContent.Requests) - 1; i >= 0; i-- {
-			if sContent.Requests[i].Request.ServiceName == serviceName {
+			if sContent.Requests[i].Expired(now) {
+				continue
+			}
+			if serviceName == "" || sContent.Requests[i].Request.ServiceName == serviceName {
 				reqItem := sContent.Requests[i]
 				return reqItem.Request, reqItem.Index, nil
 			}
@@ -180,9 +224,10 @@ func (s *discoverRequestStore) getDiscoverRequestsSinceRevision(serviceName stri
 		return nil, -1, err
 	}
 	requests := []*ads.RequestContext{}
+	now := time.Now()
 	if sContent.Requests != nil && len(sContent.Requests) > 0 {
 		for _, reqItem := range sContent.Requests {
-			if reqItem.Index > revision {
+			if reqItem.Index > revision && !reqItem.Expired(now) {
 				if serviceName == "" || serviceName == reqItem.Request.ServiceName {
 					requests = append(requests, reqItem.Request)
 				}
@@ -203,21 +248,47 @@ func (s *Store) GetDiscoverRequests(serviceName string) ([]*ads.RequestContext,
 	}
 }
 func (s *discoverRequestStore) getDiscoverRequests(serviceName string) ([]*ads.RequestContext, int64, error) {
+	requests := []*ads.RequestContext{}
+	aggregates, revision, err := s.getDiscoverRequestAggregates(serviceName)
+	if err != nil {
+		return nil, -1, err
+	}
+	for _, aggregate := range aggregates {
+		requests = append(requests, aggregate.Request)
+	}
+	return requests, revision, nil
+}
+
+// GetDiscoverRequestAggregates gets the distinct requests of a service with their hit counts.
+// Get the ones of all services when serviceName is empty.
+func (s *Store) GetDiscoverRequestAggregates(serviceName string) ([]*store.DiscoverRequestAggregate, int64, error) {
+	if discoverStore, err := getDiscoverRequestStore(s); err == nil {
+		return discoverStore.getDiscoverRequestAggregates(serviceName)
+	} else {
+		return nil, -1, err
+	}
+}
+func (s *discoverRequestStore) getDiscoverRequestAggregates(serviceName string) ([]*store.DiscoverRequestAggregate, int64, error) {
 	s.rwLock.RLock()
 	defer s.rwLock.RUnlock()
 	sContent, err := s.readDiscoverRequestStoreWithoutLock()
 	if err != nil {
 		return nil, -1, err
 	}
-	requests := []*ads.RequestContext{}
-
+	aggregates := []*store.DiscoverRequestAggregate{}
+	now := time.Now()
 	if sContent.Requests != nil && len(sContent.Requests) > 0 {
 		for _, reqItem := range sContent.Requests {
+			if reqItem.Expired(now) {
+				continue
+			}
 			if serviceName == "" || serviceName == reqItem.Request.ServiceName {
-				requests = append(requests, reqItem.Request)
+				aggregate := reqItem.DiscoverRequestAggregate
+				aggregate.Hits = reqItem.hits()
+				aggregates = append(aggregates, &aggregate)
 			}
 		}
-		return requests, sContent.Requests[len(sContent.Requests)-1].Index, nil
+		return aggregates, sContent.Requests[len(sContent.Requests)-1].Index, nil
 	}
 
 	return nil, -1, errors.Errorf(errors.EntityNotFound, "no discover request found for service %q.", serviceName)
@@ -341,3 +341,54 @@ func TestGeneratePolicies(t *testing.T) {
 	}
 
 }
+
+func TestDiscoverRequestAggregation(t *testing.T) {
+	s, err := store.NewStore("file", storeConfig)
+	if err != nil {
+		t.Fatal("fail to new file store:", err)
+	}
+	discover := s.(store.DiscoverRequestManager)
+	if err := discover.ResetDiscoverRequests(""); err != nil {
+		t.Fatal("Fail to reset all requests")
+	}
+	defer func() {
+		store.MaxDiscoverRequestNum = store.DefaultMaxDiscoverRequestNum
+		store.DeleteNumWhenReachMaxDiscoverRequest = store.DefaultDeleteNumWhenReachMaxDiscoverRequest
+		store.DiscoverRequestServiceTTLs = nil
+	}()
+	store.MaxDiscoverRequestNum = int64(3)
+	store.DeleteNumWhenReachMaxDiscoverRequest = int64(1)
+	store.DiscoverRequestServiceTTLs = map[string]time.Duration{"expired": time.Nanosecond}
+
+	save := func(serviceName, resName string) {
+		user := ads.Principal{Type: "user", Name: "user1"}
+		request := ads.RequestContext{Subject: &ads.Subject{Principals: []*ads.Principal{&user}}, ServiceName: serviceName, Resource: resName, Action: "read"}
+		if err := discover.SaveDiscoverRequest(&request); err != nil {
+			t.Fatal("fail to put request in store", err)
+		}
+	}
+	// the expired request is removed, and the request of /res0 is seen again, so that /res1 is the least
+	// recently seen one which is evicted
+	save("expired", "/res0")
+	for _, resName := range []string{"/res0", "/res1", "/res0", "/res2", "/res0", "/res3"} {
+		save("erp", resName)
+	}
+
+	aggregates, _, err := discover.GetDiscoverRequestAggregates("")
+	if err != nil {
+		t.Fatal("fail to GetDiscoverRequestAggregates:", err)
+	}
+	var resources []string
+	for _, aggregate := range aggregates {
+		resources = append(resources, fmt.Sprintf("%s %s %d", aggregate.Request.ServiceName, aggregate.Request.Resource, aggregate.Hits))
+		if aggregate.FirstSeen.After(aggregate.LastSeen) {
+			t.Errorf("request %s should be first seen before it is last seen", aggregate.Request.Resource)
+		}
+	}
+	if fmt.Sprint(resources) != "[erp /res2 1 erp /res0 3 erp /res3 1]" {
+		t.Errorf("unexpected aggregated requests %v", resources)
+	}
+	if request, _, err := discover.GetLastDiscoverRequest("expired"); err == nil {
+		t.Errorf("expired request shouldn't be returned, got %v", request)
+	}
+}
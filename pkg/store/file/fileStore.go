@@ -20,10 +20,12 @@ import (
 )
 
 type Store struct {
-	FileLocation  string
-	stop          chan struct{}
-	rwLock        sync.RWMutex
-	discoverStore *discoverRequestStore
+	FileLocation       string
+	stop               chan struct{}
+	watchLock          sync.Mutex
+	rwLock             sync.RWMutex
+	discoverStore      *discoverRequestStore
+	changeRequestStore *changeRequestStore
 }
 
 // ReadPolicyStore reads policy store from a file
@@ -290,6 +292,19 @@ func (s *Store) DeleteService(serviceName string) error {
 
 }
 
+// SetServiceOwners replaces the owners of a service
+func (s *Store) SetServiceOwners(serviceName string, owners []string) error {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+
+	service, err := s.getServiceWithoutLock(serviceName)
+	if err != nil {
+		return err
+	}
+	service.Owners = owners
+	return s.writeServiceWithoutLock(service)
+}
+
 // DeleteServices deletes all services from a file
 func (s *Store) DeleteServices() error {
 	s.rwLock.Lock()
@@ -321,13 +336,15 @@ func (s *Store) Watch() (pms.StorageChangeChannel, error) {
 	var storeChangeChan pms.StorageChangeChannel
 	storeChangeChan = make(chan pms.StoreChangeEvent)
 
-	s.stop = make(chan struct{})
+	stop := make(chan struct{})
+	s.watchLock.Lock()
+	s.stop = stop
+	s.watchLock.Unlock()
 
 	go func() {
 		defer func() {
 			watcher.Close()
 			close(storeChangeChan)
-			close(s.stop)
 		}()
 		for {
 			select {
@@ -358,7 +375,7 @@ func (s *Store) Watch() (pms.StorageChangeChannel, error) {
 				}
 			case err := <-watcher.Errors:
 				log.Warningf("Error happened when watching the policy file, error: %v", err)
-			case <-s.stop:
+			case <-stop:
 				log.Warning("Received stop signal")
 				return
 			}
@@ -368,9 +385,13 @@ func (s *Store) Watch() (pms.StorageChangeChannel, error) {
 	return storeChangeChan, nil
 }
 
+// StopWatch stops the current watch, its channel is closed
 func (s *Store) StopWatch() {
+	s.watchLock.Lock()
+	defer s.watchLock.Unlock()
 	if s.stop != nil {
-		s.stop <- struct{}{}
+		close(s.stop)
+		s.stop = nil
 	}
 }
 
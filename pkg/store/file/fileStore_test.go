@@ -5,8 +5,10 @@ package file
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"testing"
@@ -23,8 +25,13 @@ func TestMain(m *testing.M) {
 }
 
 func testMain(m *testing.M) int {
-	defer os.Remove("ps.json")
-	storeConfig["FileLocation"] = "./ps.json"
+	// the discover requests are stored next to the policy file
+	dir, err := ioutil.TempDir("", "filestore")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	storeConfig["FileLocation"] = filepath.Join(dir, "ps.json")
 
 	return m.Run()
 }
@@ -94,6 +101,32 @@ func TestWriteReadService(t *testing.T) {
 
 }
 
+func TestSetServiceOwners(t *testing.T) {
+	store, err := store.NewStore("file", storeConfig)
+	if err != nil {
+		t.Fatal("fail to new file store:", err)
+	}
+
+	service := pms.Service{Name: "owned", Owners: []string{"user:alice"}}
+	if err := store.CreateService(&service); err != nil {
+		t.Fatal("fail to create service:", err)
+	}
+	defer store.DeleteService("owned")
+	if err := store.SetServiceOwners("owned", []string{"user:bob", "group:team-a"}); err != nil {
+		t.Fatal("fail to set owners:", err)
+	}
+	servicer, err := store.GetService("owned")
+	if err != nil {
+		t.Fatal("fail to read service:", err)
+	}
+	if len(servicer.Owners) != 2 || servicer.Owners[0] != "user:bob" || servicer.Owners[1] != "group:team-a" {
+		t.Errorf("unexpected owners %v", servicer.Owners)
+	}
+	if err := store.SetServiceOwners("nonexistent", []string{"user:bob"}); err == nil {
+		t.Error("should fail as the service doesn't exist")
+	}
+}
+
 func TestFileStore_GetPolicyByName(t *testing.T) {
 	store, err := store.NewStore("file", storeConfig)
 	if err != nil {
@@ -0,0 +1,18 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package file
+
+import (
+	"os"
+
+	"github.com/teramoby/speedle-plus/pkg/errors"
+)
+
+// Ping checks that the policy file can be accessed
+func (s *Store) Ping() error {
+	if _, err := os.Stat(s.FileLocation); err != nil {
+		return errors.Wrapf(err, errors.StoreError, "failed to access policy file %s", s.FileLocation)
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package store
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/subjectutils"
+)
+
+// DefaultResourceThreshold is the number of sibling resources collapsed into a prefix resource expression
+const DefaultResourceThreshold = 3
+
+// Metadata of the policies and role policies suggested from discover requests
+const (
+	MetadataDiscoverRequests  = "discover.requests"  // number of requests supporting the suggestion
+	MetadataDiscoverResources = "discover.resources" // number of resources collapsed into the resource expression
+)
+
+// GeneralizeOptions tunes the generalization of the policies generated from discover requests
+type GeneralizeOptions struct {
+	// ResourceThreshold is the number of sibling resources, like /reports/q1 and /reports/q2, from which they are
+	// collapsed into a prefix resource expression like ^/reports/.*$. Resources aren't collapsed if it is negative.
+	ResourceThreshold int
+}
+
+var nonWordPattern = regexp.MustCompile(`\W+`)
+
+// accessCounts counts the requests of a principal, by resource and action
+type accessCounts map[string]map[string]int
+
+func (a accessCounts) add(resource, action string, count int) {
+	if _, ok := a[resource]; !ok {
+		a[resource] = map[string]int{}
+	}
+	a[resource][action] += count
+}
+
+func (a accessCounts) resources() []string {
+	resources := make([]string, 0, len(a))
+	for resource := range a {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// signature identifies the resources and actions accessed, regardless of the request counts
+func (a accessCounts) signature() string {
+	var items []string
+	for resource, actions := range a {
+		items = append(items, resource+" "+strings.Join(sortedActions(actions), ","))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ";")
+}
+
+// GeneralizePoliciesFromDiscoverRequests generates policies like GeneratePoliciesFromDiscoverRequests, and
+// generalizes them. Sibling resources are collapsed into prefix resource expressions, the actions of a resource
+// are merged into a permission, and the principals accessing the same resources with the same actions are
+// granted a role with a suggested name. The policies and role policies are annotated with the number of
+// requests supporting them.
+func GeneralizePoliciesFromDiscoverRequests(requests []*ads.RequestContext, principalType, principalName, principalIDD string,
+	opts *GeneralizeOptions) (map[string]*pms.Service, error) {
+	aggregates := make([]*DiscoverRequestAggregate, 0, len(requests))
+	for _, req := range requests {
+		aggregates = append(aggregates, &DiscoverRequestAggregate{Request: req, Hits: 1})
+	}
+	return GeneralizePoliciesFromDiscoverRequestAggregates(aggregates, principalType, principalName, principalIDD, opts)
+}
+
+// GeneralizePoliciesFromDiscoverRequestAggregates generalizes the policies of aggregated discover requests like
+// GeneralizePoliciesFromDiscoverRequests, the requests supporting the policies are counted by their hits
+func GeneralizePoliciesFromDiscoverRequestAggregates(aggregates []*DiscoverRequestAggregate, principalType, principalName, principalIDD string,
+	opts *GeneralizeOptions) (map[string]*pms.Service, error) {
+	threshold := DefaultResourceThreshold
+	if opts != nil && opts.ResourceThreshold != 0 {
+		threshold = opts.ResourceThreshold
+	}
+
+	// requests of each principal of each service
+	serviceAccess := map[string]map[string]accessCounts{}
+	for _, aggregate := range aggregates {
+		req := aggregate.Request
+		if _, ok := serviceAccess[req.ServiceName]; !ok {
+			serviceAccess[req.ServiceName] = map[string]accessCounts{}
+		}
+		var principals []string
+		if req.Subject == nil || len(req.Subject.Principals) == 0 {
+			principals = append(principals, "role:"+ads.BuiltIn_Role_Anonymous)
+		} else {
+			for _, princ := range req.Subject.Principals {
+				if principalType != "" && principalType != princ.Type ||
+					principalName != "" && principalName != princ.Name ||
+					principalIDD != "" && principalIDD != princ.IDD {
+					continue
+				}
+				principals = append(principals, subjectutils.EncodePrincipal(princ))
+			}
+		}
+		for _, principal := range principals {
+			access, ok := serviceAccess[req.ServiceName][principal]
+			if !ok {
+				access = accessCounts{}
+				serviceAccess[req.ServiceName][principal] = access
+			}
+			access.add(req.Resource, req.Action, int(aggregate.Hits))
+		}
+	}
+
+	serviceMap := map[string]*pms.Service{}
+	for serviceName, principalAccess := range serviceAccess {
+		serviceMap[serviceName] = generalizeService(serviceName, principalAccess, threshold)
+	}
+	return serviceMap, nil
+}
+
+func generalizeService(serviceName string, principalAccess map[string]accessCounts, threshold int) *pms.Service {
+	service := &pms.Service{Name: serviceName, RolePolicies: []*pms.RolePolicy{}, Policies: []*pms.Policy{}}
+
+	// collapse the sibling resources of all the principals
+	resources := map[string]bool{}
+	for _, access := range principalAccess {
+		for resource := range access {
+			resources[resource] = true
+		}
+	}
+	collapsed, collapsedCounts := collapseResources(resources, threshold)
+	groups := map[string][]string{}
+	groupAccess := map[string]accessCounts{}
+	for principal, access := range principalAccess {
+		generalized := accessCounts{}
+		for resource, actions := range access {
+			for action, count := range actions {
+				generalized.add(collapsed[resource], action, count)
+			}
+		}
+		if principal == "role:"+ads.BuiltIn_Role_Anonymous {
+			// anonymous requests are granted to the anonymous role
+			service.Policies = append(service.Policies, suggestPolicies(principal, generalized, collapsedCounts)...)
+			continue
+		}
+		signature := generalized.signature()
+		groups[signature] = append(groups[signature], principal)
+		if merged, ok := groupAccess[signature]; ok {
+			for resource, actions := range generalized {
+				for action, count := range actions {
+					merged.add(resource, action, count)
+				}
+			}
+		} else {
+			groupAccess[signature] = generalized
+		}
+	}
+
+	signatures := make([]string, 0, len(groups))
+	for signature := range groups {
+		signatures = append(signatures, signature)
+	}
+	sort.Strings(signatures)
+	usedRoles := map[string]bool{}
+	for _, signature := range signatures {
+		principals := groups[signature]
+		sort.Strings(principals)
+		access := groupAccess[signature]
+		roleName := "role_" + principals[0]
+		if len(principals) > 1 {
+			roleName = suggestRoleName(access, usedRoles)
+		}
+		usedRoles[roleName] = true
+
+		requests := 0
+		for _, actions := range access {
+			for _, count := range actions {
+				requests += count
+			}
+		}
+		service.RolePolicies = append(service.RolePolicies, &pms.RolePolicy{
+			Effect:     pms.Grant,
+			Principals: principals,
+			Roles:      []string{roleName},
+			Metadata:   map[string]string{MetadataDiscoverRequests: strconv.Itoa(requests)},
+		})
+		service.Policies = append(service.Policies, suggestPolicies("role:"+roleName, access, collapsedCounts)...)
+	}
+	return service
+}
+
+// collapseResources maps the resources to the prefix resource expressions of their parents when their parents
+// have at least threshold children, and to themselves otherwise. It also returns the number of resources
+// collapsed into each resource expression.
+func collapseResources(resources map[string]bool, threshold int) (map[string]string, map[string]int) {
+	children := map[string][]string{}
+	for resource := range resources {
+		// the children of the root aren't collapsed, which would grant all resources
+		if parent := path.Dir(resource); strings.HasPrefix(resource, "/") && parent != "/" {
+			children[parent] = append(children[parent], resource)
+		}
+	}
+	collapsed := map[string]string{}
+	counts := map[string]int{}
+	for resource := range resources {
+		collapsed[resource] = resource
+	}
+	if threshold < 0 {
+		return collapsed, counts
+	}
+	for parent, siblings := range children {
+		if len(siblings) < threshold {
+			continue
+		}
+		expression := "^" + regexp.QuoteMeta(parent+"/") + ".*$"
+		for _, resource := range siblings {
+			collapsed[resource] = expression
+		}
+		counts[expression] = len(siblings)
+	}
+	return collapsed, counts
+}
+
+// suggestPolicies grants a principal a policy per resource or resource expression, with all the actions
+func suggestPolicies(principal string, access accessCounts, collapsedCounts map[string]int) []*pms.Policy {
+	var policies []*pms.Policy
+	for _, resource := range access.resources() {
+		requests := 0
+		for _, count := range access[resource] {
+			requests += count
+		}
+		permission := &pms.Permission{Resource: resource, Actions: sortedActions(access[resource])}
+		metadata := map[string]string{MetadataDiscoverRequests: strconv.Itoa(requests)}
+		if n, ok := collapsedCounts[resource]; ok {
+			permission.Resource, permission.ResourceExpression = "", resource
+			metadata[MetadataDiscoverResources] = strconv.Itoa(n)
+		}
+		policies = append(policies, &pms.Policy{
+			Effect:      pms.Grant,
+			Principals:  [][]string{{principal}},
+			Permissions: []*pms.Permission{permission},
+			Metadata:    metadata,
+		})
+	}
+	return policies
+}
+
+// suggestRoleName names the role of principals after the first segment of the resources they access, like
+// reports_users for /reports/q1 and /reports/q2
+func suggestRoleName(access accessCounts, usedRoles map[string]bool) string {
+	var prefix string
+	for i, resource := range access.resources() {
+		resource = strings.TrimSuffix(strings.TrimPrefix(resource, "^"), ".*$")
+		resource = strings.Replace(resource, `\`, "", -1)
+		if i == 0 {
+			prefix = resource
+			continue
+		}
+		for !strings.HasPrefix(resource, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	segment := strings.Trim(nonWordPattern.ReplaceAllString(strings.SplitN(strings.TrimPrefix(prefix, "/"), "/", 2)[0], "_"), "_")
+	if segment == "" {
+		segment = "shared"
+	}
+	roleName := segment + "_users"
+	for i := 2; usedRoles[roleName]; i++ {
+		roleName = segment + "_users_" + strconv.Itoa(i)
+	}
+	return roleName
+}
+
+func sortedActions(actions map[string]int) []string {
+	sorted := make([]string, 0, len(actions))
+	for action := range actions {
+		sorted = append(sorted, action)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,79 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+)
+
+func TestGeneralizePolicies(t *testing.T) {
+	newRequest := func(user, resource, action string) *ads.RequestContext {
+		req := &ads.RequestContext{ServiceName: "crm", Resource: resource, Action: action, Subject: &ads.Subject{}}
+		if user != "" {
+			req.Subject.Principals = []*ads.Principal{{Type: ads.PRINCIPAL_TYPE_USER, Name: user}}
+		}
+		return req
+	}
+	var requests []*ads.RequestContext
+	// alice and bob read and write the reports, carol only reads one
+	for _, user := range []string{"alice", "bob"} {
+		for _, report := range []string{"q1", "q2", "q3"} {
+			requests = append(requests, newRequest(user, "/reports/"+report, "read"))
+		}
+		requests = append(requests, newRequest(user, "/reports/q1", "write"))
+	}
+	requests = append(requests, newRequest("carol", "/reports/q1", "read"), newRequest("", "/home", "get"))
+
+	serviceMap, err := GeneralizePoliciesFromDiscoverRequests(requests, "", "", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := serviceMap["crm"]
+	rolePolicies := map[string]*pms.RolePolicy{}
+	for _, rolePolicy := range service.RolePolicies {
+		rolePolicies[strings.Join(rolePolicy.Principals, ",")] = rolePolicy
+	}
+	shared := rolePolicies["user:alice,user:bob"]
+	if len(rolePolicies) != 2 || shared == nil || shared.Roles[0] != "reports_users" || shared.Metadata[MetadataDiscoverRequests] != "8" ||
+		rolePolicies["user:carol"] == nil || rolePolicies["user:carol"].Roles[0] != "role_user:carol" {
+		t.Fatalf("alice and bob should share a role, got %v", rolePolicies)
+	}
+
+	policies := map[string]*pms.Policy{}
+	for _, policy := range service.Policies {
+		permission := policy.Permissions[0]
+		policies[policy.Principals[0][0]+" "+permission.Resource+permission.ResourceExpression+" "+strings.Join(permission.Actions, ",")] = policy
+	}
+	expected := map[string]string{
+		"role:reports_users ^/reports/.*$ read,write": "8",
+		"role:role_user:carol ^/reports/.*$ read":     "1",
+		"role:anonymous_role /home get":               "1",
+	}
+	if len(policies) != len(expected) {
+		t.Errorf("unexpected policies %v", policies)
+	}
+	for key, requests := range expected {
+		if policy, ok := policies[key]; !ok || policy.Metadata[MetadataDiscoverRequests] != requests {
+			t.Errorf("policy %q should be supported by %s requests, got %v", key, requests, policy)
+		}
+	}
+	if policies["role:reports_users ^/reports/.*$ read,write"].Metadata[MetadataDiscoverResources] != "3" {
+		t.Error("the number of collapsed resources should be annotated")
+	}
+
+	// resources aren't collapsed below the threshold
+	serviceMap, _ = GeneralizePoliciesFromDiscoverRequests(requests, ads.PRINCIPAL_TYPE_USER, "alice", "", &GeneralizeOptions{ResourceThreshold: 4})
+	for _, policy := range serviceMap["crm"].Policies {
+		if policy.Permissions[0].ResourceExpression != "" {
+			t.Errorf("resources shouldn't be collapsed, got %v", policy.Permissions[0])
+		}
+		if principal := policy.Principals[0][0]; principal != "role:role_user:alice" && principal != "role:anonymous_role" {
+			t.Errorf("only the policies of alice should be generated, got %s", principal)
+		}
+	}
+}
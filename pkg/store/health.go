@@ -0,0 +1,43 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package store
+
+import (
+	"time"
+)
+
+// PingTimeout is the timeout of checking the connectivity to a store
+const PingTimeout = 3 * time.Second
+
+// Pinger is implemented by stores which can check the connectivity to their backend
+type Pinger interface {
+	// Ping returns an error if the store can't be reached
+	Ping() error
+}
+
+// StoreStatus is the connectivity of a policy store
+type StoreStatus struct {
+	Type      string `json:"type"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+	// latency of the check in milliseconds
+	Latency int64 `json:"latency"`
+}
+
+// CheckStore checks the connectivity to a store. Stores which can't be checked are reported as
+// reachable.
+func CheckStore(s interface{ Type() string }) *StoreStatus {
+	status := &StoreStatus{Type: s.Type(), Reachable: true}
+	pinger, ok := s.(Pinger)
+	if !ok {
+		return status
+	}
+	start := time.Now()
+	if err := pinger.Ping(); err != nil {
+		status.Reachable = false
+		status.Error = err.Error()
+	}
+	status.Latency = time.Since(start).Nanoseconds() / int64(time.Millisecond)
+	return status
+}
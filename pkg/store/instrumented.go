@@ -0,0 +1,277 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package store
+
+import (
+	"time"
+
+	"github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/metrics"
+)
+
+// instrumentedStore observes the latency of the operations of a policy store
+type instrumentedStore struct {
+	pms.PolicyStoreManager
+}
+
+// instrumentedDiscoverStore observes the latency of the discover request operations of a policy store
+type instrumentedDiscoverStore struct {
+	discover DiscoverRequestManager
+}
+
+// instrumentedChangeRequestStore observes the latency of the change request operations of a policy store
+type instrumentedChangeRequestStore struct {
+	changes ChangeRequestManager
+}
+
+type instrumentedDiscoverAndChangeRequestStore struct {
+	*instrumentedStore
+	*instrumentedDiscoverStore
+	*instrumentedChangeRequestStore
+}
+
+type instrumentedStoreWithDiscover struct {
+	*instrumentedStore
+	*instrumentedDiscoverStore
+}
+
+type instrumentedStoreWithChangeRequests struct {
+	*instrumentedStore
+	*instrumentedChangeRequestStore
+}
+
+// NewInstrumentedStore wraps a policy store to observe the latency of its operations in metrics. The
+// wrapper implements DiscoverRequestManager and ChangeRequestManager if the store does.
+func NewInstrumentedStore(ps pms.PolicyStoreManager) pms.PolicyStoreManager {
+	base := &instrumentedStore{ps}
+	discover, isDiscover := ps.(DiscoverRequestManager)
+	changes, isChanges := ps.(ChangeRequestManager)
+	switch {
+	case isDiscover && isChanges:
+		return &instrumentedDiscoverAndChangeRequestStore{base, &instrumentedDiscoverStore{discover}, &instrumentedChangeRequestStore{changes}}
+	case isDiscover:
+		return &instrumentedStoreWithDiscover{base, &instrumentedDiscoverStore{discover}}
+	case isChanges:
+		return &instrumentedStoreWithChangeRequests{base, &instrumentedChangeRequestStore{changes}}
+	}
+	return base
+}
+
+func observe(operation string, start time.Time, err *error) {
+	metrics.ObserveStoreOperation(operation, start, *err)
+}
+
+// Ping checks the connectivity of the wrapped store, stores which can't be checked are always reachable
+func (s *instrumentedStore) Ping() error {
+	if pinger, ok := s.PolicyStoreManager.(Pinger); ok {
+		return pinger.Ping()
+	}
+	return nil
+}
+
+func (s *instrumentedStore) ReadPolicyStore() (_ *pms.PolicyStore, err error) {
+	defer observe("ReadPolicyStore", time.Now(), &err)
+	return s.PolicyStoreManager.ReadPolicyStore()
+}
+
+func (s *instrumentedStore) WritePolicyStore(ps *pms.PolicyStore) (err error) {
+	defer observe("WritePolicyStore", time.Now(), &err)
+	return s.PolicyStoreManager.WritePolicyStore(ps)
+}
+
+func (s *instrumentedStore) CreateFunction(function *pms.Function) (_ *pms.Function, err error) {
+	defer observe("CreateFunction", time.Now(), &err)
+	return s.PolicyStoreManager.CreateFunction(function)
+}
+
+func (s *instrumentedStore) DeleteFunction(funcName string) (err error) {
+	defer observe("DeleteFunction", time.Now(), &err)
+	return s.PolicyStoreManager.DeleteFunction(funcName)
+}
+
+func (s *instrumentedStore) DeleteFunctions() (err error) {
+	defer observe("DeleteFunctions", time.Now(), &err)
+	return s.PolicyStoreManager.DeleteFunctions()
+}
+
+func (s *instrumentedStore) GetFunction(funcName string) (_ *pms.Function, err error) {
+	defer observe("GetFunction", time.Now(), &err)
+	return s.PolicyStoreManager.GetFunction(funcName)
+}
+
+func (s *instrumentedStore) ListAllFunctions(filter string) (_ []*pms.Function, err error) {
+	defer observe("ListAllFunctions", time.Now(), &err)
+	return s.PolicyStoreManager.ListAllFunctions(filter)
+}
+
+func (s *instrumentedStore) GetFunctionCount() (_ int64, err error) {
+	defer observe("GetFunctionCount", time.Now(), &err)
+	return s.PolicyStoreManager.GetFunctionCount()
+}
+
+func (s *instrumentedStore) CreateService(service *pms.Service) (err error) {
+	defer observe("CreateService", time.Now(), &err)
+	return s.PolicyStoreManager.CreateService(service)
+}
+
+func (s *instrumentedStore) DeleteService(serviceName string) (err error) {
+	defer observe("DeleteService", time.Now(), &err)
+	return s.PolicyStoreManager.DeleteService(serviceName)
+}
+
+func (s *instrumentedStore) DeleteServices() (err error) {
+	defer observe("DeleteServices", time.Now(), &err)
+	return s.PolicyStoreManager.DeleteServices()
+}
+
+func (s *instrumentedStore) GetService(serviceName string) (_ *pms.Service, err error) {
+	defer observe("GetService", time.Now(), &err)
+	return s.PolicyStoreManager.GetService(serviceName)
+}
+
+func (s *instrumentedStore) ListAllServices() (_ []*pms.Service, err error) {
+	defer observe("ListAllServices", time.Now(), &err)
+	return s.PolicyStoreManager.ListAllServices()
+}
+
+func (s *instrumentedStore) GetServiceCount() (_ int64, err error) {
+	defer observe("GetServiceCount", time.Now(), &err)
+	return s.PolicyStoreManager.GetServiceCount()
+}
+
+func (s *instrumentedStore) GetServiceNames() (_ []string, err error) {
+	defer observe("GetServiceNames", time.Now(), &err)
+	return s.PolicyStoreManager.GetServiceNames()
+}
+
+func (s *instrumentedStore) GetPolicyAndRolePolicyCounts() (_ map[string]*pms.PolicyAndRolePolicyCount, err error) {
+	defer observe("GetPolicyAndRolePolicyCounts", time.Now(), &err)
+	return s.PolicyStoreManager.GetPolicyAndRolePolicyCounts()
+}
+
+func (s *instrumentedStore) SetServiceOwners(serviceName string, owners []string) (err error) {
+	defer observe("SetServiceOwners", time.Now(), &err)
+	return s.PolicyStoreManager.SetServiceOwners(serviceName, owners)
+}
+
+func (s *instrumentedStore) CreatePolicy(serviceName string, policy *pms.Policy) (_ *pms.Policy, err error) {
+	defer observe("CreatePolicy", time.Now(), &err)
+	return s.PolicyStoreManager.CreatePolicy(serviceName, policy)
+}
+
+func (s *instrumentedStore) DeletePolicy(serviceName string, id string) (err error) {
+	defer observe("DeletePolicy", time.Now(), &err)
+	return s.PolicyStoreManager.DeletePolicy(serviceName, id)
+}
+
+func (s *instrumentedStore) DeletePolicies(serviceName string) (err error) {
+	defer observe("DeletePolicies", time.Now(), &err)
+	return s.PolicyStoreManager.DeletePolicies(serviceName)
+}
+
+func (s *instrumentedStore) GetPolicy(serviceName string, id string) (_ *pms.Policy, err error) {
+	defer observe("GetPolicy", time.Now(), &err)
+	return s.PolicyStoreManager.GetPolicy(serviceName, id)
+}
+
+func (s *instrumentedStore) ListAllPolicies(serviceName string, filter string) (_ []*pms.Policy, err error) {
+	defer observe("ListAllPolicies", time.Now(), &err)
+	return s.PolicyStoreManager.ListAllPolicies(serviceName, filter)
+}
+
+func (s *instrumentedStore) GetPolicyCount(serviceName string) (_ int64, err error) {
+	defer observe("GetPolicyCount", time.Now(), &err)
+	return s.PolicyStoreManager.GetPolicyCount(serviceName)
+}
+
+func (s *instrumentedStore) CreateRolePolicy(serviceName string, policy *pms.RolePolicy) (_ *pms.RolePolicy, err error) {
+	defer observe("CreateRolePolicy", time.Now(), &err)
+	return s.PolicyStoreManager.CreateRolePolicy(serviceName, policy)
+}
+
+func (s *instrumentedStore) DeleteRolePolicy(serviceName string, id string) (err error) {
+	defer observe("DeleteRolePolicy", time.Now(), &err)
+	return s.PolicyStoreManager.DeleteRolePolicy(serviceName, id)
+}
+
+func (s *instrumentedStore) DeleteRolePolicies(serviceName string) (err error) {
+	defer observe("DeleteRolePolicies", time.Now(), &err)
+	return s.PolicyStoreManager.DeleteRolePolicies(serviceName)
+}
+
+func (s *instrumentedStore) GetRolePolicy(serviceName string, id string) (_ *pms.RolePolicy, err error) {
+	defer observe("GetRolePolicy", time.Now(), &err)
+	return s.PolicyStoreManager.GetRolePolicy(serviceName, id)
+}
+
+func (s *instrumentedStore) ListAllRolePolicies(serviceName string, filter string) (_ []*pms.RolePolicy, err error) {
+	defer observe("ListAllRolePolicies", time.Now(), &err)
+	return s.PolicyStoreManager.ListAllRolePolicies(serviceName, filter)
+}
+
+func (s *instrumentedStore) GetRolePolicyCount(serviceName string) (_ int64, err error) {
+	defer observe("GetRolePolicyCount", time.Now(), &err)
+	return s.PolicyStoreManager.GetRolePolicyCount(serviceName)
+}
+
+func (s *instrumentedDiscoverStore) SaveDiscoverRequest(discoverRequest *ads.RequestContext) (err error) {
+	defer observe("SaveDiscoverRequest", time.Now(), &err)
+	return s.discover.SaveDiscoverRequest(discoverRequest)
+}
+
+func (s *instrumentedDiscoverStore) GetLastDiscoverRequest(serviceName string) (_ *ads.RequestContext, _ int64, err error) {
+	defer observe("GetLastDiscoverRequest", time.Now(), &err)
+	return s.discover.GetLastDiscoverRequest(serviceName)
+}
+
+func (s *instrumentedDiscoverStore) GetDiscoverRequestsSinceRevision(serviceName string, revision int64) (_ []*ads.RequestContext, _ int64, err error) {
+	defer observe("GetDiscoverRequestsSinceRevision", time.Now(), &err)
+	return s.discover.GetDiscoverRequestsSinceRevision(serviceName, revision)
+}
+
+func (s *instrumentedDiscoverStore) GetDiscoverRequests(serviceName string) (_ []*ads.RequestContext, _ int64, err error) {
+	defer observe("GetDiscoverRequests", time.Now(), &err)
+	return s.discover.GetDiscoverRequests(serviceName)
+}
+
+func (s *instrumentedDiscoverStore) ResetDiscoverRequests(serviceName string) (err error) {
+	defer observe("ResetDiscoverRequests", time.Now(), &err)
+	return s.discover.ResetDiscoverRequests(serviceName)
+}
+
+func (s *instrumentedDiscoverStore) GeneratePolicies(serviceName, principalType, principalName, principalIDD string) (_ map[string]*pms.Service, _ int64, err error) {
+	defer observe("GeneratePolicies", time.Now(), &err)
+	return s.discover.GeneratePolicies(serviceName, principalType, principalName, principalIDD)
+}
+
+func (s *instrumentedDiscoverStore) GetDiscoverRequestAggregates(serviceName string) (_ []*DiscoverRequestAggregate, _ int64, err error) {
+	defer observe("GetDiscoverRequestAggregates", time.Now(), &err)
+	return s.discover.GetDiscoverRequestAggregates(serviceName)
+}
+
+func (s *instrumentedChangeRequestStore) CreateChangeRequest(changeRequest *pms.ChangeRequest) (_ *pms.ChangeRequest, err error) {
+	defer observe("CreateChangeRequest", time.Now(), &err)
+	return s.changes.CreateChangeRequest(changeRequest)
+}
+
+func (s *instrumentedChangeRequestStore) UpdateChangeRequest(changeRequest *pms.ChangeRequest) (err error) {
+	defer observe("UpdateChangeRequest", time.Now(), &err)
+	return s.changes.UpdateChangeRequest(changeRequest)
+}
+
+func (s *instrumentedChangeRequestStore) GetChangeRequest(serviceName string, id string) (_ *pms.ChangeRequest, err error) {
+	defer observe("GetChangeRequest", time.Now(), &err)
+	return s.changes.GetChangeRequest(serviceName, id)
+}
+
+func (s *instrumentedChangeRequestStore) ListChangeRequests(serviceName string) (_ []*pms.ChangeRequest, err error) {
+	defer observe("ListChangeRequests", time.Now(), &err)
+	return s.changes.ListChangeRequests(serviceName)
+}
+
+func (s *instrumentedChangeRequestStore) PublishChangeRequest(changeRequest *pms.ChangeRequest) (err error) {
+	defer observe("PublishChangeRequest", time.Now(), &err)
+	return s.changes.PublishChangeRequest(changeRequest)
+}
@@ -0,0 +1,147 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/store"
+	"github.com/teramoby/speedle-plus/pkg/suid"
+)
+
+// change requests are kept in their own collection, which is ignored by the watch
+const changeRequestCollection = "changerequests"
+
+// CreateChangeRequest creates a change request
+func (s *Store) CreateChangeRequest(changeRequest *pms.ChangeRequest) (*pms.ChangeRequest, error) {
+	collection := s.client.Database(s.Database).Collection(changeRequestCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	dupChangeRequest := *changeRequest
+	dupChangeRequest.ID = suid.New().String()
+	if _, err := collection.InsertOne(ctx, &dupChangeRequest); err != nil {
+		return nil, err
+	}
+	return &dupChangeRequest, nil
+}
+
+// UpdateChangeRequest replaces a change request
+func (s *Store) UpdateChangeRequest(changeRequest *pms.ChangeRequest) error {
+	collection := s.client.Database(s.Database).Collection(changeRequestCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := bson.D{{"_id", changeRequest.ID}, {"servicename", changeRequest.ServiceName}}
+	result, err := collection.ReplaceOne(ctx, filter, changeRequest)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.Errorf(errors.EntityNotFound, "change request %q is not found in service %q", changeRequest.ID, changeRequest.ServiceName)
+	}
+	return nil
+}
+
+// GetChangeRequest gets a change request of a service
+func (s *Store) GetChangeRequest(serviceName string, id string) (*pms.ChangeRequest, error) {
+	collection := s.client.Database(s.Database).Collection(changeRequestCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	singleResult := collection.FindOne(ctx, bson.D{{"_id", id}, {"servicename", serviceName}})
+	if singleResult.Err() == mongo.ErrNoDocuments {
+		return nil, errors.Errorf(errors.EntityNotFound, "change request %q is not found in service %q", id, serviceName)
+	} else if singleResult.Err() != nil {
+		return nil, singleResult.Err()
+	}
+	var changeRequest pms.ChangeRequest
+	if err := singleResult.Decode(&changeRequest); err != nil {
+		return nil, err
+	}
+	return &changeRequest, nil
+}
+
+// ListChangeRequests lists the change requests of a service, or of all services when serviceName is empty
+func (s *Store) ListChangeRequests(serviceName string) ([]*pms.ChangeRequest, error) {
+	collection := s.client.Database(s.Database).Collection(changeRequestCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := bson.D{}
+	if len(serviceName) > 0 {
+		filter = bson.D{{"servicename", serviceName}}
+	}
+	cur, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	changeRequests := []*pms.ChangeRequest{}
+	for cur.Next(ctx) {
+		var changeRequest pms.ChangeRequest
+		if err := cur.Decode(&changeRequest); err != nil {
+			return nil, err
+		}
+		changeRequests = append(changeRequests, &changeRequest)
+	}
+	return changeRequests, cur.Err()
+}
+
+// PublishChangeRequest applies a change request to its service with one update of the service document,
+// and saves the change request. The change request is only published if it's still in review, and the
+// service is only updated if its policies and role policies are still those the changes are applied to.
+func (s *Store) PublishChangeRequest(changeRequest *pms.ChangeRequest) error {
+	service, err := s.GetService(changeRequest.ServiceName)
+	if err != nil {
+		return err
+	}
+	policies, rolePolicies := service.Policies, service.RolePolicies
+	if err := store.ApplyChangeRequest(service, changeRequest); err != nil {
+		return err
+	}
+	collection := s.client.Database(s.Database).Collection(changeRequestCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// publishing the change request first, so that concurrent publishes of it fail
+	filter := bson.D{{"_id", changeRequest.ID}, {"servicename", changeRequest.ServiceName}, {"status", pms.ChangeRequestReview}}
+	update := bson.D{{"$set", bson.D{
+		{"status", changeRequest.Status},
+		{"addpolicies", changeRequest.AddPolicies},
+		{"addrolepolicies", changeRequest.AddRolePolicies},
+		{"metadata", changeRequest.Metadata},
+	}}}
+	singleResult := collection.FindOneAndUpdate(ctx, filter, update)
+	if singleResult.Err() == mongo.ErrNoDocuments {
+		if _, err := s.GetChangeRequest(changeRequest.ServiceName, changeRequest.ID); err != nil {
+			return err
+		}
+		return errors.Errorf(errors.Conflict, "change request %q of service %q is not in review", changeRequest.ID, changeRequest.ServiceName)
+	} else if singleResult.Err() != nil {
+		return singleResult.Err()
+	}
+	var stored pms.ChangeRequest
+	if err := singleResult.Decode(&stored); err != nil {
+		return err
+	}
+
+	serviceCollection := s.client.Database(s.Database).Collection("services")
+	serviceFilter := bson.D{{"_id", service.Name}, {"policies", policies}, {"rolepolicies", rolePolicies}}
+	serviceUpdate := bson.D{{"$set", bson.D{{"policies", service.Policies}, {"rolepolicies", service.RolePolicies}}}}
+	result, err := serviceCollection.UpdateOne(ctx, serviceFilter, serviceUpdate)
+	if err == nil && result.MatchedCount == 0 {
+		err = errors.Errorf(errors.Conflict, "service %q is changed concurrently or deleted", service.Name)
+	}
+	if err != nil {
+		// return the change request to review, so that it could be published again
+		if _, rerr := collection.ReplaceOne(ctx, bson.D{{"_id", stored.ID}, {"servicename", stored.ServiceName}}, &stored); rerr != nil {
+			log.Errorf("unable to return change request %q of service %q to review: %v", stored.ID, stored.ServiceName, rerr)
+		}
+		return err
+	}
+	return nil
+}
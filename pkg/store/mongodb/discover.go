@@ -0,0 +1,210 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/store"
+)
+
+// discover requests are kept in their own collection, which is ignored by the watch
+const discoverRequestCollection = "discoverrequests"
+
+// discoverRequestDocument is a distinct discover request. Its revision is the time it was last seen, in
+// nanoseconds, and its key identifies the same requests.
+type discoverRequestDocument struct {
+	Key                            string `bson:"_id"`
+	ServiceName                    string `bson:"servicename"`
+	Revision                       int64  `bson:"revision"`
+	store.DiscoverRequestAggregate `bson:",inline"`
+}
+
+// ensureDiscoverIndexes creates the index removing the expired requests, and the one sorting requests by revision
+func (s *Store) ensureDiscoverIndexes(ctx context.Context, collection *mongo.Collection) {
+	s.discoverIndexOnce.Do(func() {
+		_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{Keys: bson.D{{"expireat", 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+			{Keys: bson.D{{"servicename", 1}, {"revision", 1}}},
+		})
+		if err != nil {
+			log.Warnf("unable to create indexes of discover requests: %v", err)
+		}
+	})
+}
+
+// unexpired filters the requests of a service which aren't expired, of all services when serviceName is empty.
+// Expired requests are removed by MongoDB in the background, they are filtered until they are.
+func unexpired(serviceName string, now time.Time) bson.D {
+	filter := bson.D{{"$or", bson.A{bson.D{{"expireat", bson.D{{"$exists", false}}}}, bson.D{{"expireat", bson.D{{"$gt", now}}}}}}}
+	if len(serviceName) > 0 {
+		filter = append(filter, bson.E{"servicename", serviceName})
+	}
+	return filter
+}
+
+// SaveDiscoverRequest saves a discover request, the same requests are aggregated in a document counting their hits
+func (s *Store) SaveDiscoverRequest(discoverRequest *ads.RequestContext) error {
+	collection := s.client.Database(s.Database).Collection(discoverRequestCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.ensureDiscoverIndexes(ctx, collection)
+
+	now := time.Now()
+	set := bson.D{{"servicename", discoverRequest.ServiceName}, {"request", discoverRequest}, {"lastseen", now}, {"revision", now.UnixNano()}}
+	update := bson.D{{"$inc", bson.D{{"hits", 1}}}, {"$setOnInsert", bson.D{{"firstseen", now}}}}
+	if expireAt := store.DiscoverRequestExpiration(discoverRequest.ServiceName, now); expireAt != nil {
+		set = append(set, bson.E{"expireat", *expireAt})
+	} else {
+		update = append(update, bson.E{"$unset", bson.D{{"expireat", ""}}})
+	}
+	update = append(update, bson.E{"$set", set})
+	filter := bson.D{{"_id", discoverRequest.ServiceName + "/" + store.DiscoverRequestKey(discoverRequest)}}
+	if _, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		return errors.Wrap(err, errors.StoreError, "unable to save discover request")
+	}
+
+	count, err := collection.CountDocuments(ctx, bson.D{})
+	if err != nil {
+		return errors.Wrap(err, errors.StoreError, "unable to count discover requests")
+	}
+	if count > store.MaxDiscoverRequestNum { //reach Max number of requests, remove the least recently seen ones.
+		findOptions := options.Find().SetSort(bson.D{{"revision", 1}}).SetLimit(store.DeleteNumWhenReachMaxDiscoverRequest).SetProjection(bson.D{{"_id", 1}})
+		cur, err := collection.Find(ctx, bson.D{}, findOptions)
+		if err != nil {
+			return errors.Wrap(err, errors.StoreError, "unable to find least recently seen discover requests")
+		}
+		defer cur.Close(ctx)
+		keys := bson.A{}
+		for cur.Next(ctx) {
+			var doc discoverRequestDocument
+			if err := cur.Decode(&doc); err != nil {
+				return errors.Wrap(err, errors.SerializationError, "failed to decode discover request")
+			}
+			keys = append(keys, doc.Key)
+		}
+		if _, err := collection.DeleteMany(ctx, bson.D{{"_id", bson.D{{"$in", keys}}}}); err != nil {
+			return errors.Wrapf(err, errors.StoreError, "unable to delete discover requests %v", keys)
+		}
+	}
+	return nil
+}
+
+// findDiscoverRequests finds the requests matching a filter, sorted by revision
+func (s *Store) findDiscoverRequests(filter bson.D, findOptions *options.FindOptions) ([]*discoverRequestDocument, error) {
+	collection := s.client.Database(s.Database).Collection(discoverRequestCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	cur, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.StoreError, "unable to find discover requests")
+	}
+	defer cur.Close(ctx)
+	docs := []*discoverRequestDocument{}
+	for cur.Next(ctx) {
+		var doc discoverRequestDocument
+		if err := cur.Decode(&doc); err != nil {
+			return nil, errors.Wrap(err, errors.SerializationError, "failed to decode discover request")
+		}
+		docs = append(docs, &doc)
+	}
+	return docs, cur.Err()
+}
+
+// GetLastDiscoverRequest gets last request log
+func (s *Store) GetLastDiscoverRequest(serviceName string) (*ads.RequestContext, int64, error) {
+	docs, err := s.findDiscoverRequests(unexpired(serviceName, time.Now()), options.Find().SetSort(bson.D{{"revision", -1}}).SetLimit(1))
+	if err != nil {
+		return nil, -1, err
+	}
+	if len(docs) == 0 {
+		return nil, -1, errors.Errorf(errors.EntityNotFound, "no discover request found for service %q", serviceName)
+	}
+	return docs[0].Request, docs[0].Revision, nil
+}
+
+// GetDiscoverRequestsSinceRevision gets request logs since a revision.
+func (s *Store) GetDiscoverRequestsSinceRevision(serviceName string, revision int64) ([]*ads.RequestContext, int64, error) {
+	filter := append(unexpired(serviceName, time.Now()), bson.E{"revision", bson.D{{"$gt", revision}}})
+	docs, err := s.findDiscoverRequests(filter, options.Find().SetSort(bson.D{{"revision", 1}}))
+	if err != nil {
+		return nil, revision, err
+	}
+	requests := []*ads.RequestContext{}
+	for _, doc := range docs {
+		requests = append(requests, doc.Request)
+		revision = doc.Revision
+	}
+	return requests, revision, nil
+}
+
+// GetDiscoverRequests gets request logs for a service.
+// Get all requests when serviceName is empty.
+func (s *Store) GetDiscoverRequests(serviceName string) ([]*ads.RequestContext, int64, error) {
+	aggregates, revision, err := s.GetDiscoverRequestAggregates(serviceName)
+	if err != nil {
+		return nil, -1, err
+	}
+	requests := []*ads.RequestContext{}
+	for _, aggregate := range aggregates {
+		requests = append(requests, aggregate.Request)
+	}
+	return requests, revision, nil
+}
+
+// GetDiscoverRequestAggregates gets the distinct requests of a service with their hit counts.
+// Get the ones of all services when serviceName is empty.
+func (s *Store) GetDiscoverRequestAggregates(serviceName string) ([]*store.DiscoverRequestAggregate, int64, error) {
+	docs, err := s.findDiscoverRequests(unexpired(serviceName, time.Now()), options.Find().SetSort(bson.D{{"revision", 1}}))
+	if err != nil {
+		return nil, -1, err
+	}
+	aggregates := []*store.DiscoverRequestAggregate{}
+	revision := int64(0)
+	for _, doc := range docs {
+		aggregate := doc.DiscoverRequestAggregate
+		aggregates = append(aggregates, &aggregate)
+		revision = doc.Revision
+	}
+	return aggregates, revision, nil
+}
+
+// ResetDiscoverRequests cleans request logs for a service.
+// Clean all request logs when serviceName is empty.
+func (s *Store) ResetDiscoverRequests(serviceName string) error {
+	collection := s.client.Database(s.Database).Collection(discoverRequestCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := bson.D{}
+	if len(serviceName) > 0 {
+		filter = bson.D{{"servicename", serviceName}}
+	}
+	if _, err := collection.DeleteMany(ctx, filter); err != nil {
+		return errors.Errorf(errors.StoreError, "unable to reset discover requests from service %q", serviceName)
+	}
+	return nil
+}
+
+// GeneratePolicies generates policies for principal based on existing request logs. Generate policies for all
+// principals when principalXXX are empty.
+func (s *Store) GeneratePolicies(serviceName, principalType, principalName, principalIDD string) (map[string]*pms.Service, int64, error) {
+	requests, revision, err := s.GetDiscoverRequests(serviceName)
+	if err != nil {
+		return nil, -1, err
+	}
+	serviceMap, err := store.GeneratePoliciesFromDiscoverRequests(requests, principalType, principalName, principalIDD)
+	if err != nil {
+		return nil, -1, err
+	}
+	return serviceMap, revision, nil
+}
@@ -0,0 +1,21 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package mongodb
+
+import (
+	"context"
+
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/store"
+)
+
+// Ping checks the connectivity to the MongoDB server
+func (s *Store) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), store.PingTimeout)
+	defer cancel()
+	if err := s.client.Ping(ctx, nil); err != nil {
+		return errors.Wrap(err, errors.StoreError, "failed to connect to MongoDB server")
+	}
+	return nil
+}
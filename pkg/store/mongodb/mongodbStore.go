@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"strings"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -16,8 +17,11 @@ import (
 )
 
 type Store struct {
-	client   *mongo.Client
-	Database string
+	client            *mongo.Client
+	Database          string
+	watchLock         sync.Mutex
+	cancelWatch       context.CancelFunc
+	discoverIndexOnce sync.Once
 }
 
 // ReadPolicyStore reads policy store from a file
@@ -237,6 +241,23 @@ func (s *Store) DeleteService(serviceName string) error {
 	return nil
 }
 
+// SetServiceOwners replaces the owners of a service
+func (s *Store) SetServiceOwners(serviceName string, owners []string) error {
+	serviceCollection := s.client.Database(s.Database).Collection("services")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := bson.D{{"_id", serviceName}}
+	update := bson.D{{"$set", bson.D{{"owners", owners}}}}
+	result, err := serviceCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.Errorf(errors.EntityNotFound, "service %q is not found", serviceName)
+	}
+	return nil
+}
+
 // DeleteServices deletes all services from a file
 func (s *Store) DeleteServices() error {
 	serviceCollection := s.client.Database(s.Database).Collection("services")
@@ -247,13 +268,36 @@ func (s *Store) DeleteServices() error {
 }
 
 func (s *Store) Watch() (pms.StorageChangeChannel, error) {
+	return s.watchFrom(nil)
+}
+
+// ResumeWatch watches the changes after the resume token of the last received event, so that no
+// change is missed when a watch is re-established. It fails if the token is no longer in the oplog.
+func (s *Store) ResumeWatch(last *pms.StoreChangeEvent) (pms.StorageChangeChannel, error) {
+	if last == nil || len(last.ResumeToken) == 0 {
+		return nil, errors.New(errors.StoreError, "no resume token to resume the watch from")
+	}
+	return s.watchFrom(bson.Raw(last.ResumeToken))
+}
+
+// watchFrom watches the changes after the resume token, or from now if the token is nil. The
+// returned channel is closed when the change stream fails or the watch is stopped.
+func (s *Store) watchFrom(resumeToken bson.Raw) (pms.StorageChangeChannel, error) {
 	log.Info("Enter Watch...")
 	streamOptions := options.ChangeStream().SetFullDocument(options.UpdateLookup)
-	changeStream, err := s.client.Database(s.Database).Watch(context.TODO(), mongo.Pipeline{}, streamOptions)
+	if resumeToken != nil {
+		streamOptions.SetResumeAfter(resumeToken)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	changeStream, err := s.client.Database(s.Database).Watch(ctx, mongo.Pipeline{}, streamOptions)
 	if err != nil {
+		cancel()
 		log.Error(err)
-		return nil, err
+		return nil, errors.Wrap(err, errors.StoreError, "failed to watch MongoDB change stream")
 	}
+	s.watchLock.Lock()
+	s.cancelWatch = cancel
+	s.watchLock.Unlock()
 
 	var storeChangeChan pms.StorageChangeChannel
 	storeChangeChan = make(chan pms.StoreChangeEvent)
@@ -262,9 +306,23 @@ func (s *Store) Watch() (pms.StorageChangeChannel, error) {
 		defer func() {
 			changeStream.Close(context.TODO())
 			close(storeChangeChan)
+			cancel()
 		}()
 
-		for changeStream.Next(context.TODO()) {
+		var token bson.Raw
+		// send returns false if the watch is stopped while the event is pending
+		send := func(e pms.StoreChangeEvent) bool {
+			e.ResumeToken = token
+			select {
+			case storeChangeChan <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for changeStream.Next(ctx) {
+			token = changeStream.ResumeToken()
 			// A new event variable should be declared for each event.
 			var event bson.M
 			if err := changeStream.Decode(&event); err != nil {
@@ -296,11 +354,15 @@ func (s *Store) Watch() (pms.StorageChangeChannel, error) {
 
 					serviceDeleteEvent := pms.StoreChangeEvent{Type: pms.SERVICE_DELETE, ID: id, Content: []string{service.Name}}
 					log.Info("serviceDeleteEvent:", serviceDeleteEvent)
-					storeChangeChan <- serviceDeleteEvent
+					if !send(serviceDeleteEvent) {
+						return
+					}
 					id = time.Now().Unix()
 					serviceAddEvent := pms.StoreChangeEvent{Type: pms.SERVICE_ADD, ID: id, Content: &service}
 					log.Info("serviceAddEvent:", serviceAddEvent)
-					storeChangeChan <- serviceAddEvent
+					if !send(serviceAddEvent) {
+						return
+					}
 
 				} else if event["operationType"] == "insert" {
 					log.Info("===insert service")
@@ -318,7 +380,9 @@ func (s *Store) Watch() (pms.StorageChangeChannel, error) {
 					}
 					serviceAddEvent := pms.StoreChangeEvent{Type: pms.SERVICE_ADD, ID: id, Content: &service}
 					log.Info("###serviceAddEvent:", serviceAddEvent)
-					storeChangeChan <- serviceAddEvent
+					if !send(serviceAddEvent) {
+						return
+					}
 
 				} else if event["operationType"] == "delete" {
 					log.Info("===delete service")
@@ -326,7 +390,9 @@ func (s *Store) Watch() (pms.StorageChangeChannel, error) {
 					serviceName := event["documentKey"].(bson.M)["_id"].(string)
 					serviceDeleteEvent := pms.StoreChangeEvent{Type: pms.SERVICE_DELETE, ID: id, Content: []string{serviceName}}
 					log.Info("###serviceDeleteEvent:", serviceDeleteEvent)
-					storeChangeChan <- serviceDeleteEvent
+					if !send(serviceDeleteEvent) {
+						return
+					}
 
 				}
 			} else if ns["coll"] == "functions" {
@@ -347,7 +413,9 @@ func (s *Store) Watch() (pms.StorageChangeChannel, error) {
 					}
 					funcAddEvent := pms.StoreChangeEvent{Type: pms.FUNCTION_ADD, ID: id, Content: &f}
 					log.Info("###funcAddEvent:", funcAddEvent)
-					storeChangeChan <- funcAddEvent
+					if !send(funcAddEvent) {
+						return
+					}
 
 				} else if event["operationType"] == "delete" {
 					log.Info("===delete function")
@@ -355,7 +423,9 @@ func (s *Store) Watch() (pms.StorageChangeChannel, error) {
 					funcName := event["documentKey"].(bson.M)["_id"].(string)
 					funcDeleteEvent := pms.StoreChangeEvent{Type: pms.FUNCTION_DELETE, ID: id, Content: []string{funcName}}
 					log.Info("###funcDeleteEvent:", funcDeleteEvent)
-					storeChangeChan <- funcDeleteEvent
+					if !send(funcDeleteEvent) {
+						return
+					}
 
 				}
 			}
@@ -373,8 +443,14 @@ func (s *Store) Watch() (pms.StorageChangeChannel, error) {
 
 }
 
+// StopWatch stops the current watch, its channel is closed
 func (s *Store) StopWatch() {
-
+	s.watchLock.Lock()
+	defer s.watchLock.Unlock()
+	if s.cancelWatch != nil {
+		s.cancelWatch()
+		s.cancelWatch = nil
+	}
 }
 
 func (s *Store) Type() string {
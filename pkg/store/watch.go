@@ -0,0 +1,17 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package store
+
+import (
+	"github.com/teramoby/speedle-plus/api/pms"
+)
+
+// ResumableWatcher is implemented by stores whose watch can be resumed after a change, so that no
+// change is missed when a failed watch is re-established
+type ResumableWatcher interface {
+	// ResumeWatch watches the changes after the last event received from the previous watch, which
+	// carries the revision or the resume token of the store. It returns an error if the store can't
+	// resume from the event, for example the revision has been compacted.
+	ResumeWatch(last *pms.StoreChangeEvent) (pms.StorageChangeChannel, error)
+}
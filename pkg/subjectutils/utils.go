@@ -5,6 +5,7 @@ package subjectutils
 
 import (
 	"fmt"
+	"strings"
 
 	adsapi "github.com/teramoby/speedle-plus/api/ads"
 )
@@ -17,3 +18,23 @@ func EncodePrincipal(principal *adsapi.Principal) string {
 	}
 	return fmt.Sprintf("%s:%s", principal.Type, principal.Name)
 }
+
+// DecodePrincipal decodes a principal string in the form of EncodePrincipal
+func DecodePrincipal(encoded string) (*adsapi.Principal, error) {
+	var principal adsapi.Principal
+	if strings.HasPrefix(encoded, "idd=") {
+		i := strings.IndexByte(encoded, ':')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid principal %q", encoded)
+		}
+		principal.IDD = encoded[len("idd="):i]
+		encoded = encoded[i+1:]
+	}
+	i := strings.IndexByte(encoded, ':')
+	if i <= 0 || i == len(encoded)-1 {
+		return nil, fmt.Errorf("invalid principal %q, <type>:<name> is expected", encoded)
+	}
+	principal.Type = encoded[:i]
+	principal.Name = encoded[i+1:]
+	return &principal, nil
+}
@@ -5,19 +5,36 @@ package adsgrpc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"time"
 
 	adsapi "github.com/teramoby/speedle-plus/api/ads"
 	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/errors"
 	"github.com/teramoby/speedle-plus/pkg/eval"
+	"github.com/teramoby/speedle-plus/pkg/metrics"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
 	"github.com/teramoby/speedle-plus/pkg/svcs/adsgrpc/pb"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
 
 	"github.com/teramoby/speedle-plus/pkg/logging"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	log "github.com/sirupsen/logrus"
 )
 
 // GRPCService is the ADS GRPC implementation
 type GRPCService struct {
-	evaluator eval.InternalEvaluator
+	evaluator    eval.InternalEvaluator
+	certIdentity *svcs.CertIdentity
+	clientAuthz  *svcs.ClientAuthz
 }
 
 // NewGRPCService constructs a new ADS GRPC service instance
@@ -28,6 +45,77 @@ func NewGRPCService(evaluator eval.InternalEvaluator) (*GRPCService, error) {
 	}, nil
 }
 
+// NewGRPCServiceWithServerConfig constructs a new ADS GRPC service instance, applying the server
+// configuration like the identity of client certificates
+func NewGRPCServiceWithServerConfig(evaluator eval.InternalEvaluator, conf *cfg.ServerConfig) (*GRPCService, error) {
+	return NewGRPCServiceWithClientAuthz(evaluator, conf, nil)
+}
+
+// NewGRPCServiceWithClientAuthz constructs a new ADS GRPC service instance, authenticating and authorizing
+// callers with the client authorization if it is not nil
+func NewGRPCServiceWithClientAuthz(evaluator eval.InternalEvaluator, conf *cfg.ServerConfig, clientAuthz *svcs.ClientAuthz) (*GRPCService, error) {
+	impl, err := NewGRPCService(evaluator)
+	if err != nil {
+		return nil, err
+	}
+	impl.clientAuthz = clientAuthz
+	if conf != nil && conf.ClientCertIdentity != nil {
+		impl.certIdentity, err = svcs.NewCertIdentity(conf.ClientCertIdentity, evaluator)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl, nil
+}
+
+// checkCaller authorizes the caller of an operation with the client authorization, then applies the
+// identity of the client certificate of the peer to the request context
+func (impl *GRPCService) checkCaller(ctx context.Context, operation string, reqCtx *adsapi.RequestContext) error {
+	if impl.clientAuthz == nil && impl.certIdentity == nil {
+		return nil
+	}
+	var state *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state = &tlsInfo.State
+		}
+	}
+	var err error
+	if impl.clientAuthz != nil {
+		var authorization string
+		if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+			authorization = md.Get("authorization")[0]
+		}
+		err = impl.clientAuthz.AuthorizeRequest(authorization, state, operation, reqCtx.ServiceName)
+	}
+	if err == nil && impl.certIdentity != nil {
+		err = impl.certIdentity.Apply(state, reqCtx)
+	}
+	switch errors.Code(err) {
+	case errors.Unauthorized:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Forbidden:
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return err
+}
+
+// startServerSpan starts the span of an API request, which continues the trace in the traceparent
+// metadata of the request. The span is carried into the evaluation by the request context.
+func startServerSpan(ctx context.Context, api string, reqCtx *adsapi.RequestContext) *tracing.Span {
+	var span *tracing.Span
+	reqCtx.TraceContext, span = tracing.StartSpan(tracing.Detach(tracing.ExtractGRPC(ctx)), api, tracing.SpanKindServer)
+	span.SetAttribute("protocol", metrics.ProtocolGRPC)
+	span.SetAttribute("service", reqCtx.ServiceName)
+	return span
+}
+
+func setDecisionAttributes(span *tracing.Span, allowed bool, reason adsapi.Reason, err error) {
+	span.SetAttribute("allowed", allowed)
+	span.SetAttribute("reason", reason.String())
+	span.RecordError(err)
+}
+
 func convertGRPCContextRequest(context *pb.ContextRequest) *adsapi.RequestContext {
 	ret := adsapi.RequestContext{
 		Subject:     convertGRPCSubject(context.Subject),
@@ -75,12 +163,22 @@ func convertGRPCSubject(subject *pb.Subject) *adsapi.Subject {
 }
 
 func (impl *GRPCService) IsAllowed(ctx context.Context, in *pb.ContextRequest) (*pb.IsAllowedResponse, error) {
+	defer metrics.ObserveRequest(metrics.APIIsAllowed, metrics.ProtocolGRPC, time.Now())
 	reqCtx := convertGRPCContextRequest(in)
+	span := startServerSpan(ctx, metrics.APIIsAllowed, reqCtx)
+	defer span.End()
+
+	if err := impl.checkCaller(ctx, svcs.ClientOperationCheck, reqCtx); err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]IsAllowed", reqCtx, err.Error())
+		return nil, err
+	}
 
 	// assert token
 	impl.evaluator.AssertToken(reqCtx)
 
 	allowed, reason, err := impl.evaluator.IsAllowed(*reqCtx)
+	setDecisionAttributes(span, allowed, reason, err)
 	if err != nil {
 		// Audit log
 		logging.WriteSimpleFailedAuditLog("[gRPC]IsAllowed", reqCtx, err.Error())
@@ -93,13 +191,26 @@ func (impl *GRPCService) IsAllowed(ctx context.Context, in *pb.ContextRequest) (
 	}
 
 	// Audit log
-	logging.WriteSimpleSucceededAuditLog("[gRPC]IsAllowed", reqCtx, response)
+	logging.WriteSimpleSucceededAuditLog("[gRPC]IsAllowed", reqCtx, log.Fields{
+		"allowed":  allowed,
+		"reason":   reason.String(),
+		"decision": reason.Decision(),
+	})
 
 	return &response, nil
 }
 
 func (impl *GRPCService) GetAllGrantedRoles(ctx context.Context, in *pb.ContextRequest) (*pb.AllRoleResponse, error) {
+	defer metrics.ObserveRequest(metrics.APIGrantedRoles, metrics.ProtocolGRPC, time.Now())
 	reqCtx := convertGRPCContextRequest(in)
+	span := startServerSpan(ctx, metrics.APIGrantedRoles, reqCtx)
+	defer span.End()
+
+	if err := impl.checkCaller(ctx, svcs.ClientOperationEnumerate, reqCtx); err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]GetAllGrantedRoles", reqCtx, err.Error())
+		return nil, err
+	}
 
 	// assert token
 	impl.evaluator.AssertToken(reqCtx)
@@ -120,7 +231,16 @@ func (impl *GRPCService) GetAllGrantedRoles(ctx context.Context, in *pb.ContextR
 }
 
 func (impl *GRPCService) GetAllPermissions(ctx context.Context, in *pb.ContextRequest) (*pb.AllPermissionResponse, error) {
+	defer metrics.ObserveRequest(metrics.APIGrantedPermissions, metrics.ProtocolGRPC, time.Now())
 	reqCtx := convertGRPCContextRequest(in)
+	span := startServerSpan(ctx, metrics.APIGrantedPermissions, reqCtx)
+	defer span.End()
+
+	if err := impl.checkCaller(ctx, svcs.ClientOperationEnumerate, reqCtx); err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]GetAllGrantedPermissions", reqCtx, err.Error())
+		return nil, err
+	}
 
 	// assert token
 	impl.evaluator.AssertToken(reqCtx)
@@ -150,11 +270,20 @@ func (impl *GRPCService) GetAllPermissions(ctx context.Context, in *pb.ContextRe
 
 func (impl *GRPCService) Discover(ctx context.Context, in *pb.ContextRequest) (*pb.IsAllowedResponse, error) {
 	reqCtx := convertGRPCContextRequest(in)
+	span := startServerSpan(ctx, "discover", reqCtx)
+	defer span.End()
+
+	if err := impl.checkCaller(ctx, svcs.ClientOperationDiscover, reqCtx); err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]Discovery", reqCtx, err.Error())
+		return nil, err
+	}
 
 	// assert token
 	impl.evaluator.AssertToken(reqCtx)
 
 	allowed, reason, err := impl.evaluator.Discover(*reqCtx)
+	setDecisionAttributes(span, allowed, reason, err)
 	if err != nil {
 		// Audit log
 		logging.WriteSimpleFailedAuditLog("[gRPC]Discovery", reqCtx, err.Error())
@@ -324,7 +453,16 @@ func convertAPIRolePolicy2EvaluatedRolePolicyResponse(apiRolePolicy *adsapi.Eval
 }
 
 func (impl *GRPCService) Diagnose(ctx context.Context, in *pb.ContextRequest) (*pb.EvaluationDebugResponse, error) {
+	defer metrics.ObserveRequest(metrics.APIDiagnose, metrics.ProtocolGRPC, time.Now())
 	reqCtx := convertGRPCContextRequest(in)
+	span := startServerSpan(ctx, metrics.APIDiagnose, reqCtx)
+	defer span.End()
+
+	if err := impl.checkCaller(ctx, svcs.ClientOperationDiagnose, reqCtx); err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]Diagnose", reqCtx, err.Error())
+		return nil, err
+	}
 
 	// assert token
 	impl.evaluator.AssertToken(reqCtx)
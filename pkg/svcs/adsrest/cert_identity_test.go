@@ -0,0 +1,140 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package adsrest
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/assertion"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/eval"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
+)
+
+const certIdentityStore = `{
+  "services": [
+    {
+      "name": "billing-api",
+      "policies": [
+        {
+          "id": "p1",
+          "name": "invoices",
+          "effect": "grant",
+          "permissions": [{"resource": "/invoices", "actions": ["read", "query"]}],
+          "principals": [["entity:billing"]]
+        }
+      ]
+    }
+  ]
+}`
+
+// The service is in shadow mode and grants by default, which don't apply to the authorization of callers
+const shadowCertIdentityStore = `{
+  "services": [
+    {
+      "name": "billing-api",
+      "mode": "shadow",
+      "defaultEffect": "grant"
+    }
+  ]
+}`
+
+func TestClientCertIdentity(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certidentity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	storeFile := filepath.Join(dir, "store.json")
+	if err := ioutil.WriteFile(storeFile, []byte(certIdentityStore), 0600); err != nil {
+		t.Fatal(err)
+	}
+	newEvaluator := func(storeFile string) eval.InternalEvaluator {
+		evaluator, err := eval.NewFromConfig(&cfg.Config{
+			StoreConfig: &cfg.StoreConfig{
+				StoreType:  cfg.StorageTypeFile,
+				StoreProps: map[string]interface{}{"FileLocation": storeFile},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return evaluator
+	}
+	evaluator := newEvaluator(storeFile)
+
+	verified := func(cn string) *tls.ConnectionState {
+		cert := x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+		return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{&cert}}}
+	}
+	isAllowed := func(identity *assertion.CertIdentityConfig, state *tls.ConnectionState, request *JsonContext) (int, *IsAllowedResponse) {
+		router, err := NewRouterWithServerConfig(evaluator, &cfg.ServerConfig{ClientCertIdentity: identity})
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf, _ := json.Marshal(request)
+		req := httptest.NewRequest(http.MethodPost, svcs.PolicyAtzPath+"is-allowed", bytes.NewBuffer(buf))
+		req.TLS = state
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var resp IsAllowedResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, &resp
+	}
+
+	rules := []*assertion.CertIdentityRule{{Source: assertion.CertIdentitySourceCN, Match: "(.+)-client", Entity: "$1"}}
+	forged := &JsonContext{
+		Subject:     &JsonSubject{Principals: []*JsonPrincipal{{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: "billing"}}},
+		ServiceName: "billing-api",
+		Resource:    "/invoices",
+		Action:      "read",
+	}
+	anonymous := &JsonContext{ServiceName: "billing-api", Resource: "/invoices", Action: "read"}
+
+	subjectMode := &assertion.CertIdentityConfig{Rules: rules}
+	testCases := []struct {
+		name     string
+		identity *assertion.CertIdentityConfig
+		state    *tls.ConnectionState
+		request  *JsonContext
+		status   int
+		allowed  bool
+	}{
+		{"entity from certificate", subjectMode, verified("billing-client"), anonymous, http.StatusOK, true},
+		{"caller supplied entity is replaced", subjectMode, verified("shipping-client"), forged, http.StatusOK, false},
+		{"caller supplied entity is kept", &assertion.CertIdentityConfig{Rules: rules, KeepRequestEntities: true}, verified("shipping-client"), forged, http.StatusOK, true},
+		{"no certificate", subjectMode, nil, anonymous, http.StatusOK, false},
+		{"certificate required", &assertion.CertIdentityConfig{Rules: rules, Required: true}, nil, anonymous, http.StatusUnauthorized, false},
+		{"caller authorized", &assertion.CertIdentityConfig{Rules: rules, Mode: assertion.CertIdentityModeAuthorize}, verified("billing-client"), forged, http.StatusOK, true},
+		{"caller forbidden", &assertion.CertIdentityConfig{Rules: rules, Mode: assertion.CertIdentityModeAuthorize}, verified("shipping-client"), forged, http.StatusForbidden, false},
+		{"caller unknown", &assertion.CertIdentityConfig{Rules: rules, Mode: assertion.CertIdentityModeAuthorize}, verified("billing"), forged, http.StatusUnauthorized, false},
+	}
+	for _, tc := range testCases {
+		status, resp := isAllowed(tc.identity, tc.state, tc.request)
+		if status != tc.status || resp.Allowed != tc.allowed {
+			t.Errorf("%s: expected %d, allowed %v, got %d, %+v", tc.name, tc.status, tc.allowed, status, resp)
+		}
+	}
+
+	shadowStoreFile := filepath.Join(dir, "shadow.json")
+	if err := ioutil.WriteFile(shadowStoreFile, []byte(shadowCertIdentityStore), 0600); err != nil {
+		t.Fatal(err)
+	}
+	evaluator = newEvaluator(shadowStoreFile)
+	status, resp := isAllowed(&assertion.CertIdentityConfig{Rules: rules, Mode: assertion.CertIdentityModeAuthorize}, verified("billing-client"), anonymous)
+	if status != http.StatusForbidden || resp.Allowed {
+		t.Errorf("caller of a service in shadow mode: expected %d, got %d, %+v", http.StatusForbidden, status, resp)
+	}
+}
@@ -4,6 +4,7 @@
 package adsrest
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"reflect"
@@ -15,11 +16,17 @@ import (
 	"github.com/teramoby/speedle-plus/pkg/eval"
 	"github.com/teramoby/speedle-plus/pkg/httputils"
 	"github.com/teramoby/speedle-plus/pkg/logging"
+	"github.com/teramoby/speedle-plus/pkg/metrics"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
 
 	"github.com/teramoby/speedle-plus/pkg/svcs"
+	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
 )
 
+// authzRealm is the realm of bearer token challenges
+const authzRealm = "speedle"
+
 type JsonAttribute struct {
 	Name  string      `json:"name"`
 	Type  string      `json:"type"`
@@ -48,6 +55,10 @@ type JsonContext struct {
 
 type RESTService struct {
 	Evaluator eval.InternalEvaluator
+	// CertIdentity applies the identity of client certificates to requests, optional
+	CertIdentity *svcs.CertIdentity
+	// ClientAuthz authenticates and authorizes callers, optional
+	ClientAuthz *svcs.ClientAuthz
 }
 
 type IsAllowedResponse struct {
@@ -57,8 +68,9 @@ type IsAllowedResponse struct {
 }
 
 type AuditEvaluationResult struct {
-	Allowed string `json:"allowed"`
-	Reason  string `json:"reason"`
+	Allowed  string `json:"allowed"`
+	Reason   string `json:"reason"`
+	Decision string `json:"decision"`
 }
 
 type PermissionResponse struct {
@@ -101,13 +113,15 @@ type EvaluatedCondition struct {
 
 // Should we add Both of ReasonCode and ReasonMessage
 type EvaluationDebugResponse struct {
-	Allowed        bool                   `json:"allowed"`
-	Reason         string                 `json:"reason"`
-	RequestContext JsonContext            `json:"requestContext,omitempty"`
-	Attributes     map[string]interface{} `json:"attributes,omitempty"`
-	GrantedRoles   []string               `json:"grantedRoles,omitempty"`
-	RolePolicies   []RolePolicyResponse   `json:"rolePolicies,omitempty"`
-	Policies       []PolicyResponse       `json:"policies,omitempty"`
+	Allowed          bool                               `json:"allowed"`
+	Reason           string                             `json:"reason"`
+	RequestContext   JsonContext                        `json:"requestContext,omitempty"`
+	Attributes       map[string]interface{}             `json:"attributes,omitempty"`
+	AttributeSources map[string]*adsapi.AttributeSource `json:"attributeSources,omitempty"`
+	GrantedRoles     []string                           `json:"grantedRoles,omitempty"`
+	RolePolicies     []RolePolicyResponse               `json:"rolePolicies,omitempty"`
+	Policies         []PolicyResponse                   `json:"policies,omitempty"`
+	Functions        []*adsapi.FunctionStatus           `json:"functions,omitempty"`
 }
 
 func NewRESTService(conf *cfg.Config) (*RESTService, error) {
@@ -303,14 +317,70 @@ func constructEvaluationResultForAudit(allowed bool, reason adsapi.Reason) *Audi
 	}
 
 	auditResult := AuditEvaluationResult{
-		Allowed: evaResult,
-		Reason:  reason.String(),
+		Allowed:  evaResult,
+		Reason:   reason.String(),
+		Decision: reason.Decision(),
 	}
 
 	return &auditResult
 }
 
+// startServerSpan starts the span of an API request, which continues the trace in the traceparent
+// header of the request. The span is carried into the evaluation by the request context.
+func startServerSpan(r *http.Request, api string, reqCtx *adsapi.RequestContext) *tracing.Span {
+	var span *tracing.Span
+	reqCtx.TraceContext, span = tracing.StartSpan(tracing.Extract(context.Background(), r.Header), api, tracing.SpanKindServer)
+	span.SetAttribute("protocol", metrics.ProtocolREST)
+	span.SetAttribute("service", reqCtx.ServiceName)
+	return span
+}
+
+func setDecisionAttributes(span *tracing.Span, allowed bool, reason adsapi.Reason, err error) {
+	span.SetAttribute("allowed", allowed)
+	span.SetAttribute("reason", reason.String())
+	span.RecordError(err)
+}
+
+// checkCaller authorizes the caller of an operation with the client authorization, then applies the
+// identity of the client certificate to the request context
+func (e *RESTService) checkCaller(r *http.Request, operation string, context *adsapi.RequestContext) error {
+	if e.ClientAuthz != nil {
+		if err := e.ClientAuthz.AuthorizeRequest(r.Header.Get("Authorization"), r.TLS, operation, context.ServiceName); err != nil {
+			return err
+		}
+	}
+	if e.CertIdentity == nil {
+		return nil
+	}
+	return e.CertIdentity.Apply(r.TLS, context)
+}
+
+// sendCallerError sends the error of checking the caller, challenging unauthenticated callers
+func sendCallerError(w http.ResponseWriter, err error) {
+	if errors.Code(err) == errors.Unauthorized {
+		httputils.SendBearerAtzErrorResponse(w, authzRealm, "invalid_token", err.Error())
+		return
+	}
+	httputils.HandleError(w, err)
+}
+
+// withClientAuthz authorizes the caller of an operation which is not on a service before calling the handler
+func (e *RESTService) withClientAuthz(operation string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if e.ClientAuthz != nil {
+			if err := e.ClientAuthz.AuthorizeRequest(r.Header.Get("Authorization"), r.TLS, operation, ""); err != nil {
+				sendCallerError(w, err)
+				// Audit log
+				logging.WriteSimpleFailedAuditLog(operation, r.URL.Path, err.Error())
+				return
+			}
+		}
+		handler(w, r)
+	}
+}
+
 func (e *RESTService) IsAllowed(w http.ResponseWriter, r *http.Request) {
+	defer metrics.ObserveRequest(metrics.APIIsAllowed, metrics.ProtocolREST, time.Now())
 	jsonRequest, err := DecodeJSONContext(r)
 	if err != nil {
 		httputils.HandleError(w, err)
@@ -322,8 +392,18 @@ func (e *RESTService) IsAllowed(w http.ResponseWriter, r *http.Request) {
 		httputils.HandleError(w, err)
 		return
 	}
+	span := startServerSpan(r, metrics.APIIsAllowed, context)
+	defer span.End()
+
+	if err := e.checkCaller(r, svcs.ClientOperationCheck, context); err != nil {
+		sendCallerError(w, err)
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("IsAllowed", context, err.Error())
+		return
+	}
 
 	result, reason, err := e.Evaluator.IsAllowed(*context)
+	setDecisionAttributes(span, result, reason, err)
 	response := IsAllowedResponse{
 		Allowed: result,
 		Reason:  int32(reason),
@@ -352,6 +432,7 @@ func (e *RESTService) IsAllowed(w http.ResponseWriter, r *http.Request) {
 }
 
 func (e *RESTService) GetAllGrantedRoles(w http.ResponseWriter, r *http.Request) {
+	defer metrics.ObserveRequest(metrics.APIGrantedRoles, metrics.ProtocolREST, time.Now())
 	jsonRequest, err := DecodeJSONContext(r)
 	if err != nil {
 		httputils.HandleError(w, err)
@@ -363,6 +444,15 @@ func (e *RESTService) GetAllGrantedRoles(w http.ResponseWriter, r *http.Request)
 		httputils.HandleError(w, err)
 		return
 	}
+	span := startServerSpan(r, metrics.APIGrantedRoles, context)
+	defer span.End()
+
+	if err := e.checkCaller(r, svcs.ClientOperationEnumerate, context); err != nil {
+		sendCallerError(w, err)
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("GetAllGrantedRoles", context, err.Error())
+		return
+	}
 
 	roles, err := e.Evaluator.GetAllGrantedRoles(*context)
 	if err != nil {
@@ -383,6 +473,7 @@ func (e *RESTService) GetAllGrantedRoles(w http.ResponseWriter, r *http.Request)
 }
 
 func (e *RESTService) GetAllGrantedPermissions(w http.ResponseWriter, r *http.Request) {
+	defer metrics.ObserveRequest(metrics.APIGrantedPermissions, metrics.ProtocolREST, time.Now())
 	jsonRequest, err := DecodeJSONContext(r)
 	if err != nil {
 		httputils.HandleError(w, err)
@@ -394,6 +485,15 @@ func (e *RESTService) GetAllGrantedPermissions(w http.ResponseWriter, r *http.Re
 		httputils.HandleError(w, err)
 		return
 	}
+	span := startServerSpan(r, metrics.APIGrantedPermissions, context)
+	defer span.End()
+
+	if err := e.checkCaller(r, svcs.ClientOperationEnumerate, context); err != nil {
+		sendCallerError(w, err)
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("GetAllGrantedPermissions", context, err.Error())
+		return
+	}
 
 	permissions, err := e.Evaluator.GetAllGrantedPermissions(*context)
 	if err != nil {
@@ -477,6 +577,7 @@ func ConvertAPIRolePolicy2RolePolicyResponse(apiRolePolicy *adsapi.EvaluatedRole
 }
 
 func (e *RESTService) Diagnose(w http.ResponseWriter, r *http.Request) {
+	defer metrics.ObserveRequest(metrics.APIDiagnose, metrics.ProtocolREST, time.Now())
 	jsonRequest, err := DecodeJSONContext(r)
 	if err != nil {
 		httputils.HandleError(w, err)
@@ -488,6 +589,15 @@ func (e *RESTService) Diagnose(w http.ResponseWriter, r *http.Request) {
 		httputils.HandleError(w, err)
 		return
 	}
+	span := startServerSpan(r, metrics.APIDiagnose, context)
+	defer span.End()
+
+	if err := e.checkCaller(r, svcs.ClientOperationDiagnose, context); err != nil {
+		sendCallerError(w, err)
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("Diagnose", context, err.Error())
+		return
+	}
 
 	evaResult, err := e.Evaluator.Diagnose(*context)
 	if err != nil {
@@ -515,13 +625,15 @@ func (e *RESTService) Diagnose(w http.ResponseWriter, r *http.Request) {
 
 	// Construct & return the response
 	response := EvaluationDebugResponse{
-		Allowed:        evaResult.Allowed,
-		Reason:         evaResult.Reason.String(),
-		RequestContext: *jsonRequest,
-		Attributes:     evaResult.Attributes,
-		GrantedRoles:   evaResult.GrantedRoles,
-		RolePolicies:   retRolePolicies,
-		Policies:       retPolicies,
+		Allowed:          evaResult.Allowed,
+		Reason:           evaResult.Reason.String(),
+		RequestContext:   *jsonRequest,
+		Attributes:       evaResult.Attributes,
+		AttributeSources: evaResult.AttributeSources,
+		GrantedRoles:     evaResult.GrantedRoles,
+		RolePolicies:     retRolePolicies,
+		Policies:         retPolicies,
+		Functions:        evaResult.Functions,
 	}
 
 	// Audit log
@@ -529,3 +641,47 @@ func (e *RESTService) Diagnose(w http.ResponseWriter, r *http.Request) {
 
 	httputils.SendOKResponse(w, &response)
 }
+
+func (e *RESTService) GetFunctionStatus(w http.ResponseWriter, r *http.Request) {
+	status := e.Evaluator.GetFunctionStatus()
+	if len(status) == 0 {
+		httputils.SendEmptyListResponse(w)
+		return
+	}
+	httputils.SendOKResponse(w, status)
+}
+
+func (e *RESTService) GetAsserterStatus(w http.ResponseWriter, r *http.Request) {
+	httputils.SendOKResponse(w, e.Evaluator.GetAsserterStatus())
+}
+
+func (e *RESTService) GetFunctionCacheStats(w http.ResponseWriter, r *http.Request) {
+	stats := e.Evaluator.GetFunctionCacheStats()
+	if len(stats) == 0 {
+		httputils.SendEmptyListResponse(w)
+		return
+	}
+	httputils.SendOKResponse(w, stats)
+}
+
+func (e *RESTService) GetFunctionCacheStatsByName(w http.ResponseWriter, r *http.Request) {
+	funcName := mux.Vars(r)["functionName"]
+	stats, err := e.Evaluator.GetFunctionCacheStatsByName(funcName)
+	if err != nil {
+		httputils.HandleError(w, err)
+		return
+	}
+	httputils.SendOKResponse(w, stats)
+}
+
+func (e *RESTService) FlushFunctionCache(w http.ResponseWriter, r *http.Request) {
+	funcName := mux.Vars(r)["functionName"]
+	if err := e.Evaluator.FlushFunctionCache(funcName); err != nil {
+		httputils.HandleError(w, err)
+		logging.WriteSimpleFailedAuditLog("FlushFunctionCache", funcName, err.Error())
+		return
+	}
+
+	logging.WriteSimpleSucceededAuditLog("FlushFunctionCache", funcName, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,136 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package adsrest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/assertion"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/eval"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
+)
+
+func TestClientAuthz(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clientauthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	storeFile := filepath.Join(dir, "store.json")
+	if err := ioutil.WriteFile(storeFile, []byte(certIdentityStore), 0600); err != nil {
+		t.Fatal(err)
+	}
+	evaluator, err := eval.NewFromConfig(&cfg.Config{
+		StoreConfig: &cfg.StoreConfig{
+			StoreType:  cfg.StorageTypeFile,
+			StoreProps: map[string]interface{}{"FileLocation": storeFile},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entity := func(name string) map[string]interface{} {
+		return map[string]interface{}{"principals": []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: name}}}
+	}
+	clientAuthz, err := svcs.NewClientAuthz(&cfg.ADSAuthzConfig{
+		Asserters: &assertion.RouterConfig{
+			Asserters: []*assertion.AsserterDefinition{{
+				Name: "keys",
+				Type: assertion.APIKeyAsserterType,
+				Props: map[string]interface{}{"keys": map[string]interface{}{
+					"billing-key":  entity("billing-gateway"),
+					"ops-key":      entity("ops"),
+					"stranger-key": entity("stranger"),
+					"admin-key":    entity("admin"),
+				}},
+			}},
+			Chains: map[string][]string{"apikey": {"keys"}},
+		},
+		Clients: []*cfg.ADSClientConfig{
+			{
+				Name:       "billing gateway",
+				Principals: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: "billing-gateway"}},
+				Services:   []string{"billing-api"},
+			},
+			{
+				Name:       "operators",
+				Principals: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: "ops"}},
+				Services:   []string{svcs.AllServices},
+				Operations: []string{svcs.ClientOperationCheck, svcs.ClientOperationDiagnose, svcs.ClientOperationStatus},
+			},
+			{
+				Name:       "administrators",
+				Principals: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: "admin"}},
+				Operations: []string{svcs.ClientOperationAdmin},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := NewRouterWithClientAuthz(evaluator, nil, clientAuthz)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(method, path, key, serviceName string) *httptest.ResponseRecorder {
+		buf, _ := json.Marshal(&JsonContext{
+			Subject:     &JsonSubject{Principals: []*JsonPrincipal{{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: "billing"}}},
+			ServiceName: serviceName,
+			Resource:    "/invoices",
+			Action:      "read",
+		})
+		req := httptest.NewRequest(method, svcs.PolicyAtzPath+path, bytes.NewBuffer(buf))
+		if len(key) > 0 {
+			req.Header.Set("Authorization", "ApiKey "+key)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		key         string
+		serviceName string
+		status      int
+	}{
+		{"no key", http.MethodPost, "is-allowed", "", "billing-api", http.StatusUnauthorized},
+		{"unknown key", http.MethodPost, "is-allowed", "guess", "billing-api", http.StatusUnauthorized},
+		{"unknown client", http.MethodPost, "is-allowed", "stranger-key", "billing-api", http.StatusForbidden},
+		{"client checks its service", http.MethodPost, "is-allowed", "billing-key", "billing-api", http.StatusOK},
+		{"client checks another service", http.MethodPost, "is-allowed", "billing-key", "hr-api", http.StatusForbidden},
+		{"client can't diagnose", http.MethodPost, "diagnose", "billing-key", "billing-api", http.StatusForbidden},
+		{"client can't enumerate", http.MethodPost, "all-granted-permissions", "billing-key", "billing-api", http.StatusForbidden},
+		{"client can't discover", http.MethodPost, "discover", "billing-key", "billing-api", http.StatusForbidden},
+		{"client can't read status", http.MethodGet, "function-status", "billing-key", "", http.StatusForbidden},
+		{"operator diagnoses", http.MethodPost, "diagnose", "ops-key", "billing-api", http.StatusOK},
+		{"operator reads status", http.MethodGet, "function-status", "ops-key", "", http.StatusOK},
+		{"operator can't enumerate", http.MethodPost, "all-granted-roles", "ops-key", "billing-api", http.StatusForbidden},
+		{"operator reads function cache", http.MethodGet, "function-cache", "ops-key", "", http.StatusOK},
+		{"operator can't flush function cache", http.MethodDelete, "function-cache/f1", "ops-key", "", http.StatusForbidden},
+		{"admin flushes function cache", http.MethodDelete, "function-cache/f1", "admin-key", "", http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		rec := send(tc.method, tc.path, tc.key, tc.serviceName)
+		if rec.Code != tc.status {
+			t.Errorf("%s: expected status %d, got %d, %s", tc.name, tc.status, rec.Code, rec.Body.String())
+		}
+		if tc.status == http.StatusUnauthorized && len(rec.Header().Get("WWW-Authenticate")) == 0 {
+			t.Errorf("%s: no authentication challenge", tc.name)
+		}
+	}
+}
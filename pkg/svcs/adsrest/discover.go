@@ -8,6 +8,7 @@ import (
 
 	"github.com/teramoby/speedle-plus/pkg/httputils"
 	"github.com/teramoby/speedle-plus/pkg/logging"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
 )
 
 func (e *RESTService) Discover(w http.ResponseWriter, r *http.Request) {
@@ -22,11 +23,21 @@ func (e *RESTService) Discover(w http.ResponseWriter, r *http.Request) {
 		httputils.HandleError(w, err)
 		return
 	}
+	span := startServerSpan(r, "discover", context)
+	defer span.End()
+
+	if err := e.checkCaller(r, svcs.ClientOperationDiscover, context); err != nil {
+		sendCallerError(w, err)
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("Discovery", context, err.Error())
+		return
+	}
 
 	// assert token
 	e.Evaluator.AssertToken(context)
 
 	result, reason, err := e.Evaluator.Discover(*context)
+	setDecisionAttributes(span, result, reason, err)
 	response := IsAllowedResponse{
 		Allowed: result,
 		Reason:  int32(reason),
@@ -0,0 +1,114 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package adsrest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/eval"
+	"github.com/teramoby/speedle-plus/pkg/health"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
+)
+
+func checkHealth(t *testing.T, router *mux.Router, path string) (int, *health.Status) {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var status health.Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("unexpected response of %s: %s", path, rec.Body.String())
+	}
+	return rec.Code, &status
+}
+
+// waitForHealth checks the health until the condition is met or a timeout of 5 seconds
+func waitForHealth(t *testing.T, router *mux.Router, path string, cond func(int, *health.Status) bool) *health.Status {
+	var code int
+	var status *health.Status
+	for i := 0; i < 50; i++ {
+		if code, status = checkHealth(t, router, path); cond(code, status) {
+			return status
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("unexpected health of %s, status code: %d, status: %+v", path, code, status)
+	return nil
+}
+
+func newHealthRouter(t *testing.T, storeFile string, enableWatch bool) (eval.InternalEvaluator, *mux.Router) {
+	if err := ioutil.WriteFile(storeFile, []byte(certIdentityStore), 0600); err != nil {
+		t.Fatal(err)
+	}
+	evaluator, err := eval.NewFromConfig(&cfg.Config{
+		StoreConfig: &cfg.StoreConfig{
+			StoreType:  cfg.StorageTypeFile,
+			StoreProps: map[string]interface{}{"FileLocation": storeFile},
+		},
+		EnableWatch: enableWatch,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := NewRouter(evaluator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return evaluator, router
+}
+
+func TestHealth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	storeFile := filepath.Join(dir, "watched.json")
+	evaluator, router := newHealthRouter(t, storeFile, true)
+	code, status := checkHealth(t, router, svcs.ReadyzPath)
+	if code != http.StatusOK || !status.Ready || !status.Store.Reachable || !status.Watch.Alive ||
+		status.Cache.BuiltAt == 0 || status.Cache.Services != 1 {
+		t.Fatalf("unexpected readiness, status code: %d, status: %+v", code, status)
+	}
+
+	// Changes of the store are reported as the last applied event
+	builtAt := time.Unix(status.Cache.BuiltAt, 0)
+	if err := ioutil.WriteFile(storeFile, []byte(certIdentityStore), 0600); err != nil {
+		t.Fatal(err)
+	}
+	status = waitForHealth(t, router, svcs.HealthzPath, func(code int, status *health.Status) bool {
+		return code == http.StatusOK && status.Watch.Events > 0
+	})
+	if status.Watch.LastEventType != "FULL_RELOAD" || time.Unix(status.Cache.BuiltAt, 0).Before(builtAt) {
+		t.Errorf("unexpected watch status %+v, cache status %+v", status.Watch, status.Cache)
+	}
+
+	// The ADS is neither live nor ready when its watch stops
+	evaluator.(*eval.PolicyEvalImpl).StopWatch()
+	waitForHealth(t, router, svcs.HealthzPath, func(code int, status *health.Status) bool {
+		return code == http.StatusServiceUnavailable && !status.Live && !status.Watch.Alive && len(status.Reasons) == 1
+	})
+	if code, _ := checkHealth(t, router, svcs.ReadyzPath); code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d, got %d", http.StatusServiceUnavailable, code)
+	}
+
+	// The ADS is live but not ready when its store is unreachable
+	storeFile = filepath.Join(dir, "unwatched.json")
+	_, router = newHealthRouter(t, storeFile, false)
+	os.Remove(storeFile)
+	if code, status := checkHealth(t, router, svcs.HealthzPath); code != http.StatusOK || status.Watch.Enabled {
+		t.Errorf("unexpected liveness, status code: %d, status: %+v", code, status)
+	}
+	if code, status := checkHealth(t, router, svcs.ReadyzPath); code != http.StatusServiceUnavailable || status.Store.Reachable {
+		t.Errorf("unexpected readiness, status code: %d, status: %+v", code, status)
+	}
+}
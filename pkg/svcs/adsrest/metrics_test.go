@@ -0,0 +1,78 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package adsrest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/eval"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
+)
+
+func TestMetrics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	storeFile := filepath.Join(dir, "store.json")
+	if err := ioutil.WriteFile(storeFile, []byte(certIdentityStore), 0600); err != nil {
+		t.Fatal(err)
+	}
+	evaluator, err := eval.NewFromConfig(&cfg.Config{
+		StoreConfig: &cfg.StoreConfig{
+			StoreType:  cfg.StorageTypeFile,
+			StoreProps: map[string]interface{}{"FileLocation": storeFile},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := NewRouter(evaluator)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, serviceName := range []string{"billing-api", "guessed-api"} {
+		buf, _ := json.Marshal(&JsonContext{
+			Subject:     &JsonSubject{Principals: []*JsonPrincipal{{Type: adsapi.PRINCIPAL_TYPE_ENTITY, Name: "billing"}}},
+			ServiceName: serviceName,
+			Resource:    "/invoices",
+			Action:      "read",
+		})
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, svcs.PolicyAtzPath+"is-allowed", bytes.NewBuffer(buf)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("is-allowed of service %s: expected %d, got %d", serviceName, http.StatusOK, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, svcs.MetricsPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	body := rec.Body.String()
+	for _, metric := range []string{
+		`speedle_ads_decisions_total{allowed="true",reason="GRANT_POLICY_FOUND",service="billing-api"}`,
+		`speedle_ads_decisions_total{allowed="false",reason="SERVICE_NOT_FOUND",service="unknown"}`,
+		`speedle_ads_request_duration_seconds_count{api="is-allowed",protocol="rest"}`,
+		`speedle_ads_runtime_services 1`,
+		`speedle_ads_runtime_policies 1`,
+	} {
+		if !strings.Contains(body, metric) {
+			t.Errorf("metric %s is not found", metric)
+		}
+	}
+}
@@ -6,7 +6,10 @@ package adsrest
 import (
 	"net/http"
 
+	"github.com/teramoby/speedle-plus/pkg/cfg"
 	"github.com/teramoby/speedle-plus/pkg/eval"
+	"github.com/teramoby/speedle-plus/pkg/health"
+	"github.com/teramoby/speedle-plus/pkg/metrics"
 	"github.com/teramoby/speedle-plus/pkg/svcs"
 
 	"github.com/gorilla/mux"
@@ -21,11 +24,18 @@ type route struct {
 
 type routes []route
 
-func initRouters(evaluator eval.InternalEvaluator) (*routes, error) {
+func initRouters(evaluator eval.InternalEvaluator, conf *cfg.ServerConfig, clientAuthz *svcs.ClientAuthz) (*routes, error) {
 	restService, err := NewRESTServiceWithEvaluator(evaluator)
 	if err != nil {
 		return nil, err
 	}
+	restService.ClientAuthz = clientAuthz
+	if conf != nil && conf.ClientCertIdentity != nil {
+		restService.CertIdentity, err = svcs.NewCertIdentity(conf.ClientCertIdentity, evaluator)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &routes{
 		route{
@@ -62,11 +72,58 @@ func initRouters(evaluator eval.InternalEvaluator) (*routes, error) {
 			svcs.PolicyAtzPath + "discover",
 			restService.Discover,
 		},
+
+		route{
+			"GetFunctionStatus",
+			"GET",
+			svcs.PolicyAtzPath + "function-status",
+			restService.withClientAuthz(svcs.ClientOperationStatus, restService.GetFunctionStatus),
+		},
+
+		route{
+			"GetAsserterStatus",
+			"GET",
+			svcs.PolicyAtzPath + "asserter-status",
+			restService.withClientAuthz(svcs.ClientOperationStatus, restService.GetAsserterStatus),
+		},
+
+		route{
+			"GetFunctionCacheStats",
+			"GET",
+			svcs.PolicyAtzPath + "function-cache",
+			restService.withClientAuthz(svcs.ClientOperationStatus, restService.GetFunctionCacheStats),
+		},
+
+		route{
+			"GetFunctionCacheStatsByName",
+			"GET",
+			svcs.PolicyAtzPath + "function-cache/{functionName}",
+			restService.withClientAuthz(svcs.ClientOperationStatus, restService.GetFunctionCacheStatsByName),
+		},
+
+		route{
+			"FlushFunctionCache",
+			"DELETE",
+			svcs.PolicyAtzPath + "function-cache/{functionName}",
+			restService.withClientAuthz(svcs.ClientOperationAdmin, restService.FlushFunctionCache),
+		},
 	}, nil
 }
 
 func NewRouter(evaluator eval.InternalEvaluator) (*mux.Router, error) {
-	routes, err := initRouters(evaluator)
+	return NewRouterWithServerConfig(evaluator, nil)
+}
+
+// NewRouterWithServerConfig creates the router of the ADS REST service, applying the server configuration
+// like the identity of client certificates
+func NewRouterWithServerConfig(evaluator eval.InternalEvaluator, conf *cfg.ServerConfig) (*mux.Router, error) {
+	return NewRouterWithClientAuthz(evaluator, conf, nil)
+}
+
+// NewRouterWithClientAuthz creates the router of the ADS REST service, authenticating and authorizing
+// callers with the client authorization if it is not nil
+func NewRouterWithClientAuthz(evaluator eval.InternalEvaluator, conf *cfg.ServerConfig, clientAuthz *svcs.ClientAuthz) (*mux.Router, error) {
+	routes, err := initRouters(evaluator, conf, clientAuthz)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +138,9 @@ func NewRouter(evaluator eval.InternalEvaluator) (*mux.Router, error) {
 			Name(route.Name).
 			Handler(handler)
 	}
+	router.Methods("GET").Path(svcs.MetricsPath).Name("Metrics").Handler(metrics.Handler())
+	router.Methods("GET").Path(svcs.HealthzPath).Name("Healthz").Handler(health.LivenessHandler(evaluator))
+	router.Methods("GET").Path(svcs.ReadyzPath).Name("Readyz").Handler(health.ReadinessHandler(evaluator))
 
 	return router, nil
 }
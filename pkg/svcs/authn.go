@@ -0,0 +1,105 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package svcs
+
+import (
+	"crypto/tls"
+	"strings"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/assertion"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+)
+
+// Caller is an authenticated caller of a service
+type Caller struct {
+	Principals []*adsapi.Principal
+	Attributes map[string]interface{}
+}
+
+// UserName returns the name of the first user principal of the caller
+func (c *Caller) UserName() string {
+	for _, p := range c.Principals {
+		if p.Type == adsapi.PRINCIPAL_TYPE_USER {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// HasPrincipal checks whether the caller has a principal, the identity domain is only compared when it is set
+func (c *Caller) HasPrincipal(principal *adsapi.Principal) bool {
+	for _, p := range c.Principals {
+		if principal.Type == p.Type && principal.Name == p.Name && (len(principal.IDD) == 0 || principal.IDD == p.IDD) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAuthorization returns the token and the token type in the value of an Authorization header,
+// the token type is the lower cased authentication scheme
+func ParseAuthorization(value string) (string, string) {
+	value = strings.TrimSpace(value)
+	i := strings.IndexByte(value, ' ')
+	if i < 0 {
+		return value, ""
+	}
+	return strings.TrimSpace(value[i+1:]), strings.ToLower(value[:i])
+}
+
+// Authenticator authenticates callers with tokens and verified client certificates
+type Authenticator struct {
+	asserters    *assertion.Router
+	certIdentity *assertion.CertIdentityMapper
+}
+
+// NewAuthenticator creates an authenticator asserting tokens with asserter chains and mapping
+// client certificates to entities. Both are optional, but callers can't be authenticated without any.
+func NewAuthenticator(asserters *assertion.RouterConfig, certIdentity *assertion.CertIdentityConfig) (*Authenticator, error) {
+	var a Authenticator
+	var err error
+	if asserters != nil {
+		if a.asserters, err = assertion.NewRouter(asserters); err != nil {
+			return nil, errors.Wrap(err, errors.ConfigError, "invalid asserters of caller authentication")
+		}
+	}
+	if certIdentity != nil {
+		if a.certIdentity, err = assertion.NewCertIdentityMapper(certIdentity); err != nil {
+			return nil, errors.Wrap(err, errors.ConfigError, "invalid client certificate identity of caller authentication")
+		}
+	}
+	return &a, nil
+}
+
+// Configured checks whether any asserter or client certificate identity is configured
+func (a *Authenticator) Configured() bool {
+	return a.asserters != nil || a.certIdentity != nil
+}
+
+// Authenticate returns the caller identified by a token of a token type, and by the verified client
+// certificate of the TLS connection
+func (a *Authenticator) Authenticate(token string, tokenType string, state *tls.ConnectionState) (*Caller, error) {
+	var caller Caller
+	if a.certIdentity != nil {
+		if p := a.certIdentity.PeerPrincipal(state); p != nil {
+			caller.Principals = append(caller.Principals, p)
+		}
+	}
+	if len(token) > 0 {
+		if a.asserters == nil {
+			return nil, errors.New(errors.Unauthorized, "tokens are not accepted")
+		}
+		ar, err := a.asserters.AssertToken(token, tokenType, "", nil)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.Unauthorized, "invalid token")
+		}
+		caller.Principals = append(caller.Principals, ar.Principals...)
+		caller.Attributes = ar.Attributes
+	}
+	if len(caller.Principals) == 0 {
+		return nil, errors.New(errors.Unauthorized, "authentication is required")
+	}
+	return &caller, nil
+}
@@ -0,0 +1,72 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package svcs
+
+import (
+	"crypto/tls"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/pkg/assertion"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+)
+
+// CertIdentity applies the identity of verified client certificates to authorization requests
+type CertIdentity struct {
+	mapper    *assertion.CertIdentityMapper
+	evaluator adsapi.PolicyEvaluator
+}
+
+// NewCertIdentity creates a client certificate identity. The evaluator authorizes callers in authorize mode.
+func NewCertIdentity(conf *assertion.CertIdentityConfig, evaluator adsapi.PolicyEvaluator) (*CertIdentity, error) {
+	mapper, err := assertion.NewCertIdentityMapper(conf)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ConfigError, "invalid client certificate identity configuration")
+	}
+	return &CertIdentity{mapper: mapper, evaluator: evaluator}, nil
+}
+
+// Apply applies the identity of the client certificate of a TLS connection to a request context.
+// In subject mode, the entity of the certificate is added to the subject, replacing the entities sent
+// by the caller. In authorize mode, the entity must be allowed the caller action on the requested resource.
+func (c *CertIdentity) Apply(state *tls.ConnectionState, reqCtx *adsapi.RequestContext) error {
+	principal := c.mapper.PeerPrincipal(state)
+	if principal == nil {
+		if c.mapper.Required() || c.mapper.Mode() == assertion.CertIdentityModeAuthorize {
+			return errors.New(errors.Unauthorized, "no identity in client certificate")
+		}
+		return nil
+	}
+
+	if c.mapper.Mode() == assertion.CertIdentityModeAuthorize {
+		callerCtx := adsapi.RequestContext{
+			Subject:     &adsapi.Subject{Principals: []*adsapi.Principal{principal}},
+			ServiceName: reqCtx.ServiceName,
+			Resource:    reqCtx.Resource,
+			Action:      c.mapper.CallerAction(),
+			Attributes:  reqCtx.Attributes,
+		}
+		allowed, _, err := c.evaluator.IsAllowedByPolicies(callerCtx)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errors.Errorf(errors.Forbidden, "%s %q is not allowed to query resource %q of service %q",
+				principal.Type, principal.Name, reqCtx.Resource, reqCtx.ServiceName)
+		}
+		return nil
+	}
+
+	if reqCtx.Subject == nil {
+		reqCtx.Subject = &adsapi.Subject{}
+	}
+	principals := []*adsapi.Principal{}
+	for _, p := range reqCtx.Subject.Principals {
+		if p != nil && p.Type == adsapi.PRINCIPAL_TYPE_ENTITY && !c.mapper.KeepRequestEntities() {
+			continue
+		}
+		principals = append(principals, p)
+	}
+	reqCtx.Subject.Principals = append(principals, principal)
+	return nil
+}
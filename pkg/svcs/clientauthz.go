@@ -0,0 +1,133 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package svcs
+
+import (
+	"crypto/tls"
+
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Operations of the authorization decision service which are granted to clients
+const (
+	ClientOperationCheck     = "check"     // is-allowed
+	ClientOperationEnumerate = "enumerate" // all-granted-roles and all-granted-permissions
+	ClientOperationDiagnose  = "diagnose"
+	ClientOperationDiscover  = "discover"
+	ClientOperationStatus    = "status" // function status, asserter status and function cache statistics
+	ClientOperationAdmin     = "admin"  // flush the function cache
+
+	// AllServices grants a client all services
+	AllServices = "*"
+)
+
+var clientOperations = map[string]bool{
+	ClientOperationCheck:     true,
+	ClientOperationEnumerate: true,
+	ClientOperationDiagnose:  true,
+	ClientOperationDiscover:  true,
+	ClientOperationStatus:    true,
+	ClientOperationAdmin:     true,
+}
+
+// ClientAuthz authenticates callers of the authorization decision service, and authorizes
+// them with the permissions of the clients they are identified as
+type ClientAuthz struct {
+	*Authenticator
+	clients []*cfg.ADSClientConfig
+}
+
+// NewClientAuthz creates the client authorization of the authorization decision service
+func NewClientAuthz(conf *cfg.ADSAuthzConfig) (*ClientAuthz, error) {
+	if conf == nil {
+		conf = &cfg.ADSAuthzConfig{}
+	}
+	authenticator, err := NewAuthenticator(conf.Asserters, conf.ClientCertIdentity)
+	if err != nil {
+		return nil, err
+	}
+	if !authenticator.Configured() {
+		log.Warn("No asserter or client certificate identity is configured, all authorization requests will be rejected.")
+	}
+	for _, client := range conf.Clients {
+		if len(client.Principals) == 0 {
+			return nil, errors.Errorf(errors.ConfigError, "no principal is configured for client %q", client.Name)
+		}
+		for _, op := range client.Operations {
+			if !clientOperations[op] {
+				return nil, errors.Errorf(errors.ConfigError, "unknown operation %q of client %q", op, client.Name)
+			}
+		}
+	}
+	return &ClientAuthz{Authenticator: authenticator, clients: conf.Clients}, nil
+}
+
+// serviceOperation tells whether an operation is granted per service, status and admin operations aren't
+func serviceOperation(operation string) bool {
+	return operation != ClientOperationStatus && operation != ClientOperationAdmin
+}
+
+func clientAllows(client *cfg.ADSClientConfig, operation string, serviceName string) bool {
+	allowed := len(client.Operations) == 0 && operation == ClientOperationCheck
+	for _, op := range client.Operations {
+		if op == operation {
+			allowed = true
+			break
+		}
+	}
+	if !allowed || !serviceOperation(operation) {
+		return allowed
+	}
+	for _, service := range client.Services {
+		if service == AllServices || service == serviceName {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize checks whether any client the caller is identified as may perform an operation on a service
+func (c *ClientAuthz) Authorize(caller *Caller, operation string, serviceName string) error {
+	known := false
+	for _, client := range c.clients {
+		matched := false
+		for _, p := range client.Principals {
+			if caller.HasPrincipal(p) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		if clientAllows(client, operation, serviceName) {
+			return nil
+		}
+		known = true
+	}
+	if !known {
+		return errors.New(errors.Forbidden, "caller is not a client of the authorization decision service")
+	}
+	if operation == ClientOperationStatus {
+		return errors.New(errors.Forbidden, "client is not allowed to read status")
+	}
+	if operation == ClientOperationAdmin {
+		return errors.New(errors.Forbidden, "client is not allowed to administer the authorization decision service")
+	}
+	return errors.Errorf(errors.Forbidden, "client is not allowed to %s service %q", operation, serviceName)
+}
+
+// AuthorizeRequest authenticates the caller with the value of the Authorization header and the client
+// certificate of the TLS connection, and authorizes the operation on a service
+func (c *ClientAuthz) AuthorizeRequest(authorization string, state *tls.ConnectionState, operation string, serviceName string) error {
+	token, tokenType := ParseAuthorization(authorization)
+	caller, err := c.Authenticate(token, tokenType, state)
+	if err != nil {
+		return err
+	}
+	return c.Authorize(caller, operation, serviceName)
+}
@@ -8,6 +8,12 @@ const (
 	PolicyMgmtPath = "/policy-mgmt/v1/"
 	// PolicyAtzPath is the prefix for ads rest service
 	PolicyAtzPath = "/authz-check/v1/"
+	// MetricsPath is the path of the Prometheus metrics of ads and pms
+	MetricsPath = "/metrics"
+	// HealthzPath is the liveness endpoint of ads and pms
+	HealthzPath = "/healthz"
+	// ReadyzPath is the readiness endpoint of ads and pms
+	ReadyzPath = "/readyz"
 	// Header to store asserted pincipals
 	PrincipalsHeader = "Speedle-Principals"
 )
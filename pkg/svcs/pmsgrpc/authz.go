@@ -0,0 +1,148 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsgrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"path"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/health"
+	"github.com/teramoby/speedle-plus/pkg/logging"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsgrpc/pb"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsimpl"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// operation returns the resource and action of the policy management operation of a gRPC method
+func operation(method string, req interface{}) (string, string) {
+	switch in := req.(type) {
+	case *pb.Function:
+		return pmsimpl.FunctionResource(in.Name), pmsimpl.ActionCreate
+	case *pb.FunctionQueryRequest:
+		if method == "DeleteFunctions" {
+			return pmsimpl.FunctionResource(in.Name), pmsimpl.ActionDelete
+		}
+		return pmsimpl.FunctionResource(in.Name), pmsimpl.ActionRead
+	case *pb.ServiceRequest:
+		return pmsimpl.ServiceResource(in.Name), pmsimpl.ActionCreate
+	case *pb.ServiceQueryRequest:
+		if method == "DeleteServices" {
+			return pmsimpl.ServiceResource(in.Name), pmsimpl.ActionDelete
+		}
+		return pmsimpl.ServiceResource(in.Name), pmsimpl.ActionRead
+	case *pb.PolicyRequest:
+		return pmsimpl.PolicyResource(in.ServiceName), pmsimpl.ActionCreate
+	case *pb.PolicyQueryRequest:
+		if method == "DeletePolicies" {
+			return pmsimpl.PolicyResource(in.ServiceName), pmsimpl.ActionDelete
+		}
+		return pmsimpl.PolicyResource(in.ServiceName), pmsimpl.ActionRead
+	case *pb.RolePolicyRequest:
+		return pmsimpl.RolePolicyResource(in.ServiceName), pmsimpl.ActionCreate
+	case *pb.RolePolicyQueryRequest:
+		if method == "DeleteRolePolicies" {
+			return pmsimpl.RolePolicyResource(in.ServiceName), pmsimpl.ActionDelete
+		}
+		return pmsimpl.RolePolicyResource(in.ServiceName), pmsimpl.ActionRead
+	case *pb.ServiceOwnersRequest:
+		if method == "AddServiceOwners" {
+			return pmsimpl.OwnersResource(in.ServiceName), pmsimpl.ActionCreate
+		}
+		return pmsimpl.OwnersResource(in.ServiceName), pmsimpl.ActionRead
+	case *pb.ServiceOwnerRequest:
+		// RemoveServiceOwner
+		return pmsimpl.OwnersResource(in.ServiceName), pmsimpl.ActionDelete
+	case *pb.Empty:
+		// ListPolicyCounts
+		return pmsimpl.ServiceResource(""), pmsimpl.ActionRead
+	case *pb.DiscoverRequestsRequest:
+		return pmsimpl.DiscoverResource(in.ServiceName), pmsimpl.ActionRead
+	case *pb.ResetRequestsRequest:
+		return pmsimpl.DiscoverResource(in.ServiceName), pmsimpl.ActionDelete
+	case *pb.DiscoverPoliciesRequest:
+		return pmsimpl.DiscoverResource(in.ServiceName), pmsimpl.ActionRead
+	case *pb.ChangeRequest:
+		// CreateChangeRequest and UpdateChangeRequest
+		return pmsimpl.ChangeRequestResource(in.ServiceName), pmsimpl.ActionCreate
+	case *pb.ChangeRequestQueryRequest:
+		return pmsimpl.ChangeRequestResource(in.ServiceName), pmsimpl.ActionRead
+	case *pb.ChangeRequestActionRequest:
+		switch method {
+		case "SubmitChangeRequest", "CloseChangeRequest", "CommentChangeRequest":
+			return pmsimpl.ChangeRequestResource(in.ServiceName), pmsimpl.ActionCreate
+		case "ApproveChangeRequest", "RejectChangeRequest":
+			return pmsimpl.ChangeRequestResource(in.ServiceName), pmsimpl.ActionApprove
+		case "PublishChangeRequest":
+			return pmsimpl.ChangeRequestResource(in.ServiceName), pmsimpl.ActionPublish
+		}
+		// DiffChangeRequest
+		return pmsimpl.ChangeRequestResource(in.ServiceName), pmsimpl.ActionRead
+	}
+	return "", ""
+}
+
+// callerOperation checks whether a gRPC method is allowed for all authenticated callers, which is only
+// querying the services owned by the caller
+func callerOperation(method string, req interface{}) bool {
+	in, ok := req.(*pb.ServiceQueryRequest)
+	return ok && method == "QueryServices" && in.Owned && len(in.Name) == 0 && len(in.Owner) == 0
+}
+
+// authorization returns the token and the token type in the authorization metadata
+func authorization(ctx context.Context) (string, string) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get("authorization")) == 0 {
+		return "", ""
+	}
+	return svcs.ParseAuthorization(md.Get("authorization")[0])
+}
+
+// NewAuthzInterceptor returns a unary interceptor which authenticates the caller and authorizes
+// the policy management operation before the request is handled
+func NewAuthzInterceptor(authorizer *pmsimpl.Authorizer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if health.IsGRPCHealthMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		method := path.Base(info.FullMethod)
+		var state *tls.ConnectionState
+		if p, ok := peer.FromContext(ctx); ok {
+			if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+				state = &tlsInfo.State
+			}
+		}
+		token, tokenType := authorization(ctx)
+		caller, err := authorizer.Authenticate(token, tokenType, state)
+		if err != nil {
+			// Audit log
+			logging.WriteSimpleFailedAuditLog("[gRPC]Authenticate", method, err.Error())
+			return nil, toGRPCStatus(err)
+		}
+
+		resource, action := operation(method, req)
+		switch {
+		case callerOperation(method, req):
+			// the caller may query the services it owns without permission
+		case len(resource) == 0:
+			err = errors.Errorf(errors.Forbidden, "operation %s is not allowed", method)
+		default:
+			err = authorizer.Authorize(caller, resource, action)
+		}
+		if err != nil {
+			// Audit log
+			logging.WriteFailedAuditLog("[gRPC]Authorize", log.Fields{"principals": caller.Principals, "resource": resource, "action": action}, err.Error())
+			return nil, toGRPCStatus(err)
+		}
+		return handler(pmsimpl.NewCallerContext(ctx, caller), req)
+	}
+}
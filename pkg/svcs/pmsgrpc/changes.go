@@ -0,0 +1,257 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsgrpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/logging"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsgrpc/pb"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsimpl"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type changeRequestServiceImpl struct {
+	changeRequests *pmsimpl.ChangeRequests
+}
+
+// NewChangeRequestServiceImpl initializes the gRPC service of the change request workflow
+func NewChangeRequestServiceImpl(changeRequests *pmsimpl.ChangeRequests) *changeRequestServiceImpl {
+	return &changeRequestServiceImpl{changeRequests: changeRequests}
+}
+
+// callerIdentity returns the principal of the caller as author or reviewer of change requests. Without
+// authorization, the caller is the user passed in the principals metadata.
+func callerIdentity(ctx context.Context) string {
+	if caller := pmsimpl.CallerFromContext(ctx); caller != nil {
+		return pmsimpl.CallerIdentity(caller)
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if users := md.Get(strings.ToLower(svcs.PrincipalsHeader)); len(users) > 0 && len(users[0]) > 0 {
+			return "user:" + users[0]
+		}
+	}
+	return ""
+}
+
+func convertRPCComments(rpcComments []*pb.ChangeRequestComment) []*pms.ChangeRequestComment {
+	var ret []*pms.ChangeRequestComment
+	for _, c := range rpcComments {
+		ret = append(ret, &pms.ChangeRequestComment{Author: c.Author, Text: c.Text, Time: c.Time})
+	}
+	return ret
+}
+
+func convertMetaComments(comments []*pms.ChangeRequestComment) []*pb.ChangeRequestComment {
+	var ret []*pb.ChangeRequestComment
+	for _, c := range comments {
+		ret = append(ret, &pb.ChangeRequestComment{Author: c.Author, Text: c.Text, Time: c.Time})
+	}
+	return ret
+}
+
+func convertRPCChangeRequest(rpcChangeRequest *pb.ChangeRequest) (*pms.ChangeRequest, error) {
+	ret := pms.ChangeRequest{
+		ID:                 rpcChangeRequest.Id,
+		ServiceName:        rpcChangeRequest.ServiceName,
+		Title:              rpcChangeRequest.Title,
+		Description:        rpcChangeRequest.Description,
+		Author:             rpcChangeRequest.Author,
+		Status:             rpcChangeRequest.Status,
+		DeletePolicies:     rpcChangeRequest.DeletePolicies,
+		DeleteRolePolicies: rpcChangeRequest.DeleteRolePolicies,
+		Approvals:          convertRPCComments(rpcChangeRequest.Approvals),
+		Comments:           convertRPCComments(rpcChangeRequest.Comments),
+		Metadata:           rpcChangeRequest.Metadata,
+	}
+	for _, rpcPolicy := range rpcChangeRequest.AddPolicies {
+		policy, err := convertRPCPolicy(rpcPolicy)
+		if err != nil {
+			return nil, err
+		}
+		ret.AddPolicies = append(ret.AddPolicies, policy)
+	}
+	for _, rolePolicy := range rpcChangeRequest.AddRolePolicies {
+		ret.AddRolePolicies = append(ret.AddRolePolicies, convertRPCRolePolicy(rolePolicy))
+	}
+	return &ret, nil
+}
+
+func convertMetaChangeRequest(changeRequest *pms.ChangeRequest) *pb.ChangeRequest {
+	ret := pb.ChangeRequest{
+		Id:                 changeRequest.ID,
+		ServiceName:        changeRequest.ServiceName,
+		Title:              changeRequest.Title,
+		Description:        changeRequest.Description,
+		Author:             changeRequest.Author,
+		Status:             changeRequest.Status,
+		DeletePolicies:     changeRequest.DeletePolicies,
+		DeleteRolePolicies: changeRequest.DeleteRolePolicies,
+		Approvals:          convertMetaComments(changeRequest.Approvals),
+		Comments:           convertMetaComments(changeRequest.Comments),
+		Metadata:           changeRequest.Metadata,
+	}
+	for _, policy := range changeRequest.AddPolicies {
+		ret.AddPolicies = append(ret.AddPolicies, convertMetaPolicy(policy))
+	}
+	for _, rolePolicy := range changeRequest.AddRolePolicies {
+		ret.AddRolePolicies = append(ret.AddRolePolicies, convertMetaRolePolicy(rolePolicy))
+	}
+	return &ret
+}
+
+func convertMetaChangeRequestDiff(diff *pmsimpl.ChangeRequestDiff) *pb.ChangeRequestDiff {
+	ret := pb.ChangeRequestDiff{Conflicts: diff.Conflicts}
+	for _, policy := range diff.AddedPolicies {
+		ret.AddedPolicies = append(ret.AddedPolicies, convertMetaPolicy(policy))
+	}
+	for _, policy := range diff.RemovedPolicies {
+		ret.RemovedPolicies = append(ret.RemovedPolicies, convertMetaPolicy(policy))
+	}
+	for _, rolePolicy := range diff.AddedRolePolicies {
+		ret.AddedRolePolicies = append(ret.AddedRolePolicies, convertMetaRolePolicy(rolePolicy))
+	}
+	for _, rolePolicy := range diff.RemovedRolePolicies {
+		ret.RemovedRolePolicies = append(ret.RemovedRolePolicies, convertMetaRolePolicy(rolePolicy))
+	}
+	return &ret
+}
+
+func (impl *changeRequestServiceImpl) CreateChangeRequest(ctx context.Context, in *pb.ChangeRequest) (*pb.ChangeRequest, error) {
+	if len(in.ServiceName) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "service name is not passed")
+	}
+	changeRequest, err := convertRPCChangeRequest(in)
+	if err == nil {
+		changeRequest, err = impl.changeRequests.Create(changeRequest, callerIdentity(ctx))
+	}
+	if err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]CreateChangeRequest", in, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
+	// Audit log
+	logging.WriteSimpleSucceededAuditLog("[gRPC]CreateChangeRequest", changeRequest, nil)
+	return convertMetaChangeRequest(changeRequest), nil
+}
+
+func (impl *changeRequestServiceImpl) UpdateChangeRequest(ctx context.Context, in *pb.ChangeRequest) (*pb.ChangeRequest, error) {
+	if len(in.ServiceName) == 0 || len(in.Id) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "service name or change request ID is not passed")
+	}
+	changeRequest, err := convertRPCChangeRequest(in)
+	if err == nil {
+		changeRequest, err = impl.changeRequests.Update(changeRequest, callerIdentity(ctx))
+	}
+	if err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]UpdateChangeRequest", in, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
+	// Audit log
+	logging.WriteSimpleSucceededAuditLog("[gRPC]UpdateChangeRequest", changeRequest, nil)
+	return convertMetaChangeRequest(changeRequest), nil
+}
+
+func (impl *changeRequestServiceImpl) QueryChangeRequests(ctx context.Context, in *pb.ChangeRequestQueryRequest) (*pb.ChangeRequestQueryResponse, error) {
+	if len(in.ServiceName) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "service name is not passed")
+	}
+	var changeRequests []*pms.ChangeRequest
+	var err error
+	if len(in.Id) > 0 {
+		var changeRequest *pms.ChangeRequest
+		if changeRequest, err = impl.changeRequests.Get(in.ServiceName, in.Id); err == nil {
+			changeRequests = append(changeRequests, changeRequest)
+		}
+	} else {
+		changeRequests, err = impl.changeRequests.List(in.ServiceName, in.Status)
+	}
+	if err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]QueryChangeRequests", in, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
+	// Audit log
+	logging.WriteSimpleSucceededAuditLog("[gRPC]QueryChangeRequests", in, nil)
+	ret := pb.ChangeRequestQueryResponse{}
+	for _, changeRequest := range changeRequests {
+		ret.ChangeRequests = append(ret.ChangeRequests, convertMetaChangeRequest(changeRequest))
+	}
+	return &ret, nil
+}
+
+func (impl *changeRequestServiceImpl) DiffChangeRequest(ctx context.Context, in *pb.ChangeRequestActionRequest) (*pb.ChangeRequestDiff, error) {
+	diff, err := impl.changeRequests.Diff(in.ServiceName, in.Id)
+	if err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]DiffChangeRequest", in, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
+	// Audit log
+	logging.WriteSimpleSucceededAuditLog("[gRPC]DiffChangeRequest", in, nil)
+	return convertMetaChangeRequestDiff(diff), nil
+}
+
+// act performs an action of the change request workflow
+func (impl *changeRequestServiceImpl) act(ctx context.Context, name string, in *pb.ChangeRequestActionRequest,
+	action func(serviceName, id, caller, text string) (*pms.ChangeRequest, error)) (*pb.ChangeRequest, error) {
+	if len(in.ServiceName) == 0 || len(in.Id) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "service name or change request ID is not passed")
+	}
+	caller := callerIdentity(ctx)
+	ctxFields := log.Fields{"service": in.ServiceName, "changeRequest": in.Id, "caller": caller}
+	changeRequest, err := action(in.ServiceName, in.Id, caller, in.Text)
+	if err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]"+name, ctxFields, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
+	// Audit log
+	logging.WriteSimpleSucceededAuditLog("[gRPC]"+name, ctxFields, nil)
+	return convertMetaChangeRequest(changeRequest), nil
+}
+
+func (impl *changeRequestServiceImpl) CommentChangeRequest(ctx context.Context, in *pb.ChangeRequestActionRequest) (*pb.ChangeRequest, error) {
+	return impl.act(ctx, "CommentChangeRequest", in, impl.changeRequests.Comment)
+}
+
+func (impl *changeRequestServiceImpl) SubmitChangeRequest(ctx context.Context, in *pb.ChangeRequestActionRequest) (*pb.ChangeRequest, error) {
+	return impl.act(ctx, "SubmitChangeRequest", in, func(serviceName, id, caller, _ string) (*pms.ChangeRequest, error) {
+		return impl.changeRequests.Submit(serviceName, id, caller)
+	})
+}
+
+func (impl *changeRequestServiceImpl) ApproveChangeRequest(ctx context.Context, in *pb.ChangeRequestActionRequest) (*pb.ChangeRequest, error) {
+	return impl.act(ctx, "ApproveChangeRequest", in, impl.changeRequests.Approve)
+}
+
+func (impl *changeRequestServiceImpl) RejectChangeRequest(ctx context.Context, in *pb.ChangeRequestActionRequest) (*pb.ChangeRequest, error) {
+	return impl.act(ctx, "RejectChangeRequest", in, impl.changeRequests.Reject)
+}
+
+func (impl *changeRequestServiceImpl) CloseChangeRequest(ctx context.Context, in *pb.ChangeRequestActionRequest) (*pb.ChangeRequest, error) {
+	return impl.act(ctx, "CloseChangeRequest", in, func(serviceName, id, caller, _ string) (*pms.ChangeRequest, error) {
+		return impl.changeRequests.Close(serviceName, id, caller)
+	})
+}
+
+func (impl *changeRequestServiceImpl) PublishChangeRequest(ctx context.Context, in *pb.ChangeRequestActionRequest) (*pb.ChangeRequest, error) {
+	return impl.act(ctx, "PublishChangeRequest", in, func(serviceName, id, caller, _ string) (*pms.ChangeRequest, error) {
+		return impl.changeRequests.Publish(serviceName, id, caller)
+	})
+}
@@ -4,6 +4,7 @@
 package pmsgrpc
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"google.golang.org/grpc/codes"
@@ -20,23 +21,32 @@ import (
 	"github.com/teramoby/speedle-plus/api/pms"
 
 	"strings"
+	"time"
 
 	"github.com/teramoby/speedle-plus/pkg/logging"
 )
 
 type serviceImpl struct {
-	policyStore pms.PolicyStoreManager
+	policyStore    pms.PolicyStoreManager
+	changeRequests *pmsimpl.ChangeRequests
 }
 
 // NewServiceImpl initializes a new PMS GRPC instance
 func NewServiceImpl(ps pms.PolicyStoreManager) *serviceImpl {
+	return NewServiceImplWithChangeRequests(ps, nil)
+}
+
+// NewServiceImplWithChangeRequests initializes a new PMS GRPC instance, policies of services protected by the
+// change request workflow are only changed by publishing change requests
+func NewServiceImplWithChangeRequests(ps pms.PolicyStoreManager, changeRequests *pmsimpl.ChangeRequests) *serviceImpl {
 	return &serviceImpl{
-		policyStore: ps,
+		policyStore:    ps,
+		changeRequests: changeRequests,
 	}
 }
 
-func convertRPCFunction(rpcFunction *pb.Function) *pms.Function {
-	return &pms.Function{
+func convertRPCFunction(rpcFunction *pb.Function) (*pms.Function, error) {
+	ret := pms.Function{
 		Name:           rpcFunction.Name,
 		Description:    rpcFunction.Description,
 		FuncURL:        rpcFunction.FuncUrl,
@@ -44,7 +54,27 @@ func convertRPCFunction(rpcFunction *pb.Function) *pms.Function {
 		CA:             rpcFunction.Ca,
 		ResultCachable: rpcFunction.ResultCachable,
 		ResultTTL:      rpcFunction.ResultTTL,
+		CacheSize:      int(rpcFunction.CacheSize),
+		ErrorTTL:       rpcFunction.ErrorTTL,
+	}
+	if resilience := rpcFunction.Resilience; resilience != nil {
+		ret.Resilience = &pms.FunctionResilience{
+			Timeout:          resilience.Timeout,
+			Retries:          int(resilience.Retries),
+			RetryBackoff:     resilience.RetryBackoff,
+			FailureThreshold: int(resilience.FailureThreshold),
+			OpenDuration:     resilience.OpenDuration,
+		}
+		if fallback := resilience.Fallback; fallback != nil {
+			ret.Resilience.Fallback = &pms.FunctionFallback{Type: fallback.Type, Error: fallback.Error}
+			if len(fallback.Value) > 0 {
+				if err := json.Unmarshal([]byte(fallback.Value), &ret.Resilience.Fallback.Value); err != nil {
+					return nil, errors.Wrap(err, errors.InvalidRequest, "invalid fallback value of function")
+				}
+			}
+		}
 	}
+	return &ret, nil
 }
 
 func convertMetaFunction(function *pms.Function) *pb.Function {
@@ -56,13 +86,35 @@ func convertMetaFunction(function *pms.Function) *pb.Function {
 		Ca:             function.CA,
 		ResultCachable: function.ResultCachable,
 		ResultTTL:      function.ResultTTL,
+		CacheSize:      int64(function.CacheSize),
+		ErrorTTL:       function.ErrorTTL,
+	}
+	if resilience := function.Resilience; resilience != nil {
+		ret.Resilience = &pb.FunctionResilience{
+			Timeout:          resilience.Timeout,
+			Retries:          int32(resilience.Retries),
+			RetryBackoff:     resilience.RetryBackoff,
+			FailureThreshold: int32(resilience.FailureThreshold),
+			OpenDuration:     resilience.OpenDuration,
+		}
+		if fallback := resilience.Fallback; fallback != nil {
+			ret.Resilience.Fallback = &pb.FunctionFallback{Type: fallback.Type, Error: fallback.Error}
+			if fallback.Value != nil {
+				value, _ := json.Marshal(fallback.Value)
+				ret.Resilience.Fallback.Value = string(value)
+			}
+		}
 	}
 	return &ret
 }
 
 func convertRPCServiceRequest(rpcService *pb.ServiceRequest) *pms.Service {
 	ret := pms.Service{
-		Name: rpcService.Name,
+		Name:          rpcService.Name,
+		Owners:        rpcService.Owners,
+		DefaultEffect: rpcService.DefaultEffect,
+		FailureMode:   rpcService.FailureMode,
+		Mode:          rpcService.Mode,
 	}
 	switch rpcService.Type {
 	case pb.ServiceType_APPLICATION:
@@ -105,11 +157,12 @@ func convertRPCRolePolicy(rpcPolicy *pb.RolePolicy) *pms.RolePolicy {
 	return &ret
 }
 
-func convertRPCPolicy(rpcPolicy *pb.Policy) *pms.Policy {
+func convertRPCPolicy(rpcPolicy *pb.Policy) (*pms.Policy, error) {
 	ret := pms.Policy{
 		ID:        rpcPolicy.Id,
 		Name:      rpcPolicy.Name,
 		Condition: rpcPolicy.Condition,
+		Disabled:  rpcPolicy.Disabled,
 	}
 	ret.Principals = convertRPCPrincipals(rpcPolicy.Principals)
 	switch rpcPolicy.Effect {
@@ -120,14 +173,40 @@ func convertRPCPolicy(rpcPolicy *pb.Policy) *pms.Policy {
 		ret.Effect = pms.Deny
 		break
 	}
+	var err error
+	if ret.NotBefore, err = convertRPCTime(rpcPolicy.NotBefore); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidRequest, "invalid notBefore of policy")
+	}
+	if ret.NotAfter, err = convertRPCTime(rpcPolicy.NotAfter); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidRequest, "invalid notAfter of policy")
+	}
 	if rpcPolicy.Permissions == nil {
-		return &ret
+		return &ret, nil
 	}
 
 	for _, permission := range rpcPolicy.Permissions {
 		ret.Permissions = append(ret.Permissions, convertRPCPermission(permission))
 	}
-	return &ret
+	return &ret, nil
+}
+
+// convertRPCTime parses a RFC 3339 time, an empty string is no time
+func convertRPCTime(value string) (*time.Time, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func convertMetaTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
 }
 
 func convertRPCPermission(perm *pb.Policy_Permission) *pms.Permission {
@@ -141,7 +220,11 @@ func convertRPCPermission(perm *pb.Policy_Permission) *pms.Permission {
 
 func convertMetaService(service *pms.Service) *pb.Service {
 	ret := pb.Service{
-		Name: service.Name,
+		Name:          service.Name,
+		Owners:        service.Owners,
+		DefaultEffect: service.DefaultEffect,
+		FailureMode:   service.FailureMode,
+		Mode:          service.Mode,
 	}
 	switch service.Type {
 	case pms.TypeApplication:
@@ -201,6 +284,9 @@ func convertMetaPolicy(policy *pms.Policy) *pb.Policy {
 		Id:        policy.ID,
 		Name:      policy.Name,
 		Condition: policy.Condition,
+		Disabled:  policy.Disabled,
+		NotBefore: convertMetaTime(policy.NotBefore),
+		NotAfter:  convertMetaTime(policy.NotAfter),
 	}
 	ret.Principals = convertMetaPrincipals(policy.Principals)
 	switch policy.Effect {
@@ -243,19 +329,30 @@ func toGRPCStatus(err error) error {
 		return status.Error(codes.NotFound, msg)
 	case errors.EntityAlreadyExists:
 		return status.Error(codes.AlreadyExists, msg)
+	case errors.Conflict:
+		return status.Error(codes.FailedPrecondition, msg)
 	case errors.SerializationError:
 		return status.Error(codes.Internal, msg)
 	case errors.ExceedLimit:
 		return status.Error(codes.ResourceExhausted, msg)
 	case errors.InvalidRequest:
 		return status.Error(codes.InvalidArgument, msg)
+	case errors.Unauthorized:
+		return status.Error(codes.Unauthenticated, msg)
+	case errors.Forbidden:
+		return status.Error(codes.PermissionDenied, msg)
 	default:
 		return status.Error(codes.Unknown, msg)
 	}
 }
 
 func (impl *serviceImpl) CreateFunction(ctx context.Context, in *pb.Function) (*pb.Function, error) {
-	function := convertRPCFunction(in)
+	function, err := convertRPCFunction(in)
+	if err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]CreateFunction", in, err.Error())
+		return nil, toGRPCStatus(err)
+	}
 	if function, err := impl.policyStore.CreateFunction(function); err != nil {
 		// Audit log
 		logging.WriteSimpleFailedAuditLog("[gRPC]CreateFunction", function, err.Error())
@@ -375,6 +472,13 @@ func (impl *serviceImpl) CreateService(ctx context.Context, in *pb.ServiceReques
 }
 
 func (impl *serviceImpl) QueryServices(ctx context.Context, in *pb.ServiceQueryRequest) (*pb.ServiceQueryResponse, error) {
+	owners, err := pmsimpl.OwnerFilter(ctx, in.Owned, in.Owner)
+	if err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]QueryServices", in.Name, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
 	var ss []*pms.Service
 	if len(in.Name) == 0 {
 		// Get all services
@@ -393,6 +497,9 @@ func (impl *serviceImpl) QueryServices(ctx context.Context, in *pb.ServiceQueryR
 		}
 		ss = append(ss, svc)
 	}
+	if owners != nil {
+		ss = pmsimpl.OwnedServices(ss, owners)
+	}
 	ret := pb.ServiceQueryResponse{
 		Services: make([]*pb.Service, 0),
 	}
@@ -431,6 +538,62 @@ func (impl *serviceImpl) DeleteServices(ctx context.Context, in *pb.ServiceQuery
 	return &pb.Empty{}, nil
 }
 
+func (impl *serviceImpl) GetServiceOwners(ctx context.Context, in *pb.ServiceOwnersRequest) (*pb.ServiceOwners, error) {
+	if len(in.ServiceName) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "service name is not passed")
+	}
+	service, err := impl.policyStore.GetService(in.ServiceName)
+	if err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]GetServiceOwners", in.ServiceName, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
+	// Audit log
+	logging.WriteSimpleSucceededAuditLog("[gRPC]GetServiceOwners", in.ServiceName, nil)
+	return &pb.ServiceOwners{Owners: service.Owners}, nil
+}
+
+func (impl *serviceImpl) AddServiceOwners(ctx context.Context, in *pb.ServiceOwnersRequest) (*pb.ServiceOwners, error) {
+	if len(in.ServiceName) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "service name is not passed")
+	}
+	ctxFields := map[string]interface{}{
+		"service": in.ServiceName,
+		"owners":  in.Owners,
+	}
+	owners, err := pmsimpl.AddServiceOwners(in.ServiceName, in.Owners, impl.policyStore)
+	if err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]AddServiceOwners", ctxFields, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
+	// Audit log
+	logging.WriteSimpleSucceededAuditLog("[gRPC]AddServiceOwners", ctxFields, nil)
+	return &pb.ServiceOwners{Owners: owners}, nil
+}
+
+func (impl *serviceImpl) RemoveServiceOwner(ctx context.Context, in *pb.ServiceOwnerRequest) (*pb.ServiceOwners, error) {
+	if len(in.ServiceName) == 0 || len(in.Owner) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "service name or owner is not passed")
+	}
+	ctxFields := map[string]interface{}{
+		"service": in.ServiceName,
+		"owner":   in.Owner,
+	}
+	owners, err := pmsimpl.RemoveServiceOwner(in.ServiceName, in.Owner, impl.policyStore)
+	if err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]RemoveServiceOwner", ctxFields, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
+	// Audit log
+	logging.WriteSimpleSucceededAuditLog("[gRPC]RemoveServiceOwner", ctxFields, nil)
+	return &pb.ServiceOwners{Owners: owners}, nil
+}
+
 func (impl *serviceImpl) CreatePolicy(ctx context.Context, in *pb.PolicyRequest) (*pb.Policy, error) {
 	if len(in.ServiceName) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "service name is not passed")
@@ -445,7 +608,18 @@ func (impl *serviceImpl) CreatePolicy(ctx context.Context, in *pb.PolicyRequest)
 		"policy":      in.Policy,
 	}
 
-	metaPolicy := convertRPCPolicy(in.Policy)
+	if err := impl.changeRequests.CheckDirectChange(in.ServiceName); err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]CreatePolicy", ctxFields, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
+	metaPolicy, err := convertRPCPolicy(in.Policy)
+	if err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]CreatePolicy", ctxFields, err.Error())
+		return nil, toGRPCStatus(err)
+	}
 
 	if err := pmsimpl.CheckPolicy(in.ServiceName, metaPolicy, impl.policyStore); err != nil {
 		// Audit log
@@ -534,6 +708,12 @@ func (impl *serviceImpl) DeletePolicies(ctx context.Context, in *pb.PolicyQueryR
 		"policyId":    in.PolicyID,
 	}
 
+	if err := impl.changeRequests.CheckDirectChange(in.ServiceName); err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]DeletePolicies", ctxFields, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
 	if len(in.PolicyID) == 0 {
 		if err := impl.policyStore.DeletePolicies(in.ServiceName); err != nil {
 			// Audit log
@@ -568,6 +748,12 @@ func (impl *serviceImpl) CreateRolePolicy(ctx context.Context, in *pb.RolePolicy
 		"rolePolicy":  in.RolePolicy,
 	}
 
+	if err := impl.changeRequests.CheckDirectChange(in.ServiceName); err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]CreateRolePolicy", ctxFields, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
 	metaRolePolicy := convertRPCRolePolicy(in.RolePolicy)
 
 	if err := pmsimpl.CheckRolePolicy(in.ServiceName, metaRolePolicy, impl.policyStore); err != nil {
@@ -655,6 +841,12 @@ func (impl *serviceImpl) DeleteRolePolicies(ctx context.Context, in *pb.RolePoli
 		"rolePolicyId": in.RolePolicyID,
 	}
 
+	if err := impl.changeRequests.CheckDirectChange(in.ServiceName); err != nil {
+		// Audit log
+		logging.WriteSimpleFailedAuditLog("[gRPC]DeleteRolePolicies", ctxFields, err.Error())
+		return nil, toGRPCStatus(err)
+	}
+
 	if len(in.RolePolicyID) == 0 {
 		if err := impl.policyStore.DeleteRolePolicies(in.ServiceName); err != nil {
 			// Audit log
@@ -0,0 +1,238 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsgrpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/store"
+	_ "github.com/teramoby/speedle-plus/pkg/store/file"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsgrpc/pb"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsimpl"
+)
+
+// startTestServer serves the policy manager of an empty file store in process, and returns a client of it
+func startTestServer(t *testing.T) (pb.PolicyManagerClient, func()) {
+	dir, err := ioutil.TempDir("", "pmsgrpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storeFile := filepath.Join(dir, "store.json")
+	if err := ioutil.WriteFile(storeFile, []byte(`{"services":[]}`), 0600); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	ps, err := store.NewStore(cfg.StorageTypeFile, map[string]interface{}{"FileLocation": storeFile})
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	pb.RegisterPolicyManagerServer(server, NewServiceImpl(ps))
+	go server.Serve(lis)
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		server.Stop()
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return pb.NewPolicyManagerClient(conn), func() {
+		conn.Close()
+		server.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestPolicyRoundTrip(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+	ctx := context.Background()
+
+	if _, err := client.CreateService(ctx, &pb.ServiceRequest{Name: "books"}); err != nil {
+		t.Fatal(err)
+	}
+	policy := &pb.Policy{
+		Name:        "p1",
+		Effect:      pb.Effect_GRANT,
+		Permissions: []*pb.Policy_Permission{{Resource: "/books", Actions: []string{"read"}}},
+		Principals:  []*pb.AndPrincipals{{Principals: []string{"user:alice"}}},
+		Disabled:    true,
+		NotBefore:   "2026-01-01T00:00:00Z",
+		NotAfter:    "2027-01-01T00:00:00Z",
+	}
+	created, err := client.CreatePolicy(ctx, &pb.PolicyRequest{ServiceName: "books", Policy: policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy.Id = created.Id
+	if !proto.Equal(created, policy) {
+		t.Errorf("created policy %v, want %v", created, policy)
+	}
+	resp, err := client.QueryPolicies(ctx, &pb.PolicyQueryRequest{ServiceName: "books", PolicyID: created.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Policies) != 1 || !proto.Equal(resp.Policies[0], policy) {
+		t.Errorf("queried policies %v, want %v", resp.Policies, policy)
+	}
+
+	policy.NotBefore = "tomorrow"
+	if _, err := client.CreatePolicy(ctx, &pb.PolicyRequest{ServiceName: "books", Policy: policy}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("creating a policy with invalid notBefore should fail, but %v", err)
+	}
+}
+
+func TestServiceOwners(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+	ctx := context.Background()
+
+	created, err := client.CreateService(ctx, &pb.ServiceRequest{Name: "books", Owners: []string{"user:alice"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(created.Owners, []string{"user:alice"}) {
+		t.Errorf("created service has owners %v", created.Owners)
+	}
+	if _, err := client.CreateService(ctx, &pb.ServiceRequest{Name: "music"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateService(ctx, &pb.ServiceRequest{Name: "films", Owners: []string{"role:admin"}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("creating a service owned by a role should fail, but %v", err)
+	}
+
+	owners, err := client.AddServiceOwners(ctx, &pb.ServiceOwnersRequest{ServiceName: "books", Owners: []string{"group:team-a"}})
+	if err != nil || !reflect.DeepEqual(owners.Owners, []string{"user:alice", "group:team-a"}) {
+		t.Errorf("adding owners returns %v, %v", owners, err)
+	}
+	owners, err = client.RemoveServiceOwner(ctx, &pb.ServiceOwnerRequest{ServiceName: "books", Owner: "user:alice"})
+	if err != nil || !reflect.DeepEqual(owners.Owners, []string{"group:team-a"}) {
+		t.Errorf("removing an owner returns %v, %v", owners, err)
+	}
+	owners, err = client.GetServiceOwners(ctx, &pb.ServiceOwnersRequest{ServiceName: "books"})
+	if err != nil || !reflect.DeepEqual(owners.Owners, []string{"group:team-a"}) {
+		t.Errorf("getting owners returns %v, %v", owners, err)
+	}
+
+	resp, err := client.QueryServices(ctx, &pb.ServiceQueryRequest{Owner: "group:team-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Services) != 1 || resp.Services[0].Name != "books" || !reflect.DeepEqual(resp.Services[0].Owners, []string{"group:team-a"}) {
+		t.Errorf("services owned by team-a are %v", resp.Services)
+	}
+	// the caller is unknown without authorization
+	if _, err := client.QueryServices(ctx, &pb.ServiceQueryRequest{Owned: true}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("querying owned services without authorization should fail, but %v", err)
+	}
+}
+
+func TestOwnerOperations(t *testing.T) {
+	testCases := []struct {
+		method   string
+		req      interface{}
+		resource string
+		action   string
+		anyone   bool
+	}{
+		{"GetServiceOwners", &pb.ServiceOwnersRequest{ServiceName: "books"}, "/service/books/owners", pmsimpl.ActionRead, false},
+		{"AddServiceOwners", &pb.ServiceOwnersRequest{ServiceName: "books"}, "/service/books/owners", pmsimpl.ActionCreate, false},
+		{"RemoveServiceOwner", &pb.ServiceOwnerRequest{ServiceName: "books"}, "/service/books/owners", pmsimpl.ActionDelete, false},
+		{"QueryServices", &pb.ServiceQueryRequest{Owned: true}, "/service", pmsimpl.ActionRead, true},
+		{"QueryServices", &pb.ServiceQueryRequest{Owned: true, Owner: "user:bob"}, "/service", pmsimpl.ActionRead, false},
+		{"QueryServices", &pb.ServiceQueryRequest{}, "/service", pmsimpl.ActionRead, false},
+	}
+	for _, tc := range testCases {
+		resource, action := operation(tc.method, tc.req)
+		if resource != tc.resource || action != tc.action || callerOperation(tc.method, tc.req) != tc.anyone {
+			t.Errorf("%s %v: got %s %s %v, want %s %s %v", tc.method, tc.req, resource, action, callerOperation(tc.method, tc.req), tc.resource, tc.action, tc.anyone)
+		}
+	}
+}
+
+func TestServiceDecisionRoundTrip(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+	ctx := context.Background()
+
+	created, err := client.CreateService(ctx, &pb.ServiceRequest{Name: "books", DefaultEffect: "grant", FailureMode: "closed", Mode: "shadow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.DefaultEffect != "grant" || created.FailureMode != "closed" || created.Mode != "shadow" {
+		t.Errorf("created service %v", created)
+	}
+	resp, err := client.QueryServices(ctx, &pb.ServiceQueryRequest{Name: "books"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Services) != 1 || resp.Services[0].DefaultEffect != "grant" || resp.Services[0].FailureMode != "closed" || resp.Services[0].Mode != "shadow" {
+		t.Errorf("queried services %v", resp.Services)
+	}
+
+	if _, err := client.CreateService(ctx, &pb.ServiceRequest{Name: "music", FailureMode: "ajar"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("creating a service with an invalid failure mode should fail, but %v", err)
+	}
+	if _, err := client.CreateService(ctx, &pb.ServiceRequest{Name: "music", Mode: "learn"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("creating a service with an invalid mode should fail, but %v", err)
+	}
+}
+
+func TestFunctionRoundTrip(t *testing.T) {
+	client, stop := startTestServer(t)
+	defer stop()
+	ctx := context.Background()
+
+	function := &pb.Function{
+		Name:      "isManager",
+		FuncUrl:   "http://localhost:8080/funcs/isManager",
+		CacheSize: 100,
+		ErrorTTL:  10,
+		Resilience: &pb.FunctionResilience{
+			Timeout:          500,
+			Retries:          2,
+			RetryBackoff:     100,
+			FailureThreshold: 5,
+			OpenDuration:     30,
+			Fallback:         &pb.FunctionFallback{Type: "value", Value: "false"},
+		},
+	}
+	created, err := client.CreateFunction(ctx, function)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(created, function) {
+		t.Errorf("created function %v, want %v", created, function)
+	}
+	resp, err := client.QueryFunctions(ctx, &pb.FunctionQueryRequest{Name: "isManager"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Functions) != 1 || !proto.Equal(resp.Functions[0], function) {
+		t.Errorf("queried functions %v, want %v", resp.Functions, function)
+	}
+
+	function.Name = "isOwner"
+	function.Resilience.Fallback.Value = "{false"
+	if _, err := client.CreateFunction(ctx, function); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("creating a function with an invalid fallback value should fail, but %v", err)
+	}
+}
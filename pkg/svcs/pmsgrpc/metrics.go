@@ -0,0 +1,27 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsgrpc
+
+import (
+	"context"
+	"path"
+
+	"github.com/teramoby/speedle-plus/pkg/health"
+	"github.com/teramoby/speedle-plus/pkg/metrics"
+
+	"google.golang.org/grpc"
+)
+
+// NewMetricsInterceptor returns a unary interceptor which counts the policy management operations,
+// it should be the first interceptor so that rejected requests are counted as well
+func NewMetricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if health.IsGRPCHealthMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		resp, err := handler(ctx, req)
+		metrics.ObservePMSOperation(path.Base(info.FullMethod), metrics.ProtocolGRPC, err != nil)
+		return resp, err
+	}
+}
@@ -0,0 +1,896 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: changes.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ChangeRequestComment struct {
+	Author               string   `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	Text                 string   `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Time                 string   `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChangeRequestComment) Reset()         { *m = ChangeRequestComment{} }
+func (m *ChangeRequestComment) String() string { return proto.CompactTextString(m) }
+func (*ChangeRequestComment) ProtoMessage()    {}
+func (*ChangeRequestComment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b16a38c6509bd894, []int{0}
+}
+
+func (m *ChangeRequestComment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeRequestComment.Unmarshal(m, b)
+}
+func (m *ChangeRequestComment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeRequestComment.Marshal(b, m, deterministic)
+}
+func (m *ChangeRequestComment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeRequestComment.Merge(m, src)
+}
+func (m *ChangeRequestComment) XXX_Size() int {
+	return xxx_messageInfo_ChangeRequestComment.Size(m)
+}
+func (m *ChangeRequestComment) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeRequestComment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeRequestComment proto.InternalMessageInfo
+
+func (m *ChangeRequestComment) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *ChangeRequestComment) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+func (m *ChangeRequestComment) GetTime() string {
+	if m != nil {
+		return m.Time
+	}
+	return ""
+}
+
+type ChangeRequest struct {
+	Id                   string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceName          string                  `protobuf:"bytes,2,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Title                string                  `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description          string                  `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Author               string                  `protobuf:"bytes,5,opt,name=author,proto3" json:"author,omitempty"`
+	Status               string                  `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	AddPolicies          []*Policy               `protobuf:"bytes,7,rep,name=addPolicies,proto3" json:"addPolicies,omitempty"`
+	DeletePolicies       []string                `protobuf:"bytes,8,rep,name=deletePolicies,proto3" json:"deletePolicies,omitempty"`
+	AddRolePolicies      []*RolePolicy           `protobuf:"bytes,9,rep,name=addRolePolicies,proto3" json:"addRolePolicies,omitempty"`
+	DeleteRolePolicies   []string                `protobuf:"bytes,10,rep,name=deleteRolePolicies,proto3" json:"deleteRolePolicies,omitempty"`
+	Approvals            []*ChangeRequestComment `protobuf:"bytes,11,rep,name=approvals,proto3" json:"approvals,omitempty"`
+	Comments             []*ChangeRequestComment `protobuf:"bytes,12,rep,name=comments,proto3" json:"comments,omitempty"`
+	Metadata             map[string]string       `protobuf:"bytes,13,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *ChangeRequest) Reset()         { *m = ChangeRequest{} }
+func (m *ChangeRequest) String() string { return proto.CompactTextString(m) }
+func (*ChangeRequest) ProtoMessage()    {}
+func (*ChangeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b16a38c6509bd894, []int{1}
+}
+
+func (m *ChangeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeRequest.Unmarshal(m, b)
+}
+func (m *ChangeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeRequest.Marshal(b, m, deterministic)
+}
+func (m *ChangeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeRequest.Merge(m, src)
+}
+func (m *ChangeRequest) XXX_Size() int {
+	return xxx_messageInfo_ChangeRequest.Size(m)
+}
+func (m *ChangeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeRequest proto.InternalMessageInfo
+
+func (m *ChangeRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ChangeRequest) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
+	}
+	return ""
+}
+
+func (m *ChangeRequest) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *ChangeRequest) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *ChangeRequest) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *ChangeRequest) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *ChangeRequest) GetAddPolicies() []*Policy {
+	if m != nil {
+		return m.AddPolicies
+	}
+	return nil
+}
+
+func (m *ChangeRequest) GetDeletePolicies() []string {
+	if m != nil {
+		return m.DeletePolicies
+	}
+	return nil
+}
+
+func (m *ChangeRequest) GetAddRolePolicies() []*RolePolicy {
+	if m != nil {
+		return m.AddRolePolicies
+	}
+	return nil
+}
+
+func (m *ChangeRequest) GetDeleteRolePolicies() []string {
+	if m != nil {
+		return m.DeleteRolePolicies
+	}
+	return nil
+}
+
+func (m *ChangeRequest) GetApprovals() []*ChangeRequestComment {
+	if m != nil {
+		return m.Approvals
+	}
+	return nil
+}
+
+func (m *ChangeRequest) GetComments() []*ChangeRequestComment {
+	if m != nil {
+		return m.Comments
+	}
+	return nil
+}
+
+func (m *ChangeRequest) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type ChangeRequestQueryRequest struct {
+	ServiceName          string   `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Status               string   `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChangeRequestQueryRequest) Reset()         { *m = ChangeRequestQueryRequest{} }
+func (m *ChangeRequestQueryRequest) String() string { return proto.CompactTextString(m) }
+func (*ChangeRequestQueryRequest) ProtoMessage()    {}
+func (*ChangeRequestQueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b16a38c6509bd894, []int{2}
+}
+
+func (m *ChangeRequestQueryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeRequestQueryRequest.Unmarshal(m, b)
+}
+func (m *ChangeRequestQueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeRequestQueryRequest.Marshal(b, m, deterministic)
+}
+func (m *ChangeRequestQueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeRequestQueryRequest.Merge(m, src)
+}
+func (m *ChangeRequestQueryRequest) XXX_Size() int {
+	return xxx_messageInfo_ChangeRequestQueryRequest.Size(m)
+}
+func (m *ChangeRequestQueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeRequestQueryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeRequestQueryRequest proto.InternalMessageInfo
+
+func (m *ChangeRequestQueryRequest) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
+	}
+	return ""
+}
+
+func (m *ChangeRequestQueryRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ChangeRequestQueryRequest) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+type ChangeRequestQueryResponse struct {
+	ChangeRequests       []*ChangeRequest `protobuf:"bytes,1,rep,name=changeRequests,proto3" json:"changeRequests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ChangeRequestQueryResponse) Reset()         { *m = ChangeRequestQueryResponse{} }
+func (m *ChangeRequestQueryResponse) String() string { return proto.CompactTextString(m) }
+func (*ChangeRequestQueryResponse) ProtoMessage()    {}
+func (*ChangeRequestQueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b16a38c6509bd894, []int{3}
+}
+
+func (m *ChangeRequestQueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeRequestQueryResponse.Unmarshal(m, b)
+}
+func (m *ChangeRequestQueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeRequestQueryResponse.Marshal(b, m, deterministic)
+}
+func (m *ChangeRequestQueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeRequestQueryResponse.Merge(m, src)
+}
+func (m *ChangeRequestQueryResponse) XXX_Size() int {
+	return xxx_messageInfo_ChangeRequestQueryResponse.Size(m)
+}
+func (m *ChangeRequestQueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeRequestQueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeRequestQueryResponse proto.InternalMessageInfo
+
+func (m *ChangeRequestQueryResponse) GetChangeRequests() []*ChangeRequest {
+	if m != nil {
+		return m.ChangeRequests
+	}
+	return nil
+}
+
+type ChangeRequestActionRequest struct {
+	ServiceName          string   `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Text                 string   `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChangeRequestActionRequest) Reset()         { *m = ChangeRequestActionRequest{} }
+func (m *ChangeRequestActionRequest) String() string { return proto.CompactTextString(m) }
+func (*ChangeRequestActionRequest) ProtoMessage()    {}
+func (*ChangeRequestActionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b16a38c6509bd894, []int{4}
+}
+
+func (m *ChangeRequestActionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeRequestActionRequest.Unmarshal(m, b)
+}
+func (m *ChangeRequestActionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeRequestActionRequest.Marshal(b, m, deterministic)
+}
+func (m *ChangeRequestActionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeRequestActionRequest.Merge(m, src)
+}
+func (m *ChangeRequestActionRequest) XXX_Size() int {
+	return xxx_messageInfo_ChangeRequestActionRequest.Size(m)
+}
+func (m *ChangeRequestActionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeRequestActionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeRequestActionRequest proto.InternalMessageInfo
+
+func (m *ChangeRequestActionRequest) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
+	}
+	return ""
+}
+
+func (m *ChangeRequestActionRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ChangeRequestActionRequest) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+type ChangeRequestDiff struct {
+	AddedPolicies        []*Policy     `protobuf:"bytes,1,rep,name=addedPolicies,proto3" json:"addedPolicies,omitempty"`
+	RemovedPolicies      []*Policy     `protobuf:"bytes,2,rep,name=removedPolicies,proto3" json:"removedPolicies,omitempty"`
+	AddedRolePolicies    []*RolePolicy `protobuf:"bytes,3,rep,name=addedRolePolicies,proto3" json:"addedRolePolicies,omitempty"`
+	RemovedRolePolicies  []*RolePolicy `protobuf:"bytes,4,rep,name=removedRolePolicies,proto3" json:"removedRolePolicies,omitempty"`
+	Conflicts            []string      `protobuf:"bytes,5,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ChangeRequestDiff) Reset()         { *m = ChangeRequestDiff{} }
+func (m *ChangeRequestDiff) String() string { return proto.CompactTextString(m) }
+func (*ChangeRequestDiff) ProtoMessage()    {}
+func (*ChangeRequestDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b16a38c6509bd894, []int{5}
+}
+
+func (m *ChangeRequestDiff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeRequestDiff.Unmarshal(m, b)
+}
+func (m *ChangeRequestDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeRequestDiff.Marshal(b, m, deterministic)
+}
+func (m *ChangeRequestDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeRequestDiff.Merge(m, src)
+}
+func (m *ChangeRequestDiff) XXX_Size() int {
+	return xxx_messageInfo_ChangeRequestDiff.Size(m)
+}
+func (m *ChangeRequestDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeRequestDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeRequestDiff proto.InternalMessageInfo
+
+func (m *ChangeRequestDiff) GetAddedPolicies() []*Policy {
+	if m != nil {
+		return m.AddedPolicies
+	}
+	return nil
+}
+
+func (m *ChangeRequestDiff) GetRemovedPolicies() []*Policy {
+	if m != nil {
+		return m.RemovedPolicies
+	}
+	return nil
+}
+
+func (m *ChangeRequestDiff) GetAddedRolePolicies() []*RolePolicy {
+	if m != nil {
+		return m.AddedRolePolicies
+	}
+	return nil
+}
+
+func (m *ChangeRequestDiff) GetRemovedRolePolicies() []*RolePolicy {
+	if m != nil {
+		return m.RemovedRolePolicies
+	}
+	return nil
+}
+
+func (m *ChangeRequestDiff) GetConflicts() []string {
+	if m != nil {
+		return m.Conflicts
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ChangeRequestComment)(nil), "pb.ChangeRequestComment")
+	proto.RegisterType((*ChangeRequest)(nil), "pb.ChangeRequest")
+	proto.RegisterMapType((map[string]string)(nil), "pb.ChangeRequest.MetadataEntry")
+	proto.RegisterType((*ChangeRequestQueryRequest)(nil), "pb.ChangeRequestQueryRequest")
+	proto.RegisterType((*ChangeRequestQueryResponse)(nil), "pb.ChangeRequestQueryResponse")
+	proto.RegisterType((*ChangeRequestActionRequest)(nil), "pb.ChangeRequestActionRequest")
+	proto.RegisterType((*ChangeRequestDiff)(nil), "pb.ChangeRequestDiff")
+}
+
+func init() { proto.RegisterFile("changes.proto", fileDescriptor_b16a38c6509bd894) }
+
+var fileDescriptor_b16a38c6509bd894 = []byte{
+	// 650 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0xcd, 0x6e, 0xd3, 0x4c,
+	0x14, 0x6d, 0xec, 0x34, 0x5f, 0x73, 0xf3, 0x25, 0x25, 0x37, 0xa1, 0x32, 0x11, 0x3f, 0x91, 0x17,
+	0xa8, 0x0b, 0x14, 0xa1, 0x52, 0xa1, 0x42, 0x41, 0xa2, 0x0a, 0x2c, 0xa0, 0x14, 0x15, 0x23, 0xca,
+	0x7a, 0x62, 0xdf, 0xb6, 0x03, 0xfe, 0xc3, 0x33, 0x8e, 0xc8, 0x9e, 0x97, 0xe0, 0xb1, 0x78, 0x23,
+	0xe4, 0xb1, 0x9b, 0xf8, 0x0f, 0x21, 0x4a, 0x76, 0x9e, 0x73, 0xcf, 0x39, 0xf7, 0x4e, 0xe6, 0xcc,
+	0x04, 0xba, 0xf6, 0x25, 0xf3, 0x2f, 0x48, 0x4c, 0xc2, 0x28, 0x90, 0x01, 0x6a, 0xe1, 0x6c, 0xd4,
+	0x15, 0x14, 0xcd, 0xb9, 0x4d, 0x29, 0x64, 0x9e, 0xc1, 0x70, 0xaa, 0x38, 0x16, 0x7d, 0x8d, 0x49,
+	0xc8, 0x69, 0xe0, 0x79, 0xe4, 0x4b, 0xdc, 0x81, 0x16, 0x8b, 0xe5, 0x65, 0x10, 0x19, 0x8d, 0x71,
+	0x63, 0xb7, 0x6d, 0x65, 0x2b, 0x44, 0x68, 0x4a, 0xfa, 0x26, 0x0d, 0x4d, 0xa1, 0xea, 0x5b, 0x61,
+	0xdc, 0x23, 0x43, 0xcf, 0x30, 0xee, 0x91, 0xf9, 0xb3, 0x09, 0xdd, 0x82, 0x31, 0xf6, 0x40, 0xe3,
+	0x4e, 0xe6, 0xa6, 0x71, 0x07, 0xc7, 0xd0, 0xc9, 0x46, 0x79, 0xc7, 0x3c, 0xca, 0x0c, 0xf3, 0x10,
+	0x0e, 0x61, 0x53, 0x72, 0xe9, 0x5e, 0x19, 0xa7, 0x8b, 0x44, 0xe7, 0x90, 0xb0, 0x23, 0x1e, 0x4a,
+	0x1e, 0xf8, 0x46, 0x33, 0xd5, 0xe5, 0xa0, 0xdc, 0xec, 0x9b, 0x85, 0xd9, 0x77, 0xa0, 0x25, 0x24,
+	0x93, 0xb1, 0x30, 0x5a, 0x29, 0x9e, 0xae, 0xf0, 0x01, 0x74, 0x98, 0xe3, 0x9c, 0x06, 0x2e, 0xb7,
+	0x39, 0x09, 0xe3, 0xbf, 0xb1, 0xbe, 0xdb, 0xd9, 0x83, 0x49, 0x38, 0x9b, 0x28, 0x6c, 0x61, 0xe5,
+	0xcb, 0x78, 0x1f, 0x7a, 0x0e, 0xb9, 0x24, 0x69, 0x29, 0xd8, 0x1a, 0xeb, 0xbb, 0x6d, 0xab, 0x84,
+	0xe2, 0x01, 0x6c, 0x33, 0xc7, 0xb1, 0x02, 0x77, 0x45, 0x6c, 0x2b, 0xe7, 0x5e, 0xe2, 0xbc, 0xc4,
+	0x17, 0x56, 0x99, 0x86, 0x13, 0xc0, 0xd4, 0xab, 0x20, 0x06, 0xd5, 0xa5, 0xa6, 0x82, 0x8f, 0xa1,
+	0xcd, 0xc2, 0x30, 0x0a, 0xe6, 0xcc, 0x15, 0x46, 0x47, 0xf5, 0x30, 0x92, 0x1e, 0x75, 0x07, 0x6b,
+	0xad, 0xa8, 0xb8, 0x0f, 0x5b, 0x76, 0x8a, 0x0a, 0xe3, 0xff, 0x3f, 0xc8, 0x96, 0x4c, 0x3c, 0x84,
+	0x2d, 0x8f, 0x24, 0x73, 0x98, 0x64, 0x46, 0x57, 0xa9, 0xee, 0x55, 0x54, 0x93, 0x93, 0x8c, 0xf1,
+	0xca, 0x97, 0xd1, 0xc2, 0x5a, 0x0a, 0x46, 0x87, 0xd0, 0x2d, 0x94, 0xf0, 0x06, 0xe8, 0x5f, 0x68,
+	0x91, 0xc5, 0x22, 0xf9, 0x4c, 0x4e, 0x7d, 0xce, 0xdc, 0xf8, 0x2a, 0x11, 0xe9, 0xe2, 0xa9, 0x76,
+	0xd0, 0x30, 0x09, 0x6e, 0x15, 0xba, 0xbc, 0x8f, 0x29, 0x5a, 0x64, 0xdf, 0xe5, 0x38, 0x35, 0xaa,
+	0x71, 0x4a, 0x03, 0xa8, 0x2d, 0x03, 0xb8, 0x8a, 0x83, 0x9e, 0x8f, 0x83, 0xf9, 0x09, 0x46, 0x75,
+	0x6d, 0x44, 0x18, 0xf8, 0x82, 0xf0, 0x09, 0xf4, 0xec, 0x7c, 0x55, 0x18, 0x0d, 0xf5, 0x23, 0xf4,
+	0x2b, 0x3f, 0x82, 0x55, 0x22, 0x9a, 0xb3, 0x92, 0xf1, 0x91, 0x9d, 0xc4, 0xf5, 0xfa, 0x1b, 0xb8,
+	0xba, 0x8b, 0xfa, 0xea, 0x2e, 0x9a, 0x3f, 0x34, 0xe8, 0x17, 0x9a, 0xbc, 0xe4, 0xe7, 0xe7, 0xf8,
+	0x10, 0xba, 0xcc, 0x71, 0x68, 0x95, 0xf1, 0x46, 0x25, 0xe3, 0x45, 0x02, 0xee, 0xc3, 0x76, 0x44,
+	0x5e, 0x30, 0xcf, 0x69, 0xb4, 0x8a, 0xa6, 0x4c, 0xc1, 0x67, 0xd0, 0x57, 0x36, 0x85, 0xe0, 0xea,
+	0xb5, 0xa9, 0xaf, 0x12, 0xf1, 0x05, 0x0c, 0x32, 0xc3, 0x82, 0xbe, 0x59, 0xab, 0xaf, 0xa3, 0xe2,
+	0x6d, 0x68, 0xdb, 0x81, 0x7f, 0xee, 0x72, 0x5b, 0x0a, 0x63, 0x53, 0x5d, 0x98, 0x15, 0xb0, 0xf7,
+	0xbd, 0x55, 0x7a, 0xec, 0x4e, 0x98, 0xcf, 0x2e, 0x28, 0xc2, 0xe7, 0x30, 0x98, 0x46, 0xc4, 0x24,
+	0x15, 0xaa, 0x58, 0x3d, 0xd2, 0x51, 0x15, 0x32, 0x37, 0x12, 0xf9, 0xc7, 0xd0, 0xb9, 0xb6, 0xfc,
+	0x0c, 0x06, 0x2a, 0x62, 0x05, 0x5c, 0xe0, 0x9d, 0x0a, 0x37, 0x9f, 0xf7, 0xd1, 0xdd, 0xdf, 0x95,
+	0xd3, 0x9c, 0x9a, 0x1b, 0xf8, 0x16, 0xfa, 0xc9, 0xe1, 0x17, 0x87, 0xaa, 0xca, 0x0a, 0x29, 0x1c,
+	0xdd, 0xac, 0xd4, 0x13, 0x0f, 0x73, 0x03, 0x8f, 0x61, 0x98, 0xbd, 0x05, 0x7f, 0x67, 0x58, 0xbb,
+	0xe5, 0x37, 0x30, 0xf8, 0x10, 0xcf, 0x3c, 0xbe, 0x0e, 0xaf, 0x63, 0x18, 0x1e, 0xa9, 0x27, 0x8d,
+	0xd6, 0x33, 0x98, 0x45, 0x9f, 0xc9, 0x5e, 0xc7, 0x60, 0xaf, 0x01, 0xa7, 0x6e, 0x20, 0x68, 0x3d,
+	0x7b, 0x3c, 0x8d, 0x67, 0x2e, 0x17, 0x97, 0xff, 0x6e, 0x36, 0x6b, 0xa9, 0x7f, 0xfe, 0x47, 0xbf,
+	0x06, 0x00, 0x25, 0x2d, 0xf6, 0xa8, 0x1d, 0x08, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ChangeRequestManagerClient is the client API for ChangeRequestManager service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ChangeRequestManagerClient interface {
+	CreateChangeRequest(ctx context.Context, in *ChangeRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	UpdateChangeRequest(ctx context.Context, in *ChangeRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	QueryChangeRequests(ctx context.Context, in *ChangeRequestQueryRequest, opts ...grpc.CallOption) (*ChangeRequestQueryResponse, error)
+	DiffChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequestDiff, error)
+	CommentChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	SubmitChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	ApproveChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	RejectChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	CloseChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+	PublishChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error)
+}
+
+type changeRequestManagerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChangeRequestManagerClient(cc grpc.ClientConnInterface) ChangeRequestManagerClient {
+	return &changeRequestManagerClient{cc}
+}
+
+func (c *changeRequestManagerClient) CreateChangeRequest(ctx context.Context, in *ChangeRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/pb.ChangeRequestManager/CreateChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestManagerClient) UpdateChangeRequest(ctx context.Context, in *ChangeRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/pb.ChangeRequestManager/UpdateChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestManagerClient) QueryChangeRequests(ctx context.Context, in *ChangeRequestQueryRequest, opts ...grpc.CallOption) (*ChangeRequestQueryResponse, error) {
+	out := new(ChangeRequestQueryResponse)
+	err := c.cc.Invoke(ctx, "/pb.ChangeRequestManager/QueryChangeRequests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestManagerClient) DiffChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequestDiff, error) {
+	out := new(ChangeRequestDiff)
+	err := c.cc.Invoke(ctx, "/pb.ChangeRequestManager/DiffChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestManagerClient) CommentChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/pb.ChangeRequestManager/CommentChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestManagerClient) SubmitChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/pb.ChangeRequestManager/SubmitChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestManagerClient) ApproveChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/pb.ChangeRequestManager/ApproveChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestManagerClient) RejectChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/pb.ChangeRequestManager/RejectChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestManagerClient) CloseChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/pb.ChangeRequestManager/CloseChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *changeRequestManagerClient) PublishChangeRequest(ctx context.Context, in *ChangeRequestActionRequest, opts ...grpc.CallOption) (*ChangeRequest, error) {
+	out := new(ChangeRequest)
+	err := c.cc.Invoke(ctx, "/pb.ChangeRequestManager/PublishChangeRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChangeRequestManagerServer is the server API for ChangeRequestManager service.
+type ChangeRequestManagerServer interface {
+	CreateChangeRequest(context.Context, *ChangeRequest) (*ChangeRequest, error)
+	UpdateChangeRequest(context.Context, *ChangeRequest) (*ChangeRequest, error)
+	QueryChangeRequests(context.Context, *ChangeRequestQueryRequest) (*ChangeRequestQueryResponse, error)
+	DiffChangeRequest(context.Context, *ChangeRequestActionRequest) (*ChangeRequestDiff, error)
+	CommentChangeRequest(context.Context, *ChangeRequestActionRequest) (*ChangeRequest, error)
+	SubmitChangeRequest(context.Context, *ChangeRequestActionRequest) (*ChangeRequest, error)
+	ApproveChangeRequest(context.Context, *ChangeRequestActionRequest) (*ChangeRequest, error)
+	RejectChangeRequest(context.Context, *ChangeRequestActionRequest) (*ChangeRequest, error)
+	CloseChangeRequest(context.Context, *ChangeRequestActionRequest) (*ChangeRequest, error)
+	PublishChangeRequest(context.Context, *ChangeRequestActionRequest) (*ChangeRequest, error)
+}
+
+// UnimplementedChangeRequestManagerServer can be embedded to have forward compatible implementations.
+type UnimplementedChangeRequestManagerServer struct {
+}
+
+func (*UnimplementedChangeRequestManagerServer) CreateChangeRequest(ctx context.Context, req *ChangeRequest) (*ChangeRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateChangeRequest not implemented")
+}
+func (*UnimplementedChangeRequestManagerServer) UpdateChangeRequest(ctx context.Context, req *ChangeRequest) (*ChangeRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateChangeRequest not implemented")
+}
+func (*UnimplementedChangeRequestManagerServer) QueryChangeRequests(ctx context.Context, req *ChangeRequestQueryRequest) (*ChangeRequestQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryChangeRequests not implemented")
+}
+func (*UnimplementedChangeRequestManagerServer) DiffChangeRequest(ctx context.Context, req *ChangeRequestActionRequest) (*ChangeRequestDiff, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffChangeRequest not implemented")
+}
+func (*UnimplementedChangeRequestManagerServer) CommentChangeRequest(ctx context.Context, req *ChangeRequestActionRequest) (*ChangeRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommentChangeRequest not implemented")
+}
+func (*UnimplementedChangeRequestManagerServer) SubmitChangeRequest(ctx context.Context, req *ChangeRequestActionRequest) (*ChangeRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitChangeRequest not implemented")
+}
+func (*UnimplementedChangeRequestManagerServer) ApproveChangeRequest(ctx context.Context, req *ChangeRequestActionRequest) (*ChangeRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveChangeRequest not implemented")
+}
+func (*UnimplementedChangeRequestManagerServer) RejectChangeRequest(ctx context.Context, req *ChangeRequestActionRequest) (*ChangeRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectChangeRequest not implemented")
+}
+func (*UnimplementedChangeRequestManagerServer) CloseChangeRequest(ctx context.Context, req *ChangeRequestActionRequest) (*ChangeRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseChangeRequest not implemented")
+}
+func (*UnimplementedChangeRequestManagerServer) PublishChangeRequest(ctx context.Context, req *ChangeRequestActionRequest) (*ChangeRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishChangeRequest not implemented")
+}
+
+func RegisterChangeRequestManagerServer(s *grpc.Server, srv ChangeRequestManagerServer) {
+	s.RegisterService(&_ChangeRequestManager_serviceDesc, srv)
+}
+
+func _ChangeRequestManager_CreateChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestManagerServer).CreateChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.ChangeRequestManager/CreateChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestManagerServer).CreateChangeRequest(ctx, req.(*ChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestManager_UpdateChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestManagerServer).UpdateChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.ChangeRequestManager/UpdateChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestManagerServer).UpdateChangeRequest(ctx, req.(*ChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestManager_QueryChangeRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeRequestQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestManagerServer).QueryChangeRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.ChangeRequestManager/QueryChangeRequests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestManagerServer).QueryChangeRequests(ctx, req.(*ChangeRequestQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestManager_DiffChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeRequestActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestManagerServer).DiffChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.ChangeRequestManager/DiffChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestManagerServer).DiffChangeRequest(ctx, req.(*ChangeRequestActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestManager_CommentChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeRequestActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestManagerServer).CommentChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.ChangeRequestManager/CommentChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestManagerServer).CommentChangeRequest(ctx, req.(*ChangeRequestActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestManager_SubmitChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeRequestActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestManagerServer).SubmitChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.ChangeRequestManager/SubmitChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestManagerServer).SubmitChangeRequest(ctx, req.(*ChangeRequestActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestManager_ApproveChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeRequestActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestManagerServer).ApproveChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.ChangeRequestManager/ApproveChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestManagerServer).ApproveChangeRequest(ctx, req.(*ChangeRequestActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestManager_RejectChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeRequestActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestManagerServer).RejectChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.ChangeRequestManager/RejectChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestManagerServer).RejectChangeRequest(ctx, req.(*ChangeRequestActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestManager_CloseChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeRequestActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestManagerServer).CloseChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.ChangeRequestManager/CloseChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestManagerServer).CloseChangeRequest(ctx, req.(*ChangeRequestActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChangeRequestManager_PublishChangeRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeRequestActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChangeRequestManagerServer).PublishChangeRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.ChangeRequestManager/PublishChangeRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChangeRequestManagerServer).PublishChangeRequest(ctx, req.(*ChangeRequestActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ChangeRequestManager_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.ChangeRequestManager",
+	HandlerType: (*ChangeRequestManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateChangeRequest",
+			Handler:    _ChangeRequestManager_CreateChangeRequest_Handler,
+		},
+		{
+			MethodName: "UpdateChangeRequest",
+			Handler:    _ChangeRequestManager_UpdateChangeRequest_Handler,
+		},
+		{
+			MethodName: "QueryChangeRequests",
+			Handler:    _ChangeRequestManager_QueryChangeRequests_Handler,
+		},
+		{
+			MethodName: "DiffChangeRequest",
+			Handler:    _ChangeRequestManager_DiffChangeRequest_Handler,
+		},
+		{
+			MethodName: "CommentChangeRequest",
+			Handler:    _ChangeRequestManager_CommentChangeRequest_Handler,
+		},
+		{
+			MethodName: "SubmitChangeRequest",
+			Handler:    _ChangeRequestManager_SubmitChangeRequest_Handler,
+		},
+		{
+			MethodName: "ApproveChangeRequest",
+			Handler:    _ChangeRequestManager_ApproveChangeRequest_Handler,
+		},
+		{
+			MethodName: "RejectChangeRequest",
+			Handler:    _ChangeRequestManager_RejectChangeRequest_Handler,
+		},
+		{
+			MethodName: "CloseChangeRequest",
+			Handler:    _ChangeRequestManager_CloseChangeRequest_Handler,
+		},
+		{
+			MethodName: "PublishChangeRequest",
+			Handler:    _ChangeRequestManager_PublishChangeRequest_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "changes.proto",
+}
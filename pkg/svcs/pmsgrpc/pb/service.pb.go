@@ -1,51 +1,16 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // source: service.proto
 
-/*
-Package pb is a generated protocol buffer package.
-
-It is generated from these files:
-	service.proto
-
-It has these top-level messages:
-	DiscoverRequestsRequest
-	Principal
-	Subject
-	ContextRequest
-	DiscoverRequestsResponse
-	ResetRequestsRequest
-	ResetRequestsResponse
-	DiscoverPoliciesRequest
-	DiscoverPoliciesResponse
-	Function
-	FunctionQueryRequest
-	FunctionQueryResponse
-	AndPrincipals
-	Empty
-	ServiceRequest
-	PolicyRequest
-	ServiceQueryResponse
-	ServiceQueryRequest
-	PolicyQueryRequest
-	PolicyQueryResponse
-	Policy
-	RolePolicyRequest
-	RolePolicyQueryRequest
-	RolePolicyQueryResponse
-	RolePolicy
-	Service
-	PolicyAndRolePolicyCounts
-	PolicyCountsMap
-*/
 package pb
 
-import proto "github.com/golang/protobuf/proto"
-import fmt "fmt"
-import math "math"
-
 import (
-	context "golang.org/x/net/context"
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -57,7 +22,7 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type Effect int32
 
@@ -70,6 +35,7 @@ var Effect_name = map[int32]string{
 	0: "GRANT",
 	1: "DENY",
 }
+
 var Effect_value = map[string]int32{
 	"GRANT": 0,
 	"DENY":  1,
@@ -78,7 +44,10 @@ var Effect_value = map[string]int32{
 func (x Effect) String() string {
 	return proto.EnumName(Effect_name, int32(x))
 }
-func (Effect) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (Effect) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{0}
+}
 
 type ServiceType int32
 
@@ -91,6 +60,7 @@ var ServiceType_name = map[int32]string{
 	0: "APPLICATION",
 	1: "K8S_CLUSTER",
 }
+
 var ServiceType_value = map[string]int32{
 	"APPLICATION": 0,
 	"K8S_CLUSTER": 1,
@@ -99,18 +69,44 @@ var ServiceType_value = map[string]int32{
 func (x ServiceType) String() string {
 	return proto.EnumName(ServiceType_name, int32(x))
 }
-func (ServiceType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (ServiceType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{1}
+}
 
 type DiscoverRequestsRequest struct {
-	ServiceName string `protobuf:"bytes,1,opt,name=serviceName" json:"serviceName,omitempty"`
-	Last        bool   `protobuf:"varint,2,opt,name=last" json:"last,omitempty"`
-	Revision    int64  `protobuf:"varint,3,opt,name=revision" json:"revision,omitempty"`
+	ServiceName          string   `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Last                 bool     `protobuf:"varint,2,opt,name=last,proto3" json:"last,omitempty"`
+	Revision             int64    `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiscoverRequestsRequest) Reset()         { *m = DiscoverRequestsRequest{} }
+func (m *DiscoverRequestsRequest) String() string { return proto.CompactTextString(m) }
+func (*DiscoverRequestsRequest) ProtoMessage()    {}
+func (*DiscoverRequestsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{0}
 }
 
-func (m *DiscoverRequestsRequest) Reset()                    { *m = DiscoverRequestsRequest{} }
-func (m *DiscoverRequestsRequest) String() string            { return proto.CompactTextString(m) }
-func (*DiscoverRequestsRequest) ProtoMessage()               {}
-func (*DiscoverRequestsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+func (m *DiscoverRequestsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiscoverRequestsRequest.Unmarshal(m, b)
+}
+func (m *DiscoverRequestsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiscoverRequestsRequest.Marshal(b, m, deterministic)
+}
+func (m *DiscoverRequestsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiscoverRequestsRequest.Merge(m, src)
+}
+func (m *DiscoverRequestsRequest) XXX_Size() int {
+	return xxx_messageInfo_DiscoverRequestsRequest.Size(m)
+}
+func (m *DiscoverRequestsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiscoverRequestsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiscoverRequestsRequest proto.InternalMessageInfo
 
 func (m *DiscoverRequestsRequest) GetServiceName() string {
 	if m != nil {
@@ -134,15 +130,38 @@ func (m *DiscoverRequestsRequest) GetRevision() int64 {
 }
 
 type Principal struct {
-	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
-	Name string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
-	Idd  string `protobuf:"bytes,3,opt,name=idd" json:"idd,omitempty"`
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Idd                  string   `protobuf:"bytes,3,opt,name=idd,proto3" json:"idd,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Principal) Reset()         { *m = Principal{} }
+func (m *Principal) String() string { return proto.CompactTextString(m) }
+func (*Principal) ProtoMessage()    {}
+func (*Principal) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{1}
+}
+
+func (m *Principal) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Principal.Unmarshal(m, b)
+}
+func (m *Principal) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Principal.Marshal(b, m, deterministic)
+}
+func (m *Principal) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Principal.Merge(m, src)
+}
+func (m *Principal) XXX_Size() int {
+	return xxx_messageInfo_Principal.Size(m)
+}
+func (m *Principal) XXX_DiscardUnknown() {
+	xxx_messageInfo_Principal.DiscardUnknown(m)
 }
 
-func (m *Principal) Reset()                    { *m = Principal{} }
-func (m *Principal) String() string            { return proto.CompactTextString(m) }
-func (*Principal) ProtoMessage()               {}
-func (*Principal) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+var xxx_messageInfo_Principal proto.InternalMessageInfo
 
 func (m *Principal) GetType() string {
 	if m != nil {
@@ -166,15 +185,38 @@ func (m *Principal) GetIdd() string {
 }
 
 type Subject struct {
-	Principals []*Principal `protobuf:"bytes,1,rep,name=principals" json:"principals,omitempty"`
-	TokenType  string       `protobuf:"bytes,2,opt,name=tokenType" json:"tokenType,omitempty"`
-	Token      string       `protobuf:"bytes,3,opt,name=token" json:"token,omitempty"`
+	Principals           []*Principal `protobuf:"bytes,1,rep,name=principals,proto3" json:"principals,omitempty"`
+	TokenType            string       `protobuf:"bytes,2,opt,name=tokenType,proto3" json:"tokenType,omitempty"`
+	Token                string       `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Subject) Reset()         { *m = Subject{} }
+func (m *Subject) String() string { return proto.CompactTextString(m) }
+func (*Subject) ProtoMessage()    {}
+func (*Subject) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{2}
+}
+
+func (m *Subject) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Subject.Unmarshal(m, b)
+}
+func (m *Subject) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Subject.Marshal(b, m, deterministic)
+}
+func (m *Subject) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Subject.Merge(m, src)
+}
+func (m *Subject) XXX_Size() int {
+	return xxx_messageInfo_Subject.Size(m)
+}
+func (m *Subject) XXX_DiscardUnknown() {
+	xxx_messageInfo_Subject.DiscardUnknown(m)
 }
 
-func (m *Subject) Reset()                    { *m = Subject{} }
-func (m *Subject) String() string            { return proto.CompactTextString(m) }
-func (*Subject) ProtoMessage()               {}
-func (*Subject) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+var xxx_messageInfo_Subject proto.InternalMessageInfo
 
 func (m *Subject) GetPrincipals() []*Principal {
 	if m != nil {
@@ -198,17 +240,40 @@ func (m *Subject) GetToken() string {
 }
 
 type ContextRequest struct {
-	Subject     *Subject          `protobuf:"bytes,1,opt,name=subject" json:"subject,omitempty"`
-	ServiceName string            `protobuf:"bytes,2,opt,name=serviceName" json:"serviceName,omitempty"`
-	Resource    string            `protobuf:"bytes,3,opt,name=resource" json:"resource,omitempty"`
-	Action      string            `protobuf:"bytes,4,opt,name=action" json:"action,omitempty"`
-	Attributes  map[string]string `protobuf:"bytes,5,rep,name=attributes" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Subject              *Subject          `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	ServiceName          string            `protobuf:"bytes,2,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Resource             string            `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	Action               string            `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Attributes           map[string]string `protobuf:"bytes,5,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ContextRequest) Reset()         { *m = ContextRequest{} }
+func (m *ContextRequest) String() string { return proto.CompactTextString(m) }
+func (*ContextRequest) ProtoMessage()    {}
+func (*ContextRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{3}
+}
+
+func (m *ContextRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContextRequest.Unmarshal(m, b)
+}
+func (m *ContextRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContextRequest.Marshal(b, m, deterministic)
+}
+func (m *ContextRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContextRequest.Merge(m, src)
+}
+func (m *ContextRequest) XXX_Size() int {
+	return xxx_messageInfo_ContextRequest.Size(m)
+}
+func (m *ContextRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContextRequest.DiscardUnknown(m)
 }
 
-func (m *ContextRequest) Reset()                    { *m = ContextRequest{} }
-func (m *ContextRequest) String() string            { return proto.CompactTextString(m) }
-func (*ContextRequest) ProtoMessage()               {}
-func (*ContextRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+var xxx_messageInfo_ContextRequest proto.InternalMessageInfo
 
 func (m *ContextRequest) GetSubject() *Subject {
 	if m != nil {
@@ -246,14 +311,37 @@ func (m *ContextRequest) GetAttributes() map[string]string {
 }
 
 type DiscoverRequestsResponse struct {
-	Requests []*ContextRequest `protobuf:"bytes,1,rep,name=requests" json:"requests,omitempty"`
-	Revision int64             `protobuf:"varint,2,opt,name=revision" json:"revision,omitempty"`
+	Requests             []*ContextRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	Revision             int64             `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DiscoverRequestsResponse) Reset()         { *m = DiscoverRequestsResponse{} }
+func (m *DiscoverRequestsResponse) String() string { return proto.CompactTextString(m) }
+func (*DiscoverRequestsResponse) ProtoMessage()    {}
+func (*DiscoverRequestsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{4}
+}
+
+func (m *DiscoverRequestsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiscoverRequestsResponse.Unmarshal(m, b)
+}
+func (m *DiscoverRequestsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiscoverRequestsResponse.Marshal(b, m, deterministic)
+}
+func (m *DiscoverRequestsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiscoverRequestsResponse.Merge(m, src)
+}
+func (m *DiscoverRequestsResponse) XXX_Size() int {
+	return xxx_messageInfo_DiscoverRequestsResponse.Size(m)
+}
+func (m *DiscoverRequestsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiscoverRequestsResponse.DiscardUnknown(m)
 }
 
-func (m *DiscoverRequestsResponse) Reset()                    { *m = DiscoverRequestsResponse{} }
-func (m *DiscoverRequestsResponse) String() string            { return proto.CompactTextString(m) }
-func (*DiscoverRequestsResponse) ProtoMessage()               {}
-func (*DiscoverRequestsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+var xxx_messageInfo_DiscoverRequestsResponse proto.InternalMessageInfo
 
 func (m *DiscoverRequestsResponse) GetRequests() []*ContextRequest {
 	if m != nil {
@@ -270,13 +358,36 @@ func (m *DiscoverRequestsResponse) GetRevision() int64 {
 }
 
 type ResetRequestsRequest struct {
-	ServiceName string `protobuf:"bytes,1,opt,name=serviceName" json:"serviceName,omitempty"`
+	ServiceName          string   `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResetRequestsRequest) Reset()         { *m = ResetRequestsRequest{} }
+func (m *ResetRequestsRequest) String() string { return proto.CompactTextString(m) }
+func (*ResetRequestsRequest) ProtoMessage()    {}
+func (*ResetRequestsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{5}
+}
+
+func (m *ResetRequestsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetRequestsRequest.Unmarshal(m, b)
+}
+func (m *ResetRequestsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetRequestsRequest.Marshal(b, m, deterministic)
+}
+func (m *ResetRequestsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetRequestsRequest.Merge(m, src)
+}
+func (m *ResetRequestsRequest) XXX_Size() int {
+	return xxx_messageInfo_ResetRequestsRequest.Size(m)
+}
+func (m *ResetRequestsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetRequestsRequest.DiscardUnknown(m)
 }
 
-func (m *ResetRequestsRequest) Reset()                    { *m = ResetRequestsRequest{} }
-func (m *ResetRequestsRequest) String() string            { return proto.CompactTextString(m) }
-func (*ResetRequestsRequest) ProtoMessage()               {}
-func (*ResetRequestsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+var xxx_messageInfo_ResetRequestsRequest proto.InternalMessageInfo
 
 func (m *ResetRequestsRequest) GetServiceName() string {
 	if m != nil {
@@ -286,24 +397,70 @@ func (m *ResetRequestsRequest) GetServiceName() string {
 }
 
 type ResetRequestsResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResetRequestsResponse) Reset()         { *m = ResetRequestsResponse{} }
+func (m *ResetRequestsResponse) String() string { return proto.CompactTextString(m) }
+func (*ResetRequestsResponse) ProtoMessage()    {}
+func (*ResetRequestsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{6}
+}
+
+func (m *ResetRequestsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetRequestsResponse.Unmarshal(m, b)
+}
+func (m *ResetRequestsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetRequestsResponse.Marshal(b, m, deterministic)
+}
+func (m *ResetRequestsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetRequestsResponse.Merge(m, src)
+}
+func (m *ResetRequestsResponse) XXX_Size() int {
+	return xxx_messageInfo_ResetRequestsResponse.Size(m)
+}
+func (m *ResetRequestsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetRequestsResponse.DiscardUnknown(m)
 }
 
-func (m *ResetRequestsResponse) Reset()                    { *m = ResetRequestsResponse{} }
-func (m *ResetRequestsResponse) String() string            { return proto.CompactTextString(m) }
-func (*ResetRequestsResponse) ProtoMessage()               {}
-func (*ResetRequestsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+var xxx_messageInfo_ResetRequestsResponse proto.InternalMessageInfo
 
 type DiscoverPoliciesRequest struct {
-	ServiceName   string `protobuf:"bytes,1,opt,name=serviceName" json:"serviceName,omitempty"`
-	PrincipalType string `protobuf:"bytes,2,opt,name=principalType" json:"principalType,omitempty"`
-	PrincipalName string `protobuf:"bytes,3,opt,name=principalName" json:"principalName,omitempty"`
-	PrincipalIdd  string `protobuf:"bytes,4,opt,name=principalIdd" json:"principalIdd,omitempty"`
+	ServiceName          string   `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	PrincipalType        string   `protobuf:"bytes,2,opt,name=principalType,proto3" json:"principalType,omitempty"`
+	PrincipalName        string   `protobuf:"bytes,3,opt,name=principalName,proto3" json:"principalName,omitempty"`
+	PrincipalIdd         string   `protobuf:"bytes,4,opt,name=principalIdd,proto3" json:"principalIdd,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DiscoverPoliciesRequest) Reset()                    { *m = DiscoverPoliciesRequest{} }
-func (m *DiscoverPoliciesRequest) String() string            { return proto.CompactTextString(m) }
-func (*DiscoverPoliciesRequest) ProtoMessage()               {}
-func (*DiscoverPoliciesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (m *DiscoverPoliciesRequest) Reset()         { *m = DiscoverPoliciesRequest{} }
+func (m *DiscoverPoliciesRequest) String() string { return proto.CompactTextString(m) }
+func (*DiscoverPoliciesRequest) ProtoMessage()    {}
+func (*DiscoverPoliciesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{7}
+}
+
+func (m *DiscoverPoliciesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiscoverPoliciesRequest.Unmarshal(m, b)
+}
+func (m *DiscoverPoliciesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiscoverPoliciesRequest.Marshal(b, m, deterministic)
+}
+func (m *DiscoverPoliciesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiscoverPoliciesRequest.Merge(m, src)
+}
+func (m *DiscoverPoliciesRequest) XXX_Size() int {
+	return xxx_messageInfo_DiscoverPoliciesRequest.Size(m)
+}
+func (m *DiscoverPoliciesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiscoverPoliciesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiscoverPoliciesRequest proto.InternalMessageInfo
 
 func (m *DiscoverPoliciesRequest) GetServiceName() string {
 	if m != nil {
@@ -334,14 +491,37 @@ func (m *DiscoverPoliciesRequest) GetPrincipalIdd() string {
 }
 
 type DiscoverPoliciesResponse struct {
-	Services []*Service `protobuf:"bytes,1,rep,name=services" json:"services,omitempty"`
-	Revision int64      `protobuf:"varint,2,opt,name=revision" json:"revision,omitempty"`
+	Services             []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	Revision             int64      `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *DiscoverPoliciesResponse) Reset()         { *m = DiscoverPoliciesResponse{} }
+func (m *DiscoverPoliciesResponse) String() string { return proto.CompactTextString(m) }
+func (*DiscoverPoliciesResponse) ProtoMessage()    {}
+func (*DiscoverPoliciesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{8}
 }
 
-func (m *DiscoverPoliciesResponse) Reset()                    { *m = DiscoverPoliciesResponse{} }
-func (m *DiscoverPoliciesResponse) String() string            { return proto.CompactTextString(m) }
-func (*DiscoverPoliciesResponse) ProtoMessage()               {}
-func (*DiscoverPoliciesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (m *DiscoverPoliciesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiscoverPoliciesResponse.Unmarshal(m, b)
+}
+func (m *DiscoverPoliciesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiscoverPoliciesResponse.Marshal(b, m, deterministic)
+}
+func (m *DiscoverPoliciesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiscoverPoliciesResponse.Merge(m, src)
+}
+func (m *DiscoverPoliciesResponse) XXX_Size() int {
+	return xxx_messageInfo_DiscoverPoliciesResponse.Size(m)
+}
+func (m *DiscoverPoliciesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiscoverPoliciesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiscoverPoliciesResponse proto.InternalMessageInfo
 
 func (m *DiscoverPoliciesResponse) GetServices() []*Service {
 	if m != nil {
@@ -358,19 +538,45 @@ func (m *DiscoverPoliciesResponse) GetRevision() int64 {
 }
 
 type Function struct {
-	Name           string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Description    string `protobuf:"bytes,2,opt,name=description" json:"description,omitempty"`
-	FuncUrl        string `protobuf:"bytes,3,opt,name=funcUrl" json:"funcUrl,omitempty"`
-	LocalFuncUrl   string `protobuf:"bytes,4,opt,name=localFuncUrl" json:"localFuncUrl,omitempty"`
-	Ca             string `protobuf:"bytes,5,opt,name=ca" json:"ca,omitempty"`
-	ResultCachable bool   `protobuf:"varint,6,opt,name=resultCachable" json:"resultCachable,omitempty"`
-	ResultTTL      int64  `protobuf:"varint,7,opt,name=resultTTL" json:"resultTTL,omitempty"`
+	Name                 string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description          string              `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	FuncUrl              string              `protobuf:"bytes,3,opt,name=funcUrl,proto3" json:"funcUrl,omitempty"`
+	LocalFuncUrl         string              `protobuf:"bytes,4,opt,name=localFuncUrl,proto3" json:"localFuncUrl,omitempty"`
+	Ca                   string              `protobuf:"bytes,5,opt,name=ca,proto3" json:"ca,omitempty"`
+	ResultCachable       bool                `protobuf:"varint,6,opt,name=resultCachable,proto3" json:"resultCachable,omitempty"`
+	ResultTTL            int64               `protobuf:"varint,7,opt,name=resultTTL,proto3" json:"resultTTL,omitempty"`
+	Resilience           *FunctionResilience `protobuf:"bytes,8,opt,name=resilience,proto3" json:"resilience,omitempty"`
+	CacheSize            int64               `protobuf:"varint,9,opt,name=cacheSize,proto3" json:"cacheSize,omitempty"`
+	ErrorTTL             int64               `protobuf:"varint,10,opt,name=errorTTL,proto3" json:"errorTTL,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *Function) Reset()         { *m = Function{} }
+func (m *Function) String() string { return proto.CompactTextString(m) }
+func (*Function) ProtoMessage()    {}
+func (*Function) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{9}
 }
 
-func (m *Function) Reset()                    { *m = Function{} }
-func (m *Function) String() string            { return proto.CompactTextString(m) }
-func (*Function) ProtoMessage()               {}
-func (*Function) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (m *Function) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Function.Unmarshal(m, b)
+}
+func (m *Function) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Function.Marshal(b, m, deterministic)
+}
+func (m *Function) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Function.Merge(m, src)
+}
+func (m *Function) XXX_Size() int {
+	return xxx_messageInfo_Function.Size(m)
+}
+func (m *Function) XXX_DiscardUnknown() {
+	xxx_messageInfo_Function.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Function proto.InternalMessageInfo
 
 func (m *Function) GetName() string {
 	if m != nil {
@@ -421,15 +627,193 @@ func (m *Function) GetResultTTL() int64 {
 	return 0
 }
 
+func (m *Function) GetResilience() *FunctionResilience {
+	if m != nil {
+		return m.Resilience
+	}
+	return nil
+}
+
+func (m *Function) GetCacheSize() int64 {
+	if m != nil {
+		return m.CacheSize
+	}
+	return 0
+}
+
+func (m *Function) GetErrorTTL() int64 {
+	if m != nil {
+		return m.ErrorTTL
+	}
+	return 0
+}
+
+type FunctionResilience struct {
+	Timeout              int64             `protobuf:"varint,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Retries              int32             `protobuf:"varint,2,opt,name=retries,proto3" json:"retries,omitempty"`
+	RetryBackoff         int64             `protobuf:"varint,3,opt,name=retryBackoff,proto3" json:"retryBackoff,omitempty"`
+	FailureThreshold     int32             `protobuf:"varint,4,opt,name=failureThreshold,proto3" json:"failureThreshold,omitempty"`
+	OpenDuration         int64             `protobuf:"varint,5,opt,name=openDuration,proto3" json:"openDuration,omitempty"`
+	Fallback             *FunctionFallback `protobuf:"bytes,6,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *FunctionResilience) Reset()         { *m = FunctionResilience{} }
+func (m *FunctionResilience) String() string { return proto.CompactTextString(m) }
+func (*FunctionResilience) ProtoMessage()    {}
+func (*FunctionResilience) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{10}
+}
+
+func (m *FunctionResilience) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FunctionResilience.Unmarshal(m, b)
+}
+func (m *FunctionResilience) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FunctionResilience.Marshal(b, m, deterministic)
+}
+func (m *FunctionResilience) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FunctionResilience.Merge(m, src)
+}
+func (m *FunctionResilience) XXX_Size() int {
+	return xxx_messageInfo_FunctionResilience.Size(m)
+}
+func (m *FunctionResilience) XXX_DiscardUnknown() {
+	xxx_messageInfo_FunctionResilience.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FunctionResilience proto.InternalMessageInfo
+
+func (m *FunctionResilience) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *FunctionResilience) GetRetries() int32 {
+	if m != nil {
+		return m.Retries
+	}
+	return 0
+}
+
+func (m *FunctionResilience) GetRetryBackoff() int64 {
+	if m != nil {
+		return m.RetryBackoff
+	}
+	return 0
+}
+
+func (m *FunctionResilience) GetFailureThreshold() int32 {
+	if m != nil {
+		return m.FailureThreshold
+	}
+	return 0
+}
+
+func (m *FunctionResilience) GetOpenDuration() int64 {
+	if m != nil {
+		return m.OpenDuration
+	}
+	return 0
+}
+
+func (m *FunctionResilience) GetFallback() *FunctionFallback {
+	if m != nil {
+		return m.Fallback
+	}
+	return nil
+}
+
+type FunctionFallback struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FunctionFallback) Reset()         { *m = FunctionFallback{} }
+func (m *FunctionFallback) String() string { return proto.CompactTextString(m) }
+func (*FunctionFallback) ProtoMessage()    {}
+func (*FunctionFallback) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{11}
+}
+
+func (m *FunctionFallback) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FunctionFallback.Unmarshal(m, b)
+}
+func (m *FunctionFallback) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FunctionFallback.Marshal(b, m, deterministic)
+}
+func (m *FunctionFallback) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FunctionFallback.Merge(m, src)
+}
+func (m *FunctionFallback) XXX_Size() int {
+	return xxx_messageInfo_FunctionFallback.Size(m)
+}
+func (m *FunctionFallback) XXX_DiscardUnknown() {
+	xxx_messageInfo_FunctionFallback.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FunctionFallback proto.InternalMessageInfo
+
+func (m *FunctionFallback) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *FunctionFallback) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *FunctionFallback) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type FunctionQueryRequest struct {
-	Name    string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Filters string `protobuf:"bytes,2,opt,name=filters" json:"filters,omitempty"`
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Filters              string   `protobuf:"bytes,2,opt,name=filters,proto3" json:"filters,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FunctionQueryRequest) Reset()         { *m = FunctionQueryRequest{} }
+func (m *FunctionQueryRequest) String() string { return proto.CompactTextString(m) }
+func (*FunctionQueryRequest) ProtoMessage()    {}
+func (*FunctionQueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{12}
 }
 
-func (m *FunctionQueryRequest) Reset()                    { *m = FunctionQueryRequest{} }
-func (m *FunctionQueryRequest) String() string            { return proto.CompactTextString(m) }
-func (*FunctionQueryRequest) ProtoMessage()               {}
-func (*FunctionQueryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (m *FunctionQueryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FunctionQueryRequest.Unmarshal(m, b)
+}
+func (m *FunctionQueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FunctionQueryRequest.Marshal(b, m, deterministic)
+}
+func (m *FunctionQueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FunctionQueryRequest.Merge(m, src)
+}
+func (m *FunctionQueryRequest) XXX_Size() int {
+	return xxx_messageInfo_FunctionQueryRequest.Size(m)
+}
+func (m *FunctionQueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FunctionQueryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FunctionQueryRequest proto.InternalMessageInfo
 
 func (m *FunctionQueryRequest) GetName() string {
 	if m != nil {
@@ -446,13 +830,36 @@ func (m *FunctionQueryRequest) GetFilters() string {
 }
 
 type FunctionQueryResponse struct {
-	Functions []*Function `protobuf:"bytes,1,rep,name=functions" json:"functions,omitempty"`
+	Functions            []*Function `protobuf:"bytes,1,rep,name=functions,proto3" json:"functions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *FunctionQueryResponse) Reset()         { *m = FunctionQueryResponse{} }
+func (m *FunctionQueryResponse) String() string { return proto.CompactTextString(m) }
+func (*FunctionQueryResponse) ProtoMessage()    {}
+func (*FunctionQueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{13}
 }
 
-func (m *FunctionQueryResponse) Reset()                    { *m = FunctionQueryResponse{} }
-func (m *FunctionQueryResponse) String() string            { return proto.CompactTextString(m) }
-func (*FunctionQueryResponse) ProtoMessage()               {}
-func (*FunctionQueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (m *FunctionQueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FunctionQueryResponse.Unmarshal(m, b)
+}
+func (m *FunctionQueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FunctionQueryResponse.Marshal(b, m, deterministic)
+}
+func (m *FunctionQueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FunctionQueryResponse.Merge(m, src)
+}
+func (m *FunctionQueryResponse) XXX_Size() int {
+	return xxx_messageInfo_FunctionQueryResponse.Size(m)
+}
+func (m *FunctionQueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FunctionQueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FunctionQueryResponse proto.InternalMessageInfo
 
 func (m *FunctionQueryResponse) GetFunctions() []*Function {
 	if m != nil {
@@ -462,13 +869,36 @@ func (m *FunctionQueryResponse) GetFunctions() []*Function {
 }
 
 type AndPrincipals struct {
-	Principals []string `protobuf:"bytes,1,rep,name=principals" json:"principals,omitempty"`
+	Principals           []string `protobuf:"bytes,1,rep,name=principals,proto3" json:"principals,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AndPrincipals) Reset()         { *m = AndPrincipals{} }
+func (m *AndPrincipals) String() string { return proto.CompactTextString(m) }
+func (*AndPrincipals) ProtoMessage()    {}
+func (*AndPrincipals) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{14}
+}
+
+func (m *AndPrincipals) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AndPrincipals.Unmarshal(m, b)
+}
+func (m *AndPrincipals) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AndPrincipals.Marshal(b, m, deterministic)
+}
+func (m *AndPrincipals) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AndPrincipals.Merge(m, src)
+}
+func (m *AndPrincipals) XXX_Size() int {
+	return xxx_messageInfo_AndPrincipals.Size(m)
+}
+func (m *AndPrincipals) XXX_DiscardUnknown() {
+	xxx_messageInfo_AndPrincipals.DiscardUnknown(m)
 }
 
-func (m *AndPrincipals) Reset()                    { *m = AndPrincipals{} }
-func (m *AndPrincipals) String() string            { return proto.CompactTextString(m) }
-func (*AndPrincipals) ProtoMessage()               {}
-func (*AndPrincipals) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+var xxx_messageInfo_AndPrincipals proto.InternalMessageInfo
 
 func (m *AndPrincipals) GetPrincipals() []string {
 	if m != nil {
@@ -478,22 +908,72 @@ func (m *AndPrincipals) GetPrincipals() []string {
 }
 
 type Empty struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Empty) Reset()         { *m = Empty{} }
+func (m *Empty) String() string { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()    {}
+func (*Empty) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{15}
+}
+
+func (m *Empty) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Empty.Unmarshal(m, b)
+}
+func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+}
+func (m *Empty) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Empty.Merge(m, src)
+}
+func (m *Empty) XXX_Size() int {
+	return xxx_messageInfo_Empty.Size(m)
+}
+func (m *Empty) XXX_DiscardUnknown() {
+	xxx_messageInfo_Empty.DiscardUnknown(m)
 }
 
-func (m *Empty) Reset()                    { *m = Empty{} }
-func (m *Empty) String() string            { return proto.CompactTextString(m) }
-func (*Empty) ProtoMessage()               {}
-func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+var xxx_messageInfo_Empty proto.InternalMessageInfo
 
 type ServiceRequest struct {
-	Name string      `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Type ServiceType `protobuf:"varint,2,opt,name=type,enum=pb.ServiceType" json:"type,omitempty"`
+	Name                 string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 ServiceType `protobuf:"varint,2,opt,name=type,proto3,enum=pb.ServiceType" json:"type,omitempty"`
+	Owners               []string    `protobuf:"bytes,3,rep,name=owners,proto3" json:"owners,omitempty"`
+	DefaultEffect        string      `protobuf:"bytes,4,opt,name=defaultEffect,proto3" json:"defaultEffect,omitempty"`
+	FailureMode          string      `protobuf:"bytes,5,opt,name=failureMode,proto3" json:"failureMode,omitempty"`
+	Mode                 string      `protobuf:"bytes,6,opt,name=mode,proto3" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ServiceRequest) Reset()         { *m = ServiceRequest{} }
+func (m *ServiceRequest) String() string { return proto.CompactTextString(m) }
+func (*ServiceRequest) ProtoMessage()    {}
+func (*ServiceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{16}
+}
+
+func (m *ServiceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceRequest.Unmarshal(m, b)
+}
+func (m *ServiceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceRequest.Marshal(b, m, deterministic)
+}
+func (m *ServiceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceRequest.Merge(m, src)
+}
+func (m *ServiceRequest) XXX_Size() int {
+	return xxx_messageInfo_ServiceRequest.Size(m)
+}
+func (m *ServiceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceRequest.DiscardUnknown(m)
 }
 
-func (m *ServiceRequest) Reset()                    { *m = ServiceRequest{} }
-func (m *ServiceRequest) String() string            { return proto.CompactTextString(m) }
-func (*ServiceRequest) ProtoMessage()               {}
-func (*ServiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+var xxx_messageInfo_ServiceRequest proto.InternalMessageInfo
 
 func (m *ServiceRequest) GetName() string {
 	if m != nil {
@@ -509,15 +989,66 @@ func (m *ServiceRequest) GetType() ServiceType {
 	return ServiceType_APPLICATION
 }
 
+func (m *ServiceRequest) GetOwners() []string {
+	if m != nil {
+		return m.Owners
+	}
+	return nil
+}
+
+func (m *ServiceRequest) GetDefaultEffect() string {
+	if m != nil {
+		return m.DefaultEffect
+	}
+	return ""
+}
+
+func (m *ServiceRequest) GetFailureMode() string {
+	if m != nil {
+		return m.FailureMode
+	}
+	return ""
+}
+
+func (m *ServiceRequest) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
 type PolicyRequest struct {
-	ServiceName string  `protobuf:"bytes,1,opt,name=serviceName" json:"serviceName,omitempty"`
-	Policy      *Policy `protobuf:"bytes,2,opt,name=policy" json:"policy,omitempty"`
+	ServiceName          string   `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Policy               *Policy  `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyRequest) Reset()         { *m = PolicyRequest{} }
+func (m *PolicyRequest) String() string { return proto.CompactTextString(m) }
+func (*PolicyRequest) ProtoMessage()    {}
+func (*PolicyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{17}
+}
+
+func (m *PolicyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyRequest.Unmarshal(m, b)
+}
+func (m *PolicyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyRequest.Marshal(b, m, deterministic)
+}
+func (m *PolicyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyRequest.Merge(m, src)
+}
+func (m *PolicyRequest) XXX_Size() int {
+	return xxx_messageInfo_PolicyRequest.Size(m)
+}
+func (m *PolicyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyRequest.DiscardUnknown(m)
 }
 
-func (m *PolicyRequest) Reset()                    { *m = PolicyRequest{} }
-func (m *PolicyRequest) String() string            { return proto.CompactTextString(m) }
-func (*PolicyRequest) ProtoMessage()               {}
-func (*PolicyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+var xxx_messageInfo_PolicyRequest proto.InternalMessageInfo
 
 func (m *PolicyRequest) GetServiceName() string {
 	if m != nil {
@@ -534,13 +1065,36 @@ func (m *PolicyRequest) GetPolicy() *Policy {
 }
 
 type ServiceQueryResponse struct {
-	Services []*Service `protobuf:"bytes,1,rep,name=services" json:"services,omitempty"`
+	Services             []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ServiceQueryResponse) Reset()         { *m = ServiceQueryResponse{} }
+func (m *ServiceQueryResponse) String() string { return proto.CompactTextString(m) }
+func (*ServiceQueryResponse) ProtoMessage()    {}
+func (*ServiceQueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{18}
+}
+
+func (m *ServiceQueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceQueryResponse.Unmarshal(m, b)
+}
+func (m *ServiceQueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceQueryResponse.Marshal(b, m, deterministic)
+}
+func (m *ServiceQueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceQueryResponse.Merge(m, src)
+}
+func (m *ServiceQueryResponse) XXX_Size() int {
+	return xxx_messageInfo_ServiceQueryResponse.Size(m)
+}
+func (m *ServiceQueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceQueryResponse.DiscardUnknown(m)
 }
 
-func (m *ServiceQueryResponse) Reset()                    { *m = ServiceQueryResponse{} }
-func (m *ServiceQueryResponse) String() string            { return proto.CompactTextString(m) }
-func (*ServiceQueryResponse) ProtoMessage()               {}
-func (*ServiceQueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+var xxx_messageInfo_ServiceQueryResponse proto.InternalMessageInfo
 
 func (m *ServiceQueryResponse) GetServices() []*Service {
 	if m != nil {
@@ -550,13 +1104,38 @@ func (m *ServiceQueryResponse) GetServices() []*Service {
 }
 
 type ServiceQueryRequest struct {
-	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Owner                string   `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Owned                bool     `protobuf:"varint,3,opt,name=owned,proto3" json:"owned,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServiceQueryRequest) Reset()         { *m = ServiceQueryRequest{} }
+func (m *ServiceQueryRequest) String() string { return proto.CompactTextString(m) }
+func (*ServiceQueryRequest) ProtoMessage()    {}
+func (*ServiceQueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{19}
+}
+
+func (m *ServiceQueryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceQueryRequest.Unmarshal(m, b)
+}
+func (m *ServiceQueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceQueryRequest.Marshal(b, m, deterministic)
+}
+func (m *ServiceQueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceQueryRequest.Merge(m, src)
+}
+func (m *ServiceQueryRequest) XXX_Size() int {
+	return xxx_messageInfo_ServiceQueryRequest.Size(m)
+}
+func (m *ServiceQueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceQueryRequest.DiscardUnknown(m)
 }
 
-func (m *ServiceQueryRequest) Reset()                    { *m = ServiceQueryRequest{} }
-func (m *ServiceQueryRequest) String() string            { return proto.CompactTextString(m) }
-func (*ServiceQueryRequest) ProtoMessage()               {}
-func (*ServiceQueryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+var xxx_messageInfo_ServiceQueryRequest proto.InternalMessageInfo
 
 func (m *ServiceQueryRequest) GetName() string {
 	if m != nil {
@@ -565,16 +1144,53 @@ func (m *ServiceQueryRequest) GetName() string {
 	return ""
 }
 
+func (m *ServiceQueryRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *ServiceQueryRequest) GetOwned() bool {
+	if m != nil {
+		return m.Owned
+	}
+	return false
+}
+
 type PolicyQueryRequest struct {
-	ServiceName string `protobuf:"bytes,1,opt,name=serviceName" json:"serviceName,omitempty"`
-	PolicyID    string `protobuf:"bytes,2,opt,name=policyID" json:"policyID,omitempty"`
-	Filters     string `protobuf:"bytes,3,opt,name=filters" json:"filters,omitempty"`
+	ServiceName          string   `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	PolicyID             string   `protobuf:"bytes,2,opt,name=policyID,proto3" json:"policyID,omitempty"`
+	Filters              string   `protobuf:"bytes,3,opt,name=filters,proto3" json:"filters,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyQueryRequest) Reset()         { *m = PolicyQueryRequest{} }
+func (m *PolicyQueryRequest) String() string { return proto.CompactTextString(m) }
+func (*PolicyQueryRequest) ProtoMessage()    {}
+func (*PolicyQueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{20}
+}
+
+func (m *PolicyQueryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyQueryRequest.Unmarshal(m, b)
+}
+func (m *PolicyQueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyQueryRequest.Marshal(b, m, deterministic)
+}
+func (m *PolicyQueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyQueryRequest.Merge(m, src)
+}
+func (m *PolicyQueryRequest) XXX_Size() int {
+	return xxx_messageInfo_PolicyQueryRequest.Size(m)
+}
+func (m *PolicyQueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyQueryRequest.DiscardUnknown(m)
 }
 
-func (m *PolicyQueryRequest) Reset()                    { *m = PolicyQueryRequest{} }
-func (m *PolicyQueryRequest) String() string            { return proto.CompactTextString(m) }
-func (*PolicyQueryRequest) ProtoMessage()               {}
-func (*PolicyQueryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+var xxx_messageInfo_PolicyQueryRequest proto.InternalMessageInfo
 
 func (m *PolicyQueryRequest) GetServiceName() string {
 	if m != nil {
@@ -598,13 +1214,36 @@ func (m *PolicyQueryRequest) GetFilters() string {
 }
 
 type PolicyQueryResponse struct {
-	Policies []*Policy `protobuf:"bytes,1,rep,name=policies" json:"policies,omitempty"`
+	Policies             []*Policy `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *PolicyQueryResponse) Reset()         { *m = PolicyQueryResponse{} }
+func (m *PolicyQueryResponse) String() string { return proto.CompactTextString(m) }
+func (*PolicyQueryResponse) ProtoMessage()    {}
+func (*PolicyQueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{21}
+}
+
+func (m *PolicyQueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyQueryResponse.Unmarshal(m, b)
+}
+func (m *PolicyQueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyQueryResponse.Marshal(b, m, deterministic)
+}
+func (m *PolicyQueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyQueryResponse.Merge(m, src)
+}
+func (m *PolicyQueryResponse) XXX_Size() int {
+	return xxx_messageInfo_PolicyQueryResponse.Size(m)
+}
+func (m *PolicyQueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyQueryResponse.DiscardUnknown(m)
 }
 
-func (m *PolicyQueryResponse) Reset()                    { *m = PolicyQueryResponse{} }
-func (m *PolicyQueryResponse) String() string            { return proto.CompactTextString(m) }
-func (*PolicyQueryResponse) ProtoMessage()               {}
-func (*PolicyQueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+var xxx_messageInfo_PolicyQueryResponse proto.InternalMessageInfo
 
 func (m *PolicyQueryResponse) GetPolicies() []*Policy {
 	if m != nil {
@@ -612,20 +1251,46 @@ func (m *PolicyQueryResponse) GetPolicies() []*Policy {
 	}
 	return nil
 }
-
-type Policy struct {
-	Id          string               `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
-	Name        string               `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
-	Effect      Effect               `protobuf:"varint,3,opt,name=effect,enum=pb.Effect" json:"effect,omitempty"`
-	Permissions []*Policy_Permission `protobuf:"bytes,4,rep,name=permissions" json:"permissions,omitempty"`
-	Principals  []*AndPrincipals     `protobuf:"bytes,5,rep,name=principals" json:"principals,omitempty"`
-	Condition   string               `protobuf:"bytes,6,opt,name=condition" json:"condition,omitempty"`
+
+type Policy struct {
+	Id                   string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string               `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Effect               Effect               `protobuf:"varint,3,opt,name=effect,proto3,enum=pb.Effect" json:"effect,omitempty"`
+	Permissions          []*Policy_Permission `protobuf:"bytes,4,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	Principals           []*AndPrincipals     `protobuf:"bytes,5,rep,name=principals,proto3" json:"principals,omitempty"`
+	Condition            string               `protobuf:"bytes,6,opt,name=condition,proto3" json:"condition,omitempty"`
+	Disabled             bool                 `protobuf:"varint,7,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	NotBefore            string               `protobuf:"bytes,8,opt,name=notBefore,proto3" json:"notBefore,omitempty"`
+	NotAfter             string               `protobuf:"bytes,9,opt,name=notAfter,proto3" json:"notAfter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Policy) Reset()         { *m = Policy{} }
+func (m *Policy) String() string { return proto.CompactTextString(m) }
+func (*Policy) ProtoMessage()    {}
+func (*Policy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{22}
+}
+
+func (m *Policy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Policy.Unmarshal(m, b)
+}
+func (m *Policy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Policy.Marshal(b, m, deterministic)
+}
+func (m *Policy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Policy.Merge(m, src)
+}
+func (m *Policy) XXX_Size() int {
+	return xxx_messageInfo_Policy.Size(m)
+}
+func (m *Policy) XXX_DiscardUnknown() {
+	xxx_messageInfo_Policy.DiscardUnknown(m)
 }
 
-func (m *Policy) Reset()                    { *m = Policy{} }
-func (m *Policy) String() string            { return proto.CompactTextString(m) }
-func (*Policy) ProtoMessage()               {}
-func (*Policy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+var xxx_messageInfo_Policy proto.InternalMessageInfo
 
 func (m *Policy) GetId() string {
 	if m != nil {
@@ -669,16 +1334,60 @@ func (m *Policy) GetCondition() string {
 	return ""
 }
 
+func (m *Policy) GetDisabled() bool {
+	if m != nil {
+		return m.Disabled
+	}
+	return false
+}
+
+func (m *Policy) GetNotBefore() string {
+	if m != nil {
+		return m.NotBefore
+	}
+	return ""
+}
+
+func (m *Policy) GetNotAfter() string {
+	if m != nil {
+		return m.NotAfter
+	}
+	return ""
+}
+
 type Policy_Permission struct {
-	Resource           string   `protobuf:"bytes,1,opt,name=resource" json:"resource,omitempty"`
-	ResourceExpression string   `protobuf:"bytes,2,opt,name=resource_expression,json=resourceExpression" json:"resource_expression,omitempty"`
-	Actions            []string `protobuf:"bytes,3,rep,name=actions" json:"actions,omitempty"`
+	Resource             string   `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	ResourceExpression   string   `protobuf:"bytes,2,opt,name=resource_expression,json=resourceExpression,proto3" json:"resource_expression,omitempty"`
+	Actions              []string `protobuf:"bytes,3,rep,name=actions,proto3" json:"actions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Policy_Permission) Reset()         { *m = Policy_Permission{} }
+func (m *Policy_Permission) String() string { return proto.CompactTextString(m) }
+func (*Policy_Permission) ProtoMessage()    {}
+func (*Policy_Permission) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{22, 0}
+}
+
+func (m *Policy_Permission) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Policy_Permission.Unmarshal(m, b)
+}
+func (m *Policy_Permission) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Policy_Permission.Marshal(b, m, deterministic)
+}
+func (m *Policy_Permission) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Policy_Permission.Merge(m, src)
+}
+func (m *Policy_Permission) XXX_Size() int {
+	return xxx_messageInfo_Policy_Permission.Size(m)
+}
+func (m *Policy_Permission) XXX_DiscardUnknown() {
+	xxx_messageInfo_Policy_Permission.DiscardUnknown(m)
 }
 
-func (m *Policy_Permission) Reset()                    { *m = Policy_Permission{} }
-func (m *Policy_Permission) String() string            { return proto.CompactTextString(m) }
-func (*Policy_Permission) ProtoMessage()               {}
-func (*Policy_Permission) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20, 0} }
+var xxx_messageInfo_Policy_Permission proto.InternalMessageInfo
 
 func (m *Policy_Permission) GetResource() string {
 	if m != nil {
@@ -702,14 +1411,37 @@ func (m *Policy_Permission) GetActions() []string {
 }
 
 type RolePolicyRequest struct {
-	ServiceName string      `protobuf:"bytes,1,opt,name=serviceName" json:"serviceName,omitempty"`
-	RolePolicy  *RolePolicy `protobuf:"bytes,2,opt,name=rolePolicy" json:"rolePolicy,omitempty"`
+	ServiceName          string      `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	RolePolicy           *RolePolicy `protobuf:"bytes,2,opt,name=rolePolicy,proto3" json:"rolePolicy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *RolePolicyRequest) Reset()         { *m = RolePolicyRequest{} }
+func (m *RolePolicyRequest) String() string { return proto.CompactTextString(m) }
+func (*RolePolicyRequest) ProtoMessage()    {}
+func (*RolePolicyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{23}
+}
+
+func (m *RolePolicyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RolePolicyRequest.Unmarshal(m, b)
+}
+func (m *RolePolicyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RolePolicyRequest.Marshal(b, m, deterministic)
+}
+func (m *RolePolicyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RolePolicyRequest.Merge(m, src)
+}
+func (m *RolePolicyRequest) XXX_Size() int {
+	return xxx_messageInfo_RolePolicyRequest.Size(m)
+}
+func (m *RolePolicyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RolePolicyRequest.DiscardUnknown(m)
 }
 
-func (m *RolePolicyRequest) Reset()                    { *m = RolePolicyRequest{} }
-func (m *RolePolicyRequest) String() string            { return proto.CompactTextString(m) }
-func (*RolePolicyRequest) ProtoMessage()               {}
-func (*RolePolicyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+var xxx_messageInfo_RolePolicyRequest proto.InternalMessageInfo
 
 func (m *RolePolicyRequest) GetServiceName() string {
 	if m != nil {
@@ -726,15 +1458,38 @@ func (m *RolePolicyRequest) GetRolePolicy() *RolePolicy {
 }
 
 type RolePolicyQueryRequest struct {
-	ServiceName  string `protobuf:"bytes,1,opt,name=serviceName" json:"serviceName,omitempty"`
-	RolePolicyID string `protobuf:"bytes,2,opt,name=rolePolicyID" json:"rolePolicyID,omitempty"`
-	Filters      string `protobuf:"bytes,3,opt,name=filters" json:"filters,omitempty"`
+	ServiceName          string   `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	RolePolicyID         string   `protobuf:"bytes,2,opt,name=rolePolicyID,proto3" json:"rolePolicyID,omitempty"`
+	Filters              string   `protobuf:"bytes,3,opt,name=filters,proto3" json:"filters,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RolePolicyQueryRequest) Reset()         { *m = RolePolicyQueryRequest{} }
+func (m *RolePolicyQueryRequest) String() string { return proto.CompactTextString(m) }
+func (*RolePolicyQueryRequest) ProtoMessage()    {}
+func (*RolePolicyQueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{24}
+}
+
+func (m *RolePolicyQueryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RolePolicyQueryRequest.Unmarshal(m, b)
+}
+func (m *RolePolicyQueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RolePolicyQueryRequest.Marshal(b, m, deterministic)
+}
+func (m *RolePolicyQueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RolePolicyQueryRequest.Merge(m, src)
+}
+func (m *RolePolicyQueryRequest) XXX_Size() int {
+	return xxx_messageInfo_RolePolicyQueryRequest.Size(m)
+}
+func (m *RolePolicyQueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RolePolicyQueryRequest.DiscardUnknown(m)
 }
 
-func (m *RolePolicyQueryRequest) Reset()                    { *m = RolePolicyQueryRequest{} }
-func (m *RolePolicyQueryRequest) String() string            { return proto.CompactTextString(m) }
-func (*RolePolicyQueryRequest) ProtoMessage()               {}
-func (*RolePolicyQueryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+var xxx_messageInfo_RolePolicyQueryRequest proto.InternalMessageInfo
 
 func (m *RolePolicyQueryRequest) GetServiceName() string {
 	if m != nil {
@@ -758,13 +1513,36 @@ func (m *RolePolicyQueryRequest) GetFilters() string {
 }
 
 type RolePolicyQueryResponse struct {
-	RolePolicies []*RolePolicy `protobuf:"bytes,1,rep,name=rolePolicies" json:"rolePolicies,omitempty"`
+	RolePolicies         []*RolePolicy `protobuf:"bytes,1,rep,name=rolePolicies,proto3" json:"rolePolicies,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *RolePolicyQueryResponse) Reset()         { *m = RolePolicyQueryResponse{} }
+func (m *RolePolicyQueryResponse) String() string { return proto.CompactTextString(m) }
+func (*RolePolicyQueryResponse) ProtoMessage()    {}
+func (*RolePolicyQueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{25}
+}
+
+func (m *RolePolicyQueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RolePolicyQueryResponse.Unmarshal(m, b)
+}
+func (m *RolePolicyQueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RolePolicyQueryResponse.Marshal(b, m, deterministic)
+}
+func (m *RolePolicyQueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RolePolicyQueryResponse.Merge(m, src)
+}
+func (m *RolePolicyQueryResponse) XXX_Size() int {
+	return xxx_messageInfo_RolePolicyQueryResponse.Size(m)
+}
+func (m *RolePolicyQueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RolePolicyQueryResponse.DiscardUnknown(m)
 }
 
-func (m *RolePolicyQueryResponse) Reset()                    { *m = RolePolicyQueryResponse{} }
-func (m *RolePolicyQueryResponse) String() string            { return proto.CompactTextString(m) }
-func (*RolePolicyQueryResponse) ProtoMessage()               {}
-func (*RolePolicyQueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+var xxx_messageInfo_RolePolicyQueryResponse proto.InternalMessageInfo
 
 func (m *RolePolicyQueryResponse) GetRolePolicies() []*RolePolicy {
 	if m != nil {
@@ -774,20 +1552,43 @@ func (m *RolePolicyQueryResponse) GetRolePolicies() []*RolePolicy {
 }
 
 type RolePolicy struct {
-	Id                  string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
-	Name                string   `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
-	Effect              Effect   `protobuf:"varint,3,opt,name=effect,enum=pb.Effect" json:"effect,omitempty"`
-	Roles               []string `protobuf:"bytes,4,rep,name=roles" json:"roles,omitempty"`
-	Principals          []string `protobuf:"bytes,5,rep,name=principals" json:"principals,omitempty"`
-	Resources           []string `protobuf:"bytes,6,rep,name=resources" json:"resources,omitempty"`
-	ResourceExpressions []string `protobuf:"bytes,7,rep,name=resource_expressions,json=resourceExpressions" json:"resource_expressions,omitempty"`
-	Condition           string   `protobuf:"bytes,8,opt,name=condition" json:"condition,omitempty"`
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Effect               Effect   `protobuf:"varint,3,opt,name=effect,proto3,enum=pb.Effect" json:"effect,omitempty"`
+	Roles                []string `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`
+	Principals           []string `protobuf:"bytes,5,rep,name=principals,proto3" json:"principals,omitempty"`
+	Resources            []string `protobuf:"bytes,6,rep,name=resources,proto3" json:"resources,omitempty"`
+	ResourceExpressions  []string `protobuf:"bytes,7,rep,name=resource_expressions,json=resourceExpressions,proto3" json:"resource_expressions,omitempty"`
+	Condition            string   `protobuf:"bytes,8,opt,name=condition,proto3" json:"condition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RolePolicy) Reset()         { *m = RolePolicy{} }
+func (m *RolePolicy) String() string { return proto.CompactTextString(m) }
+func (*RolePolicy) ProtoMessage()    {}
+func (*RolePolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{26}
+}
+
+func (m *RolePolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RolePolicy.Unmarshal(m, b)
+}
+func (m *RolePolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RolePolicy.Marshal(b, m, deterministic)
+}
+func (m *RolePolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RolePolicy.Merge(m, src)
+}
+func (m *RolePolicy) XXX_Size() int {
+	return xxx_messageInfo_RolePolicy.Size(m)
+}
+func (m *RolePolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_RolePolicy.DiscardUnknown(m)
 }
 
-func (m *RolePolicy) Reset()                    { *m = RolePolicy{} }
-func (m *RolePolicy) String() string            { return proto.CompactTextString(m) }
-func (*RolePolicy) ProtoMessage()               {}
-func (*RolePolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+var xxx_messageInfo_RolePolicy proto.InternalMessageInfo
 
 func (m *RolePolicy) GetId() string {
 	if m != nil {
@@ -846,16 +1647,43 @@ func (m *RolePolicy) GetCondition() string {
 }
 
 type Service struct {
-	Name         string        `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Type         ServiceType   `protobuf:"varint,2,opt,name=type,enum=pb.ServiceType" json:"type,omitempty"`
-	Policies     []*Policy     `protobuf:"bytes,3,rep,name=policies" json:"policies,omitempty"`
-	RolePolicies []*RolePolicy `protobuf:"bytes,4,rep,name=role_policies,json=rolePolicies" json:"role_policies,omitempty"`
+	Name                 string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 ServiceType   `protobuf:"varint,2,opt,name=type,proto3,enum=pb.ServiceType" json:"type,omitempty"`
+	Policies             []*Policy     `protobuf:"bytes,3,rep,name=policies,proto3" json:"policies,omitempty"`
+	RolePolicies         []*RolePolicy `protobuf:"bytes,4,rep,name=role_policies,json=rolePolicies,proto3" json:"role_policies,omitempty"`
+	Owners               []string      `protobuf:"bytes,5,rep,name=owners,proto3" json:"owners,omitempty"`
+	DefaultEffect        string        `protobuf:"bytes,6,opt,name=defaultEffect,proto3" json:"defaultEffect,omitempty"`
+	FailureMode          string        `protobuf:"bytes,7,opt,name=failureMode,proto3" json:"failureMode,omitempty"`
+	Mode                 string        `protobuf:"bytes,8,opt,name=mode,proto3" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *Service) Reset()         { *m = Service{} }
+func (m *Service) String() string { return proto.CompactTextString(m) }
+func (*Service) ProtoMessage()    {}
+func (*Service) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{27}
+}
+
+func (m *Service) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Service.Unmarshal(m, b)
+}
+func (m *Service) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Service.Marshal(b, m, deterministic)
+}
+func (m *Service) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Service.Merge(m, src)
+}
+func (m *Service) XXX_Size() int {
+	return xxx_messageInfo_Service.Size(m)
+}
+func (m *Service) XXX_DiscardUnknown() {
+	xxx_messageInfo_Service.DiscardUnknown(m)
 }
 
-func (m *Service) Reset()                    { *m = Service{} }
-func (m *Service) String() string            { return proto.CompactTextString(m) }
-func (*Service) ProtoMessage()               {}
-func (*Service) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+var xxx_messageInfo_Service proto.InternalMessageInfo
 
 func (m *Service) GetName() string {
 	if m != nil {
@@ -885,15 +1713,199 @@ func (m *Service) GetRolePolicies() []*RolePolicy {
 	return nil
 }
 
+func (m *Service) GetOwners() []string {
+	if m != nil {
+		return m.Owners
+	}
+	return nil
+}
+
+func (m *Service) GetDefaultEffect() string {
+	if m != nil {
+		return m.DefaultEffect
+	}
+	return ""
+}
+
+func (m *Service) GetFailureMode() string {
+	if m != nil {
+		return m.FailureMode
+	}
+	return ""
+}
+
+func (m *Service) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+type ServiceOwnersRequest struct {
+	ServiceName          string   `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Owners               []string `protobuf:"bytes,2,rep,name=owners,proto3" json:"owners,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServiceOwnersRequest) Reset()         { *m = ServiceOwnersRequest{} }
+func (m *ServiceOwnersRequest) String() string { return proto.CompactTextString(m) }
+func (*ServiceOwnersRequest) ProtoMessage()    {}
+func (*ServiceOwnersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{28}
+}
+
+func (m *ServiceOwnersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceOwnersRequest.Unmarshal(m, b)
+}
+func (m *ServiceOwnersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceOwnersRequest.Marshal(b, m, deterministic)
+}
+func (m *ServiceOwnersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceOwnersRequest.Merge(m, src)
+}
+func (m *ServiceOwnersRequest) XXX_Size() int {
+	return xxx_messageInfo_ServiceOwnersRequest.Size(m)
+}
+func (m *ServiceOwnersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceOwnersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceOwnersRequest proto.InternalMessageInfo
+
+func (m *ServiceOwnersRequest) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
+	}
+	return ""
+}
+
+func (m *ServiceOwnersRequest) GetOwners() []string {
+	if m != nil {
+		return m.Owners
+	}
+	return nil
+}
+
+type ServiceOwnerRequest struct {
+	ServiceName          string   `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Owner                string   `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServiceOwnerRequest) Reset()         { *m = ServiceOwnerRequest{} }
+func (m *ServiceOwnerRequest) String() string { return proto.CompactTextString(m) }
+func (*ServiceOwnerRequest) ProtoMessage()    {}
+func (*ServiceOwnerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{29}
+}
+
+func (m *ServiceOwnerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceOwnerRequest.Unmarshal(m, b)
+}
+func (m *ServiceOwnerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceOwnerRequest.Marshal(b, m, deterministic)
+}
+func (m *ServiceOwnerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceOwnerRequest.Merge(m, src)
+}
+func (m *ServiceOwnerRequest) XXX_Size() int {
+	return xxx_messageInfo_ServiceOwnerRequest.Size(m)
+}
+func (m *ServiceOwnerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceOwnerRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceOwnerRequest proto.InternalMessageInfo
+
+func (m *ServiceOwnerRequest) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
+	}
+	return ""
+}
+
+func (m *ServiceOwnerRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+type ServiceOwners struct {
+	Owners               []string `protobuf:"bytes,1,rep,name=owners,proto3" json:"owners,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServiceOwners) Reset()         { *m = ServiceOwners{} }
+func (m *ServiceOwners) String() string { return proto.CompactTextString(m) }
+func (*ServiceOwners) ProtoMessage()    {}
+func (*ServiceOwners) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{30}
+}
+
+func (m *ServiceOwners) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceOwners.Unmarshal(m, b)
+}
+func (m *ServiceOwners) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceOwners.Marshal(b, m, deterministic)
+}
+func (m *ServiceOwners) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceOwners.Merge(m, src)
+}
+func (m *ServiceOwners) XXX_Size() int {
+	return xxx_messageInfo_ServiceOwners.Size(m)
+}
+func (m *ServiceOwners) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceOwners.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceOwners proto.InternalMessageInfo
+
+func (m *ServiceOwners) GetOwners() []string {
+	if m != nil {
+		return m.Owners
+	}
+	return nil
+}
+
 type PolicyAndRolePolicyCounts struct {
-	PolicyCount     int64 `protobuf:"varint,1,opt,name=policyCount" json:"policyCount,omitempty"`
-	RolePolicyCount int64 `protobuf:"varint,2,opt,name=rolePolicyCount" json:"rolePolicyCount,omitempty"`
+	PolicyCount          int64    `protobuf:"varint,1,opt,name=policyCount,proto3" json:"policyCount,omitempty"`
+	RolePolicyCount      int64    `protobuf:"varint,2,opt,name=rolePolicyCount,proto3" json:"rolePolicyCount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyAndRolePolicyCounts) Reset()         { *m = PolicyAndRolePolicyCounts{} }
+func (m *PolicyAndRolePolicyCounts) String() string { return proto.CompactTextString(m) }
+func (*PolicyAndRolePolicyCounts) ProtoMessage()    {}
+func (*PolicyAndRolePolicyCounts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{31}
+}
+
+func (m *PolicyAndRolePolicyCounts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyAndRolePolicyCounts.Unmarshal(m, b)
+}
+func (m *PolicyAndRolePolicyCounts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyAndRolePolicyCounts.Marshal(b, m, deterministic)
+}
+func (m *PolicyAndRolePolicyCounts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyAndRolePolicyCounts.Merge(m, src)
+}
+func (m *PolicyAndRolePolicyCounts) XXX_Size() int {
+	return xxx_messageInfo_PolicyAndRolePolicyCounts.Size(m)
+}
+func (m *PolicyAndRolePolicyCounts) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyAndRolePolicyCounts.DiscardUnknown(m)
 }
 
-func (m *PolicyAndRolePolicyCounts) Reset()                    { *m = PolicyAndRolePolicyCounts{} }
-func (m *PolicyAndRolePolicyCounts) String() string            { return proto.CompactTextString(m) }
-func (*PolicyAndRolePolicyCounts) ProtoMessage()               {}
-func (*PolicyAndRolePolicyCounts) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+var xxx_messageInfo_PolicyAndRolePolicyCounts proto.InternalMessageInfo
 
 func (m *PolicyAndRolePolicyCounts) GetPolicyCount() int64 {
 	if m != nil {
@@ -910,13 +1922,36 @@ func (m *PolicyAndRolePolicyCounts) GetRolePolicyCount() int64 {
 }
 
 type PolicyCountsMap struct {
-	CountMap map[string]*PolicyAndRolePolicyCounts `protobuf:"bytes,1,rep,name=countMap" json:"countMap,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CountMap             map[string]*PolicyAndRolePolicyCounts `protobuf:"bytes,1,rep,name=countMap,proto3" json:"countMap,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                              `json:"-"`
+	XXX_unrecognized     []byte                                `json:"-"`
+	XXX_sizecache        int32                                 `json:"-"`
+}
+
+func (m *PolicyCountsMap) Reset()         { *m = PolicyCountsMap{} }
+func (m *PolicyCountsMap) String() string { return proto.CompactTextString(m) }
+func (*PolicyCountsMap) ProtoMessage()    {}
+func (*PolicyCountsMap) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{32}
+}
+
+func (m *PolicyCountsMap) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyCountsMap.Unmarshal(m, b)
+}
+func (m *PolicyCountsMap) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyCountsMap.Marshal(b, m, deterministic)
+}
+func (m *PolicyCountsMap) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyCountsMap.Merge(m, src)
+}
+func (m *PolicyCountsMap) XXX_Size() int {
+	return xxx_messageInfo_PolicyCountsMap.Size(m)
+}
+func (m *PolicyCountsMap) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyCountsMap.DiscardUnknown(m)
 }
 
-func (m *PolicyCountsMap) Reset()                    { *m = PolicyCountsMap{} }
-func (m *PolicyCountsMap) String() string            { return proto.CompactTextString(m) }
-func (*PolicyCountsMap) ProtoMessage()               {}
-func (*PolicyCountsMap) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+var xxx_messageInfo_PolicyCountsMap proto.InternalMessageInfo
 
 func (m *PolicyCountsMap) GetCountMap() map[string]*PolicyAndRolePolicyCounts {
 	if m != nil {
@@ -926,16 +1961,21 @@ func (m *PolicyCountsMap) GetCountMap() map[string]*PolicyAndRolePolicyCounts {
 }
 
 func init() {
+	proto.RegisterEnum("pb.Effect", Effect_name, Effect_value)
+	proto.RegisterEnum("pb.ServiceType", ServiceType_name, ServiceType_value)
 	proto.RegisterType((*DiscoverRequestsRequest)(nil), "pb.DiscoverRequestsRequest")
 	proto.RegisterType((*Principal)(nil), "pb.Principal")
 	proto.RegisterType((*Subject)(nil), "pb.Subject")
 	proto.RegisterType((*ContextRequest)(nil), "pb.ContextRequest")
+	proto.RegisterMapType((map[string]string)(nil), "pb.ContextRequest.AttributesEntry")
 	proto.RegisterType((*DiscoverRequestsResponse)(nil), "pb.DiscoverRequestsResponse")
 	proto.RegisterType((*ResetRequestsRequest)(nil), "pb.ResetRequestsRequest")
 	proto.RegisterType((*ResetRequestsResponse)(nil), "pb.ResetRequestsResponse")
 	proto.RegisterType((*DiscoverPoliciesRequest)(nil), "pb.DiscoverPoliciesRequest")
 	proto.RegisterType((*DiscoverPoliciesResponse)(nil), "pb.DiscoverPoliciesResponse")
 	proto.RegisterType((*Function)(nil), "pb.Function")
+	proto.RegisterType((*FunctionResilience)(nil), "pb.FunctionResilience")
+	proto.RegisterType((*FunctionFallback)(nil), "pb.FunctionFallback")
 	proto.RegisterType((*FunctionQueryRequest)(nil), "pb.FunctionQueryRequest")
 	proto.RegisterType((*FunctionQueryResponse)(nil), "pb.FunctionQueryResponse")
 	proto.RegisterType((*AndPrincipals)(nil), "pb.AndPrincipals")
@@ -953,22 +1993,141 @@ func init() {
 	proto.RegisterType((*RolePolicyQueryResponse)(nil), "pb.RolePolicyQueryResponse")
 	proto.RegisterType((*RolePolicy)(nil), "pb.RolePolicy")
 	proto.RegisterType((*Service)(nil), "pb.Service")
+	proto.RegisterType((*ServiceOwnersRequest)(nil), "pb.ServiceOwnersRequest")
+	proto.RegisterType((*ServiceOwnerRequest)(nil), "pb.ServiceOwnerRequest")
+	proto.RegisterType((*ServiceOwners)(nil), "pb.ServiceOwners")
 	proto.RegisterType((*PolicyAndRolePolicyCounts)(nil), "pb.PolicyAndRolePolicyCounts")
 	proto.RegisterType((*PolicyCountsMap)(nil), "pb.PolicyCountsMap")
-	proto.RegisterEnum("pb.Effect", Effect_name, Effect_value)
-	proto.RegisterEnum("pb.ServiceType", ServiceType_name, ServiceType_value)
+	proto.RegisterMapType((map[string]*PolicyAndRolePolicyCounts)(nil), "pb.PolicyCountsMap.CountMapEntry")
+}
+
+func init() { proto.RegisterFile("service.proto", fileDescriptor_a0b84a42fa06f626) }
+
+var fileDescriptor_a0b84a42fa06f626 = []byte{
+	// 1755 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x5b, 0x73, 0xe3, 0x48,
+	0x15, 0xb6, 0xec, 0xf8, 0x76, 0x3c, 0x76, 0x9c, 0x8e, 0x67, 0xa2, 0x31, 0xb3, 0x5b, 0xa1, 0x81,
+	0xdd, 0xd4, 0x54, 0xe1, 0xb0, 0x1e, 0x58, 0x52, 0x50, 0x29, 0xca, 0x71, 0x32, 0xa9, 0x14, 0x49,
+	0x36, 0x28, 0xc9, 0x03, 0xbc, 0xa4, 0x14, 0xa9, 0xbd, 0x11, 0x51, 0x24, 0x21, 0xc9, 0x61, 0xcc,
+	0xaf, 0xa0, 0x8a, 0xdf, 0xc0, 0x13, 0xfc, 0x05, 0x5e, 0xf9, 0x25, 0x3c, 0xf3, 0xce, 0x1b, 0xd5,
+	0x17, 0xb5, 0xba, 0x65, 0xe5, 0x06, 0xfb, 0xe4, 0x3e, 0x97, 0x3e, 0xb7, 0xfe, 0xce, 0xe9, 0xb6,
+	0xa0, 0x9b, 0x90, 0xf8, 0xde, 0x73, 0xc8, 0x28, 0x8a, 0xc3, 0x34, 0x44, 0xd5, 0xe8, 0x1a, 0xdf,
+	0xc2, 0xc6, 0xbe, 0x97, 0x38, 0xe1, 0x3d, 0x89, 0x2d, 0xf2, 0x87, 0x39, 0x49, 0xd2, 0x44, 0xfc,
+	0xa2, 0x4d, 0xe8, 0x08, 0xfd, 0x53, 0xfb, 0x8e, 0x98, 0xc6, 0xa6, 0xb1, 0xd5, 0xb6, 0x54, 0x16,
+	0x42, 0xb0, 0xe2, 0xdb, 0x49, 0x6a, 0x56, 0x37, 0x8d, 0xad, 0x96, 0xc5, 0xd6, 0x68, 0x08, 0xad,
+	0x98, 0xdc, 0x7b, 0x89, 0x17, 0x06, 0x66, 0x6d, 0xd3, 0xd8, 0xaa, 0x59, 0x92, 0xc6, 0x07, 0xd0,
+	0x3e, 0x8b, 0xbd, 0xc0, 0xf1, 0x22, 0xdb, 0xa7, 0x9b, 0xd3, 0x45, 0x94, 0xd9, 0x65, 0x6b, 0xca,
+	0x0b, 0xa8, 0xaf, 0x2a, 0xe7, 0xd1, 0x35, 0xea, 0x43, 0xcd, 0x73, 0x5d, 0x66, 0xab, 0x6d, 0xd1,
+	0x25, 0xf6, 0xa1, 0x79, 0x3e, 0xbf, 0xfe, 0x3d, 0x71, 0x52, 0xf4, 0x63, 0x80, 0x28, 0xb3, 0x98,
+	0x98, 0xc6, 0x66, 0x6d, 0xab, 0x33, 0xee, 0x8e, 0xa2, 0xeb, 0x91, 0xf4, 0x63, 0x29, 0x0a, 0xe8,
+	0x1d, 0xb4, 0xd3, 0xf0, 0x96, 0x04, 0x17, 0x8b, 0x28, 0x73, 0x92, 0x33, 0xd0, 0x00, 0xea, 0x8c,
+	0x10, 0xbe, 0x38, 0x81, 0xff, 0x5c, 0x85, 0xde, 0x34, 0x0c, 0x52, 0xf2, 0x29, 0xcd, 0x2a, 0xf3,
+	0x23, 0x68, 0x26, 0x3c, 0x00, 0x16, 0x7d, 0x67, 0xdc, 0xa1, 0x2e, 0x45, 0x4c, 0x56, 0x26, 0x2b,
+	0x16, 0xb0, 0xba, 0x5c, 0x40, 0x56, 0xac, 0x24, 0x9c, 0xc7, 0x0e, 0x11, 0x4e, 0x25, 0x8d, 0xde,
+	0x40, 0xc3, 0x76, 0x52, 0x5a, 0xc6, 0x15, 0x26, 0x11, 0x14, 0xda, 0x03, 0xb0, 0xd3, 0x34, 0xf6,
+	0xae, 0xe7, 0x29, 0x49, 0xcc, 0x3a, 0x4b, 0x19, 0x53, 0xff, 0x7a, 0x90, 0xa3, 0x89, 0x54, 0x3a,
+	0x08, 0xd2, 0x78, 0x61, 0x29, 0xbb, 0x86, 0xbb, 0xb0, 0x5a, 0x10, 0xd3, 0x32, 0xdf, 0x92, 0x85,
+	0x38, 0x0d, 0xba, 0xa4, 0xe5, 0xb8, 0xb7, 0xfd, 0x79, 0x16, 0x38, 0x27, 0x7e, 0x51, 0xdd, 0x31,
+	0xf0, 0x0c, 0xcc, 0x65, 0xd0, 0x24, 0x51, 0x18, 0x24, 0x04, 0x8d, 0x68, 0x4a, 0x9c, 0x27, 0xce,
+	0x03, 0x2d, 0x07, 0x67, 0x49, 0x1d, 0x0d, 0x2f, 0xd5, 0x02, 0x5e, 0x76, 0x60, 0x60, 0x91, 0x84,
+	0xa4, 0x2f, 0x46, 0x26, 0xde, 0x80, 0xd7, 0x85, 0x9d, 0x3c, 0x3c, 0xfc, 0x37, 0x23, 0x07, 0xfc,
+	0x59, 0xe8, 0x7b, 0x8e, 0x47, 0x5e, 0x00, 0xf8, 0x1f, 0x42, 0x57, 0xa2, 0x49, 0xc1, 0x90, 0xce,
+	0xd4, 0xb4, 0x98, 0xa5, 0x5a, 0x41, 0x8b, 0xd9, 0xc2, 0xf0, 0x4a, 0x32, 0x8e, 0x5c, 0x57, 0x9c,
+	0xb2, 0xc6, 0xc3, 0x57, 0x60, 0x2e, 0x07, 0x2b, 0x0a, 0xfd, 0x25, 0xb4, 0x44, 0x68, 0x59, 0xa1,
+	0x39, 0x0a, 0x39, 0xcf, 0x92, 0xc2, 0x47, 0x2b, 0xfc, 0xcf, 0x2a, 0xb4, 0x3e, 0xce, 0x03, 0x8e,
+	0xac, 0xac, 0xfb, 0x0c, 0xa5, 0xfb, 0x36, 0xa1, 0xe3, 0x92, 0xc4, 0x89, 0xbd, 0x28, 0xcd, 0xf6,
+	0xb7, 0x2d, 0x95, 0x85, 0x4c, 0x68, 0xce, 0xe6, 0x81, 0x73, 0x19, 0xfb, 0x22, 0xcf, 0x8c, 0xa4,
+	0x19, 0xfa, 0xa1, 0x63, 0xfb, 0x1f, 0x85, 0x58, 0x64, 0xa8, 0xf2, 0x50, 0x0f, 0xaa, 0x8e, 0x6d,
+	0xd6, 0x99, 0xa4, 0xea, 0xd8, 0xe8, 0x0b, 0xe8, 0xc5, 0x24, 0x99, 0xfb, 0xe9, 0xd4, 0x76, 0x6e,
+	0xec, 0x6b, 0x9f, 0x98, 0x0d, 0x36, 0x5c, 0x0a, 0x5c, 0xda, 0xc9, 0x9c, 0x73, 0x71, 0x71, 0x6c,
+	0x36, 0x59, 0x56, 0x39, 0x03, 0x7d, 0x0d, 0x10, 0x93, 0xc4, 0xf3, 0x3d, 0x12, 0x38, 0xc4, 0x6c,
+	0xb1, 0x1e, 0x7d, 0x43, 0xab, 0x93, 0xe5, 0x6a, 0x49, 0xa9, 0xa5, 0x68, 0x52, 0xab, 0x8e, 0xed,
+	0xdc, 0x90, 0x73, 0xef, 0x4f, 0xc4, 0x6c, 0x73, 0xab, 0x92, 0x41, 0x0b, 0x49, 0xe2, 0x38, 0x8c,
+	0xa9, 0x4b, 0xe0, 0x85, 0xcc, 0x68, 0xfc, 0x6f, 0x03, 0xd0, 0xb2, 0x71, 0x5a, 0x9c, 0xd4, 0xbb,
+	0x23, 0xe1, 0x9c, 0x4f, 0x8a, 0x9a, 0x95, 0x91, 0x54, 0x12, 0x93, 0x34, 0xf6, 0x48, 0xc2, 0x8a,
+	0x5a, 0xb7, 0x32, 0x92, 0x96, 0x8d, 0x2e, 0x17, 0x7b, 0xb6, 0x73, 0x1b, 0xce, 0x66, 0x62, 0x8a,
+	0x6a, 0x3c, 0xf4, 0x1e, 0xfa, 0x33, 0xdb, 0xf3, 0xe7, 0x31, 0xb9, 0xb8, 0x89, 0x49, 0x72, 0x13,
+	0xfa, 0x1c, 0x40, 0x75, 0x6b, 0x89, 0x4f, 0xed, 0x85, 0x11, 0x09, 0xf6, 0xe7, 0xb1, 0xcd, 0xce,
+	0xb0, 0xce, 0xed, 0xa9, 0x3c, 0xf4, 0x13, 0x68, 0xcd, 0x6c, 0xdf, 0xbf, 0xb6, 0x9d, 0x5b, 0x56,
+	0xf0, 0xce, 0x78, 0xa0, 0x96, 0xeb, 0xa3, 0x90, 0x59, 0x52, 0x0b, 0x5b, 0xd0, 0x2f, 0x4a, 0x4b,
+	0x47, 0x7a, 0xe9, 0x14, 0xa1, 0x5c, 0x56, 0xba, 0x6c, 0xd4, 0x32, 0x02, 0xef, 0xc3, 0x20, 0xb3,
+	0xf9, 0x9b, 0x39, 0x89, 0x17, 0x59, 0x63, 0x96, 0x01, 0x93, 0xc2, 0xce, 0xf3, 0x53, 0x12, 0x27,
+	0xc2, 0x72, 0x46, 0xe2, 0x29, 0xbc, 0x2e, 0x58, 0x11, 0x1d, 0xf3, 0x1e, 0xda, 0x33, 0x21, 0xc8,
+	0x5a, 0xe6, 0x95, 0x06, 0x8a, 0x5c, 0x8c, 0xb7, 0xa1, 0x3b, 0x09, 0xdc, 0xb3, 0xfc, 0xea, 0xf8,
+	0x7c, 0xe9, 0xa6, 0x69, 0xab, 0x57, 0x0b, 0x6e, 0x42, 0xfd, 0xe0, 0x2e, 0x4a, 0x17, 0xf8, 0x1f,
+	0x06, 0xf4, 0xb2, 0x26, 0x7c, 0x24, 0xfe, 0x1f, 0x88, 0x5a, 0xd1, 0xe0, 0x7b, 0xe3, 0x55, 0xa5,
+	0x75, 0xe9, 0x0c, 0x11, 0xc5, 0x7b, 0x03, 0x8d, 0xf0, 0x8f, 0x01, 0xcd, 0xb1, 0xc6, 0x1c, 0x0a,
+	0x8a, 0x4e, 0x18, 0x97, 0xcc, 0xec, 0xb9, 0x9f, 0x1e, 0xcc, 0x66, 0xf4, 0x1a, 0xe2, 0xad, 0xa5,
+	0x33, 0x69, 0xef, 0x0a, 0x30, 0x9c, 0x84, 0x2e, 0x11, 0x4d, 0xa6, 0xb2, 0x68, 0x60, 0x77, 0x54,
+	0xd4, 0xe0, 0x81, 0xd1, 0x35, 0xbe, 0x84, 0x2e, 0x9b, 0x35, 0x8b, 0xe7, 0x8f, 0x45, 0x0c, 0x8d,
+	0x88, 0x6d, 0x61, 0xd9, 0x74, 0xc6, 0xc0, 0x6e, 0x60, 0x6e, 0x44, 0x48, 0xf0, 0xaf, 0x60, 0x20,
+	0xf2, 0xd3, 0x0f, 0xe5, 0xb9, 0x63, 0x0c, 0x5f, 0xc2, 0xba, 0x6e, 0xe0, 0xe1, 0xda, 0x0e, 0xa0,
+	0xce, 0x0a, 0x95, 0x61, 0x8e, 0x11, 0x19, 0x97, 0x3f, 0x25, 0x5a, 0x9c, 0x4b, 0x1f, 0x13, 0x88,
+	0x47, 0xaa, 0x59, 0x7d, 0x3a, 0xe7, 0x21, 0xb4, 0x78, 0x66, 0x47, 0xfb, 0xc2, 0x8d, 0xa4, 0x55,
+	0x6c, 0xd6, 0x74, 0x6c, 0xee, 0xc2, 0xba, 0xe6, 0x4d, 0x14, 0xe1, 0x0b, 0x61, 0xcc, 0x93, 0x45,
+	0x50, 0x4b, 0x28, 0x65, 0xf8, 0xaf, 0x35, 0x68, 0x70, 0x26, 0x1d, 0x9c, 0x9e, 0x2b, 0x02, 0xab,
+	0x7a, 0x6e, 0xe9, 0xd3, 0x09, 0x43, 0x83, 0x70, 0x7c, 0xd4, 0x18, 0xca, 0x98, 0x51, 0x0e, 0x0e,
+	0x4b, 0x48, 0xd0, 0xcf, 0xa1, 0x13, 0x91, 0xf8, 0xce, 0x4b, 0x12, 0xd6, 0x16, 0x2b, 0xcc, 0xfb,
+	0xeb, 0xdc, 0xfb, 0xe8, 0x4c, 0x4a, 0x2d, 0x55, 0x13, 0x7d, 0xa5, 0x35, 0x04, 0x7f, 0x87, 0xac,
+	0xd1, 0x7d, 0x5a, 0xdf, 0x14, 0x9f, 0x5f, 0x4e, 0x18, 0xb8, 0x1e, 0x1b, 0x43, 0x1c, 0x73, 0x39,
+	0x83, 0x56, 0xd4, 0xf5, 0x12, 0x3a, 0xdd, 0x5d, 0x36, 0xd1, 0x5b, 0x96, 0xa4, 0xe9, 0xce, 0x20,
+	0x4c, 0xf7, 0xc8, 0x2c, 0x8c, 0xf9, 0x3c, 0x6f, 0x5b, 0x39, 0x83, 0xee, 0x0c, 0xc2, 0x74, 0x32,
+	0x4b, 0x49, 0xcc, 0xa6, 0x76, 0xdb, 0x92, 0xf4, 0x30, 0x01, 0xc8, 0x33, 0xd0, 0x1e, 0x5c, 0x46,
+	0xe1, 0xc1, 0xb5, 0x0d, 0xeb, 0xd9, 0xfa, 0x8a, 0x7c, 0x8a, 0x62, 0x92, 0x24, 0xf9, 0x95, 0x87,
+	0x32, 0xd1, 0x81, 0x94, 0xd0, 0x63, 0xb6, 0xc5, 0x34, 0xe1, 0xed, 0x99, 0x91, 0x98, 0xc0, 0x9a,
+	0x15, 0xfa, 0xe4, 0xa5, 0x7d, 0x34, 0x02, 0x88, 0xe5, 0x36, 0xd1, 0x4b, 0x3d, 0x5a, 0x52, 0xc5,
+	0x98, 0xa2, 0x81, 0x3f, 0xc1, 0x9b, 0x5c, 0xf2, 0x42, 0xfc, 0xd2, 0x5b, 0x46, 0xee, 0x95, 0x18,
+	0xd6, 0x78, 0x8f, 0xe0, 0xf8, 0x04, 0x36, 0x96, 0x3c, 0x0b, 0x2c, 0x8f, 0x15, 0xc3, 0x39, 0x9e,
+	0x8b, 0x69, 0x68, 0x3a, 0xf8, 0x3f, 0x06, 0x40, 0x2e, 0xfc, 0xce, 0xb0, 0x3d, 0x80, 0x3a, 0x75,
+	0xc3, 0x51, 0xdd, 0xb6, 0x38, 0x81, 0x3e, 0x5f, 0x02, 0x6e, 0xbb, 0x88, 0xd2, 0xec, 0xb0, 0x13,
+	0xb3, 0xc1, 0xc4, 0x39, 0x03, 0x7d, 0x05, 0x83, 0x12, 0x94, 0x24, 0x66, 0x93, 0x29, 0xae, 0x2f,
+	0xc3, 0xa4, 0x00, 0xfb, 0x56, 0x01, 0xf6, 0xf8, 0x2f, 0x55, 0x68, 0x8a, 0xc1, 0xf6, 0xbf, 0x5f,
+	0x14, 0xea, 0x00, 0xa9, 0x3d, 0x3c, 0x40, 0xd0, 0x07, 0xe8, 0xd2, 0x22, 0x5c, 0x49, 0xe5, 0x95,
+	0xa7, 0x4f, 0x47, 0xb9, 0x85, 0xea, 0x8f, 0xdf, 0x42, 0x8d, 0x67, 0xdc, 0x42, 0xcd, 0x87, 0x6f,
+	0xa1, 0x96, 0x72, 0x0b, 0x9d, 0xc9, 0xeb, 0xe2, 0x1b, 0xe6, 0xec, 0xf9, 0xc0, 0xce, 0xa3, 0xad,
+	0xaa, 0xd1, 0xe2, 0x13, 0x58, 0x57, 0x2d, 0x3e, 0xdf, 0x60, 0xe9, 0x6d, 0x82, 0xbf, 0x84, 0xae,
+	0x16, 0xa0, 0xe2, 0xd7, 0xd0, 0xfc, 0x7e, 0x0b, 0x6f, 0x79, 0x55, 0x27, 0x81, 0x9b, 0x97, 0x78,
+	0x1a, 0xce, 0x83, 0x34, 0xa1, 0xde, 0xa3, 0x9c, 0x16, 0x6f, 0x44, 0x95, 0x85, 0xb6, 0x60, 0x35,
+	0xd6, 0x77, 0x89, 0x47, 0x7c, 0x91, 0x8d, 0xff, 0x6e, 0xc0, 0xaa, 0x6a, 0xfc, 0xc4, 0x8e, 0xd0,
+	0x2e, 0xb4, 0x1c, 0x4a, 0x9c, 0xd8, 0x91, 0x68, 0xc4, 0xef, 0xe7, 0xb8, 0x90, 0x6a, 0xa3, 0xa9,
+	0xd0, 0xe1, 0xff, 0x14, 0xe5, 0x96, 0xe1, 0xef, 0xa0, 0xab, 0x89, 0x4a, 0xfe, 0x25, 0x7e, 0x50,
+	0xdf, 0x77, 0x9d, 0xf1, 0x67, 0xb9, 0xf9, 0x92, 0x7c, 0x95, 0x3f, 0x91, 0xef, 0x3f, 0x83, 0x86,
+	0x40, 0x48, 0x1b, 0xea, 0x87, 0xd6, 0xe4, 0xf4, 0xa2, 0x5f, 0x41, 0x2d, 0x58, 0xd9, 0x3f, 0x38,
+	0xfd, 0x6d, 0xdf, 0x78, 0xbf, 0x0d, 0x1d, 0x05, 0xe6, 0x68, 0x15, 0x3a, 0x93, 0xb3, 0xb3, 0xe3,
+	0xa3, 0xe9, 0xe4, 0xe2, 0xe8, 0x9b, 0xd3, 0x7e, 0x85, 0x32, 0x7e, 0xbd, 0x73, 0x7e, 0x35, 0x3d,
+	0xbe, 0x3c, 0xbf, 0x38, 0xb0, 0xfa, 0xc6, 0xf8, 0x5f, 0xed, 0xec, 0xe1, 0x72, 0x62, 0x07, 0xf6,
+	0xb7, 0x24, 0x46, 0x23, 0xe8, 0x4d, 0x63, 0x62, 0xa7, 0x44, 0xfe, 0xc3, 0xd1, 0x9e, 0x7b, 0x43,
+	0x8d, 0xc2, 0x15, 0x74, 0x08, 0x3d, 0x36, 0xca, 0x32, 0x56, 0x82, 0x4c, 0x55, 0x43, 0x1d, 0xb0,
+	0xc3, 0xb7, 0x25, 0x12, 0xf1, 0x17, 0xb3, 0x82, 0x76, 0x60, 0x75, 0x9f, 0xf8, 0x24, 0x25, 0xcf,
+	0xb1, 0xd4, 0x66, 0x83, 0x8b, 0x3d, 0x1d, 0x2b, 0x68, 0x0c, 0x5d, 0x1e, 0xb2, 0x9c, 0x08, 0xea,
+	0x63, 0x48, 0xec, 0x50, 0x1f, 0x48, 0xb8, 0x82, 0xf6, 0xa1, 0xcb, 0x0c, 0x9e, 0x67, 0x7f, 0xf8,
+	0x36, 0x14, 0xb9, 0xe6, 0xca, 0x5c, 0x16, 0xc8, 0x98, 0xbf, 0x86, 0x1e, 0x8f, 0xf9, 0x69, 0x33,
+	0x5a, 0xc4, 0xdb, 0xf0, 0x8a, 0x47, 0x2c, 0x66, 0xf7, 0x9a, 0x32, 0x77, 0x84, 0xbe, 0x32, 0x8a,
+	0x70, 0x05, 0xed, 0x89, 0x70, 0xf3, 0xf1, 0x92, 0x8b, 0x35, 0x37, 0x1b, 0x4b, 0x7c, 0x19, 0xec,
+	0xcf, 0xb2, 0x60, 0x9f, 0x34, 0xa2, 0xc5, 0xfa, 0x4b, 0xe8, 0xf3, 0x58, 0x95, 0xbb, 0xe6, 0x75,
+	0x61, 0xf4, 0x89, 0x7d, 0x85, 0x89, 0x88, 0x2b, 0xe8, 0x14, 0xd6, 0xb8, 0x65, 0x75, 0x34, 0x0e,
+	0x75, 0x35, 0xcd, 0xf5, 0xf7, 0x4a, 0x65, 0x32, 0x87, 0x5d, 0x40, 0x3c, 0x87, 0x67, 0x1b, 0xd4,
+	0x72, 0xf9, 0x29, 0xf4, 0x8f, 0xbd, 0x24, 0xd5, 0xa6, 0x49, 0xae, 0x30, 0x5c, 0x2f, 0x69, 0x73,
+	0x5c, 0x41, 0x13, 0xe8, 0x1f, 0x92, 0x54, 0x1f, 0x5c, 0x2a, 0x2a, 0xb4, 0x61, 0x3b, 0x5c, 0x5b,
+	0x92, 0x70, 0x13, 0x13, 0xd7, 0xfd, 0xbf, 0x4c, 0xec, 0x01, 0xb2, 0xc8, 0x5d, 0x78, 0x4f, 0x54,
+	0x81, 0x86, 0x37, 0x75, 0x44, 0x97, 0xdb, 0xb0, 0x60, 0xfd, 0x90, 0xa4, 0xc5, 0xcf, 0x50, 0x88,
+	0x15, 0xfd, 0x81, 0x2f, 0x9a, 0xc3, 0x77, 0xe5, 0x42, 0x79, 0x24, 0xa7, 0xe2, 0xab, 0xd1, 0x92,
+	0x55, 0x96, 0x5f, 0xd9, 0xa7, 0xa8, 0xe1, 0xdb, 0x12, 0x89, 0xb4, 0xa7, 0xc7, 0x28, 0xcf, 0x58,
+	0x8b, 0xb1, 0xf0, 0x11, 0x6a, 0xf8, 0xae, 0x5c, 0x98, 0xd9, 0xbc, 0x6e, 0xb0, 0x6f, 0xb7, 0x1f,
+	0xfe, 0x3b, 0x00, 0xe6, 0xd8, 0x60, 0xe6, 0xcc, 0x15, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
-var _ grpc.ClientConn
+var _ grpc.ClientConnInterface
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// Client API for PolicyManager service
+const _ = grpc.SupportPackageIsVersion6
 
+// PolicyManagerClient is the client API for PolicyManager service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type PolicyManagerClient interface {
 	CreateFunction(ctx context.Context, in *Function, opts ...grpc.CallOption) (*Function, error)
 	QueryFunctions(ctx context.Context, in *FunctionQueryRequest, opts ...grpc.CallOption) (*FunctionQueryResponse, error)
@@ -983,22 +2142,25 @@ type PolicyManagerClient interface {
 	QueryRolePolicies(ctx context.Context, in *RolePolicyQueryRequest, opts ...grpc.CallOption) (*RolePolicyQueryResponse, error)
 	DeleteRolePolicies(ctx context.Context, in *RolePolicyQueryRequest, opts ...grpc.CallOption) (*Empty, error)
 	ListPolicyCounts(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PolicyCountsMap, error)
+	GetServiceOwners(ctx context.Context, in *ServiceOwnersRequest, opts ...grpc.CallOption) (*ServiceOwners, error)
+	AddServiceOwners(ctx context.Context, in *ServiceOwnersRequest, opts ...grpc.CallOption) (*ServiceOwners, error)
+	RemoveServiceOwner(ctx context.Context, in *ServiceOwnerRequest, opts ...grpc.CallOption) (*ServiceOwners, error)
 	GetDiscoverRequests(ctx context.Context, in *DiscoverRequestsRequest, opts ...grpc.CallOption) (*DiscoverRequestsResponse, error)
 	ResetDiscoverRequests(ctx context.Context, in *ResetRequestsRequest, opts ...grpc.CallOption) (*ResetRequestsResponse, error)
 	GetDiscoverPolicies(ctx context.Context, in *DiscoverPoliciesRequest, opts ...grpc.CallOption) (*DiscoverPoliciesResponse, error)
 }
 
 type policyManagerClient struct {
-	cc *grpc.ClientConn
+	cc grpc.ClientConnInterface
 }
 
-func NewPolicyManagerClient(cc *grpc.ClientConn) PolicyManagerClient {
+func NewPolicyManagerClient(cc grpc.ClientConnInterface) PolicyManagerClient {
 	return &policyManagerClient{cc}
 }
 
 func (c *policyManagerClient) CreateFunction(ctx context.Context, in *Function, opts ...grpc.CallOption) (*Function, error) {
 	out := new(Function)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/CreateFunction", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/CreateFunction", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1007,7 +2169,7 @@ func (c *policyManagerClient) CreateFunction(ctx context.Context, in *Function,
 
 func (c *policyManagerClient) QueryFunctions(ctx context.Context, in *FunctionQueryRequest, opts ...grpc.CallOption) (*FunctionQueryResponse, error) {
 	out := new(FunctionQueryResponse)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/QueryFunctions", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/QueryFunctions", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1016,7 +2178,7 @@ func (c *policyManagerClient) QueryFunctions(ctx context.Context, in *FunctionQu
 
 func (c *policyManagerClient) DeleteFunctions(ctx context.Context, in *FunctionQueryRequest, opts ...grpc.CallOption) (*Empty, error) {
 	out := new(Empty)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/DeleteFunctions", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/DeleteFunctions", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1025,7 +2187,7 @@ func (c *policyManagerClient) DeleteFunctions(ctx context.Context, in *FunctionQ
 
 func (c *policyManagerClient) CreateService(ctx context.Context, in *ServiceRequest, opts ...grpc.CallOption) (*Service, error) {
 	out := new(Service)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/CreateService", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/CreateService", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1034,7 +2196,7 @@ func (c *policyManagerClient) CreateService(ctx context.Context, in *ServiceRequ
 
 func (c *policyManagerClient) QueryServices(ctx context.Context, in *ServiceQueryRequest, opts ...grpc.CallOption) (*ServiceQueryResponse, error) {
 	out := new(ServiceQueryResponse)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/QueryServices", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/QueryServices", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1043,7 +2205,7 @@ func (c *policyManagerClient) QueryServices(ctx context.Context, in *ServiceQuer
 
 func (c *policyManagerClient) DeleteServices(ctx context.Context, in *ServiceQueryRequest, opts ...grpc.CallOption) (*Empty, error) {
 	out := new(Empty)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/DeleteServices", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/DeleteServices", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1052,7 +2214,7 @@ func (c *policyManagerClient) DeleteServices(ctx context.Context, in *ServiceQue
 
 func (c *policyManagerClient) CreatePolicy(ctx context.Context, in *PolicyRequest, opts ...grpc.CallOption) (*Policy, error) {
 	out := new(Policy)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/CreatePolicy", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/CreatePolicy", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1061,7 +2223,7 @@ func (c *policyManagerClient) CreatePolicy(ctx context.Context, in *PolicyReques
 
 func (c *policyManagerClient) QueryPolicies(ctx context.Context, in *PolicyQueryRequest, opts ...grpc.CallOption) (*PolicyQueryResponse, error) {
 	out := new(PolicyQueryResponse)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/QueryPolicies", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/QueryPolicies", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1070,7 +2232,7 @@ func (c *policyManagerClient) QueryPolicies(ctx context.Context, in *PolicyQuery
 
 func (c *policyManagerClient) DeletePolicies(ctx context.Context, in *PolicyQueryRequest, opts ...grpc.CallOption) (*Empty, error) {
 	out := new(Empty)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/DeletePolicies", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/DeletePolicies", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1079,7 +2241,7 @@ func (c *policyManagerClient) DeletePolicies(ctx context.Context, in *PolicyQuer
 
 func (c *policyManagerClient) CreateRolePolicy(ctx context.Context, in *RolePolicyRequest, opts ...grpc.CallOption) (*RolePolicy, error) {
 	out := new(RolePolicy)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/CreateRolePolicy", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/CreateRolePolicy", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1088,7 +2250,7 @@ func (c *policyManagerClient) CreateRolePolicy(ctx context.Context, in *RolePoli
 
 func (c *policyManagerClient) QueryRolePolicies(ctx context.Context, in *RolePolicyQueryRequest, opts ...grpc.CallOption) (*RolePolicyQueryResponse, error) {
 	out := new(RolePolicyQueryResponse)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/QueryRolePolicies", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/QueryRolePolicies", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1097,7 +2259,7 @@ func (c *policyManagerClient) QueryRolePolicies(ctx context.Context, in *RolePol
 
 func (c *policyManagerClient) DeleteRolePolicies(ctx context.Context, in *RolePolicyQueryRequest, opts ...grpc.CallOption) (*Empty, error) {
 	out := new(Empty)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/DeleteRolePolicies", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/DeleteRolePolicies", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1106,7 +2268,34 @@ func (c *policyManagerClient) DeleteRolePolicies(ctx context.Context, in *RolePo
 
 func (c *policyManagerClient) ListPolicyCounts(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PolicyCountsMap, error) {
 	out := new(PolicyCountsMap)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/ListPolicyCounts", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/ListPolicyCounts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *policyManagerClient) GetServiceOwners(ctx context.Context, in *ServiceOwnersRequest, opts ...grpc.CallOption) (*ServiceOwners, error) {
+	out := new(ServiceOwners)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/GetServiceOwners", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *policyManagerClient) AddServiceOwners(ctx context.Context, in *ServiceOwnersRequest, opts ...grpc.CallOption) (*ServiceOwners, error) {
+	out := new(ServiceOwners)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/AddServiceOwners", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *policyManagerClient) RemoveServiceOwner(ctx context.Context, in *ServiceOwnerRequest, opts ...grpc.CallOption) (*ServiceOwners, error) {
+	out := new(ServiceOwners)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/RemoveServiceOwner", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1115,7 +2304,7 @@ func (c *policyManagerClient) ListPolicyCounts(ctx context.Context, in *Empty, o
 
 func (c *policyManagerClient) GetDiscoverRequests(ctx context.Context, in *DiscoverRequestsRequest, opts ...grpc.CallOption) (*DiscoverRequestsResponse, error) {
 	out := new(DiscoverRequestsResponse)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/GetDiscoverRequests", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/GetDiscoverRequests", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1124,7 +2313,7 @@ func (c *policyManagerClient) GetDiscoverRequests(ctx context.Context, in *Disco
 
 func (c *policyManagerClient) ResetDiscoverRequests(ctx context.Context, in *ResetRequestsRequest, opts ...grpc.CallOption) (*ResetRequestsResponse, error) {
 	out := new(ResetRequestsResponse)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/ResetDiscoverRequests", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/ResetDiscoverRequests", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1133,15 +2322,14 @@ func (c *policyManagerClient) ResetDiscoverRequests(ctx context.Context, in *Res
 
 func (c *policyManagerClient) GetDiscoverPolicies(ctx context.Context, in *DiscoverPoliciesRequest, opts ...grpc.CallOption) (*DiscoverPoliciesResponse, error) {
 	out := new(DiscoverPoliciesResponse)
-	err := grpc.Invoke(ctx, "/pb.PolicyManager/GetDiscoverPolicies", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/pb.PolicyManager/GetDiscoverPolicies", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// Server API for PolicyManager service
-
+// PolicyManagerServer is the server API for PolicyManager service.
 type PolicyManagerServer interface {
 	CreateFunction(context.Context, *Function) (*Function, error)
 	QueryFunctions(context.Context, *FunctionQueryRequest) (*FunctionQueryResponse, error)
@@ -1156,11 +2344,76 @@ type PolicyManagerServer interface {
 	QueryRolePolicies(context.Context, *RolePolicyQueryRequest) (*RolePolicyQueryResponse, error)
 	DeleteRolePolicies(context.Context, *RolePolicyQueryRequest) (*Empty, error)
 	ListPolicyCounts(context.Context, *Empty) (*PolicyCountsMap, error)
+	GetServiceOwners(context.Context, *ServiceOwnersRequest) (*ServiceOwners, error)
+	AddServiceOwners(context.Context, *ServiceOwnersRequest) (*ServiceOwners, error)
+	RemoveServiceOwner(context.Context, *ServiceOwnerRequest) (*ServiceOwners, error)
 	GetDiscoverRequests(context.Context, *DiscoverRequestsRequest) (*DiscoverRequestsResponse, error)
 	ResetDiscoverRequests(context.Context, *ResetRequestsRequest) (*ResetRequestsResponse, error)
 	GetDiscoverPolicies(context.Context, *DiscoverPoliciesRequest) (*DiscoverPoliciesResponse, error)
 }
 
+// UnimplementedPolicyManagerServer can be embedded to have forward compatible implementations.
+type UnimplementedPolicyManagerServer struct {
+}
+
+func (*UnimplementedPolicyManagerServer) CreateFunction(ctx context.Context, req *Function) (*Function, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFunction not implemented")
+}
+func (*UnimplementedPolicyManagerServer) QueryFunctions(ctx context.Context, req *FunctionQueryRequest) (*FunctionQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryFunctions not implemented")
+}
+func (*UnimplementedPolicyManagerServer) DeleteFunctions(ctx context.Context, req *FunctionQueryRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFunctions not implemented")
+}
+func (*UnimplementedPolicyManagerServer) CreateService(ctx context.Context, req *ServiceRequest) (*Service, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateService not implemented")
+}
+func (*UnimplementedPolicyManagerServer) QueryServices(ctx context.Context, req *ServiceQueryRequest) (*ServiceQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryServices not implemented")
+}
+func (*UnimplementedPolicyManagerServer) DeleteServices(ctx context.Context, req *ServiceQueryRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteServices not implemented")
+}
+func (*UnimplementedPolicyManagerServer) CreatePolicy(ctx context.Context, req *PolicyRequest) (*Policy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePolicy not implemented")
+}
+func (*UnimplementedPolicyManagerServer) QueryPolicies(ctx context.Context, req *PolicyQueryRequest) (*PolicyQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryPolicies not implemented")
+}
+func (*UnimplementedPolicyManagerServer) DeletePolicies(ctx context.Context, req *PolicyQueryRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePolicies not implemented")
+}
+func (*UnimplementedPolicyManagerServer) CreateRolePolicy(ctx context.Context, req *RolePolicyRequest) (*RolePolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRolePolicy not implemented")
+}
+func (*UnimplementedPolicyManagerServer) QueryRolePolicies(ctx context.Context, req *RolePolicyQueryRequest) (*RolePolicyQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryRolePolicies not implemented")
+}
+func (*UnimplementedPolicyManagerServer) DeleteRolePolicies(ctx context.Context, req *RolePolicyQueryRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRolePolicies not implemented")
+}
+func (*UnimplementedPolicyManagerServer) ListPolicyCounts(ctx context.Context, req *Empty) (*PolicyCountsMap, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPolicyCounts not implemented")
+}
+func (*UnimplementedPolicyManagerServer) GetServiceOwners(ctx context.Context, req *ServiceOwnersRequest) (*ServiceOwners, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServiceOwners not implemented")
+}
+func (*UnimplementedPolicyManagerServer) AddServiceOwners(ctx context.Context, req *ServiceOwnersRequest) (*ServiceOwners, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddServiceOwners not implemented")
+}
+func (*UnimplementedPolicyManagerServer) RemoveServiceOwner(ctx context.Context, req *ServiceOwnerRequest) (*ServiceOwners, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveServiceOwner not implemented")
+}
+func (*UnimplementedPolicyManagerServer) GetDiscoverRequests(ctx context.Context, req *DiscoverRequestsRequest) (*DiscoverRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDiscoverRequests not implemented")
+}
+func (*UnimplementedPolicyManagerServer) ResetDiscoverRequests(ctx context.Context, req *ResetRequestsRequest) (*ResetRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetDiscoverRequests not implemented")
+}
+func (*UnimplementedPolicyManagerServer) GetDiscoverPolicies(ctx context.Context, req *DiscoverPoliciesRequest) (*DiscoverPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDiscoverPolicies not implemented")
+}
+
 func RegisterPolicyManagerServer(s *grpc.Server, srv PolicyManagerServer) {
 	s.RegisterService(&_PolicyManager_serviceDesc, srv)
 }
@@ -1399,6 +2652,60 @@ func _PolicyManager_ListPolicyCounts_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PolicyManager_GetServiceOwners_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceOwnersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyManagerServer).GetServiceOwners(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.PolicyManager/GetServiceOwners",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyManagerServer).GetServiceOwners(ctx, req.(*ServiceOwnersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PolicyManager_AddServiceOwners_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceOwnersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyManagerServer).AddServiceOwners(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.PolicyManager/AddServiceOwners",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyManagerServer).AddServiceOwners(ctx, req.(*ServiceOwnersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PolicyManager_RemoveServiceOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceOwnerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyManagerServer).RemoveServiceOwner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.PolicyManager/RemoveServiceOwner",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyManagerServer).RemoveServiceOwner(ctx, req.(*ServiceOwnerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PolicyManager_GetDiscoverRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DiscoverRequestsRequest)
 	if err := dec(in); err != nil {
@@ -1509,6 +2816,18 @@ var _PolicyManager_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListPolicyCounts",
 			Handler:    _PolicyManager_ListPolicyCounts_Handler,
 		},
+		{
+			MethodName: "GetServiceOwners",
+			Handler:    _PolicyManager_GetServiceOwners_Handler,
+		},
+		{
+			MethodName: "AddServiceOwners",
+			Handler:    _PolicyManager_AddServiceOwners_Handler,
+		},
+		{
+			MethodName: "RemoveServiceOwner",
+			Handler:    _PolicyManager_RemoveServiceOwner_Handler,
+		},
 		{
 			MethodName: "GetDiscoverRequests",
 			Handler:    _PolicyManager_GetDiscoverRequests_Handler,
@@ -1525,97 +2844,3 @@ var _PolicyManager_serviceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.proto",
 }
-
-func init() { proto.RegisterFile("service.proto", fileDescriptor0) }
-
-var fileDescriptor0 = []byte{
-	// 1400 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xdb, 0x6e, 0xdb, 0x46,
-	0x13, 0x16, 0x25, 0xeb, 0x34, 0xb2, 0x64, 0x79, 0x6d, 0xc7, 0x8c, 0xfe, 0xe4, 0x87, 0xbb, 0x6d,
-	0x53, 0x37, 0x40, 0x65, 0x44, 0xe9, 0xc1, 0x68, 0x61, 0x14, 0x8e, 0xac, 0x04, 0x46, 0x6d, 0xd7,
-	0xa5, 0x9d, 0x8b, 0xf6, 0xc6, 0xa0, 0xa8, 0x75, 0xca, 0x86, 0x21, 0x59, 0x2e, 0x65, 0x44, 0x6f,
-	0xd1, 0xeb, 0xde, 0xf4, 0x01, 0xda, 0x77, 0xe9, 0x4d, 0x5f, 0xa6, 0x77, 0xc5, 0x1e, 0xb8, 0xdc,
-	0xa5, 0x14, 0x1f, 0x8a, 0x5e, 0x89, 0x3b, 0x33, 0x3b, 0xf3, 0xcd, 0xec, 0x37, 0xb3, 0xa4, 0xa0,
-	0x4d, 0x49, 0x72, 0xe5, 0x7b, 0xa4, 0x1f, 0x27, 0x51, 0x1a, 0xa1, 0x72, 0x3c, 0xc6, 0xaf, 0x61,
-	0xf3, 0xc0, 0xa7, 0x5e, 0x74, 0x45, 0x12, 0x87, 0xfc, 0x3c, 0x25, 0x34, 0xa5, 0xf2, 0x17, 0x6d,
-	0x41, 0x4b, 0xda, 0x9f, 0xb8, 0x6f, 0x88, 0x6d, 0x6d, 0x59, 0xdb, 0x4d, 0x47, 0x17, 0x21, 0x04,
-	0x4b, 0x81, 0x4b, 0x53, 0xbb, 0xbc, 0x65, 0x6d, 0x37, 0x1c, 0xfe, 0x8c, 0x7a, 0xd0, 0x48, 0xc8,
-	0x95, 0x4f, 0xfd, 0x28, 0xb4, 0x2b, 0x5b, 0xd6, 0x76, 0xc5, 0x51, 0x6b, 0x3c, 0x82, 0xe6, 0x69,
-	0xe2, 0x87, 0x9e, 0x1f, 0xbb, 0x01, 0xdb, 0x9c, 0xce, 0xe2, 0xcc, 0x2f, 0x7f, 0x66, 0xb2, 0x90,
-	0xc5, 0x2a, 0x0b, 0x19, 0x7b, 0x46, 0x5d, 0xa8, 0xf8, 0x93, 0x09, 0xf7, 0xd5, 0x74, 0xd8, 0x23,
-	0x0e, 0xa0, 0x7e, 0x36, 0x1d, 0xff, 0x44, 0xbc, 0x14, 0x7d, 0x02, 0x10, 0x67, 0x1e, 0xa9, 0x6d,
-	0x6d, 0x55, 0xb6, 0x5b, 0x83, 0x76, 0x3f, 0x1e, 0xf7, 0x55, 0x1c, 0x47, 0x33, 0x40, 0x0f, 0xa0,
-	0x99, 0x46, 0xaf, 0x49, 0x78, 0xce, 0x02, 0x8b, 0x20, 0xb9, 0x00, 0xad, 0x43, 0x95, 0x2f, 0x64,
-	0x2c, 0xb1, 0xc0, 0xbf, 0x94, 0xa1, 0x33, 0x8c, 0xc2, 0x94, 0xbc, 0x4d, 0xb3, 0xca, 0x7c, 0x08,
-	0x75, 0x2a, 0x00, 0x70, 0xf4, 0xad, 0x41, 0x8b, 0x85, 0x94, 0x98, 0x9c, 0x4c, 0x57, 0x2c, 0x60,
-	0x79, 0xbe, 0x80, 0xbc, 0x58, 0x34, 0x9a, 0x26, 0x1e, 0x91, 0x41, 0xd5, 0x1a, 0xdd, 0x83, 0x9a,
-	0xeb, 0xa5, 0xac, 0x8c, 0x4b, 0x5c, 0x23, 0x57, 0xe8, 0x19, 0x80, 0x9b, 0xa6, 0x89, 0x3f, 0x9e,
-	0xa6, 0x84, 0xda, 0x55, 0x9e, 0x32, 0x66, 0xf1, 0x4d, 0x90, 0xfd, 0x7d, 0x65, 0x34, 0x0a, 0xd3,
-	0x64, 0xe6, 0x68, 0xbb, 0x7a, 0x7b, 0xb0, 0x52, 0x50, 0xb3, 0x32, 0xbf, 0x26, 0x33, 0x79, 0x1a,
-	0xec, 0x91, 0x95, 0xe3, 0xca, 0x0d, 0xa6, 0x19, 0x70, 0xb1, 0xf8, 0xb2, 0xbc, 0x6b, 0xe1, 0x4b,
-	0xb0, 0xe7, 0x49, 0x43, 0xe3, 0x28, 0xa4, 0x04, 0xf5, 0x59, 0x4a, 0x42, 0x26, 0xcf, 0x03, 0xcd,
-	0x83, 0x73, 0x94, 0x8d, 0xc1, 0x97, 0x72, 0x81, 0x2f, 0xbb, 0xb0, 0xee, 0x10, 0x4a, 0xd2, 0x3b,
-	0x33, 0x13, 0x6f, 0xc2, 0x46, 0x61, 0xa7, 0x80, 0x87, 0x7f, 0xb7, 0x72, 0xc2, 0x9f, 0x46, 0x81,
-	0xef, 0xf9, 0xe4, 0x0e, 0x84, 0xff, 0x00, 0xda, 0x8a, 0x4d, 0x1a, 0x87, 0x4c, 0xa1, 0x61, 0xc5,
-	0x3d, 0x55, 0x0a, 0x56, 0xdc, 0x17, 0x86, 0x65, 0x25, 0x38, 0x9c, 0x4c, 0xe4, 0x29, 0x1b, 0x32,
-	0x7c, 0x91, 0x17, 0x3a, 0x07, 0x2b, 0x0b, 0xfd, 0x11, 0x34, 0x24, 0xb4, 0xac, 0xd0, 0x82, 0x85,
-	0x42, 0xe6, 0x28, 0xe5, 0xb5, 0x15, 0xfe, 0xcb, 0x82, 0xc6, 0xf3, 0x69, 0x28, 0x98, 0x95, 0x75,
-	0x9f, 0xa5, 0x75, 0xdf, 0x16, 0xb4, 0x26, 0x84, 0x7a, 0x89, 0x1f, 0xa7, 0xd9, 0xfe, 0xa6, 0xa3,
-	0x8b, 0x90, 0x0d, 0xf5, 0xcb, 0x69, 0xe8, 0xbd, 0x4c, 0x02, 0x99, 0x67, 0xb6, 0x64, 0x19, 0x06,
-	0x91, 0xe7, 0x06, 0xcf, 0xa5, 0x5a, 0x66, 0xa8, 0xcb, 0x50, 0x07, 0xca, 0x9e, 0x6b, 0x57, 0xb9,
-	0xa6, 0xec, 0xb9, 0xe8, 0x11, 0x74, 0x12, 0x42, 0xa7, 0x41, 0x3a, 0x74, 0xbd, 0x1f, 0xdd, 0x71,
-	0x40, 0xec, 0x1a, 0x1f, 0x2e, 0x05, 0x29, 0xeb, 0x64, 0x21, 0x39, 0x3f, 0x3f, 0xb2, 0xeb, 0x3c,
-	0xab, 0x5c, 0x80, 0x0f, 0x60, 0x3d, 0xcb, 0xea, 0xbb, 0x29, 0x49, 0x66, 0xd9, 0x09, 0x2f, 0xca,
-	0x90, 0xe1, 0xf7, 0x83, 0x94, 0x24, 0x54, 0x66, 0x97, 0x2d, 0xf1, 0x10, 0x36, 0x0a, 0x5e, 0x64,
-	0xe9, 0x1f, 0x43, 0xf3, 0x52, 0x2a, 0xb2, 0xda, 0x2f, 0xb3, 0xda, 0x67, 0xd6, 0x4e, 0xae, 0xc6,
-	0x3b, 0xd0, 0xde, 0x0f, 0x27, 0xa7, 0xf9, 0x0c, 0xfa, 0xff, 0xdc, 0xc8, 0x6a, 0xea, 0x33, 0x0a,
-	0xd7, 0xa1, 0x3a, 0x7a, 0x13, 0xa7, 0x33, 0x7c, 0x08, 0x9d, 0xec, 0x30, 0xaf, 0x81, 0xff, 0xbe,
-	0x1c, 0xa3, 0x0c, 0x7b, 0x67, 0xb0, 0xa2, 0x51, 0x80, 0x71, 0x51, 0xcc, 0x55, 0xfc, 0x12, 0xda,
-	0x9c, 0x3f, 0xb3, 0xdb, 0x53, 0x1d, 0x43, 0x2d, 0xe6, 0x5b, 0xb8, 0xe7, 0xd6, 0x00, 0xf8, 0x54,
-	0x15, 0x4e, 0xa4, 0x06, 0x7f, 0x0d, 0xeb, 0x32, 0x96, 0x59, 0x9f, 0xdb, 0x52, 0x13, 0x7f, 0x0c,
-	0x6b, 0xa6, 0x83, 0x77, 0xe6, 0x89, 0x03, 0x40, 0x22, 0xba, 0x61, 0x79, 0x73, 0x1e, 0x3d, 0x68,
-	0x08, 0xb4, 0x87, 0x07, 0xf2, 0x7c, 0xd5, 0x5a, 0x3f, 0xfa, 0x8a, 0x79, 0xf4, 0x7b, 0xb0, 0x66,
-	0x44, 0x93, 0x89, 0x3d, 0x92, 0xce, 0x7c, 0x95, 0x98, 0x5e, 0x16, 0xa5, 0xc3, 0x7f, 0x96, 0xa1,
-	0x26, 0x84, 0x8c, 0xe0, 0xfe, 0x44, 0x02, 0x2b, 0xfb, 0x93, 0x85, 0x57, 0x1c, 0x86, 0x1a, 0xb9,
-	0xbc, 0x64, 0xd7, 0x49, 0x85, 0x9f, 0x22, 0x77, 0x3a, 0xe2, 0x12, 0x47, 0x6a, 0xd0, 0x17, 0xd0,
-	0x8a, 0x49, 0xf2, 0xc6, 0xa7, 0x94, 0xb3, 0x6e, 0x89, 0x47, 0xdf, 0xc8, 0xa3, 0xf7, 0x4f, 0x95,
-	0xd6, 0xd1, 0x2d, 0xd1, 0x13, 0x83, 0x6f, 0xe2, 0xbe, 0x58, 0x65, 0xfb, 0x0c, 0x5a, 0x16, 0xaf,
-	0x49, 0x2f, 0x0a, 0x27, 0x3e, 0x6f, 0xf9, 0x9a, 0xb8, 0x26, 0x95, 0xa0, 0x47, 0x01, 0xf2, 0x58,
-	0xc6, 0x15, 0x66, 0x15, 0xae, 0xb0, 0x1d, 0x58, 0xcb, 0x9e, 0x2f, 0xc8, 0xdb, 0x38, 0x21, 0x94,
-	0xe6, 0x43, 0x04, 0x65, 0xaa, 0x91, 0xd2, 0xb0, 0x03, 0x71, 0x65, 0x5b, 0x55, 0x78, 0x63, 0x64,
-	0x4b, 0x4c, 0x60, 0xd5, 0x89, 0x02, 0x72, 0x57, 0x16, 0xf7, 0x01, 0x12, 0xb5, 0x4d, 0x32, 0xb9,
-	0xc3, 0x92, 0xd7, 0x9c, 0x69, 0x16, 0xf8, 0x2d, 0xdc, 0xcb, 0x35, 0x77, 0x64, 0x1a, 0x86, 0xe5,
-	0xdc, 0x93, 0x62, 0x9b, 0x21, 0xbb, 0x86, 0x71, 0xc7, 0xb0, 0x39, 0x17, 0x59, 0xb2, 0x6e, 0xa0,
-	0x39, 0xce, 0x99, 0x57, 0x4c, 0xc3, 0xb0, 0xc1, 0x7f, 0x5b, 0x00, 0xb9, 0xf2, 0x3f, 0x63, 0xe1,
-	0x3a, 0x54, 0x59, 0x18, 0xc1, 0xbf, 0xa6, 0x23, 0x16, 0x85, 0x91, 0x56, 0x2d, 0x8e, 0x34, 0x39,
-	0xac, 0xf9, 0x61, 0x53, 0xbb, 0xc6, 0xd5, 0xb9, 0x00, 0x3d, 0x81, 0xf5, 0x05, 0x2c, 0xa1, 0x76,
-	0x9d, 0x1b, 0xae, 0xcd, 0xd3, 0xa4, 0x40, 0xd0, 0x46, 0x81, 0xa0, 0xf8, 0x37, 0x0b, 0xea, 0x72,
-	0xac, 0xfc, 0xeb, 0x91, 0x69, 0xb4, 0x7a, 0xe5, 0xdd, 0xad, 0x8e, 0x9e, 0x42, 0x9b, 0x15, 0xe1,
-	0x42, 0x19, 0x2f, 0xdd, 0xe2, 0x74, 0x5e, 0xc1, 0x7d, 0x21, 0xdf, 0x0f, 0x27, 0xb9, 0xd1, 0x30,
-	0x9a, 0x86, 0x29, 0x65, 0x4c, 0x8b, 0xf3, 0x35, 0x47, 0x5e, 0x71, 0x74, 0x11, 0xda, 0x86, 0x95,
-	0xc4, 0xdc, 0x25, 0x2f, 0xf6, 0xa2, 0x18, 0xff, 0x61, 0xc1, 0x8a, 0xee, 0xfc, 0xd8, 0x8d, 0xd1,
-	0x1e, 0x34, 0x3c, 0xb6, 0x38, 0x76, 0x63, 0x49, 0xa5, 0xf7, 0xf2, 0xcc, 0x94, 0x59, 0x7f, 0x28,
-	0x6d, 0xc4, 0xdb, 0xa3, 0xda, 0xd2, 0xfb, 0x01, 0xda, 0x86, 0x6a, 0xc1, 0x9b, 0xe3, 0x53, 0xfd,
-	0xcd, 0xb1, 0x35, 0x78, 0x98, 0xbb, 0x5f, 0x90, 0xaf, 0xf6, 0x62, 0xf9, 0xf8, 0x21, 0xd4, 0x04,
-	0xe1, 0x50, 0x13, 0xaa, 0x2f, 0x9c, 0xfd, 0x93, 0xf3, 0x6e, 0x09, 0x35, 0x60, 0xe9, 0x60, 0x74,
-	0xf2, 0x7d, 0xd7, 0x7a, 0xbc, 0x03, 0x2d, 0xed, 0xa0, 0xd0, 0x0a, 0xb4, 0xf6, 0x4f, 0x4f, 0x8f,
-	0x0e, 0x87, 0xfb, 0xe7, 0x87, 0xdf, 0x9e, 0x74, 0x4b, 0x4c, 0xf0, 0xcd, 0xee, 0xd9, 0xc5, 0xf0,
-	0xe8, 0xe5, 0xd9, 0xf9, 0xc8, 0xe9, 0x5a, 0x83, 0x5f, 0x1b, 0xd9, 0xc5, 0x77, 0xec, 0x86, 0xee,
-	0x2b, 0x92, 0xa0, 0x3e, 0x74, 0x86, 0x09, 0x71, 0x53, 0xa2, 0xde, 0x7a, 0x8c, 0x9b, 0xbb, 0x67,
-	0xac, 0x70, 0x09, 0xbd, 0x80, 0x0e, 0x6f, 0xc6, 0x4c, 0x44, 0x91, 0xad, 0x5b, 0xe8, 0x23, 0xa2,
-	0x77, 0x7f, 0x81, 0x46, 0xbe, 0x76, 0x96, 0xd0, 0x2e, 0xac, 0x1c, 0x90, 0x80, 0xe4, 0x81, 0xaf,
-	0xf3, 0xd4, 0xe4, 0xad, 0xc7, 0xdf, 0x02, 0x4a, 0x68, 0x00, 0x6d, 0x01, 0x59, 0x71, 0x5a, 0xbf,
-	0x4c, 0xe5, 0x0e, 0xfd, 0x82, 0xc5, 0x25, 0x74, 0x00, 0x6d, 0xee, 0xf0, 0x2c, 0x7b, 0x09, 0xdc,
-	0xd4, 0xf4, 0x46, 0x28, 0x7b, 0x5e, 0xa1, 0x30, 0x7f, 0x0e, 0x1d, 0x81, 0xf9, 0x66, 0x37, 0x06,
-	0xe2, 0x1d, 0x58, 0x16, 0x88, 0xe5, 0xf4, 0x59, 0xd5, 0x3a, 0x47, 0xda, 0x6b, 0xcd, 0x84, 0x4b,
-	0xe8, 0x99, 0x84, 0x9b, 0x35, 0x08, 0xba, 0x97, 0xab, 0x8d, 0x30, 0x9b, 0x73, 0x72, 0x05, 0xf6,
-	0xb3, 0x0c, 0xec, 0x8d, 0x4e, 0x0c, 0xac, 0x5f, 0x41, 0x57, 0x60, 0xd5, 0xa6, 0xe5, 0x46, 0xa1,
-	0x79, 0xe5, 0xbe, 0x42, 0x4f, 0xe3, 0x12, 0x3a, 0x81, 0x55, 0xe1, 0x59, 0x6b, 0x6e, 0xd4, 0x33,
-	0xcd, 0x8c, 0xd0, 0xff, 0x5b, 0xa8, 0x53, 0x39, 0xec, 0x01, 0x12, 0x39, 0xdc, 0xda, 0xa1, 0x91,
-	0xcb, 0xa7, 0xd0, 0x3d, 0xf2, 0x69, 0x6a, 0x4c, 0x93, 0xdc, 0xa0, 0xb7, 0xb6, 0xa0, 0xcd, 0x71,
-	0x09, 0x39, 0xb0, 0xf6, 0x82, 0xa4, 0xc5, 0x0f, 0x3a, 0xc4, 0xa1, 0xbe, 0xe3, 0xbf, 0x81, 0xde,
-	0x83, 0xc5, 0x4a, 0x95, 0xc8, 0x89, 0xfc, 0xfe, 0x9a, 0xf3, 0xca, 0xe9, 0xb6, 0xe8, 0xa3, 0x4e,
-	0x74, 0xcf, 0xe2, 0x8f, 0xb6, 0x22, 0x46, 0x55, 0x19, 0x03, 0x63, 0xe1, 0x73, 0xce, 0xc4, 0x58,
-	0xfc, 0x7c, 0xc2, 0xa5, 0x71, 0x8d, 0xff, 0x0b, 0xf2, 0xf4, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0x77, 0xb6, 0x2a, 0x0a, 0x16, 0x11, 0x00, 0x00,
-}
@@ -0,0 +1,31 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsgrpc
+
+import (
+	"context"
+	"path"
+
+	"github.com/teramoby/speedle-plus/pkg/health"
+	"github.com/teramoby/speedle-plus/pkg/metrics"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
+
+	"google.golang.org/grpc"
+)
+
+// NewTracingInterceptor returns a unary interceptor which traces the policy management operations,
+// the span continues the trace in the traceparent metadata of the request
+func NewTracingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !tracing.Enabled() || health.IsGRPCHealthMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, span := tracing.StartSpan(tracing.ExtractGRPC(ctx), path.Base(info.FullMethod), tracing.SpanKindServer)
+		defer span.End()
+		span.SetAttribute("protocol", metrics.ProtocolGRPC)
+		resp, err := handler(ctx, req)
+		span.RecordError(err)
+		return resp, err
+	}
+}
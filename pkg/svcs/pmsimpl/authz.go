@@ -0,0 +1,180 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsimpl
+
+import (
+	"context"
+	"strings"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/eval"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultAdminService is the service of the policies authorizing policy management operations
+	DefaultAdminService = "speedle-admin"
+
+	// Actions of policy management operations
+	ActionCreate = "create"
+	ActionRead   = "read"
+	ActionDelete = "delete"
+)
+
+// ServiceResource returns the resource of a service, or of all services if the name is empty
+func ServiceResource(serviceName string) string {
+	if len(serviceName) == 0 {
+		return "/service"
+	}
+	return "/service/" + serviceName
+}
+
+// PolicyResource returns the resource of the policies of a service
+func PolicyResource(serviceName string) string {
+	return ServiceResource(serviceName) + "/policy"
+}
+
+// RolePolicyResource returns the resource of the role policies of a service
+func RolePolicyResource(serviceName string) string {
+	return ServiceResource(serviceName) + "/role-policy"
+}
+
+// OwnersResource returns the resource of the owners of a service
+func OwnersResource(serviceName string) string {
+	return ServiceResource(serviceName) + "/owners"
+}
+
+// DiscoverResource returns the resource of the discover requests of a service, or of all services if the name is empty
+func DiscoverResource(serviceName string) string {
+	if len(serviceName) == 0 {
+		return "/discover"
+	}
+	return ServiceResource(serviceName) + "/discover"
+}
+
+// FunctionResource returns the resource of a function, or of all functions if the name is empty
+func FunctionResource(funcName string) string {
+	if len(funcName) == 0 {
+		return "/function"
+	}
+	return "/function/" + funcName
+}
+
+// Caller is an authenticated caller of the policy management service
+type Caller = svcs.Caller
+
+type callerKey struct{}
+
+// NewCallerContext returns a context carrying the authenticated caller
+func NewCallerContext(ctx context.Context, caller *Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the authenticated caller in a context, or nil if there is none
+func CallerFromContext(ctx context.Context) *Caller {
+	caller, _ := ctx.Value(callerKey{}).(*Caller)
+	return caller
+}
+
+// Authorizer authenticates callers of the policy management service with tokens and client
+// certificates, and authorizes operations with the policies of the admin service
+type Authorizer struct {
+	*svcs.Authenticator
+	adminService string
+	admins       []*adsapi.Principal
+	evaluator    adsapi.PolicyEvaluator
+	policyStore  pms.PolicyStoreManager
+}
+
+// NewAuthorizer creates an authorizer evaluating the policies of the admin service in the policy store
+func NewAuthorizer(conf *cfg.PMSAuthzConfig, ps pms.PolicyStoreManager) (*Authorizer, error) {
+	if conf == nil {
+		conf = &cfg.PMSAuthzConfig{}
+	}
+	a := Authorizer{
+		adminService: conf.AdminService,
+		admins:       conf.Admins,
+		policyStore:  ps,
+	}
+	if len(a.adminService) == 0 {
+		a.adminService = DefaultAdminService
+	}
+
+	var err error
+	if a.Authenticator, err = svcs.NewAuthenticator(conf.Asserters, conf.ClientCertIdentity); err != nil {
+		return nil, err
+	}
+	if !a.Configured() {
+		log.Warn("No asserter or client certificate identity is configured, all policy management requests will be rejected.")
+	}
+
+	// The embedded evaluator watches the store, so that changes of the admin service apply immediately
+	if a.evaluator, err = eval.NewWithStore(&cfg.Config{EnableWatch: true}, ps); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// AdminService returns the service of the policies authorizing policy management operations
+func (a *Authorizer) AdminService() string {
+	return a.adminService
+}
+
+func (a *Authorizer) isAdmin(caller *Caller) bool {
+	for _, admin := range a.admins {
+		if caller.HasPrincipal(admin) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOwner checks whether the caller owns the service of a resource. Owners manage everything in
+// their service, but only read the service itself, which is created and deleted by admins.
+func (a *Authorizer) isOwner(caller *Caller, resource string, action string) bool {
+	prefix := ServiceResource("") + "/"
+	if !strings.HasPrefix(resource, prefix) {
+		return false
+	}
+	serviceName := strings.TrimPrefix(resource, prefix)
+	inService := false
+	if i := strings.IndexByte(serviceName, '/'); i >= 0 {
+		serviceName, inService = serviceName[:i], true
+	}
+	if !inService && action != ActionRead {
+		return false
+	}
+	service, err := a.policyStore.GetService(serviceName)
+	if err != nil {
+		return false
+	}
+	return IsServiceOwner(service, caller.Principals)
+}
+
+// Authorize checks whether the caller is allowed to perform an action on a resource of the policy management service.
+// Admins and owners of services are allowed, other callers are authorized by the policies of the admin service.
+func (a *Authorizer) Authorize(caller *Caller, resource string, action string) error {
+	if a.isAdmin(caller) || a.isOwner(caller, resource, action) {
+		return nil
+	}
+	allowed, _, err := a.evaluator.IsAllowedByPolicies(adsapi.RequestContext{
+		Subject:     &adsapi.Subject{Principals: caller.Principals},
+		ServiceName: a.adminService,
+		Resource:    resource,
+		Action:      action,
+		Attributes:  caller.Attributes,
+	})
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errors.Errorf(errors.Forbidden, "not allowed to %s %s", action, resource)
+	}
+	return nil
+}
@@ -0,0 +1,397 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsimpl
+
+import (
+	"time"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/store"
+	"github.com/teramoby/speedle-plus/pkg/subjectutils"
+)
+
+const (
+	// DefaultRequiredApprovals is the number of approvals required to publish a change request by default
+	DefaultRequiredApprovals = 1
+
+	// Actions of change requests, besides create and read
+	ActionApprove = "approve"
+	ActionPublish = "publish"
+)
+
+// ChangeRequestResource returns the resource of the change requests of a service
+func ChangeRequestResource(serviceName string) string {
+	return ServiceResource(serviceName) + "/change-request"
+}
+
+// CallerIdentity returns the principal identifying a caller as author or reviewer of change requests,
+// which is the first user principal, or the first principal if the caller has no user principal
+func CallerIdentity(caller *Caller) string {
+	if caller == nil || len(caller.Principals) == 0 {
+		return ""
+	}
+	for _, p := range caller.Principals {
+		if p.Type == adsapi.PRINCIPAL_TYPE_USER {
+			return subjectutils.EncodePrincipal(p)
+		}
+	}
+	return subjectutils.EncodePrincipal(caller.Principals[0])
+}
+
+// ChangeRequestDiff is the difference between a change request and the live policies of its service
+type ChangeRequestDiff struct {
+	AddedPolicies       []*pms.Policy     `json:"addedPolicies,omitempty"`
+	RemovedPolicies     []*pms.Policy     `json:"removedPolicies,omitempty"`
+	AddedRolePolicies   []*pms.RolePolicy `json:"addedRolePolicies,omitempty"`
+	RemovedRolePolicies []*pms.RolePolicy `json:"removedRolePolicies,omitempty"`
+	Conflicts           []string          `json:"conflicts,omitempty"` //changes which can't be published
+}
+
+// ChangeRequests is the draft, review and publish workflow of policy changes. A change request is drafted
+// by its author, submitted for review, approved by reviewers other than the author, and published at once.
+// The review is opt-in: only the protected services must be changed by change requests, policies of other
+// services may still be changed directly by any authorized caller without a second pair of eyes.
+type ChangeRequests struct {
+	policyStore       pms.PolicyStoreManager
+	requiredApprovals int
+	protectedServices map[string]bool
+}
+
+// NewChangeRequests creates the change request workflow of the policy store
+func NewChangeRequests(conf *cfg.ChangeRequestConfig, ps pms.PolicyStoreManager) *ChangeRequests {
+	if conf == nil {
+		conf = &cfg.ChangeRequestConfig{}
+	}
+	c := ChangeRequests{
+		policyStore:       ps,
+		requiredApprovals: conf.RequiredApprovals,
+		protectedServices: map[string]bool{},
+	}
+	if c.requiredApprovals <= 0 {
+		c.requiredApprovals = DefaultRequiredApprovals
+	}
+	for _, service := range conf.ProtectedServices {
+		c.protectedServices[service] = true
+	}
+	return &c
+}
+
+// RequiredApprovals returns the number of approvals required to publish a change request
+func (c *ChangeRequests) RequiredApprovals() int {
+	return c.requiredApprovals
+}
+
+// CheckDirectChange checks whether policies and role policies of a service may be changed without change requests,
+// which is the case for every service not configured as protected
+func (c *ChangeRequests) CheckDirectChange(serviceName string) error {
+	if c != nil && c.protectedServices[serviceName] {
+		return errors.Errorf(errors.Forbidden, "policies of service %q are only changed by publishing change requests", serviceName)
+	}
+	return nil
+}
+
+func (c *ChangeRequests) manager() (store.ChangeRequestManager, error) {
+	manager, ok := c.policyStore.(store.ChangeRequestManager)
+	if !ok {
+		return nil, errors.Errorf(errors.InvalidRequest, "change requests are not supported by %s store", c.policyStore.Type())
+	}
+	return manager, nil
+}
+
+func (c *ChangeRequests) checkChanges(changeRequest *pms.ChangeRequest) error {
+	for _, policy := range changeRequest.AddPolicies {
+		if err := CheckPolicy(changeRequest.ServiceName, policy, c.policyStore); err != nil {
+			return err
+		}
+	}
+	for _, rolePolicy := range changeRequest.AddRolePolicies {
+		if err := CheckRolePolicy(changeRequest.ServiceName, rolePolicy, c.policyStore); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func now() string {
+	return time.Unix(time.Now().Unix(), 0).Format(time.RFC3339)
+}
+
+func checkStatus(changeRequest *pms.ChangeRequest, allowed ...string) error {
+	for _, status := range allowed {
+		if changeRequest.Status == status {
+			return nil
+		}
+	}
+	return errors.Errorf(errors.Conflict, "change request %q is %s", changeRequest.ID, changeRequest.Status)
+}
+
+func checkAuthor(changeRequest *pms.ChangeRequest, caller string) error {
+	if changeRequest.Author != caller {
+		return errors.Errorf(errors.Forbidden, "only the author %s may change change request %q", changeRequest.Author, changeRequest.ID)
+	}
+	return nil
+}
+
+// Create creates a draft change request of a service authored by the caller
+func (c *ChangeRequests) Create(changeRequest *pms.ChangeRequest, caller string) (*pms.ChangeRequest, error) {
+	manager, err := c.manager()
+	if err != nil {
+		return nil, err
+	}
+	if len(caller) == 0 {
+		return nil, errors.New(errors.InvalidRequest, "the author of the change request is unknown")
+	}
+	if _, err := c.policyStore.GetService(changeRequest.ServiceName); err != nil {
+		return nil, err
+	}
+	if err := c.checkChanges(changeRequest); err != nil {
+		return nil, err
+	}
+	changeRequest.Author = caller
+	changeRequest.Status = pms.ChangeRequestDraft
+	changeRequest.Approvals = nil
+	changeRequest.Comments = nil
+	changeRequest.Metadata = map[string]string{"createtime": now()}
+	return manager.CreateChangeRequest(changeRequest)
+}
+
+// Get gets a change request of a service
+func (c *ChangeRequests) Get(serviceName string, id string) (*pms.ChangeRequest, error) {
+	manager, err := c.manager()
+	if err != nil {
+		return nil, err
+	}
+	return manager.GetChangeRequest(serviceName, id)
+}
+
+// List lists the change requests of a service, only those in a status if the status is not empty
+func (c *ChangeRequests) List(serviceName string, status string) ([]*pms.ChangeRequest, error) {
+	manager, err := c.manager()
+	if err != nil {
+		return nil, err
+	}
+	changeRequests, err := manager.ListChangeRequests(serviceName)
+	if err != nil || len(status) == 0 {
+		return changeRequests, err
+	}
+	result := []*pms.ChangeRequest{}
+	for _, changeRequest := range changeRequests {
+		if changeRequest.Status == status {
+			result = append(result, changeRequest)
+		}
+	}
+	return result, nil
+}
+
+// Update replaces the title, description and changes of a change request. Only the author may update a
+// change request, which returns to draft and loses its approvals.
+func (c *ChangeRequests) Update(changes *pms.ChangeRequest, caller string) (*pms.ChangeRequest, error) {
+	changeRequest, err := c.Get(changes.ServiceName, changes.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAuthor(changeRequest, caller); err != nil {
+		return nil, err
+	}
+	if err := checkStatus(changeRequest, pms.ChangeRequestDraft, pms.ChangeRequestReview); err != nil {
+		return nil, err
+	}
+	if err := c.checkChanges(changes); err != nil {
+		return nil, err
+	}
+	changeRequest.Title = changes.Title
+	changeRequest.Description = changes.Description
+	changeRequest.AddPolicies = changes.AddPolicies
+	changeRequest.DeletePolicies = changes.DeletePolicies
+	changeRequest.AddRolePolicies = changes.AddRolePolicies
+	changeRequest.DeleteRolePolicies = changes.DeleteRolePolicies
+	changeRequest.Status = pms.ChangeRequestDraft
+	changeRequest.Approvals = nil
+	return changeRequest, c.update(changeRequest)
+}
+
+func (c *ChangeRequests) update(changeRequest *pms.ChangeRequest) error {
+	manager, err := c.manager()
+	if err != nil {
+		return err
+	}
+	if changeRequest.Metadata == nil {
+		changeRequest.Metadata = map[string]string{}
+	}
+	changeRequest.Metadata["updatetime"] = now()
+	return manager.UpdateChangeRequest(changeRequest)
+}
+
+// Comment adds a comment of the caller to a change request
+func (c *ChangeRequests) Comment(serviceName string, id string, caller string, text string) (*pms.ChangeRequest, error) {
+	if len(text) == 0 {
+		return nil, errors.New(errors.InvalidRequest, "no comment is passed")
+	}
+	changeRequest, err := c.Get(serviceName, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(changeRequest, pms.ChangeRequestDraft, pms.ChangeRequestReview); err != nil {
+		return nil, err
+	}
+	changeRequest.Comments = append(changeRequest.Comments, &pms.ChangeRequestComment{Author: caller, Text: text, Time: now()})
+	return changeRequest, c.update(changeRequest)
+}
+
+// Submit submits a draft change request for review, only the author may submit a change request
+func (c *ChangeRequests) Submit(serviceName string, id string, caller string) (*pms.ChangeRequest, error) {
+	changeRequest, err := c.Get(serviceName, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAuthor(changeRequest, caller); err != nil {
+		return nil, err
+	}
+	if err := checkStatus(changeRequest, pms.ChangeRequestDraft); err != nil {
+		return nil, err
+	}
+	if len(changeRequest.AddPolicies)+len(changeRequest.DeletePolicies)+len(changeRequest.AddRolePolicies)+len(changeRequest.DeleteRolePolicies) == 0 {
+		return nil, errors.Errorf(errors.InvalidRequest, "change request %q has no change", id)
+	}
+	changeRequest.Status = pms.ChangeRequestReview
+	return changeRequest, c.update(changeRequest)
+}
+
+// Approve approves a change request in review. Reviewers must be different from the author, and approve only once.
+func (c *ChangeRequests) Approve(serviceName string, id string, caller string, text string) (*pms.ChangeRequest, error) {
+	changeRequest, err := c.Get(serviceName, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(changeRequest, pms.ChangeRequestReview); err != nil {
+		return nil, err
+	}
+	if len(caller) == 0 || caller == changeRequest.Author {
+		return nil, errors.Errorf(errors.Forbidden, "change request %q can't be approved by its author", id)
+	}
+	for _, approval := range changeRequest.Approvals {
+		if approval.Author == caller {
+			return nil, errors.Errorf(errors.EntityAlreadyExists, "change request %q is already approved by %s", id, caller)
+		}
+	}
+	changeRequest.Approvals = append(changeRequest.Approvals, &pms.ChangeRequestComment{Author: caller, Text: text, Time: now()})
+	return changeRequest, c.update(changeRequest)
+}
+
+// Reject returns a change request in review to its author with a comment, its approvals are dropped
+func (c *ChangeRequests) Reject(serviceName string, id string, caller string, text string) (*pms.ChangeRequest, error) {
+	if len(text) == 0 {
+		return nil, errors.New(errors.InvalidRequest, "the reason of rejecting a change request is not passed")
+	}
+	changeRequest, err := c.Get(serviceName, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(changeRequest, pms.ChangeRequestReview); err != nil {
+		return nil, err
+	}
+	changeRequest.Status = pms.ChangeRequestDraft
+	changeRequest.Approvals = nil
+	changeRequest.Comments = append(changeRequest.Comments, &pms.ChangeRequestComment{Author: caller, Text: text, Time: now()})
+	return changeRequest, c.update(changeRequest)
+}
+
+// Close abandons a change request which is not published
+func (c *ChangeRequests) Close(serviceName string, id string, caller string) (*pms.ChangeRequest, error) {
+	changeRequest, err := c.Get(serviceName, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(changeRequest, pms.ChangeRequestDraft, pms.ChangeRequestReview); err != nil {
+		return nil, err
+	}
+	changeRequest.Status = pms.ChangeRequestClosed
+	if changeRequest.Metadata == nil {
+		changeRequest.Metadata = map[string]string{}
+	}
+	changeRequest.Metadata["closeby"] = caller
+	return changeRequest, c.update(changeRequest)
+}
+
+// Publish applies an approved change request to its service at once
+func (c *ChangeRequests) Publish(serviceName string, id string, caller string) (*pms.ChangeRequest, error) {
+	manager, err := c.manager()
+	if err != nil {
+		return nil, err
+	}
+	changeRequest, err := manager.GetChangeRequest(serviceName, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(changeRequest, pms.ChangeRequestReview); err != nil {
+		return nil, err
+	}
+	if len(changeRequest.Approvals) < c.requiredApprovals {
+		return nil, errors.Errorf(errors.Conflict, "change request %q has %d of %d required approvals", id, len(changeRequest.Approvals), c.requiredApprovals)
+	}
+	// the policy store may have changed since the changes were checked
+	if err := c.checkChanges(changeRequest); err != nil {
+		return nil, err
+	}
+	publishTime := now()
+	for _, policy := range changeRequest.AddPolicies {
+		policy.Metadata = map[string]string{"createby": changeRequest.Author, "createtime": publishTime, "changeRequest": id}
+	}
+	for _, rolePolicy := range changeRequest.AddRolePolicies {
+		rolePolicy.Metadata = map[string]string{"createby": changeRequest.Author, "createtime": publishTime, "changeRequest": id}
+	}
+	changeRequest.Status = pms.ChangeRequestPublished
+	if changeRequest.Metadata == nil {
+		changeRequest.Metadata = map[string]string{}
+	}
+	changeRequest.Metadata["publishby"] = caller
+	changeRequest.Metadata["publishtime"] = publishTime
+	changeRequest.Metadata["updatetime"] = publishTime
+	if err := manager.PublishChangeRequest(changeRequest); err != nil {
+		return nil, err
+	}
+	return changeRequest, nil
+}
+
+// Diff compares a change request with the live policies of its service
+func (c *ChangeRequests) Diff(serviceName string, id string) (*ChangeRequestDiff, error) {
+	changeRequest, err := c.Get(serviceName, id)
+	if err != nil {
+		return nil, err
+	}
+	service, err := c.policyStore.GetService(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	diff := ChangeRequestDiff{
+		AddedPolicies:     changeRequest.AddPolicies,
+		AddedRolePolicies: changeRequest.AddRolePolicies,
+	}
+	policies := map[string]*pms.Policy{}
+	for _, policy := range service.Policies {
+		policies[policy.ID] = policy
+	}
+	for _, policyID := range changeRequest.DeletePolicies {
+		if policy, ok := policies[policyID]; ok {
+			diff.RemovedPolicies = append(diff.RemovedPolicies, policy)
+		} else {
+			diff.Conflicts = append(diff.Conflicts, "policy "+policyID+" to delete is not found")
+		}
+	}
+	rolePolicies := map[string]*pms.RolePolicy{}
+	for _, rolePolicy := range service.RolePolicies {
+		rolePolicies[rolePolicy.ID] = rolePolicy
+	}
+	for _, rolePolicyID := range changeRequest.DeleteRolePolicies {
+		if rolePolicy, ok := rolePolicies[rolePolicyID]; ok {
+			diff.RemovedRolePolicies = append(diff.RemovedRolePolicies, rolePolicy)
+		} else {
+			diff.Conflicts = append(diff.Conflicts, "role policy "+rolePolicyID+" to delete is not found")
+		}
+	}
+	return &diff, nil
+}
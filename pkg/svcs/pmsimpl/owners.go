@@ -0,0 +1,131 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsimpl
+
+import (
+	"context"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/subjectutils"
+)
+
+// CheckOwners checks that owners are user, group or entity principals, like "user:alice" or "idd=corp:group:team-a"
+func CheckOwners(owners []string) error {
+	for _, owner := range owners {
+		p, err := subjectutils.DecodePrincipal(owner)
+		if err != nil {
+			return errors.Wrap(err, errors.InvalidRequest, "invalid owner")
+		}
+		switch p.Type {
+		case adsapi.PRINCIPAL_TYPE_USER, adsapi.PRINCIPAL_TYPE_GROUP, adsapi.PRINCIPAL_TYPE_ENTITY:
+		default:
+			return errors.Errorf(errors.InvalidRequest, "invalid owner %q, only users, groups and entities can own services", owner)
+		}
+	}
+	return nil
+}
+
+// IsServiceOwner checks whether any of the principals owns the service
+func IsServiceOwner(service *pms.Service, principals []*adsapi.Principal) bool {
+	caller := Caller{Principals: principals}
+	for _, owner := range service.Owners {
+		p, err := subjectutils.DecodePrincipal(owner)
+		if err != nil {
+			continue
+		}
+		if caller.HasPrincipal(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// OwnerFilter returns the owners filtering listed services, or nil if services are not filtered. If owned is true
+// services owned by the authenticated caller in the context are listed, if owner is not empty services owned by
+// the principal.
+func OwnerFilter(ctx context.Context, owned bool, owner string) ([]*adsapi.Principal, error) {
+	var owners []*adsapi.Principal
+	if owned {
+		caller := CallerFromContext(ctx)
+		if caller == nil {
+			return nil, errors.New(errors.InvalidRequest, "owned services are only listed when authorization is enabled")
+		}
+		owners = append(owners, caller.Principals...)
+	}
+	if len(owner) > 0 {
+		p, err := subjectutils.DecodePrincipal(owner)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.InvalidRequest, "invalid owner")
+		}
+		owners = append(owners, p)
+	}
+	return owners, nil
+}
+
+// OwnedServices returns the services owned by any of the owners
+func OwnedServices(services []*pms.Service, owners []*adsapi.Principal) []*pms.Service {
+	owned := []*pms.Service{}
+	for _, service := range services {
+		if IsServiceOwner(service, owners) {
+			owned = append(owned, service)
+		}
+	}
+	return owned
+}
+
+// AddServiceOwners adds owners to a service, and returns all owners of the service
+func AddServiceOwners(serviceName string, owners []string, policyStore pms.PolicyStoreManager) ([]string, error) {
+	if len(owners) == 0 {
+		return nil, errors.New(errors.InvalidRequest, "no owner is passed")
+	}
+	if err := CheckOwners(owners); err != nil {
+		return nil, err
+	}
+	service, err := policyStore.GetService(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	result := service.Owners
+	for _, owner := range owners {
+		if !contains(result, owner) {
+			result = append(result, owner)
+		}
+	}
+	if err := policyStore.SetServiceOwners(serviceName, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RemoveServiceOwner removes an owner from a service, and returns the remaining owners of the service
+func RemoveServiceOwner(serviceName string, owner string, policyStore pms.PolicyStoreManager) ([]string, error) {
+	service, err := policyStore.GetService(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(service.Owners, owner) {
+		return nil, errors.Errorf(errors.EntityNotFound, "%q is not an owner of service %q", owner, serviceName)
+	}
+	result := []string{}
+	for _, o := range service.Owners {
+		if o != owner {
+			result = append(result, o)
+		}
+	}
+	if err := policyStore.SetServiceOwners(serviceName, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -23,6 +23,7 @@ Check the following items:
 	1. The maximum number of service;
 	2. The maximum number of Policy + RolePolicy;
 	3. The size of each Policy and RolePolicy;
+	4. The owners of the service;
 */
 func CheckService(service *pms.Service, policyStore pms.PolicyStoreManager) error {
 	// Check the number of the service
@@ -64,7 +65,17 @@ func CheckService(service *pms.Service, policyStore pms.PolicyStoreManager) erro
 		}
 	}
 
-	return nil
+	if service.DefaultEffect != "" && service.DefaultEffect != pms.Grant && service.DefaultEffect != pms.Deny {
+		return errors.Errorf(errors.InvalidRequest, "defaultEffect of service should be %q or %q.", pms.Grant, pms.Deny)
+	}
+	if service.FailureMode != "" && service.FailureMode != pms.FailOpen && service.FailureMode != pms.FailClosed {
+		return errors.Errorf(errors.InvalidRequest, "failureMode of service should be %q or %q.", pms.FailOpen, pms.FailClosed)
+	}
+	if service.Mode != "" && service.Mode != pms.ModeEnforce && service.Mode != pms.ModeShadow && service.Mode != pms.ModeDiscover {
+		return errors.Errorf(errors.InvalidRequest, "mode of service should be %q, %q or %q.", pms.ModeEnforce, pms.ModeShadow, pms.ModeDiscover)
+	}
+
+	return CheckOwners(service.Owners)
 }
 
 /*
@@ -83,6 +94,10 @@ func CheckPolicy(serviceName string, policy *pms.Policy, policyStore pms.PolicyS
 		return errors.New(errors.InvalidRequest, "no effect provided in policy.")
 	}
 
+	if policy.NotBefore != nil && policy.NotAfter != nil && !policy.NotBefore.Before(*policy.NotAfter) {
+		return errors.New(errors.InvalidRequest, "notBefore of policy should be before notAfter.")
+	}
+
 	// Check the number of Policy + RolePolicy
 	existingCount, err := getPolicyAndRolePolicyCount("", policyStore)
 	if nil != err {
@@ -112,6 +127,10 @@ func CheckRolePolicy(serviceName string, rolePolicy *pms.RolePolicy, policyStore
 		return errors.New(errors.InvalidRequest, "no effect provided in role policy.")
 	}
 
+	if rolePolicy.NotBefore != nil && rolePolicy.NotAfter != nil && !rolePolicy.NotBefore.Before(*rolePolicy.NotAfter) {
+		return errors.New(errors.InvalidRequest, "notBefore of role policy should be before notAfter.")
+	}
+
 	// Check the number of Policy + RolePolicy
 	existingCount, err := getPolicyAndRolePolicyCount("", policyStore)
 	if nil != err {
@@ -0,0 +1,172 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsrest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/httputils"
+	"github.com/teramoby/speedle-plus/pkg/logging"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsimpl"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// authzRealm is the realm of bearer token challenges
+const authzRealm = "speedle"
+
+// operation returns the resource and action of the policy management operation of a request
+type operation func(r *http.Request) (resource string, action string, err error)
+
+func serviceOperation(resource func(string) string, action string) operation {
+	return func(r *http.Request) (string, string, error) {
+		return resource(mux.Vars(r)["serviceName"]), action, nil
+	}
+}
+
+func functionOperation(action string) operation {
+	return func(r *http.Request) (string, string, error) {
+		return pmsimpl.FunctionResource(mux.Vars(r)["functionName"]), action, nil
+	}
+}
+
+func fixedOperation(resource string, action string) operation {
+	return func(r *http.Request) (string, string, error) {
+		return resource, action, nil
+	}
+}
+
+// createOperation authorizes creating the entity named in the request body
+func createOperation(resource func(string) string) operation {
+	return func(r *http.Request) (string, string, error) {
+		name, err := nameInBody(r)
+		if err != nil {
+			return "", "", err
+		}
+		return resource(name), pmsimpl.ActionCreate, nil
+	}
+}
+
+// listServicesOperation authorizes listing services. Any authenticated caller may list the services it owns.
+func listServicesOperation(r *http.Request) (string, string, error) {
+	query := r.URL.Query()
+	if owned, _ := strconv.ParseBool(query.Get("owned")); owned && len(query.Get("owner")) == 0 {
+		return "", "", nil
+	}
+	return pmsimpl.ServiceResource(""), pmsimpl.ActionRead, nil
+}
+
+// nameInBody returns the name of the entity in the JSON request body, and restores the body for the handler
+func nameInBody(r *http.Request) (string, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return "", errors.Wrap(err, errors.InvalidRequest, "failed to read request body")
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	var entity struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &entity); err != nil {
+		return "", errors.Wrap(err, errors.InvalidRequest, "failed to decode request body")
+	}
+	if len(entity.Name) == 0 {
+		return "", errors.New(errors.InvalidRequest, "name is not passed")
+	}
+	return entity.Name, nil
+}
+
+// routeOperations are the operations of routes by route name
+var routeOperations = map[string]operation{
+	"CreatePolicy":             serviceOperation(pmsimpl.PolicyResource, pmsimpl.ActionCreate),
+	"DeletePolicies":           serviceOperation(pmsimpl.PolicyResource, pmsimpl.ActionDelete),
+	"DeletePolicy":             serviceOperation(pmsimpl.PolicyResource, pmsimpl.ActionDelete),
+	"GetPolicy":                serviceOperation(pmsimpl.PolicyResource, pmsimpl.ActionRead),
+	"ListPolicies":             serviceOperation(pmsimpl.PolicyResource, pmsimpl.ActionRead),
+	"CreateRolePolicy":         serviceOperation(pmsimpl.RolePolicyResource, pmsimpl.ActionCreate),
+	"DeleteRolePolicies":       serviceOperation(pmsimpl.RolePolicyResource, pmsimpl.ActionDelete),
+	"DeleteRolePolicy":         serviceOperation(pmsimpl.RolePolicyResource, pmsimpl.ActionDelete),
+	"GetRolePolicy":            serviceOperation(pmsimpl.RolePolicyResource, pmsimpl.ActionRead),
+	"ListRolePolicies":         serviceOperation(pmsimpl.RolePolicyResource, pmsimpl.ActionRead),
+	"CreateService":            createOperation(pmsimpl.ServiceResource),
+	"DeleteService":            serviceOperation(pmsimpl.ServiceResource, pmsimpl.ActionDelete),
+	"DeleteServices":           fixedOperation(pmsimpl.ServiceResource(""), pmsimpl.ActionDelete),
+	"GetService":               serviceOperation(pmsimpl.ServiceResource, pmsimpl.ActionRead),
+	"ListServices":             listServicesOperation,
+	"GetServiceOwners":         serviceOperation(pmsimpl.OwnersResource, pmsimpl.ActionRead),
+	"AddServiceOwners":         serviceOperation(pmsimpl.OwnersResource, pmsimpl.ActionCreate),
+	"RemoveServiceOwner":       serviceOperation(pmsimpl.OwnersResource, pmsimpl.ActionDelete),
+	"ListPolicyCounts":         fixedOperation(pmsimpl.ServiceResource(""), pmsimpl.ActionRead),
+	"CreateFunction":           createOperation(pmsimpl.FunctionResource),
+	"DeleteFunction":           functionOperation(pmsimpl.ActionDelete),
+	"DeleteFunctions":          fixedOperation(pmsimpl.FunctionResource(""), pmsimpl.ActionDelete),
+	"GetFunction":              functionOperation(pmsimpl.ActionRead),
+	"ListFunctions":            fixedOperation(pmsimpl.FunctionResource(""), pmsimpl.ActionRead),
+	"GetAllDiscoverRequests":   fixedOperation(pmsimpl.DiscoverResource(""), pmsimpl.ActionRead),
+	"GetDiscoverRequests":      serviceOperation(pmsimpl.DiscoverResource, pmsimpl.ActionRead),
+	"ResetDiscoverRequests":    serviceOperation(pmsimpl.DiscoverResource, pmsimpl.ActionDelete),
+	"ResetAllDiscoverRequests": fixedOperation(pmsimpl.DiscoverResource(""), pmsimpl.ActionDelete),
+	"GetDiscoverPolicies":      serviceOperation(pmsimpl.DiscoverResource, pmsimpl.ActionRead),
+	"GetAllDiscoverPolicies":   fixedOperation(pmsimpl.DiscoverResource(""), pmsimpl.ActionRead),
+	"GetDiscoverStatistics":    serviceOperation(pmsimpl.DiscoverResource, pmsimpl.ActionRead),
+	"GetAllDiscoverStatistics": fixedOperation(pmsimpl.DiscoverResource(""), pmsimpl.ActionRead),
+	"ListChangeRequests":       serviceOperation(pmsimpl.ChangeRequestResource, pmsimpl.ActionRead),
+	"CreateChangeRequest":      serviceOperation(pmsimpl.ChangeRequestResource, pmsimpl.ActionCreate),
+	"GetChangeRequest":         serviceOperation(pmsimpl.ChangeRequestResource, pmsimpl.ActionRead),
+	"UpdateChangeRequest":      serviceOperation(pmsimpl.ChangeRequestResource, pmsimpl.ActionCreate),
+	"GetChangeRequestDiff":     serviceOperation(pmsimpl.ChangeRequestResource, pmsimpl.ActionRead),
+	"CommentChangeRequest":     serviceOperation(pmsimpl.ChangeRequestResource, pmsimpl.ActionCreate),
+	"SubmitChangeRequest":      serviceOperation(pmsimpl.ChangeRequestResource, pmsimpl.ActionCreate),
+	"ApproveChangeRequest":     serviceOperation(pmsimpl.ChangeRequestResource, pmsimpl.ActionApprove),
+	"RejectChangeRequest":      serviceOperation(pmsimpl.ChangeRequestResource, pmsimpl.ActionApprove),
+	"CloseChangeRequest":       serviceOperation(pmsimpl.ChangeRequestResource, pmsimpl.ActionCreate),
+	"PublishChangeRequest":     serviceOperation(pmsimpl.ChangeRequestResource, pmsimpl.ActionPublish),
+}
+
+// authzHandler authenticates the caller and authorizes the operation of a route before calling the handler
+func authzHandler(authorizer *pmsimpl.Authorizer, routeName string, next http.Handler) http.Handler {
+	op, ok := routeOperations[routeName]
+	if !ok {
+		log.Warnf("No operation is defined for route %s, requests are rejected.", routeName)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, tokenType := svcs.ParseAuthorization(r.Header.Get("Authorization"))
+		caller, err := authorizer.Authenticate(token, tokenType, r.TLS)
+		if err != nil {
+			// Audit log
+			logging.WriteSimpleFailedAuditLog("Authenticate", routeName, err.Error())
+			httputils.SendBearerAtzErrorResponse(w, authzRealm, "invalid_token", err.Error())
+			return
+		}
+
+		if !ok {
+			httputils.HandleError(w, errors.Errorf(errors.Forbidden, "operation %s is not allowed", routeName))
+			return
+		}
+		// operations without resource are allowed for all authenticated callers
+		resource, action, err := op(r)
+		if err == nil && len(resource) > 0 {
+			err = authorizer.Authorize(caller, resource, action)
+		}
+		if err != nil {
+			// Audit log
+			logging.WriteFailedAuditLog("Authorize", log.Fields{"principals": caller.Principals, "resource": resource, "action": action}, err.Error())
+			httputils.HandleError(w, err)
+			return
+		}
+
+		// the creator of policies is the authenticated user, never the header sent by the caller
+		r.Header.Del(svcs.PrincipalsHeader)
+		if user := caller.UserName(); len(user) > 0 {
+			r.Header.Set(svcs.PrincipalsHeader, user)
+		}
+		next.ServeHTTP(w, r.WithContext(pmsimpl.NewCallerContext(r.Context(), caller)))
+	})
+}
@@ -0,0 +1,211 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsrest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	pmsapi "github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/assertion"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/store"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsimpl"
+
+	"github.com/gorilla/mux"
+)
+
+// The admin service is in shadow mode and grants by default, which don't apply to the authorization
+// of policy management operations
+const authzStore = `{
+  "services": [
+    {
+      "name": "speedle-admin",
+      "mode": "shadow",
+      "defaultEffect": "grant",
+      "policies": [
+        {
+          "id": "team-a",
+          "name": "team-a manages its policies",
+          "effect": "grant",
+          "permissions": [{"resource": "/service/team-a/policy", "actions": ["create", "read", "delete"]}],
+          "principals": [["group:team-a"]]
+        }
+      ]
+    }
+  ]
+}`
+
+// newAuthzTestRouter creates a router authorizing callers with tokens of root, alice of group team-a and bob
+func newAuthzTestRouter(t *testing.T) (*mux.Router, func()) {
+	dir, err := ioutil.TempDir("", "pmsauthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storeFile := filepath.Join(dir, "store.json")
+	if err := ioutil.WriteFile(storeFile, []byte(authzStore), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ps, err := store.NewStore(cfg.StorageTypeFile, map[string]interface{}{"FileLocation": storeFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subject := func(principals ...*adsapi.Principal) map[string]interface{} {
+		return map[string]interface{}{"principals": principals}
+	}
+	authorizer, err := pmsimpl.NewAuthorizer(&cfg.PMSAuthzConfig{
+		Admins: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_USER, Name: "root"}},
+		Asserters: &assertion.RouterConfig{
+			Asserters: []*assertion.AsserterDefinition{{
+				Name: "tokens",
+				Type: assertion.APIKeyAsserterType,
+				Props: map[string]interface{}{"keys": map[string]interface{}{
+					"root-token":  subject(&adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_USER, Name: "root"}),
+					"alice-token": subject(&adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_USER, Name: "alice"}, &adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_GROUP, Name: "team-a"}),
+					"bob-token":   subject(&adsapi.Principal{Type: adsapi.PRINCIPAL_TYPE_USER, Name: "bob"}),
+				}},
+			}},
+			Chains: map[string][]string{"bearer": {"tokens"}},
+		},
+	}, ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := NewRouterWithAuthorizer(ps, authorizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return router, func() {
+		// the authorizer watches the store file, stop watching before it is removed
+		ps.StopWatch()
+		os.RemoveAll(dir)
+	}
+}
+
+func sendWithToken(router http.Handler, method, path, token string, body interface{}, header map[string]string) *httptest.ResponseRecorder {
+	var buf []byte
+	if body != nil {
+		buf, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, svcs.PolicyMgmtPath+path, bytes.NewBuffer(buf))
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPMSAuthorization(t *testing.T) {
+	router, cleanup := newAuthzTestRouter(t)
+	defer cleanup()
+
+	policy := &pmsapi.Policy{
+		Name:        "p1",
+		Effect:      "grant",
+		Permissions: []*pmsapi.Permission{{Resource: "/books", Actions: []string{"read"}}},
+		Principals:  [][]string{{"user:carol"}},
+	}
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   interface{}
+		status int
+	}{
+		{"no token", "GET", "service", "", nil, http.StatusUnauthorized},
+		{"unknown token", "GET", "service", "guess", nil, http.StatusUnauthorized},
+		{"admin creates service", "POST", "service", "root-token", &pmsapi.Service{Name: "team-a"}, http.StatusCreated},
+		{"team creates policy", "POST", "service/team-a/policy", "alice-token", policy, http.StatusCreated},
+		{"team lists policies", "GET", "service/team-a/policy", "alice-token", nil, http.StatusOK},
+		{"team can't delete service", "DELETE", "service/team-a", "alice-token", nil, http.StatusForbidden},
+		{"team can't create service", "POST", "service", "alice-token", &pmsapi.Service{Name: "team-b"}, http.StatusForbidden},
+		{"team can't change admin service", "POST", "service/speedle-admin/policy", "alice-token", policy, http.StatusForbidden},
+		{"other user can't read policies", "GET", "service/team-a/policy", "bob-token", nil, http.StatusForbidden},
+		{"admin deletes service", "DELETE", "service/team-a", "root-token", nil, http.StatusNoContent},
+	}
+	for _, tc := range testCases {
+		rec := sendWithToken(router, tc.method, tc.path, tc.token, tc.body, map[string]string{svcs.PrincipalsHeader: "mallory"})
+		if rec.Code != tc.status {
+			t.Errorf("%s: expected status %d, got %d, %s", tc.name, tc.status, rec.Code, rec.Body.String())
+		}
+		if tc.status == http.StatusUnauthorized && len(rec.Header().Get("WWW-Authenticate")) == 0 {
+			t.Errorf("%s: no authentication challenge", tc.name)
+		}
+		if tc.name == "team creates policy" {
+			var created pmsapi.Policy
+			json.Unmarshal(rec.Body.Bytes(), &created)
+			if created.Metadata["createby"] != "alice" {
+				t.Errorf("policy should be created by the authenticated user, got %v", created.Metadata)
+			}
+		}
+	}
+}
+
+func TestServiceOwners(t *testing.T) {
+	router, cleanup := newAuthzTestRouter(t)
+	defer cleanup()
+
+	policy := &pmsapi.Policy{
+		Name:        "p1",
+		Effect:      "grant",
+		Permissions: []*pmsapi.Permission{{Resource: "/books", Actions: []string{"read"}}},
+		Principals:  [][]string{{"user:carol"}},
+	}
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   interface{}
+		status int
+	}{
+		{"admin creates owned service", "POST", "service", "root-token", &pmsapi.Service{Name: "books", Owners: []string{"user:bob"}}, http.StatusCreated},
+		{"admin creates service", "POST", "service", "root-token", &pmsapi.Service{Name: "music"}, http.StatusCreated},
+		{"invalid owner", "POST", "service", "root-token", &pmsapi.Service{Name: "films", Owners: []string{"role:reader"}}, http.StatusBadRequest},
+		{"owner creates policy", "POST", "service/books/policy", "bob-token", policy, http.StatusCreated},
+		{"owner reads service", "GET", "service/books", "bob-token", nil, http.StatusOK},
+		{"owner can't delete service", "DELETE", "service/books", "bob-token", nil, http.StatusForbidden},
+		{"non-owner can't create policy", "POST", "service/music/policy", "bob-token", policy, http.StatusForbidden},
+		{"owner adds owner", "POST", "service/books/owners", "bob-token", map[string][]string{"owners": {"group:team-a"}}, http.StatusOK},
+		{"group owner creates policy", "POST", "service/books/policy", "alice-token", policy, http.StatusCreated},
+		{"group owner removes owner", "DELETE", "service/books/owners/user:bob", "alice-token", nil, http.StatusNoContent},
+		{"removed owner can't create policy", "POST", "service/books/policy", "bob-token", policy, http.StatusForbidden},
+		{"non-owner can't list services", "GET", "service", "bob-token", nil, http.StatusForbidden},
+		{"non-owner lists owned services", "GET", "service?owned=true", "bob-token", nil, http.StatusOK},
+	}
+	for _, tc := range testCases {
+		rec := sendWithToken(router, tc.method, tc.path, tc.token, tc.body, nil)
+		if rec.Code != tc.status {
+			t.Errorf("%s: expected status %d, got %d, %s", tc.name, tc.status, rec.Code, rec.Body.String())
+		}
+	}
+
+	for token, expected := range map[string][]string{"alice-token": {"books"}, "bob-token": {}} {
+		rec := sendWithToken(router, "GET", "service?owned=true", token, nil, nil)
+		var services []*pmsapi.Service
+		json.Unmarshal(rec.Body.Bytes(), &services)
+		names := []string{}
+		for _, service := range services {
+			names = append(names, service.Name)
+		}
+		if len(names) != len(expected) || (len(names) > 0 && names[0] != expected[0]) {
+			t.Errorf("services owned by %s: expected %v, got %v", token, expected, names)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsrest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/errors"
+	"github.com/teramoby/speedle-plus/pkg/httputils"
+	"github.com/teramoby/speedle-plus/pkg/logging"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsimpl"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+type commentBody struct {
+	Text string `json:"text,omitempty"`
+}
+
+// commentInBody returns the comment in the request body, the body is optional
+func commentInBody(r *http.Request) (string, error) {
+	raw, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return "", errors.Wrap(err, errors.InvalidRequest, "failed to read request body")
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return "", nil
+	}
+	var body commentBody
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return "", errors.Wrap(err, errors.InvalidRequest, "failed to decode request body")
+	}
+	return body.Text, nil
+}
+
+// callerIdentity returns the principal of the caller as author or reviewer of change requests. Without
+// authorization, the caller is the user passed in the principals header.
+func callerIdentity(r *http.Request) string {
+	if caller := pmsimpl.CallerFromContext(r.Context()); caller != nil {
+		return pmsimpl.CallerIdentity(caller)
+	}
+	if user := r.Header.Get(svcs.PrincipalsHeader); len(user) > 0 {
+		return "user:" + user
+	}
+	return ""
+}
+
+// checkDirectChange rejects changes of policies and role policies of services protected by change requests
+func (mgr *RESTService) checkDirectChange(w http.ResponseWriter, operation string, serviceName string) bool {
+	if err := mgr.ChangeRequests.CheckDirectChange(serviceName); err != nil {
+		httputils.HandleError(w, err)
+		logging.WriteSimpleFailedAuditLog(operation, serviceName, err.Error())
+		return false
+	}
+	return true
+}
+
+func (mgr *RESTService) ListChangeRequests(w http.ResponseWriter, r *http.Request) {
+	serviceName := mux.Vars(r)["serviceName"]
+	changeRequests, err := mgr.ChangeRequests.List(serviceName, r.URL.Query().Get("status"))
+	if err != nil {
+		httputils.HandleError(w, err)
+		logging.WriteSimpleFailedAuditLog("ListChangeRequests", serviceName, err.Error())
+		return
+	}
+
+	logging.WriteSimpleSucceededAuditLog("ListChangeRequests", serviceName, nil)
+	httputils.SendOKResponse(w, changeRequests)
+}
+
+func (mgr *RESTService) CreateChangeRequest(w http.ResponseWriter, r *http.Request) {
+	var changeRequest pms.ChangeRequest
+	if err := decodeRequestBody(r, &changeRequest); err != nil {
+		httputils.HandleError(w, err)
+		return
+	}
+	changeRequest.ServiceName = mux.Vars(r)["serviceName"]
+
+	ret, err := mgr.ChangeRequests.Create(&changeRequest, callerIdentity(r))
+	if err != nil {
+		httputils.HandleError(w, err)
+		logging.WriteSimpleFailedAuditLog("CreateChangeRequest", &changeRequest, err.Error())
+		return
+	}
+
+	logging.WriteSimpleSucceededAuditLog("CreateChangeRequest", ret, nil)
+	httputils.SendCreatedResponse(w, ret)
+}
+
+func (mgr *RESTService) GetChangeRequest(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	changeRequest, err := mgr.ChangeRequests.Get(vars["serviceName"], vars["changeRequestID"])
+	if err != nil {
+		httputils.HandleError(w, err)
+		logging.WriteSimpleFailedAuditLog("GetChangeRequest", vars, err.Error())
+		return
+	}
+
+	logging.WriteSimpleSucceededAuditLog("GetChangeRequest", vars, nil)
+	httputils.SendOKResponse(w, changeRequest)
+}
+
+func (mgr *RESTService) UpdateChangeRequest(w http.ResponseWriter, r *http.Request) {
+	var changes pms.ChangeRequest
+	if err := decodeRequestBody(r, &changes); err != nil {
+		httputils.HandleError(w, err)
+		return
+	}
+	vars := mux.Vars(r)
+	changes.ServiceName, changes.ID = vars["serviceName"], vars["changeRequestID"]
+
+	ret, err := mgr.ChangeRequests.Update(&changes, callerIdentity(r))
+	if err != nil {
+		httputils.HandleError(w, err)
+		logging.WriteSimpleFailedAuditLog("UpdateChangeRequest", &changes, err.Error())
+		return
+	}
+
+	logging.WriteSimpleSucceededAuditLog("UpdateChangeRequest", ret, nil)
+	httputils.SendOKResponse(w, ret)
+}
+
+func (mgr *RESTService) GetChangeRequestDiff(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	diff, err := mgr.ChangeRequests.Diff(vars["serviceName"], vars["changeRequestID"])
+	if err != nil {
+		httputils.HandleError(w, err)
+		logging.WriteSimpleFailedAuditLog("GetChangeRequestDiff", vars, err.Error())
+		return
+	}
+
+	logging.WriteSimpleSucceededAuditLog("GetChangeRequestDiff", vars, nil)
+	httputils.SendOKResponse(w, diff)
+}
+
+// changeRequestAction returns the handler of an action on a change request, like approving it.
+// The request body may pass a comment of the action.
+func (mgr *RESTService) changeRequestAction(name string, action func(serviceName, id, caller, text string) (*pms.ChangeRequest, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		text, err := commentInBody(r)
+		if err != nil {
+			httputils.HandleError(w, err)
+			return
+		}
+		vars := mux.Vars(r)
+		caller := callerIdentity(r)
+		ctxFields := log.Fields{"service": vars["serviceName"], "changeRequest": vars["changeRequestID"], "caller": caller}
+
+		changeRequest, err := action(vars["serviceName"], vars["changeRequestID"], caller, text)
+		if err != nil {
+			httputils.HandleError(w, err)
+			logging.WriteSimpleFailedAuditLog(name, ctxFields, err.Error())
+			return
+		}
+
+		logging.WriteSimpleSucceededAuditLog(name, ctxFields, nil)
+		httputils.SendOKResponse(w, changeRequest)
+	}
+}
+
+func (mgr *RESTService) CommentChangeRequest(w http.ResponseWriter, r *http.Request) {
+	mgr.changeRequestAction("CommentChangeRequest", mgr.ChangeRequests.Comment)(w, r)
+}
+
+func (mgr *RESTService) SubmitChangeRequest(w http.ResponseWriter, r *http.Request) {
+	mgr.changeRequestAction("SubmitChangeRequest", func(serviceName, id, caller, _ string) (*pms.ChangeRequest, error) {
+		return mgr.ChangeRequests.Submit(serviceName, id, caller)
+	})(w, r)
+}
+
+func (mgr *RESTService) ApproveChangeRequest(w http.ResponseWriter, r *http.Request) {
+	mgr.changeRequestAction("ApproveChangeRequest", mgr.ChangeRequests.Approve)(w, r)
+}
+
+func (mgr *RESTService) RejectChangeRequest(w http.ResponseWriter, r *http.Request) {
+	mgr.changeRequestAction("RejectChangeRequest", mgr.ChangeRequests.Reject)(w, r)
+}
+
+func (mgr *RESTService) CloseChangeRequest(w http.ResponseWriter, r *http.Request) {
+	mgr.changeRequestAction("CloseChangeRequest", func(serviceName, id, caller, _ string) (*pms.ChangeRequest, error) {
+		return mgr.ChangeRequests.Close(serviceName, id, caller)
+	})(w, r)
+}
+
+func (mgr *RESTService) PublishChangeRequest(w http.ResponseWriter, r *http.Request) {
+	mgr.changeRequestAction("PublishChangeRequest", func(serviceName, id, caller, _ string) (*pms.ChangeRequest, error) {
+		return mgr.ChangeRequests.Publish(serviceName, id, caller)
+	})(w, r)
+}
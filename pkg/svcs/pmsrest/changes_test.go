@@ -0,0 +1,135 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsrest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pmsapi "github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/store"
+	"github.com/teramoby/speedle-plus/pkg/svcs"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsimpl"
+)
+
+const changesStore = `{
+  "services": [
+    {
+      "name": "books",
+      "policies": [
+        {
+          "id": "old",
+          "name": "old policy",
+          "effect": "grant",
+          "permissions": [{"resource": "/books", "actions": ["read"]}],
+          "principals": [["user:carol"]]
+        }
+      ]
+    }
+  ]
+}`
+
+func TestChangeRequestWorkflow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pmschanges")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	storeFile := filepath.Join(dir, "store.json")
+	if err := ioutil.WriteFile(storeFile, []byte(changesStore), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ps, err := store.NewStore(cfg.StorageTypeFile, map[string]interface{}{"FileLocation": storeFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changeRequests := pmsimpl.NewChangeRequests(&cfg.ChangeRequestConfig{RequiredApprovals: 1, ProtectedServices: []string{"books"}}, ps)
+	router, err := NewRouterWithChangeRequests(ps, nil, changeRequests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	as := func(user string) map[string]string {
+		return map[string]string{svcs.PrincipalsHeader: user}
+	}
+	policy := &pmsapi.Policy{
+		Name:        "new policy",
+		Effect:      "grant",
+		Permissions: []*pmsapi.Permission{{Resource: "/books", Actions: []string{"write"}}},
+		Principals:  [][]string{{"user:dave"}},
+	}
+
+	// policies of protected services can't be changed directly
+	rec := sendWithToken(router, "POST", "service/books/policy", "", policy, as("alice"))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("direct change of a protected service: expected %d, got %d", http.StatusForbidden, rec.Code)
+	}
+
+	rec = sendWithToken(router, "POST", "service/books/change-request", "", &pmsapi.ChangeRequest{
+		Title:          "replace the old policy",
+		AddPolicies:    []*pmsapi.Policy{policy},
+		DeletePolicies: []string{"old"},
+	}, as("alice"))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create change request: expected %d, got %d, %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var changeRequest pmsapi.ChangeRequest
+	if err := json.Unmarshal(rec.Body.Bytes(), &changeRequest); err != nil {
+		t.Fatal(err)
+	}
+	if changeRequest.Author != "user:alice" || changeRequest.Status != pmsapi.ChangeRequestDraft {
+		t.Fatalf("unexpected change request %+v", changeRequest)
+	}
+	path := "service/books/change-request/" + changeRequest.ID
+
+	testCases := []struct {
+		name   string
+		action string
+		user   string
+		body   interface{}
+		status int
+	}{
+		{"approving a draft", "/approve", "bob", nil, http.StatusConflict},
+		{"commenting a draft", "/comment", "bob", map[string]string{"text": "please add a description"}, http.StatusOK},
+		{"submitting by another user", "/submit", "bob", nil, http.StatusForbidden},
+		{"submitting by the author", "/submit", "alice", nil, http.StatusOK},
+		{"approving by the author", "/approve", "alice", nil, http.StatusForbidden},
+		{"commenting under review", "/comment", "alice", map[string]string{"text": "description added"}, http.StatusOK},
+		{"publishing without approval", "/publish", "alice", nil, http.StatusConflict},
+		{"rejecting without reason", "/reject", "bob", nil, http.StatusBadRequest},
+		{"approving by a reviewer", "/approve", "bob", map[string]string{"text": "looks good"}, http.StatusOK},
+		{"approving twice", "/approve", "bob", nil, http.StatusConflict},
+		{"publishing beyond the policy limit", "/publish", "alice", nil, http.StatusForbidden},
+		{"publishing", "/publish", "alice", nil, http.StatusOK},
+		{"publishing twice", "/publish", "alice", nil, http.StatusConflict},
+		{"commenting a published change request", "/comment", "bob", map[string]string{"text": "too late"}, http.StatusConflict},
+	}
+	for _, tc := range testCases {
+		// the changes are checked again when published
+		if tc.name == "publishing beyond the policy limit" {
+			pmsimpl.MaxPolicyNum = 1
+		} else {
+			pmsimpl.MaxPolicyNum = -1
+		}
+		rec := sendWithToken(router, "POST", path+tc.action, "", tc.body, as(tc.user))
+		if rec.Code != tc.status {
+			t.Errorf("%s: expected %d, got %d, %s", tc.name, tc.status, rec.Code, rec.Body.String())
+		}
+	}
+
+	service, err := ps.GetService("books")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(service.Policies) != 1 || service.Policies[0].Name != "new policy" {
+		t.Fatalf("change request is not applied, policies %+v", service.Policies)
+	}
+	if service.Policies[0].Metadata["changeRequest"] != changeRequest.ID {
+		t.Errorf("expected the published policy to refer change request %s, got %v", changeRequest.ID, service.Policies[0].Metadata)
+	}
+}
@@ -5,17 +5,18 @@ package pmsrest
 
 import (
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
 	"github.com/teramoby/speedle-plus/api/ads"
 	"github.com/teramoby/speedle-plus/api/pms"
 	"github.com/teramoby/speedle-plus/pkg/errors"
 	"github.com/teramoby/speedle-plus/pkg/httputils"
 	"github.com/teramoby/speedle-plus/pkg/logging"
 	"github.com/teramoby/speedle-plus/pkg/store"
-	log "github.com/sirupsen/logrus"
 )
 
 type GetDiscoverRequestsResponse struct {
@@ -23,6 +24,18 @@ type GetDiscoverRequestsResponse struct {
 	Revision int64                 `json:"revision"`
 }
 
+// GetDiscoverRequestAggregatesResponse is the distinct discover requests with their hit counts
+type GetDiscoverRequestAggregatesResponse struct {
+	Aggregates []*store.DiscoverRequestAggregate `json:"aggregates"`
+	Revision   int64                             `json:"revision"`
+}
+
+// GetDiscoverStatisticsResponse is the statistics of the discover requests of services
+type GetDiscoverStatisticsResponse struct {
+	Statistics []*store.DiscoverStatistics `json:"statistics"`
+	Revision   int64                       `json:"revision"`
+}
+
 type GetDiscoverPoliciesResponse struct {
 	Services []*pms.Service `json:"services"`
 	Revision int64          `json:"revision"`
@@ -35,11 +48,13 @@ func (e *RESTService) GetAllDiscoverRequests(w http.ResponseWriter, r *http.Requ
 
 	last := r.URL.Query().Get("last")
 	revisionStr := r.URL.Query().Get("revision")
+	aggregate := r.URL.Query().Get("aggregate")
 
 	// Audit contextual fields for request
 	ctxFields := map[string]interface{}{
-		"last":     last,
-		"revision": revisionStr,
+		"last":      last,
+		"revision":  revisionStr,
+		"aggregate": aggregate,
 	}
 
 	if strings.EqualFold("true", last) { //get last discover request
@@ -85,6 +100,8 @@ func (e *RESTService) GetAllDiscoverRequests(w http.ResponseWriter, r *http.Requ
 
 		// Audit log
 		logging.WriteSucceededAuditLog("GetAllDiscoverRequests", ctxFields, map[string]interface{}{"requestCount": len(requests)})
+	} else if strings.EqualFold("true", aggregate) { //get distinct requests with their hit counts
+		e.sendDiscoverRequestAggregates(w, "", "GetAllDiscoverRequests", ctxFields)
 	} else {
 		//get all service requests
 		requests, revision, err := e.PolicyStore.(store.DiscoverRequestManager).GetDiscoverRequests("")
@@ -114,12 +131,14 @@ func (e *RESTService) GetDiscoverRequests(w http.ResponseWriter, r *http.Request
 
 	last := r.URL.Query().Get("last")
 	revisionStr := r.URL.Query().Get("revision")
+	aggregate := r.URL.Query().Get("aggregate")
 
 	// Audit contextual fields for request
 	ctxFields := map[string]interface{}{
 		"serverName": serviceName,
 		"last":       last,
 		"revision":   revisionStr,
+		"aggregate":  aggregate,
 	}
 
 	if strings.EqualFold("true", last) { //get last discover request
@@ -165,6 +184,8 @@ func (e *RESTService) GetDiscoverRequests(w http.ResponseWriter, r *http.Request
 
 		// Audit log
 		logging.WriteSucceededAuditLog("GetDiscoverRequests", ctxFields, map[string]interface{}{"requestCount": len(requests)})
+	} else if strings.EqualFold("true", aggregate) { //get distinct requests with their hit counts
+		e.sendDiscoverRequestAggregates(w, serviceName, "GetDiscoverRequests", ctxFields)
 	} else {
 		//get all service requests
 		requests, revision, err := e.PolicyStore.(store.DiscoverRequestManager).GetDiscoverRequests(serviceName)
@@ -182,6 +203,72 @@ func (e *RESTService) GetDiscoverRequests(w http.ResponseWriter, r *http.Request
 	}
 }
 
+func (e *RESTService) sendDiscoverRequestAggregates(w http.ResponseWriter, serviceName, operation string, ctxFields map[string]interface{}) {
+	aggregates, revision, err := e.PolicyStore.(store.DiscoverRequestManager).GetDiscoverRequestAggregates(serviceName)
+	if err != nil {
+		log.Errorf("%v, Cause: %v", err, errors.Cause(err))
+		httputils.HandleError(w, err)
+		// Audit log
+		logging.WriteFailedAuditLog(operation, ctxFields, err.Error())
+		return
+	}
+	response := GetDiscoverRequestAggregatesResponse{Aggregates: aggregates, Revision: revision}
+	httputils.SendOKResponse(w, &response)
+	// Audit log
+	logging.WriteSucceededAuditLog(operation, ctxFields, map[string]interface{}{"requestCount": len(aggregates)})
+}
+
+// GetDiscoverStatistics gets the statistics of the discover requests of a service, or of all services, with their
+// top principals, resources and actions
+func (e *RESTService) GetDiscoverStatistics(w http.ResponseWriter, r *http.Request) {
+	if !e.checkPolicyForDiscover(w) {
+		return
+	}
+
+	serviceName := mux.Vars(r)["serviceName"]
+	topStr := r.URL.Query().Get("top")
+	// Audit contextual fields for request
+	ctxFields := map[string]interface{}{
+		"serverName": serviceName,
+		"top":        topStr,
+	}
+
+	top := store.DefaultDiscoverStatisticsTop
+	if len(topStr) != 0 {
+		var err error
+		if top, err = strconv.Atoi(topStr); err != nil || top <= 0 {
+			err = errors.Errorf(errors.InvalidRequest, "invalid top %q", topStr)
+			log.Error(err)
+			httputils.HandleError(w, err)
+
+			// Audit log
+			logging.WriteFailedAuditLog("GetDiscoverStatistics", ctxFields, err.Error())
+			return
+		}
+	}
+	aggregates, revision, err := e.PolicyStore.(store.DiscoverRequestManager).GetDiscoverRequestAggregates(serviceName)
+	if err != nil && errors.Code(err) != errors.EntityNotFound {
+		log.Errorf("%v, Cause: %v", err, errors.Cause(err))
+		httputils.HandleError(w, err)
+
+		// Audit log
+		logging.WriteFailedAuditLog("GetDiscoverStatistics", ctxFields, err.Error())
+		return
+	}
+	statistics := store.ComputeDiscoverStatistics(aggregates, top)
+	response := GetDiscoverStatisticsResponse{Statistics: []*store.DiscoverStatistics{}, Revision: revision}
+	for _, serviceStatistics := range statistics {
+		response.Statistics = append(response.Statistics, serviceStatistics)
+	}
+	sort.Slice(response.Statistics, func(i, j int) bool {
+		return response.Statistics[i].ServiceName < response.Statistics[j].ServiceName
+	})
+	httputils.SendOKResponse(w, &response)
+
+	// Audit log
+	logging.WriteSucceededAuditLog("GetDiscoverStatistics", ctxFields, map[string]interface{}{"serviceCount": len(response.Statistics)})
+}
+
 func (e *RESTService) ResetDiscoverRequests(w http.ResponseWriter, r *http.Request) {
 	if !e.checkPolicyForDiscover(w) {
 		return
@@ -235,15 +322,24 @@ func (e *RESTService) GetDiscoverPolicies(w http.ResponseWriter, r *http.Request
 	principalType := r.URL.Query().Get("principalType")
 	principalName := r.URL.Query().Get("principalName")
 	principalIDD := r.URL.Query().Get("principalIDD")
+	generalize := r.URL.Query().Get("generalize") == "true"
 	// Audit contextual fields for request
 	ctxFields := map[string]interface{}{
 		"serverName":    serviceName,
 		"principalType": principalType,
 		"principalName": principalName,
 		"principalIDD":  principalIDD,
+		"generalize":    generalize,
 	}
 
-	serviceMap, revision, err := e.PolicyStore.(store.DiscoverRequestManager).GeneratePolicies(serviceName, principalType, principalName, principalIDD)
+	var serviceMap map[string]*pms.Service
+	var revision int64
+	var err error
+	if generalize {
+		serviceMap, revision, err = e.generalizePolicies(serviceName, principalType, principalName, principalIDD, r.URL.Query().Get("resourceThreshold"))
+	} else {
+		serviceMap, revision, err = e.PolicyStore.(store.DiscoverRequestManager).GeneratePolicies(serviceName, principalType, principalName, principalIDD)
+	}
 	if err != nil {
 		log.Error(err)
 		httputils.HandleError(w, err)
@@ -271,6 +367,27 @@ func (e *RESTService) GetDiscoverPolicies(w http.ResponseWriter, r *http.Request
 
 }
 
+// generalizePolicies generates generalized policies from the discover requests of a service
+func (e *RESTService) generalizePolicies(serviceName, principalType, principalName, principalIDD, resourceThreshold string) (map[string]*pms.Service, int64, error) {
+	opts := &store.GeneralizeOptions{}
+	if resourceThreshold != "" {
+		threshold, err := strconv.Atoi(resourceThreshold)
+		if err != nil {
+			return nil, -1, errors.Errorf(errors.InvalidRequest, "invalid resourceThreshold %q", resourceThreshold)
+		}
+		opts.ResourceThreshold = threshold
+	}
+	aggregates, revision, err := e.PolicyStore.(store.DiscoverRequestManager).GetDiscoverRequestAggregates(serviceName)
+	if err != nil {
+		return nil, -1, err
+	}
+	serviceMap, err := store.GeneralizePoliciesFromDiscoverRequestAggregates(aggregates, principalType, principalName, principalIDD, opts)
+	if err != nil {
+		return nil, -1, err
+	}
+	return serviceMap, revision, nil
+}
+
 func (e *RESTService) checkPolicyForDiscover(w http.ResponseWriter) bool {
 	if _, ok := e.PolicyStore.(store.DiscoverRequestManager); !ok {
 		err := errors.Errorf(errors.InvalidRequest, "%q policy store doesn't support discover request management", e.PolicyStore.Type())
@@ -6,8 +6,10 @@ package pmsrest
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
 	"github.com/teramoby/speedle-plus/pkg/errors"
 	"github.com/teramoby/speedle-plus/pkg/httputils"
 	"github.com/teramoby/speedle-plus/pkg/logging"
@@ -24,7 +26,8 @@ import (
 )
 
 type RESTService struct {
-	PolicyStore pms.PolicyStoreManager
+	PolicyStore    pms.PolicyStoreManager
+	ChangeRequests *pmsimpl.ChangeRequests
 }
 
 type serviceRequestBody struct {
@@ -33,7 +36,7 @@ type serviceRequestBody struct {
 }
 
 func NewRestService(s pms.PolicyStoreManager) (*RESTService, error) {
-	return &RESTService{PolicyStore: s}, nil
+	return &RESTService{PolicyStore: s, ChangeRequests: pmsimpl.NewChangeRequests(nil, s)}, nil
 }
 
 // returns:
@@ -191,13 +194,24 @@ func (mgr *RESTService) GetService(w http.ResponseWriter, r *http.Request) {
 	httputils.SendOKResponse(w, &service)
 }
 
+// ListServices lists all services. With query parameter "owner=<principal>" only services owned by the principal
+// are listed, with "owned=true" only services owned by the authenticated caller.
 func (mgr *RESTService) ListServices(w http.ResponseWriter, r *http.Request) {
+	owners, err := parseOwnerFilter(r)
+	if err != nil {
+		httputils.HandleError(w, err)
+		return
+	}
+
 	services, err := mgr.PolicyStore.ListAllServices()
 	if err != nil {
 		httputils.HandleError(w, err)
 		logging.WriteSimpleFailedAuditLog("ListServices", nil, err.Error())
 		return
 	}
+	if owners != nil {
+		services = pmsimpl.OwnedServices(services, owners)
+	}
 
 	logging.WriteSimpleSucceededAuditLog("ListServices", nil, len(services))
 
@@ -208,6 +222,61 @@ func (mgr *RESTService) ListServices(w http.ResponseWriter, r *http.Request) {
 	httputils.SendOKResponse(w, &services)
 }
 
+// parseOwnerFilter returns the owners filtering listed services, or nil if services are not filtered
+func parseOwnerFilter(r *http.Request) ([]*adsapi.Principal, error) {
+	owned, _ := strconv.ParseBool(r.URL.Query().Get("owned"))
+	return pmsimpl.OwnerFilter(r.Context(), owned, r.URL.Query().Get("owner"))
+}
+
+type ownersBody struct {
+	Owners []string `json:"owners"`
+}
+
+func (mgr *RESTService) GetServiceOwners(w http.ResponseWriter, r *http.Request) {
+	serviceName := mux.Vars(r)["serviceName"]
+	service, err := mgr.PolicyStore.GetService(serviceName)
+	if err != nil {
+		httputils.HandleError(w, err)
+		logging.WriteSimpleFailedAuditLog("GetServiceOwners", serviceName, err.Error())
+		return
+	}
+
+	logging.WriteSimpleSucceededAuditLog("GetServiceOwners", serviceName, nil)
+	httputils.SendOKResponse(w, &ownersBody{Owners: service.Owners})
+}
+
+func (mgr *RESTService) AddServiceOwners(w http.ResponseWriter, r *http.Request) {
+	serviceName := mux.Vars(r)["serviceName"]
+	var body ownersBody
+	if err := decodeRequestBody(r, &body); err != nil {
+		httputils.HandleError(w, err)
+		return
+	}
+
+	owners, err := pmsimpl.AddServiceOwners(serviceName, body.Owners, mgr.PolicyStore)
+	if err != nil {
+		httputils.HandleError(w, err)
+		logging.WriteSimpleFailedAuditLog("AddServiceOwners", log.Fields{"service": serviceName, "owners": body.Owners}, err.Error())
+		return
+	}
+
+	logging.WriteSimpleSucceededAuditLog("AddServiceOwners", log.Fields{"service": serviceName, "owners": body.Owners}, nil)
+	httputils.SendOKResponse(w, &ownersBody{Owners: owners})
+}
+
+func (mgr *RESTService) RemoveServiceOwner(w http.ResponseWriter, r *http.Request) {
+	serviceName := mux.Vars(r)["serviceName"]
+	owner := mux.Vars(r)["owner"]
+	if _, err := pmsimpl.RemoveServiceOwner(serviceName, owner, mgr.PolicyStore); err != nil {
+		httputils.HandleError(w, err)
+		logging.WriteSimpleFailedAuditLog("RemoveServiceOwner", log.Fields{"service": serviceName, "owner": owner}, err.Error())
+		return
+	}
+
+	logging.WriteSimpleSucceededAuditLog("RemoveServiceOwner", log.Fields{"service": serviceName, "owner": owner}, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (mgr *RESTService) ListPolicyAndRolePolicyCounts(w http.ResponseWriter, r *http.Request) {
 	countMap, err := mgr.PolicyStore.GetPolicyAndRolePolicyCounts()
 	if err != nil {
@@ -229,6 +298,9 @@ func (mgr *RESTService) CreatePolicy(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if !mgr.checkDirectChange(w, "CreatePolicy", serviceName) {
+		return
+	}
 	var policy pms.Policy
 	if err := decodeRequestBody(r, &policy); err != nil {
 		httputils.HandleError(w, err)
@@ -269,6 +341,9 @@ func (mgr *RESTService) DeletePolicies(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if !mgr.checkDirectChange(w, "DeletePolicies", serviceName) {
+		return
+	}
 
 	if err := mgr.PolicyStore.DeletePolicies(serviceName); err != nil {
 		httputils.HandleError(w, err)
@@ -288,6 +363,9 @@ func (mgr *RESTService) DeletePolicy(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if !mgr.checkDirectChange(w, "DeletePolicy", serviceName) {
+		return
+	}
 
 	// Audit contextual fields for request
 	ctxFields := log.Fields{
@@ -364,6 +442,9 @@ func (mgr *RESTService) CreateRolePolicy(w http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	if !mgr.checkDirectChange(w, "CreateRolePolicy", serviceName) {
+		return
+	}
 	var rolePolicy pms.RolePolicy
 	if err := decodeRequestBody(r, &rolePolicy); err != nil {
 		httputils.HandleError(w, err)
@@ -403,6 +484,9 @@ func (mgr *RESTService) DeleteRolePolicies(w http.ResponseWriter, r *http.Reques
 		})
 		return
 	}
+	if !mgr.checkDirectChange(w, "DeleteRolePolicies", serviceName) {
+		return
+	}
 
 	if err := mgr.PolicyStore.DeleteRolePolicies(serviceName); err != nil {
 		httputils.HandleError(w, err)
@@ -422,6 +506,9 @@ func (mgr *RESTService) DeleteRolePolicy(w http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	if !mgr.checkDirectChange(w, "DeleteRolePolicy", serviceName) {
+		return
+	}
 
 	// Audit contextual fields for request
 	ctxFields := log.Fields{
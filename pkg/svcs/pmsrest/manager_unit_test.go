@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 
 	"os"
+	"path/filepath"
 	"testing"
 
 	"time"
@@ -24,7 +25,7 @@ import (
 	"github.com/teramoby/speedle-plus/pkg/svcs"
 )
 
-var storeFile = "./fakestore.json"
+var storeFile string
 var creator = "creator"
 var testserver *httptest.Server
 
@@ -75,12 +76,18 @@ func TestMain(m *testing.M) {
 }
 
 func testMain(m *testing.M) int {
-	err := ioutil.WriteFile(storeFile, []byte(`{"services":[{"name":"fakeservice","type":"app"}]}`), 0644)
+	dir, err := ioutil.TempDir("", "pmsrest")
+	if err != nil {
+		log.Fatal(err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	storeFile = filepath.Join(dir, "fakestore.json")
+	err = ioutil.WriteFile(storeFile, []byte(`{"services":[{"name":"fakeservice","type":"app"}]}`), 0644)
 	if err != nil {
 		log.Fatal(err)
 		return 1
 	}
-	defer os.Remove(storeFile)
 	testserver, err = NewTestServer()
 	if err != nil {
 		log.Fatal("failed to start test server. error:", err)
@@ -0,0 +1,31 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsrest
+
+import (
+	"net/http"
+
+	"github.com/teramoby/speedle-plus/pkg/metrics"
+)
+
+// statusRecorder keeps the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// metricsHandler counts the policy management operations of a route, requests failed with 4xx or
+// 5xx status codes are counted as failures
+func metricsHandler(name string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		handler.ServeHTTP(recorder, r)
+		metrics.ObservePMSOperation(name, metrics.ProtocolREST, recorder.status >= http.StatusBadRequest)
+	})
+}
@@ -8,7 +8,10 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/health"
+	"github.com/teramoby/speedle-plus/pkg/metrics"
 	"github.com/teramoby/speedle-plus/pkg/svcs"
+	"github.com/teramoby/speedle-plus/pkg/svcs/pmsimpl"
 )
 
 type route struct {
@@ -18,12 +21,15 @@ type route struct {
 	HandlerFunc http.HandlerFunc
 }
 
-func initRouters(ps pms.PolicyStoreManager) (*[]route, error) {
+func initRouters(ps pms.PolicyStoreManager, changeRequests *pmsimpl.ChangeRequests) (*[]route, error) {
 
 	manager, err := NewRestService(ps)
 	if err != nil {
 		return nil, err
 	}
+	if changeRequests != nil {
+		manager.ChangeRequests = changeRequests
+	}
 
 	svcRoutes := []route{}
 
@@ -136,6 +142,27 @@ func initRouters(ps pms.PolicyStoreManager) (*[]route, error) {
 			manager.ListServices,
 		},
 
+		{
+			"GetServiceOwners",
+			"GET",
+			svcs.PolicyMgmtPath + "service/{serviceName}/owners",
+			manager.GetServiceOwners,
+		},
+
+		{
+			"AddServiceOwners",
+			"POST",
+			svcs.PolicyMgmtPath + "service/{serviceName}/owners",
+			manager.AddServiceOwners,
+		},
+
+		{
+			"RemoveServiceOwner",
+			"DELETE",
+			svcs.PolicyMgmtPath + "service/{serviceName}/owners/{owner}",
+			manager.RemoveServiceOwner,
+		},
+
 		{
 			"ListPolicyCounts",
 			"GET",
@@ -225,15 +252,121 @@ func initRouters(ps pms.PolicyStoreManager) (*[]route, error) {
 			svcs.PolicyMgmtPath + "discover-policy",
 			manager.GetDiscoverPolicies,
 		},
+
+		{
+			"GetDiscoverStatistics",
+			"GET",
+			svcs.PolicyMgmtPath + "discover-statistics/{serviceName}",
+			manager.GetDiscoverStatistics,
+		},
+
+		{
+			"GetAllDiscoverStatistics",
+			"GET",
+			svcs.PolicyMgmtPath + "discover-statistics",
+			manager.GetDiscoverStatistics,
+		},
 	}
 	svcRoutes = append(svcRoutes, discoverRequestManageRoutes...)
 
+	changeRequestRoutes := []route{
+		{
+			"ListChangeRequests",
+			"GET",
+			svcs.PolicyMgmtPath + "service/{serviceName}/change-request",
+			manager.ListChangeRequests,
+		},
+
+		{
+			"CreateChangeRequest",
+			"POST",
+			svcs.PolicyMgmtPath + "service/{serviceName}/change-request",
+			manager.CreateChangeRequest,
+		},
+
+		{
+			"GetChangeRequest",
+			"GET",
+			svcs.PolicyMgmtPath + "service/{serviceName}/change-request/{changeRequestID}",
+			manager.GetChangeRequest,
+		},
+
+		{
+			"UpdateChangeRequest",
+			"PUT",
+			svcs.PolicyMgmtPath + "service/{serviceName}/change-request/{changeRequestID}",
+			manager.UpdateChangeRequest,
+		},
+
+		{
+			"GetChangeRequestDiff",
+			"GET",
+			svcs.PolicyMgmtPath + "service/{serviceName}/change-request/{changeRequestID}/diff",
+			manager.GetChangeRequestDiff,
+		},
+
+		{
+			"CommentChangeRequest",
+			"POST",
+			svcs.PolicyMgmtPath + "service/{serviceName}/change-request/{changeRequestID}/comment",
+			manager.CommentChangeRequest,
+		},
+
+		{
+			"SubmitChangeRequest",
+			"POST",
+			svcs.PolicyMgmtPath + "service/{serviceName}/change-request/{changeRequestID}/submit",
+			manager.SubmitChangeRequest,
+		},
+
+		{
+			"ApproveChangeRequest",
+			"POST",
+			svcs.PolicyMgmtPath + "service/{serviceName}/change-request/{changeRequestID}/approve",
+			manager.ApproveChangeRequest,
+		},
+
+		{
+			"RejectChangeRequest",
+			"POST",
+			svcs.PolicyMgmtPath + "service/{serviceName}/change-request/{changeRequestID}/reject",
+			manager.RejectChangeRequest,
+		},
+
+		{
+			"CloseChangeRequest",
+			"POST",
+			svcs.PolicyMgmtPath + "service/{serviceName}/change-request/{changeRequestID}/close",
+			manager.CloseChangeRequest,
+		},
+
+		{
+			"PublishChangeRequest",
+			"POST",
+			svcs.PolicyMgmtPath + "service/{serviceName}/change-request/{changeRequestID}/publish",
+			manager.PublishChangeRequest,
+		},
+	}
+	svcRoutes = append(svcRoutes, changeRequestRoutes...)
+
 	return &svcRoutes, nil
 
 }
 
 func NewRouter(ps pms.PolicyStoreManager) (*mux.Router, error) {
-	routes, err := initRouters(ps)
+	return NewRouterWithAuthorizer(ps, nil)
+}
+
+// NewRouterWithAuthorizer creates the router of the PMS REST service. If the authorizer is not nil,
+// callers are authenticated and their operations authorized before requests are handled.
+func NewRouterWithAuthorizer(ps pms.PolicyStoreManager, authorizer *pmsimpl.Authorizer) (*mux.Router, error) {
+	return NewRouterWithChangeRequests(ps, authorizer, nil)
+}
+
+// NewRouterWithChangeRequests creates the router of the PMS REST service with the change request workflow,
+// which is created with the default configuration if it is nil
+func NewRouterWithChangeRequests(ps pms.PolicyStoreManager, authorizer *pmsimpl.Authorizer, changeRequests *pmsimpl.ChangeRequests) (*mux.Router, error) {
+	routes, err := initRouters(ps, changeRequests)
 	if err != nil {
 		return nil, err
 	}
@@ -242,12 +375,19 @@ func NewRouter(ps pms.PolicyStoreManager) (*mux.Router, error) {
 	for _, route := range *routes {
 		var handler http.Handler
 		handler = route.HandlerFunc
+		if authorizer != nil {
+			handler = authzHandler(authorizer, route.Name, handler)
+		}
 		router.
 			Methods(route.Method).
 			Path(route.Pattern).
 			Name(route.Name).
-			Handler(handler)
+			Handler(tracingHandler(route.Name, metricsHandler(route.Name, handler)))
 	}
+	router.Methods("GET").Path(svcs.MetricsPath).Name("Metrics").Handler(metrics.Handler())
+	checker := &health.StoreChecker{Store: ps}
+	router.Methods("GET").Path(svcs.HealthzPath).Name("Healthz").Handler(health.LivenessHandler(checker))
+	router.Methods("GET").Path(svcs.ReadyzPath).Name("Readyz").Handler(health.ReadinessHandler(checker))
 
 	return router, nil
 }
@@ -0,0 +1,29 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package pmsrest
+
+import (
+	"net/http"
+
+	"github.com/teramoby/speedle-plus/pkg/metrics"
+	"github.com/teramoby/speedle-plus/pkg/tracing"
+)
+
+// tracingHandler traces the requests of a route, the span continues the trace in the traceparent
+// header of the request
+func tracingHandler(name string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !tracing.Enabled() {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		ctx, span := tracing.StartSpan(tracing.Extract(r.Context(), r.Header), name, tracing.SpanKindServer)
+		defer span.End()
+		span.SetAttribute("protocol", metrics.ProtocolREST)
+		span.SetAttribute("http.method", r.Method)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		handler.ServeHTTP(recorder, r.WithContext(ctx))
+		span.SetAttribute("http.status_code", recorder.status)
+	})
+}
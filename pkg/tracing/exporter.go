@@ -0,0 +1,345 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teramoby/speedle-plus/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Exporters shipped with speedle
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+const (
+	defaultServiceName  = "speedle"
+	defaultOTLPEndpoint = "http://localhost:4318"
+	batchSize           = 256
+	batchInterval       = 5 * time.Second
+)
+
+// Config is the tracing configuration of a service
+type Config struct {
+	// Exporter is the name of the exporter, tracing is disabled if it is empty or "none"
+	Exporter string `json:"exporter,omitempty"`
+	// Endpoint is the base URL of the OTLP/HTTP collector, like http://localhost:4318
+	Endpoint string `json:"endpoint,omitempty"`
+	// ServiceName is the service.name resource attribute of the exported spans
+	ServiceName string `json:"serviceName,omitempty"`
+	// SampleRatio is the ratio of new traces which are sampled, 0 means 1
+	SampleRatio float64 `json:"sampleRatio,omitempty"`
+	// Headers are sent with each export request, for example authorization headers of the collector
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Exporter sends ended spans to a tracing backend
+type Exporter interface {
+	ExportSpans(spans []*SpanData)
+}
+
+// ExporterBuilder builds an exporter from the configuration
+type ExporterBuilder func(config *Config) (Exporter, error)
+
+var (
+	exportersLock sync.RWMutex
+	exporters     = map[string]ExporterBuilder{
+		ExporterStdout: func(config *Config) (Exporter, error) {
+			return NewStdoutExporter(os.Stdout), nil
+		},
+		ExporterOTLP: func(config *Config) (Exporter, error) {
+			return NewOTLPExporter(config), nil
+		},
+	}
+)
+
+// RegisterExporter registers an exporter, which can then be set in the configuration by its name
+func RegisterExporter(name string, builder ExporterBuilder) {
+	exportersLock.Lock()
+	defer exportersLock.Unlock()
+	exporters[name] = builder
+}
+
+// Init sets the tracer of the process from the configuration. Tracing is disabled if the
+// configuration is nil or has no exporter. The returned tracer should be shut down when the process
+// exits, it is nil if tracing is disabled.
+func Init(config *Config) (*Tracer, error) {
+	if config == nil || config.Exporter == "" || config.Exporter == ExporterNone {
+		SetTracer(nil)
+		return nil, nil
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, errors.Errorf(errors.ConfigError, "sample ratio %v of tracing is not between 0 and 1", config.SampleRatio)
+	}
+	exportersLock.RLock()
+	builder, ok := exporters[config.Exporter]
+	exportersLock.RUnlock()
+	if !ok {
+		return nil, errors.Errorf(errors.ConfigError, "unsupported tracing exporter %q", config.Exporter)
+	}
+	exporter, err := builder(config)
+	if err != nil {
+		return nil, err
+	}
+	ratio := config.SampleRatio
+	if ratio == 0 {
+		ratio = 1
+	}
+	tracer := NewTracer(exporter, ratio, true)
+	SetTracer(tracer)
+	log.Infof("Tracing enabled, exporter: %s, sample ratio: %v.", config.Exporter, ratio)
+	return tracer, nil
+}
+
+// batcher exports spans in batches in the background
+type batcher struct {
+	exporter Exporter
+	spans    chan *SpanData
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newBatcher(exporter Exporter) *batcher {
+	b := &batcher{
+		exporter: exporter,
+		spans:    make(chan *SpanData, batchSize*4),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *batcher) add(span *SpanData) {
+	select {
+	case b.spans <- span:
+	default:
+		log.Debugf("Tracing queue is full, span %s is dropped.", span.Name)
+	}
+}
+
+func (b *batcher) run() {
+	defer close(b.done)
+	ticker := time.NewTicker(batchInterval)
+	defer ticker.Stop()
+	batch := make([]*SpanData, 0, batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			b.exporter.ExportSpans(batch)
+			batch = make([]*SpanData, 0, batchSize)
+		}
+	}
+	for {
+		select {
+		case span, ok := <-b.spans:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, span)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (b *batcher) stop() {
+	b.stopOnce.Do(func() {
+		close(b.spans)
+		<-b.done
+	})
+}
+
+// InMemoryExporter keeps the exported spans in memory, it is used in tests
+type InMemoryExporter struct {
+	sync.Mutex
+	spans []*SpanData
+}
+
+// ExportSpans keeps the spans
+func (e *InMemoryExporter) ExportSpans(spans []*SpanData) {
+	e.Lock()
+	defer e.Unlock()
+	e.spans = append(e.spans, spans...)
+}
+
+// Spans returns the exported spans in the order they ended
+func (e *InMemoryExporter) Spans() []*SpanData {
+	e.Lock()
+	defer e.Unlock()
+	return append([]*SpanData{}, e.spans...)
+}
+
+// Reset removes the exported spans
+func (e *InMemoryExporter) Reset() {
+	e.Lock()
+	defer e.Unlock()
+	e.spans = nil
+}
+
+// StdoutExporter writes spans as JSON lines
+type StdoutExporter struct {
+	sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewStdoutExporter creates an exporter writing spans to the writer
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{encoder: json.NewEncoder(w)}
+}
+
+// ExportSpans writes the spans
+func (e *StdoutExporter) ExportSpans(spans []*SpanData) {
+	e.Lock()
+	defer e.Unlock()
+	for _, span := range spans {
+		if err := e.encoder.Encode(span); err != nil {
+			log.Warnf("Failed to write span %s: %v.", span.Name, err)
+		}
+	}
+}
+
+// OTLPExporter posts spans to an OpenTelemetry collector with the OTLP/HTTP JSON protocol
+type OTLPExporter struct {
+	url         string
+	serviceName string
+	headers     map[string]string
+	client      *http.Client
+}
+
+// NewOTLPExporter creates an OTLP exporter
+func NewOTLPExporter(config *Config) *OTLPExporter {
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = defaultOTLPEndpoint
+	}
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	return &OTLPExporter{
+		url:         strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		serviceName: serviceName,
+		headers:     config.Headers,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type otlpKeyValue struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            *otlpStatus    `json:"status,omitempty"`
+}
+
+func otlpValue(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case string:
+		return map[string]interface{}{"stringValue": v}
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case int:
+		return map[string]interface{}{"intValue": fmt.Sprint(v)}
+	case int64:
+		return map[string]interface{}{"intValue": fmt.Sprint(v)}
+	case float64:
+		return map[string]interface{}{"doubleValue": v}
+	default:
+		return map[string]interface{}{"stringValue": fmt.Sprint(v)}
+	}
+}
+
+// ExportSpans posts the spans, failures are logged and the spans are dropped
+func (e *OTLPExporter) ExportSpans(spans []*SpanData) {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentSpanID,
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: fmt.Sprint(span.Start.UnixNano()),
+			EndTimeUnixNano:   fmt.Sprint(span.End.UnixNano()),
+		}
+		for key, value := range span.Attributes {
+			s.Attributes = append(s.Attributes, otlpKeyValue{Key: key, Value: otlpValue(value)})
+		}
+		if span.Error != "" {
+			// STATUS_CODE_ERROR
+			s.Status = &otlpStatus{Code: 2, Message: span.Error}
+		}
+		otlpSpans = append(otlpSpans, s)
+	}
+	payload := map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []otlpKeyValue{{Key: "service.name", Value: otlpValue(e.serviceName)}},
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": "github.com/teramoby/speedle-plus"},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Warnf("Failed to marshal %d spans: %v.", len(spans), err)
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		log.Warnf("Failed to create the export request to %s: %v.", e.url, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		log.Warnf("Failed to export %d spans to %s: %v.", len(spans), e.url, err)
+		return
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusBadRequest {
+		log.Warnf("Failed to export %d spans to %s, status: %d.", len(spans), e.url, resp.StatusCode)
+	}
+}
@@ -0,0 +1,102 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// TraceparentHeader is the W3C trace context header, it is also the key of gRPC metadata
+const TraceparentHeader = "traceparent"
+
+// ParseTraceparent parses a W3C traceparent header like
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+func ParseTraceparent(traceparent string) (SpanContext, bool) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return sc, false
+	}
+	// Version 00 has exactly four fields, later versions may add fields
+	if parts[0] == "00" && len(parts) != 4 {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return sc, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return sc, false
+	}
+	sc.Sampled = flags[0]&0x01 == 0x01
+	return sc, sc.IsValid()
+}
+
+// Traceparent formats the span context as a W3C traceparent header
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceIDString(), sc.SpanIDString(), flags)
+}
+
+// Extract returns a context carrying the trace context of the traceparent header of an incoming
+// request
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if sc, ok := ParseTraceparent(header.Get(TraceparentHeader)); ok {
+		return ContextWithRemoteParent(ctx, sc)
+	}
+	return ctx
+}
+
+// Inject sets the traceparent header of an outgoing request to the current span of the context
+func Inject(ctx context.Context, header http.Header) {
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		header.Set(TraceparentHeader, sc.Traceparent())
+	}
+}
+
+// Headers returns the traceparent header of the current span of the context as a map, which is empty
+// if there is no span
+func Headers(ctx context.Context) map[string]string {
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		return map[string]string{TraceparentHeader: sc.Traceparent()}
+	}
+	return nil
+}
+
+// ExtractGRPC returns a context carrying the trace context of the traceparent metadata of an incoming
+// gRPC request
+func ExtractGRPC(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if values := md.Get(TraceparentHeader); len(values) > 0 {
+		if sc, ok := ParseTraceparent(values[0]); ok {
+			return ContextWithRemoteParent(ctx, sc)
+		}
+	}
+	return ctx
+}
+
+// InjectGRPC returns a context which sends the current span of the context in the traceparent metadata
+// of outgoing gRPC requests
+func InjectGRPC(ctx context.Context) context.Context {
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		return metadata.AppendToOutgoingContext(ctx, TraceparentHeader, sc.Traceparent())
+	}
+	return ctx
+}
@@ -0,0 +1,265 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+// Package tracing traces requests of the authorization decision service and the policy management
+// service. Spans are compatible with OpenTelemetry: trace contexts are propagated in W3C traceparent
+// headers, and spans can be exported to OpenTelemetry collectors with the OTLP exporter.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SpanKind is the role of a span in a trace
+type SpanKind int
+
+// Kinds of spans, the values are the ones of OTLP
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// SpanContext identifies a span in a trace
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// IsValid checks whether the trace ID and the span ID are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// TraceIDString returns the trace ID in hex
+func (sc SpanContext) TraceIDString() string {
+	return hex.EncodeToString(sc.TraceID[:])
+}
+
+// SpanIDString returns the span ID in hex
+func (sc SpanContext) SpanIDString() string {
+	return hex.EncodeToString(sc.SpanID[:])
+}
+
+// SpanData is an ended span passed to exporters
+type SpanData struct {
+	Name         string                 `json:"name"`
+	Kind         SpanKind               `json:"kind"`
+	TraceID      string                 `json:"traceId"`
+	SpanID       string                 `json:"spanId"`
+	ParentSpanID string                 `json:"parentSpanId,omitempty"`
+	Start        time.Time              `json:"start"`
+	End          time.Time              `json:"end"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+// Span is an operation of a trace. Methods of a nil span do nothing, which is the span returned when
+// tracing is disabled.
+type Span struct {
+	sync.Mutex
+	tracer *Tracer
+	sc     SpanContext
+	data   SpanData
+	ended  bool
+}
+
+// SpanContext returns the identity of the span
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// SetAttribute sets an attribute of the span, values should be strings, numbers or booleans
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]interface{})
+	}
+	s.data.Attributes[key] = value
+}
+
+// RecordError marks the span as failed, nil errors are ignored
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.data.Error = err.Error()
+}
+
+// End ends the span and exports it if it is sampled. Spans are only ended once.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	if s.ended {
+		s.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.Unlock()
+	if s.sc.Sampled {
+		s.tracer.export(&data)
+	}
+}
+
+// Tracer creates spans and sends the sampled ones to its exporter
+type Tracer struct {
+	exporter    Exporter
+	sampleRatio float64
+	batcher     *batcher
+}
+
+// NewTracer creates a tracer. Root spans are sampled with the ratio, spans with a parent follow the
+// sampling decision of the parent. Spans are exported in batches in the background if batch is true,
+// otherwise each span is exported when it ends.
+func NewTracer(exporter Exporter, sampleRatio float64, batch bool) *Tracer {
+	t := &Tracer{exporter: exporter, sampleRatio: sampleRatio}
+	if batch {
+		t.batcher = newBatcher(exporter)
+	}
+	return t
+}
+
+func (t *Tracer) export(span *SpanData) {
+	if t.batcher != nil {
+		t.batcher.add(span)
+		return
+	}
+	t.exporter.ExportSpans([]*SpanData{span})
+}
+
+// Shutdown exports the pending spans and stops the tracer
+func (t *Tracer) Shutdown() {
+	if t.batcher != nil {
+		t.batcher.stop()
+	}
+}
+
+var globalTracer atomic.Value // *Tracer
+
+// SetTracer sets the tracer of the process, nil disables tracing
+func SetTracer(t *Tracer) {
+	globalTracer.Store(&t)
+}
+
+// GetTracer returns the tracer of the process, or nil if tracing is disabled
+func GetTracer() *Tracer {
+	if t, ok := globalTracer.Load().(**Tracer); ok {
+		return *t
+	}
+	return nil
+}
+
+// Enabled checks whether requests are traced
+func Enabled() bool {
+	return GetTracer() != nil
+}
+
+type spanKey struct{}
+type remoteKey struct{}
+
+// ContextWithSpan returns a context carrying the span
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the span of the context, or nil if there is no span
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteParent returns a context carrying the span context received from the caller
+func ContextWithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context of the current span of the context, or the one
+// received from the caller
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+	if span := SpanFromContext(ctx); span != nil {
+		return span.sc
+	}
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+// Detach returns a background context carrying the trace context of the context, without its
+// deadline and cancellation, for calls which shouldn't be cancelled with the request
+func Detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if span := SpanFromContext(ctx); span != nil {
+		return ContextWithSpan(detached, span)
+	}
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		return ContextWithRemoteParent(detached, sc)
+	}
+	return detached
+}
+
+// StartSpan starts a span which is a child of the span of the context. It returns the context carrying
+// the new span, and a nil span if tracing is disabled.
+func StartSpan(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tracer := GetTracer()
+	if tracer == nil {
+		return ctx, nil
+	}
+
+	parent := SpanContextFromContext(ctx)
+	span := &Span{tracer: tracer}
+	if parent.IsValid() {
+		span.sc.TraceID = parent.TraceID
+		span.sc.Sampled = parent.Sampled
+		span.data.ParentSpanID = parent.SpanIDString()
+	} else {
+		rand.Read(span.sc.TraceID[:])
+		span.sc.Sampled = tracer.sample(span.sc.TraceID)
+	}
+	rand.Read(span.sc.SpanID[:])
+	span.data.Name = name
+	span.data.Kind = kind
+	span.data.TraceID = span.sc.TraceIDString()
+	span.data.SpanID = span.sc.SpanIDString()
+	span.data.Start = time.Now()
+	return ContextWithSpan(ctx, span), span
+}
+
+// sample decides whether a new trace is sampled by its ID, so that the decision is stable
+func (t *Tracer) sample(traceID [16]byte) bool {
+	if t.sampleRatio >= 1 {
+		return true
+	}
+	if t.sampleRatio <= 0 {
+		return false
+	}
+	bound := uint64(t.sampleRatio * (1 << 63))
+	return binary.BigEndian.Uint64(traceID[8:])>>1 < bound
+}
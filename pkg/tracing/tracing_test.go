@@ -0,0 +1,144 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	testCases := []struct {
+		traceparent string
+		valid       bool
+		sampled     bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true, false},
+		// later versions may have more fields
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false, false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01", false, false},
+		{"", false, false},
+	}
+	for _, tc := range testCases {
+		sc, ok := ParseTraceparent(tc.traceparent)
+		if ok != tc.valid {
+			t.Errorf("%q: got valid %v, want %v", tc.traceparent, ok, tc.valid)
+			continue
+		}
+		if ok && sc.Sampled != tc.sampled {
+			t.Errorf("%q: got sampled %v, want %v", tc.traceparent, sc.Sampled, tc.sampled)
+		}
+	}
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if sc, _ := ParseTraceparent(traceparent); sc.Traceparent() != traceparent {
+		t.Errorf("got %q, want %q", sc.Traceparent(), traceparent)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	if ctx, span := StartSpan(context.Background(), "disabled", SpanKindInternal); span != nil || SpanFromContext(ctx) != nil {
+		t.Fatal("no span should be started when tracing is disabled")
+	}
+
+	exporter := &InMemoryExporter{}
+	SetTracer(NewTracer(exporter, 1, false))
+	defer SetTracer(nil)
+
+	header := http.Header{}
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, server := StartSpan(Extract(context.Background(), header), "server", SpanKindServer)
+	childCtx, child := StartSpan(ctx, "child", SpanKindClient)
+	child.SetAttribute("function", "f")
+	child.RecordError(errors.New("failed"))
+
+	outgoing := http.Header{}
+	Inject(childCtx, outgoing)
+	if want := child.SpanContext().Traceparent(); outgoing.Get(TraceparentHeader) != want {
+		t.Errorf("injected %q, want %q", outgoing.Get(TraceparentHeader), want)
+	}
+	md, _ := metadata.FromOutgoingContext(InjectGRPC(childCtx))
+	if values := md.Get(TraceparentHeader); len(values) != 1 || values[0] != outgoing.Get(TraceparentHeader) {
+		t.Errorf("unexpected gRPC metadata %v", md)
+	}
+	child.End()
+	child.End()
+	server.End()
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Name != "child" || spans[0].ParentSpanID != server.SpanContext().SpanIDString() ||
+		spans[0].Error != "failed" || spans[0].Attributes["function"] != "f" {
+		payload, _ := json.Marshal(spans[0])
+		t.Errorf("unexpected child span %s", payload)
+	}
+	if spans[1].TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || spans[1].ParentSpanID != "00f067aa0ba902b7" {
+		payload, _ := json.Marshal(spans[1])
+		t.Errorf("unexpected server span %s", payload)
+	}
+
+	// Spans of traces which are not sampled by the caller are not exported
+	exporter.Reset()
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	_, span := StartSpan(Extract(context.Background(), header), "server", SpanKindServer)
+	span.End()
+	if len(exporter.Spans()) != 0 {
+		t.Error("spans of traces which are not sampled should not be exported")
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var payload map[string]interface{}
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	tracer, err := Init(&Config{Exporter: ExporterOTLP, Endpoint: server.URL, ServiceName: "ads", Headers: map[string]string{"Authorization": "Bearer t"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := StartSpan(context.Background(), "is-allowed", SpanKindServer)
+	span.SetAttribute("allowed", true)
+	span.End()
+	tracer.Shutdown()
+	SetTracer(nil)
+
+	if auth != "Bearer t" {
+		t.Errorf("got authorization header %q", auth)
+	}
+	resourceSpans, _ := payload["resourceSpans"].([]interface{})
+	if len(resourceSpans) != 1 {
+		t.Fatalf("unexpected payload %v", payload)
+	}
+	scopeSpans := resourceSpans[0].(map[string]interface{})["scopeSpans"].([]interface{})
+	spans := scopeSpans[0].(map[string]interface{})["spans"].([]interface{})
+	if len(spans) != 1 || spans[0].(map[string]interface{})["traceId"] != span.SpanContext().TraceIDString() {
+		t.Errorf("unexpected spans %v", spans)
+	}
+
+	if _, err := Init(&Config{Exporter: "zipkin"}); err == nil {
+		t.Error("unknown exporters should be rejected")
+	}
+}
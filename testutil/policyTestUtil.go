@@ -0,0 +1,40 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package testutil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/teramoby/speedle-plus/testutil/policytest"
+)
+
+// RunPolicyTests runs the cases of a policy test file as subtests with an in-process evaluator. Failed
+// cases are reported with the diagnosis of their requests, and the policies no request matched are
+// logged.
+func RunPolicyTests(t *testing.T, testFile string) *policytest.Report {
+	report, err := policytest.RunFile(testFile)
+	if err != nil {
+		t.Fatalf("failed to run policy tests of %s: %v", testFile, err)
+	}
+	for _, result := range report.Results {
+		result := result
+		t.Run(result.Name, func(t *testing.T) {
+			for _, failure := range result.Failures {
+				t.Error(failure)
+			}
+			if result.Diagnose != nil {
+				diagnose, _ := json.MarshalIndent(result.Diagnose, "", "    ")
+				t.Logf("diagnose: %s", diagnose)
+			}
+		})
+	}
+	for _, policy := range report.Coverage.UncoveredPolicies {
+		t.Logf("policy %s is never matched", policy)
+	}
+	for _, rolePolicy := range report.Coverage.UncoveredRolePolicies {
+		t.Logf("role policy %s is never matched", rolePolicy)
+	}
+	return report
+}
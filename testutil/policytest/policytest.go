@@ -0,0 +1,333 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+// Package policytest runs unit tests of policies. A test file lists requests with their expected
+// decisions, granted roles or granted permissions, and refers to the .json or .spdl policy file they
+// are evaluated against with an in-process evaluator. The report tells which policies and role
+// policies no request matched.
+package policytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/api/pms"
+	"github.com/teramoby/speedle-plus/pkg/cfg"
+	"github.com/teramoby/speedle-plus/pkg/decisionlog"
+	"github.com/teramoby/speedle-plus/pkg/eval"
+	"github.com/teramoby/speedle-plus/pkg/store"
+	_ "github.com/teramoby/speedle-plus/pkg/store/file"
+)
+
+// Suite is the content of a test file
+type Suite struct {
+	// Policies is the .json or .spdl policy file, relative to the test file
+	Policies string `json:"policies"`
+	// Service is the service of the requests which don't have one
+	Service string  `json:"service,omitempty"`
+	Cases   []*Case `json:"cases"`
+}
+
+// Case is a request with its expectations, only the set expectations are checked
+type Case struct {
+	Name    string                `json:"name"`
+	Request adsapi.RequestContext `json:"request"`
+	Expect  Expectation           `json:"expect"`
+}
+
+// Expectation is the expected outcome of a request
+type Expectation struct {
+	Allowed *bool  `json:"allowed,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	// GrantedRoles are all the roles granted to the subject, in any order
+	GrantedRoles []string `json:"grantedRoles,omitempty"`
+	// GrantedPermissions are all the permissions granted to the subject, in any order
+	GrantedPermissions []pms.Permission `json:"grantedPermissions,omitempty"`
+}
+
+// Result is the outcome of a case
+type Result struct {
+	Name     string                   `json:"name"`
+	Passed   bool                     `json:"passed"`
+	Failures []string                 `json:"failures,omitempty"`
+	Diagnose *adsapi.EvaluationResult `json:"diagnose,omitempty"` //diagnosis of the request of a failed case
+}
+
+// Coverage tells which policies and role policies were matched by a request
+type Coverage struct {
+	Policies              int      `json:"policies"`
+	RolePolicies          int      `json:"rolePolicies"`
+	UncoveredPolicies     []string `json:"uncoveredPolicies,omitempty"`
+	UncoveredRolePolicies []string `json:"uncoveredRolePolicies,omitempty"`
+}
+
+// Report is the outcome of a test file
+type Report struct {
+	File     string    `json:"file"`
+	Results  []*Result `json:"results"`
+	Passed   int       `json:"passed"`
+	Failed   int       `json:"failed"`
+	Coverage *Coverage `json:"coverage"`
+}
+
+// evaluator evaluates the cases
+type evaluator interface {
+	adsapi.PolicyEvaluator
+	Decide(ctx adsapi.RequestContext) *decisionlog.Record
+}
+
+// loadedStore serves the policies read once, so that the IDs generated for SPDL policies are the ones
+// of the coverage
+type loadedStore struct {
+	pms.PolicyStoreManagerADS
+	ps *pms.PolicyStore
+}
+
+func (s *loadedStore) ReadPolicyStore() (*pms.PolicyStore, error) {
+	return s.ps, nil
+}
+
+// LoadSuite reads a test file
+func LoadSuite(testFile string) (*Suite, error) {
+	raw, err := ioutil.ReadFile(testFile)
+	if err != nil {
+		return nil, err
+	}
+	var suite Suite
+	if err := json.Unmarshal(raw, &suite); err != nil {
+		return nil, fmt.Errorf("invalid test file %s: %v", testFile, err)
+	}
+	if suite.Policies == "" {
+		return nil, fmt.Errorf("policy file of test file %s is not set", testFile)
+	}
+	if !filepath.IsAbs(suite.Policies) {
+		suite.Policies = filepath.Join(filepath.Dir(testFile), suite.Policies)
+	}
+	return &suite, nil
+}
+
+// RunFile runs the cases of a test file
+func RunFile(testFile string) (*Report, error) {
+	suite, err := LoadSuite(testFile)
+	if err != nil {
+		return nil, err
+	}
+	report, err := Run(suite)
+	if err != nil {
+		return nil, err
+	}
+	report.File = testFile
+	return report, nil
+}
+
+// Run runs the cases of a suite against its policy file
+func Run(suite *Suite) (*Report, error) {
+	fileStore, err := store.NewStore(cfg.StorageTypeFile, map[string]interface{}{"FileLocation": suite.Policies})
+	if err != nil {
+		return nil, err
+	}
+	ps, err := fileStore.ReadPolicyStore()
+	if err != nil {
+		return nil, err
+	}
+	ie, err := eval.NewWithStore(&cfg.Config{}, &loadedStore{PolicyStoreManagerADS: fileStore, ps: ps})
+	if err != nil {
+		return nil, err
+	}
+	e, ok := ie.(evaluator)
+	if !ok {
+		return nil, fmt.Errorf("evaluator can't run policy tests")
+	}
+
+	report := &Report{File: suite.Policies}
+	matchedPolicies := map[string]bool{}
+	matchedRolePolicies := map[string]bool{}
+	for i, c := range suite.Cases {
+		ctx := c.Request
+		if ctx.ServiceName == "" {
+			ctx.ServiceName = suite.Service
+		}
+		result := &Result{Name: c.Name}
+		if result.Name == "" {
+			result.Name = fmt.Sprintf("case %d", i+1)
+		}
+		result.Failures = runCase(e, ctx, &c.Expect, matchedPolicies, matchedRolePolicies)
+		if result.Passed = len(result.Failures) == 0; result.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+			result.Diagnose, _ = e.Diagnose(ctx)
+		}
+		report.Results = append(report.Results, result)
+	}
+	report.Coverage = coverage(ps, matchedPolicies, matchedRolePolicies)
+	return report, nil
+}
+
+func runCase(e evaluator, ctx adsapi.RequestContext, expect *Expectation, matchedPolicies, matchedRolePolicies map[string]bool) []string {
+	var failures []string
+	// The decision is always made, so that the policies it matches are covered
+	record := e.Decide(ctx)
+	for _, id := range record.Policies {
+		matchedPolicies[id] = true
+	}
+	for _, id := range record.RolePolicies {
+		matchedRolePolicies[id] = true
+	}
+	allowed := record.Decision == decisionlog.DecisionAllow
+	got := record.Reason
+	if record.Error != "" {
+		got += ", error: " + record.Error
+	}
+	if expect.Allowed != nil && *expect.Allowed != allowed {
+		failures = append(failures, fmt.Sprintf("expected allowed %v, got %v (%s)", *expect.Allowed, allowed, got))
+	}
+	if expect.Reason != "" && expect.Reason != record.Reason {
+		failures = append(failures, fmt.Sprintf("expected reason %s, got %s", expect.Reason, got))
+	}
+
+	if expect.GrantedRoles != nil {
+		roles, err := e.GetAllGrantedRoles(ctx)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("failed to get granted roles: %v", err))
+		} else if diff := diffSets(expect.GrantedRoles, roles); diff != "" {
+			failures = append(failures, "granted roles differ: "+diff)
+		}
+	}
+	if expect.GrantedPermissions != nil {
+		permissions, err := e.GetAllGrantedPermissions(ctx)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("failed to get granted permissions: %v", err))
+		} else if diff := diffSets(flattenPermissions(expect.GrantedPermissions), flattenPermissions(permissions)); diff != "" {
+			failures = append(failures, "granted permissions differ: "+diff)
+		}
+	}
+	return failures
+}
+
+// flattenPermissions returns the permissions as "action resource" strings
+func flattenPermissions(permissions []pms.Permission) []string {
+	var flattened []string
+	for _, permission := range permissions {
+		resource := permission.Resource
+		if resource == "" {
+			resource = "expr:" + permission.ResourceExpression
+		}
+		for _, action := range permission.Actions {
+			flattened = append(flattened, action+" "+resource)
+		}
+	}
+	return flattened
+}
+
+// diffSets returns the missing items prefixed with - and the unexpected items prefixed with +, or an
+// empty string if the sets are the same
+func diffSets(expected, actual []string) string {
+	expectedSet := toSet(expected)
+	actualSet := toSet(actual)
+	var diffs []string
+	for item := range expectedSet {
+		if !actualSet[item] {
+			diffs = append(diffs, "-"+item)
+		}
+	}
+	for item := range actualSet {
+		if !expectedSet[item] {
+			diffs = append(diffs, "+"+item)
+		}
+	}
+	sort.Strings(diffs)
+	return strings.Join(diffs, ", ")
+}
+
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
+func coverage(ps *pms.PolicyStore, matchedPolicies, matchedRolePolicies map[string]bool) *Coverage {
+	c := &Coverage{}
+	for _, service := range ps.Services {
+		for _, policy := range service.Policies {
+			c.Policies++
+			if !matchedPolicies[policy.ID] {
+				c.UncoveredPolicies = append(c.UncoveredPolicies, service.Name+"/"+describePolicy(policy))
+			}
+		}
+		for _, rolePolicy := range service.RolePolicies {
+			c.RolePolicies++
+			if !matchedRolePolicies[rolePolicy.ID] {
+				c.UncoveredRolePolicies = append(c.UncoveredRolePolicies, service.Name+"/"+describeRolePolicy(rolePolicy))
+			}
+		}
+	}
+	return c
+}
+
+// describePolicy names a policy, SPDL policies don't have names and their IDs are generated, so they
+// are described by their content like "grant role:auditor get,delete /reports"
+func describePolicy(policy *pms.Policy) string {
+	if policy.Name != "" {
+		return policy.Name
+	}
+	var principals []string
+	for _, and := range policy.Principals {
+		principals = append(principals, strings.Join(and, "&"))
+	}
+	var permissions []string
+	for _, permission := range policy.Permissions {
+		resource := permission.Resource
+		if resource == "" {
+			resource = "expr:" + permission.ResourceExpression
+		}
+		permissions = append(permissions, strings.Join(permission.Actions, ",")+" "+resource)
+	}
+	return fmt.Sprintf("%s %s %s", policy.Effect, strings.Join(principals, "|"), strings.Join(permissions, "; "))
+}
+
+// describeRolePolicy names a role policy like describePolicy, for example "grant user:alice employee"
+func describeRolePolicy(rolePolicy *pms.RolePolicy) string {
+	if rolePolicy.Name != "" {
+		return rolePolicy.Name
+	}
+	return fmt.Sprintf("%s %s %s", rolePolicy.Effect, strings.Join(rolePolicy.Principals, "|"), strings.Join(rolePolicy.Roles, ","))
+}
+
+// Write writes the results of the cases, with the diagnosis of the failed ones, and the coverage
+func (r *Report) Write(w io.Writer) {
+	fmt.Fprintf(w, "%s:\n", r.File)
+	for _, result := range r.Results {
+		if result.Passed {
+			fmt.Fprintf(w, "  PASS  %s\n", result.Name)
+			continue
+		}
+		fmt.Fprintf(w, "  FAIL  %s\n", result.Name)
+		for _, failure := range result.Failures {
+			fmt.Fprintf(w, "        %s\n", failure)
+		}
+		if result.Diagnose != nil {
+			diagnose, _ := json.MarshalIndent(result.Diagnose, "        ", "    ")
+			fmt.Fprintf(w, "        diagnose: %s\n", diagnose)
+		}
+	}
+	fmt.Fprintf(w, "%d passed, %d failed\n", r.Passed, r.Failed)
+	if c := r.Coverage; c != nil {
+		fmt.Fprintf(w, "Coverage: %d of %d policies, %d of %d role policies matched by requests\n",
+			c.Policies-len(c.UncoveredPolicies), c.Policies, c.RolePolicies-len(c.UncoveredRolePolicies), c.RolePolicies)
+		for _, policy := range c.UncoveredPolicies {
+			fmt.Fprintf(w, "  never matched: policy %s\n", policy)
+		}
+		for _, rolePolicy := range c.UncoveredRolePolicies {
+			fmt.Fprintf(w, "  never matched: role policy %s\n", rolePolicy)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+//Copyright (c) 2018, Oracle and/or its affiliates. All rights reserved.
+//Licensed under the Universal Permissive License (UPL) Version 1.0 as shown at http://oss.oracle.com/licenses/upl.
+
+package policytest_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	adsapi "github.com/teramoby/speedle-plus/api/ads"
+	"github.com/teramoby/speedle-plus/testutil"
+	"github.com/teramoby/speedle-plus/testutil/policytest"
+)
+
+func TestRunPolicyTests(t *testing.T) {
+	report := testutil.RunPolicyTests(t, "testdata/expenses_test.json")
+	if report.Passed != 4 || report.Coverage.Policies != 3 || report.Coverage.RolePolicies != 3 {
+		t.Errorf("unexpected report %+v, coverage %+v", report, report.Coverage)
+	}
+	if len(report.Coverage.UncoveredPolicies) != 1 || report.Coverage.UncoveredPolicies[0] != "expenses/grant role:auditor get,delete /reports" {
+		t.Errorf("the auditor policy should be reported as never matched, got %v", report.Coverage.UncoveredPolicies)
+	}
+}
+
+func TestFailedCases(t *testing.T) {
+	allowed := true
+	alice := adsapi.RequestContext{
+		Subject:  &adsapi.Subject{Principals: []*adsapi.Principal{{Type: adsapi.PRINCIPAL_TYPE_USER, Name: "alice"}}},
+		Resource: "/reports",
+		Action:   "delete",
+	}
+	suite := &policytest.Suite{
+		Policies: "testdata/expenses.spdl",
+		Service:  "expenses",
+		Cases: []*policytest.Case{
+			{Name: "alice deletes", Request: alice, Expect: policytest.Expectation{Allowed: &allowed}},
+			{Request: alice, Expect: policytest.Expectation{GrantedRoles: []string{"auditor"}}},
+		},
+	}
+	report, err := policytest.Run(suite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Failed != 2 {
+		t.Fatalf("both cases should fail, report %+v", report)
+	}
+	if failures := report.Results[0].Failures; len(failures) != 1 || failures[0] != "expected allowed true, got false (NO_APPLICABLE_POLICIES)" {
+		t.Errorf("unexpected failures %v", failures)
+	}
+	if failures := report.Results[1].Failures; report.Results[1].Name != "case 2" || len(failures) != 1 || failures[0] != "granted roles differ: +employee, -auditor" {
+		t.Errorf("unexpected failures %v of %s", failures, report.Results[1].Name)
+	}
+	if report.Results[0].Diagnose == nil || len(report.Results[0].Diagnose.GrantedRoles) != 1 {
+		t.Errorf("failed cases should be diagnosed, got %+v", report.Results[0].Diagnose)
+	}
+
+	var out bytes.Buffer
+	report.Write(&out)
+	if !strings.Contains(out.String(), "  FAIL  alice deletes\n") || !strings.Contains(out.String(), "0 passed, 2 failed\n") ||
+		!strings.Contains(out.String(), "diagnose: {") {
+		t.Errorf("unexpected report:\n%s", out.String())
+	}
+
+	if _, err := policytest.RunFile("testdata/expenses.spdl"); err == nil {
+		t.Error("policy file isn't a test file")
+	}
+}